@@ -1,12 +1,12 @@
 package main
 
 import (
-	"bytes"
 	"context"
 	crypto_rand "crypto/rand"
 	database_sql "database/sql"
 	"encoding/hex"
 	"fmt"
+	"io/fs"
 	"math/rand"
 	"net/url"
 	"strings"
@@ -15,15 +15,20 @@ import (
 	"github.com/jacobpatterson1549/selene-bananas/db"
 	"github.com/jacobpatterson1549/selene-bananas/db/firestore"
 	"github.com/jacobpatterson1549/selene-bananas/db/mongo"
+	"github.com/jacobpatterson1549/selene-bananas/db/result"
 	"github.com/jacobpatterson1549/selene-bananas/db/sql"
 	"github.com/jacobpatterson1549/selene-bananas/db/sql/postgres"
+	"github.com/jacobpatterson1549/selene-bananas/db/state"
 	"github.com/jacobpatterson1549/selene-bananas/db/user"
+	"github.com/jacobpatterson1549/selene-bananas/game"
+	"github.com/jacobpatterson1549/selene-bananas/game/board"
 	"github.com/jacobpatterson1549/selene-bananas/game/player"
 	"github.com/jacobpatterson1549/selene-bananas/game/tile"
 	"github.com/jacobpatterson1549/selene-bananas/game/word"
 	"github.com/jacobpatterson1549/selene-bananas/server"
 	"github.com/jacobpatterson1549/selene-bananas/server/auth"
 	gameController "github.com/jacobpatterson1549/selene-bananas/server/game"
+	"github.com/jacobpatterson1549/selene-bananas/server/game/bot"
 	"github.com/jacobpatterson1549/selene-bananas/server/game/lobby"
 	playerController "github.com/jacobpatterson1549/selene-bananas/server/game/player"
 	"github.com/jacobpatterson1549/selene-bananas/server/game/socket"
@@ -90,6 +95,46 @@ func (f Flags) createFirestoreUserBackend(ctx context.Context, cfg db.Config, pr
 	return ub, nil
 }
 
+// CreateStateBackend creates the backend to save the states of games to, using the same database as the user backend.
+// If the user backend has no database, game states are saved as files in the GameStateDir or kept in memory if it is empty.
+func (f Flags) CreateStateBackend(ub user.Backend) state.Backend {
+	switch ub := ub.(type) {
+	case *postgres.UserBackend:
+		sb := postgres.StateBackend{
+			Database: ub.Database,
+		}
+		return &sb
+	case *mongo.UserBackend:
+		return mongo.NewStateBackend(ub)
+	case *firestore.UserBackend:
+		return firestore.NewStateBackend(ub)
+	}
+	if len(f.GameStateDir) != 0 {
+		sb := state.FileBackend{
+			Dir: f.GameStateDir,
+		}
+		return sb
+	}
+	return new(state.MemoryBackend)
+}
+
+// CreateResultBackend creates the backend to record the results of finished games to, using the same database as the user backend.
+// If the user backend has no database, game results are kept in memory.
+func (f Flags) CreateResultBackend(ub user.Backend) result.Backend {
+	switch ub := ub.(type) {
+	case *postgres.UserBackend:
+		rb := postgres.ResultBackend{
+			Database: ub.Database,
+		}
+		return &rb
+	case *mongo.UserBackend:
+		return mongo.NewResultBackend(ub)
+	case *firestore.UserBackend:
+		return firestore.NewResultBackend(ub)
+	}
+	return new(result.MemoryBackend)
+}
+
 // CreateSQLDatabase creates and sets up a SQL database.
 func (f Flags) CreateSQLDatabase(ctx context.Context, cfg db.Config, driverName string, e EmbeddedData) (*sql.Database, error) {
 	sqlDB, err := database_sql.Open(driverName, f.DatabaseURL)
@@ -110,8 +155,34 @@ func (f Flags) CreateSQLDatabase(ctx context.Context, cfg db.Config, driverName
 	return &database, nil
 }
 
+// wordValidators creates a word validator for each language games can be played in from the word files in the file system.
+func wordValidators(fsys fs.FS) (map[game.Language]gameController.WordValidator, error) {
+	validators, err := word.NewValidators(fsys)
+	if err != nil {
+		return nil, err
+	}
+	wordValidators := make(map[game.Language]gameController.WordValidator, len(game.Languages))
+	for _, l := range game.Languages {
+		v, ok := validators[string(l)]
+		if !ok {
+			return nil, fmt.Errorf("no words for %v language", l)
+		}
+		wordValidators[l] = v
+	}
+	return wordValidators, nil
+}
+
+// botDictionaries gets the word validators that bots can use to find words to play.
+func botDictionaries(wordValidators map[game.Language]gameController.WordValidator) map[game.Language]board.Dictionary {
+	dictionaries := make(map[game.Language]board.Dictionary, len(wordValidators))
+	for l, v := range wordValidators {
+		dictionaries[l] = v
+	}
+	return dictionaries
+}
+
 // CreateServer creates the server.
-func (f Flags) CreateServer(ctx context.Context, log log.Logger, ub user.Backend, e EmbeddedData) (*server.Server, error) {
+func (f Flags) CreateServer(ctx context.Context, log log.Logger, ub user.Backend, sb state.Backend, rb result.Backend, e EmbeddedData) (*server.Server, error) {
 	timeFunc := func() int64 {
 		return time.Now().Unix()
 	}
@@ -132,18 +203,25 @@ func (f Flags) CreateServer(ctx context.Context, log log.Logger, ub user.Backend
 	if err != nil {
 		return nil, fmt.Errorf("creating user dao: %w", err)
 	}
-	socketRunnerCfg := f.socketRunnerConfig(timeFunc)
-	socketRunner, err := socketRunnerCfg.NewRunner(log)
+	stateDao, err := state.NewDao(sb)
 	if err != nil {
-		return nil, fmt.Errorf("creating socket runner: %w", err)
+		return nil, fmt.Errorf("creating game state dao: %w", err)
+	}
+	resultDao, err := result.NewDao(rb)
+	if err != nil {
+		return nil, fmt.Errorf("creating game result dao: %w", err)
 	}
-	wordsReader := bytes.NewReader(e.Words)
-	wordValidator, err := word.NewValidator(wordsReader)
+	wordValidators, err := wordValidators(e.WordsFS)
 	if err != nil {
-		return nil, fmt.Errorf("creating word validator: %v", err)
+		return nil, fmt.Errorf("creating word validators: %v", err)
+	}
+	socketRunnerCfg := f.socketRunnerConfig(timeFunc, botDictionaries(wordValidators))
+	socketRunner, err := socketRunnerCfg.NewRunner(log)
+	if err != nil {
+		return nil, fmt.Errorf("creating socket runner: %w", err)
 	}
 	gameRunnerCfg := f.gameRunnerConfig(timeFunc)
-	gameRunner, err := gameRunnerCfg.NewRunner(log, wordValidator, userDao)
+	gameRunner, err := gameRunnerCfg.NewRunner(log, wordValidators, userDao, stateDao, resultDao)
 	if err != nil {
 		return nil, fmt.Errorf("creating game runner: %w", err)
 	}
@@ -162,21 +240,23 @@ func (f Flags) CreateServer(ctx context.Context, log log.Logger, ub user.Backend
 	}
 	colorCfg := f.colorConfig()
 	cfg := server.Config{
-		HTTPPort:      f.HTTPPort,
-		HTTPSPort:     f.HTTPSPort,
-		StopDur:       20 * time.Second, // should be longer than the PingPeriod of sockets so they can close gracefully
-		CacheSec:      f.CacheSec,
-		Version:       strings.TrimSpace(string(e.Version)),
-		TLSCertPEM:    string(e.TLSCertPEM),
-		TLSKeyPEM:     string(e.TLSKeyPEM),
-		Challenge:     challenge,
-		ColorConfig:   colorCfg,
-		NoTLSRedirect: f.NoTLSRedirect,
+		HTTPPort:            f.HTTPPort,
+		HTTPSPort:           f.HTTPSPort,
+		StopDur:             20 * time.Second, // should be longer than the PingPeriod of sockets so they can close gracefully
+		CacheSec:            f.CacheSec,
+		Version:             strings.TrimSpace(string(e.Version)),
+		TLSCertPEM:          string(e.TLSCertPEM),
+		TLSKeyPEM:           string(e.TLSKeyPEM),
+		Challenge:           challenge,
+		ColorConfig:         colorCfg,
+		NoTLSRedirect:       f.NoTLSRedirect,
+		LeaderboardCacheSec: f.LeaderboardSec,
 	}
 	p := server.Parameters{
 		Logger:         log,
 		Tokenizer:      tokenizer,
 		UserDao:        userDao,
+		ResultDao:      resultDao,
 		Lobby:          lobby,
 		StaticFS:       e.StaticFS,
 		TemplateFS:     e.TemplateFS,
@@ -263,14 +343,17 @@ func (f Flags) gameConfig(timeFunc func() int64) gameController.Config {
 		NumNewTiles:            21,
 		TileLetters:            "", // 144 default tiles = 144-6*21 = 18 tiles left, which leaves a maximum of 3 snags
 		IdlePeriod:             60 * time.Minute,
+		SpectateDelay:          time.Duration(f.SpectateDelaySec) * time.Second,
+		ClockPeriod:            1 * time.Second,
+		SaveDelay:              5 * time.Second,
 		ShuffleUnusedTilesFunc: shuffleUnusedTilesFunc,
 		ShufflePlayersFunc:     shufflePlayersFunc,
 	}
 	return cfg
 }
 
-// socketRunnerConfig creates the configuration for creating new sockets (each tab that is connected to the lobby).
-func (f Flags) socketRunnerConfig(timeFunc func() int64) socket.RunnerConfig {
+// socketRunnerConfig creates the configuration for creating new sockets (each tab that is connected to the lobby) and bots.
+func (f Flags) socketRunnerConfig(timeFunc func() int64, dictionaries map[game.Language]board.Dictionary) socket.RunnerConfig {
 	socketCfg := socket.Config{
 		Debug:          f.DebugGame,
 		TimeFunc:       timeFunc,
@@ -279,11 +362,35 @@ func (f Flags) socketRunnerConfig(timeFunc func() int64) socket.RunnerConfig {
 		PingPeriod:     15 * time.Second,
 		HTTPPingPeriod: 10 * time.Minute,
 	}
+	botCfg := bot.Config{
+		Debug:        f.DebugGame,
+		Dictionaries: dictionaries,
+		Skills: map[player.Difficulty]bot.Skill{
+			player.Easy: {
+				ThinkTime:      8 * time.Second,
+				VocabularySize: 2000,
+			},
+			player.Medium: {
+				ThinkTime:      4 * time.Second,
+				VocabularySize: 10000,
+			},
+			player.Hard: {
+				ThinkTime:      2 * time.Second,
+				VocabularySize: 100000,
+			},
+		},
+		BoardConfig: board.Config{
+			NumRows: 20,
+			NumCols: 20,
+		},
+	}
 	cfg := socket.RunnerConfig{
 		Debug:            f.DebugGame,
 		MaxSockets:       32,
 		MaxPlayerSockets: 5,
+		ReconnectPeriod:  1 * time.Minute,
 		SocketConfig:     socketCfg,
+		BotConfig:        botCfg,
 	}
 	return cfg
 }
@@ -3,12 +3,17 @@ package main
 import (
 	"context"
 	"database/sql"
+	"reflect"
 	"testing"
 	"testing/fstest"
 	"time"
 
 	"github.com/jacobpatterson1549/selene-bananas/db"
+	"github.com/jacobpatterson1549/selene-bananas/db/result"
+	"github.com/jacobpatterson1549/selene-bananas/db/sql/postgres"
+	"github.com/jacobpatterson1549/selene-bananas/db/state"
 	"github.com/jacobpatterson1549/selene-bananas/db/user"
+	"github.com/jacobpatterson1549/selene-bananas/game"
 	"github.com/jacobpatterson1549/selene-bananas/server/log/logtest"
 )
 
@@ -47,6 +52,57 @@ func TestCreateUserBackend(t *testing.T) {
 	}
 }
 
+func TestCreateStateBackend(t *testing.T) {
+	t.Run("postgres", func(t *testing.T) {
+		var f Flags
+		ub := new(postgres.UserBackend)
+		sb := f.CreateStateBackend(ub)
+		if _, ok := sb.(*postgres.StateBackend); !ok {
+			t.Errorf("wanted *postgres.StateBackend, got %T", sb)
+		}
+	})
+	t.Run("no database, no directory", func(t *testing.T) {
+		var f Flags
+		var ub user.NoDatabaseBackend
+		sb := f.CreateStateBackend(ub)
+		if _, ok := sb.(*state.MemoryBackend); !ok {
+			t.Errorf("wanted *state.MemoryBackend, got %T", sb)
+		}
+	})
+	t.Run("no database, directory", func(t *testing.T) {
+		f := Flags{
+			GameStateDir: "games",
+		}
+		var ub user.NoDatabaseBackend
+		sb := f.CreateStateBackend(ub)
+		want := state.FileBackend{
+			Dir: "games",
+		}
+		if want != sb {
+			t.Errorf("not equal:\nwanted: %v\ngot:    %v", want, sb)
+		}
+	})
+}
+
+func TestCreateResultBackend(t *testing.T) {
+	t.Run("postgres", func(t *testing.T) {
+		var f Flags
+		ub := new(postgres.UserBackend)
+		rb := f.CreateResultBackend(ub)
+		if _, ok := rb.(*postgres.ResultBackend); !ok {
+			t.Errorf("wanted *postgres.ResultBackend, got %T", rb)
+		}
+	})
+	t.Run("no database", func(t *testing.T) {
+		var f Flags
+		var ub user.NoDatabaseBackend
+		rb := f.CreateResultBackend(ub)
+		if _, ok := rb.(*result.MemoryBackend); !ok {
+			t.Errorf("wanted *result.MemoryBackend, got %T", rb)
+		}
+	})
+}
+
 // TestCreateSQLDatabase only checks the happy path, making sure defaults defined in config.go are valid.
 func TestCreateSQLDatabase(t *testing.T) {
 	var f Flags
@@ -61,6 +117,14 @@ func TestCreateSQLDatabase(t *testing.T) {
 			"users.sql":                        &fstest.MapFile{Data: []byte("1")},
 			"user_update_password.sql":         &fstest.MapFile{Data: []byte("4")},
 			"user_update_points_increment.sql": &fstest.MapFile{Data: []byte("5")},
+			"game_states.sql":                  &fstest.MapFile{Data: []byte("7")},
+			"game_state_save.sql":              &fstest.MapFile{Data: []byte("8")},
+			"game_state_read_all.sql":          &fstest.MapFile{Data: []byte("9")},
+			"game_state_delete.sql":            &fstest.MapFile{Data: []byte("10")},
+			"game_results.sql":                 &fstest.MapFile{Data: []byte("11")},
+			"game_result_create.sql":           &fstest.MapFile{Data: []byte("12")},
+			"game_result_read_all.sql":         &fstest.MapFile{Data: []byte("13")},
+			"user_read_top.sql":                &fstest.MapFile{Data: []byte("14")},
 		},
 	}
 	ctx := context.Background()
@@ -81,16 +145,22 @@ func TestCreateServer(t *testing.T) {
 	ctx := context.Background()
 	log := logtest.DiscardLogger
 	var ub mockUserBackend
+	sb := new(state.MemoryBackend)
+	rb := new(result.MemoryBackend)
 	wantVersion := "9d2ffad8e5e5383569d37ec381147f2d"
 	staticFS := new(fstest.MapFS)
 	dummyFile := new(fstest.MapFile)
 	e := EmbeddedData{
-		Version:    []byte(wantVersion + "\n"),
-		Words:      []byte("apple\nbanana\ncarrot"),
+		Version: []byte(wantVersion + "\n"),
+		WordsFS: fstest.MapFS{
+			"en.txt": &fstest.MapFile{Data: []byte("apple\nbanana\ncarrot")},
+			"es.txt": &fstest.MapFile{Data: []byte("manzana\nplátano\nzanahoria")},
+			"de.txt": &fstest.MapFile{Data: []byte("apfel\nbanane\nmöhre")},
+		},
 		StaticFS:   staticFS,
 		TemplateFS: fstest.MapFS{"file": dummyFile},
 	}
-	s, err := f.CreateServer(ctx, log, ub, e)
+	s, err := f.CreateServer(ctx, log, ub, sb, rb, e)
 	switch {
 	case err != nil:
 		t.Errorf("unwanted error: %v", err)
@@ -101,6 +171,64 @@ func TestCreateServer(t *testing.T) {
 	}
 }
 
+func TestWordValidators(t *testing.T) {
+	wordValidatorsTests := []struct {
+		fsys   fstest.MapFS
+		wantOk bool
+	}{
+		{ // no words
+			fsys: fstest.MapFS{},
+		},
+		{ // missing languages
+			fsys: fstest.MapFS{
+				"en.txt": &fstest.MapFile{Data: []byte("apple")},
+			},
+		},
+		{
+			fsys: fstest.MapFS{
+				"en.txt": &fstest.MapFile{Data: []byte("apple")},
+				"es.txt": &fstest.MapFile{Data: []byte("año")},
+				"de.txt": &fstest.MapFile{Data: []byte("über")},
+			},
+			wantOk: true,
+		},
+	}
+	for i, test := range wordValidatorsTests {
+		got, err := wordValidators(test.fsys)
+		switch {
+		case !test.wantOk:
+			if err == nil {
+				t.Errorf("Test %v: wanted error", i)
+			}
+		case err != nil:
+			t.Errorf("Test %v: unwanted error: %v", i, err)
+		case len(game.Languages) != len(got):
+			t.Errorf("Test %v: wanted a word validator for each language, got %v", i, got)
+		case !got[game.Spanish].Validate("AÑO"):
+			t.Errorf("Test %v: wanted Spanish words to be validated", i)
+		}
+	}
+}
+
+func TestBotDictionaries(t *testing.T) {
+	fsys := fstest.MapFS{
+		"en.txt": &fstest.MapFile{Data: []byte("apple")},
+		"es.txt": &fstest.MapFile{Data: []byte("año")},
+		"de.txt": &fstest.MapFile{Data: []byte("über")},
+	}
+	wordValidators, err := wordValidators(fsys)
+	if err != nil {
+		t.Fatalf("unwanted error: %v", err)
+	}
+	got := botDictionaries(wordValidators)
+	switch {
+	case len(game.Languages) != len(got):
+		t.Errorf("wanted a bot dictionary for each language, got %v", got)
+	case !reflect.DeepEqual([]string{"über"}, got[game.German].Words()):
+		t.Errorf("wanted German words for bots, got %v", got[game.German].Words())
+	}
+}
+
 func TestGameConfig(t *testing.T) {
 	tests := []bool{
 		true,
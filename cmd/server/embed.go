@@ -16,7 +16,7 @@ var EmbeddedFS embed.FS
 // EmbeddedData is used to retrieve files embedded in the server.
 type EmbeddedData struct {
 	Version    []byte
-	Words      []byte
+	WordsFS    fs.FS
 	TLSCertPEM []byte
 	TLSKeyPEM  []byte
 	StaticFS   fs.FS
@@ -25,7 +25,7 @@ type EmbeddedData struct {
 }
 
 // UnembedFS validates, unembeds, and returns the files from the "embed" directory of the file system.
-// Version and words are required, file systems are unembedded
+// Version is required, file systems are unembedded
 func UnembedFS(fsys fs.FS) (*EmbeddedData, error) {
 	unembedSubdirectory := func(fsys fs.FS, subdirectory string) (fs.FS, error) {
 		if _, err := fsys.Open(subdirectory); err != nil {
@@ -41,9 +41,9 @@ func UnembedFS(fsys fs.FS) (*EmbeddedData, error) {
 	if err != nil {
 		return nil, fmt.Errorf("unembedding version: %w", err)
 	}
-	embeddedWords, err := fs.ReadFile(embedFS, "words.txt")
+	wordsFS, err := unembedSubdirectory(embedFS, "words")
 	if err != nil {
-		return nil, fmt.Errorf("unembedding words file: %w", err)
+		return nil, fmt.Errorf("unembedding words file system: %w", err)
 	}
 	tlsCertPEM, err := fs.ReadFile(embedFS, "tls-cert.pem")
 	if err != nil {
@@ -67,7 +67,7 @@ func UnembedFS(fsys fs.FS) (*EmbeddedData, error) {
 	}
 	e := EmbeddedData{
 		Version:    version,
-		Words:      embeddedWords,
+		WordsFS:    wordsFS,
 		TLSCertPEM: tlsCertPEM,
 		TLSKeyPEM:  tlsKeyPEM,
 		StaticFS:   staticFS,
@@ -77,7 +77,7 @@ func UnembedFS(fsys fs.FS) (*EmbeddedData, error) {
 	return &e, nil
 }
 
-// sqlFiles opens the SQL files needed to manage user data.
+// sqlFiles opens the SQL files needed to manage user data, game states, and game results.
 func (e EmbeddedData) sqlFiles() ([]io.Reader, error) {
 	sqlFileNames := []string{
 		"users",
@@ -86,6 +86,14 @@ func (e EmbeddedData) sqlFiles() ([]io.Reader, error) {
 		"user_update_password",
 		"user_update_points_increment",
 		"user_delete",
+		"game_states",
+		"game_state_save",
+		"game_state_read_all",
+		"game_state_delete",
+		"game_results",
+		"game_result_create",
+		"game_result_read_all",
+		"user_read_top",
 	}
 	userSQLFiles := make([]io.Reader, len(sqlFileNames))
 	for i, n := range sqlFileNames {
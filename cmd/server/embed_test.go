@@ -10,7 +10,6 @@ import (
 
 func TestUnembedFS(t *testing.T) {
 	version := []byte("v")
-	words := []byte("a\nb\nc")
 	tlsCert := []byte("C")
 	tlsKey := []byte("K")
 	unembedOrFail := func(fsys fs.FS, subdirectory string) fs.FS {
@@ -27,7 +26,7 @@ func TestUnembedFS(t *testing.T) {
 	const (
 		// useful constants for ensuring tests test similar areas
 		embedVersion    = "embed/version.txt"
-		embedWords      = "embed/words.txt"
+		embedWordsFS    = "embed/words"
 		embedTLSCertPEM = "embed/tls-cert.pem"
 		embedTLSKeyPEM  = "embed/tls-key.pem"
 		embedStaticFS   = "embed/static"
@@ -55,20 +54,20 @@ func TestUnembedFS(t *testing.T) {
 		{ // no tls cert
 			FS: fstest.MapFS{
 				embedVersion: &fstest.MapFile{Data: version},
-				embedWords:   &fstest.MapFile{Data: words},
+				embedWordsFS: &fstest.MapFile{},
 			},
 		},
 		{ // no tls key
 			FS: fstest.MapFS{
 				embedVersion:    &fstest.MapFile{Data: version},
-				embedWords:      &fstest.MapFile{Data: words},
+				embedWordsFS:    &fstest.MapFile{},
 				embedTLSCertPEM: &fstest.MapFile{Data: tlsCert},
 			},
 		},
 		{ // no static fs
 			FS: fstest.MapFS{
 				embedVersion:    &fstest.MapFile{Data: version},
-				embedWords:      &fstest.MapFile{Data: words},
+				embedWordsFS:    &fstest.MapFile{},
 				embedTLSCertPEM: &fstest.MapFile{Data: tlsCert},
 				embedTLSKeyPEM:  &fstest.MapFile{Data: tlsKey},
 			},
@@ -76,7 +75,7 @@ func TestUnembedFS(t *testing.T) {
 		{ // no template fs
 			FS: fstest.MapFS{
 				embedVersion:    &fstest.MapFile{Data: version},
-				embedWords:      &fstest.MapFile{Data: words},
+				embedWordsFS:    &fstest.MapFile{},
 				embedTLSCertPEM: &fstest.MapFile{Data: tlsCert},
 				embedTLSKeyPEM:  &fstest.MapFile{Data: tlsKey},
 				embedStaticFS:   &fstest.MapFile{},
@@ -85,7 +84,7 @@ func TestUnembedFS(t *testing.T) {
 		{ // no SQL fs
 			FS: fstest.MapFS{
 				embedVersion:    &fstest.MapFile{Data: version},
-				embedWords:      &fstest.MapFile{Data: words},
+				embedWordsFS:    &fstest.MapFile{},
 				embedTLSCertPEM: &fstest.MapFile{Data: tlsCert},
 				embedTLSKeyPEM:  &fstest.MapFile{Data: tlsKey},
 				embedStaticFS:   &fstest.MapFile{},
@@ -95,7 +94,7 @@ func TestUnembedFS(t *testing.T) {
 		{ // happy path
 			FS: fstest.MapFS{
 				"embed/version.txt":  &fstest.MapFile{Data: version},
-				"embed/words.txt":    &fstest.MapFile{Data: words},
+				"embed/words":        &fstest.MapFile{},
 				"embed/tls-cert.pem": &fstest.MapFile{Data: tlsCert},
 				"embed/tls-key.pem":  &fstest.MapFile{Data: tlsKey},
 				"embed/static":       &fstest.MapFile{},
@@ -105,7 +104,6 @@ func TestUnembedFS(t *testing.T) {
 			wantOk: true,
 			want: &EmbeddedData{
 				Version:    version,
-				Words:      words,
 				TLSCertPEM: tlsCert,
 				TLSKeyPEM:  tlsKey,
 			},
@@ -122,6 +120,7 @@ func TestUnembedFS(t *testing.T) {
 			t.Errorf("Test %v: unwanted error: %v", i, err)
 		default:
 			// create the embedded file systems from the parent embedded file system for deep equal to work
+			test.want.WordsFS = unembedOrFail(test.FS, "words")
 			test.want.StaticFS = unembedOrFail(test.FS, "static")
 			test.want.TemplateFS = unembedOrFail(test.FS, "template")
 			test.want.SQLFS = unembedOrFail(test.FS, "sql")
@@ -153,10 +152,18 @@ func TestSQLFiles(t *testing.T) {
 				"users.sql":                        &fstest.MapFile{Data: []byte("1")},
 				"user_update_password.sql":         &fstest.MapFile{Data: []byte("4")},
 				"user_update_points_increment.sql": &fstest.MapFile{Data: []byte("5")},
+				"game_states.sql":                  &fstest.MapFile{Data: []byte("7")},
+				"game_state_save.sql":              &fstest.MapFile{Data: []byte("8")},
+				"game_state_read_all.sql":          &fstest.MapFile{Data: []byte("9")},
+				"game_state_delete.sql":            &fstest.MapFile{Data: []byte("10")},
+				"game_results.sql":                 &fstest.MapFile{Data: []byte("11")},
+				"game_result_create.sql":           &fstest.MapFile{Data: []byte("12")},
+				"game_result_read_all.sql":         &fstest.MapFile{Data: []byte("13")},
+				"user_read_top.sql":                &fstest.MapFile{Data: []byte("14")},
 			},
 		}
 		gotFiles, err := e.sqlFiles()
-		wantFileData := []string{"1", "2", "3", "4", "5", "6"}
+		wantFileData := []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12", "13", "14"}
 		switch {
 		case err != nil:
 			t.Errorf("unwanted error: %v", err)
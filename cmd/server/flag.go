@@ -21,6 +21,9 @@ const (
 	environmentVariableGCCliID           = "GOOGLE_CLIENT_ID"
 	environmentVariableGCCliSecret       = "GOOGLE_CLIENT_SECRET"
 	environmentVariableOauth2RedirectURL = "OAUTH2_REDIRECT_URL"
+	environmentVariableGameStateDir      = "GAME_STATE_DIR"
+	environmentVariableSpectateDelaySec  = "SPECTATE_DELAY_SEC"
+	environmentVariableLeaderboardSec    = "LEADERBOARD_CACHE_SEC"
 )
 
 // Flags are the configuration options which can be easily configured at run startup for different environments.
@@ -37,11 +40,15 @@ type Flags struct {
 	GCCliID           string
 	GCCliSecret       string
 	Oauth2RedirectURL string
+	GameStateDir      string
+	SpectateDelaySec  int
+	LeaderboardSec    int
 }
 
 const (
-	defaultCacheSec     = 60 * 60 * 24 // 1 day
-	defaultDBTimeoutSec = 5
+	defaultCacheSec       = 60 * 60 * 24 // 1 day
+	defaultDBTimeoutSec   = 5
+	defaultLeaderboardSec = 60
 )
 
 // usage prints how to run the server to the flagset's output.
@@ -59,6 +66,9 @@ func usage(fs *flag.FlagSet) {
 		environmentVariableGCCliID,
 		environmentVariableGCCliSecret,
 		environmentVariableOauth2RedirectURL,
+		environmentVariableGameStateDir,
+		environmentVariableSpectateDelaySec,
+		environmentVariableLeaderboardSec,
 	}
 	fmt.Fprintf(fs.Output(), "Runs the server\n")
 	fmt.Fprintf(fs.Output(), "Reads environment variables when possible: [%s]\n", strings.Join(envVars, ","))
@@ -104,6 +114,9 @@ func (f *Flags) newFlagSet(osLookupEnvFunc func(string) (string, bool), portOver
 	fs.StringVar(&f.GCCliID, "google-client-id", envValue(environmentVariableGCCliID), "The ClientID for Google Oath2 user logins.")
 	fs.StringVar(&f.GCCliSecret, "google-client-secret", envValue(environmentVariableGCCliSecret), "The password for the Google Oath2 user logins.")
 	fs.StringVar(&f.Oauth2RedirectURL, "oauth2-redirect-url", envValue(environmentVariableOauth2RedirectURL), "The Scheme and host to redirect Oauth2 requests back to locally.  Should have a scheme and host")
+	fs.StringVar(&f.GameStateDir, "game-state-dir", envValue(environmentVariableGameStateDir), "The directory to save in-progress games to when no database is used.  Games are only kept in memory if not specified.")
+	fs.IntVar(&f.SpectateDelaySec, "spectate-delay-sec", envValueInt(environmentVariableSpectateDelaySec, 0), "The number of seconds the boards shown to spectators lag behind the boards of the players.  Spectators see moves as they happen if not positive.")
+	fs.IntVar(&f.LeaderboardSec, "leaderboard-cache-sec", envValueInt(environmentVariableLeaderboardSec, defaultLeaderboardSec), "The number of seconds the leaderboard is cached before the top users are read again.")
 	return fs
 }
 
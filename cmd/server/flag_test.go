@@ -16,8 +16,9 @@ func TestNewFlags(t *testing.T) {
 	}{
 		{ // defaults
 			want: &Flags{
-				CacheSec:     defaultCacheSec,
-				DBTimeoutSec: defaultDBTimeoutSec,
+				CacheSec:       defaultCacheSec,
+				DBTimeoutSec:   defaultDBTimeoutSec,
+				LeaderboardSec: defaultLeaderboardSec,
 			},
 		},
 		{ // all command line
@@ -32,41 +33,53 @@ func TestNewFlags(t *testing.T) {
 				"-acme-challenge-key=8",
 				"-no-tls-redirect",
 				"-db-timeout-sec=30",
+				"-game-state-dir=10",
+				"-spectate-delay-sec=11",
+				"-leaderboard-cache-sec=12",
 			},
 			want: &Flags{
-				HTTPPort:       1,
-				HTTPSPort:      2,
-				DatabaseURL:    "3",
-				DebugGame:      true,
-				CacheSec:       6,
-				ChallengeToken: "7",
-				ChallengeKey:   "8",
-				NoTLSRedirect:  true,
-				DBTimeoutSec:   30,
+				HTTPPort:         1,
+				HTTPSPort:        2,
+				DatabaseURL:      "3",
+				DebugGame:        true,
+				CacheSec:         6,
+				ChallengeToken:   "7",
+				ChallengeKey:     "8",
+				NoTLSRedirect:    true,
+				DBTimeoutSec:     30,
+				GameStateDir:     "10",
+				SpectateDelaySec: 11,
+				LeaderboardSec:   12,
 			},
 		},
 		{ // all environment variables
 			envVars: map[string]string{
-				"HTTP_PORT":            "1",
-				"HTTPS_PORT":           "2",
-				"DATABASE_URL":         "3",
-				"DEBUG_MESSAGES":       "",
-				"CACHE_SECONDS":        "6",
-				"ACME_CHALLENGE_TOKEN": "7",
-				"ACME_CHALLENGE_KEY":   "8",
-				"NO_TLS_REDIRECT":      "",
-				"DB_TIMEOUT_SEC":       "9",
+				"HTTP_PORT":             "1",
+				"HTTPS_PORT":            "2",
+				"DATABASE_URL":          "3",
+				"DEBUG_MESSAGES":        "",
+				"CACHE_SECONDS":         "6",
+				"ACME_CHALLENGE_TOKEN":  "7",
+				"ACME_CHALLENGE_KEY":    "8",
+				"NO_TLS_REDIRECT":       "",
+				"DB_TIMEOUT_SEC":        "9",
+				"GAME_STATE_DIR":        "10",
+				"SPECTATE_DELAY_SEC":    "11",
+				"LEADERBOARD_CACHE_SEC": "12",
 			},
 			want: &Flags{
-				HTTPPort:       1,
-				HTTPSPort:      2,
-				DatabaseURL:    "3",
-				DebugGame:      true,
-				CacheSec:       6,
-				ChallengeToken: "7",
-				ChallengeKey:   "8",
-				NoTLSRedirect:  true,
-				DBTimeoutSec:   9,
+				HTTPPort:         1,
+				HTTPSPort:        2,
+				DatabaseURL:      "3",
+				DebugGame:        true,
+				CacheSec:         6,
+				ChallengeToken:   "7",
+				ChallengeKey:     "8",
+				NoTLSRedirect:    true,
+				DBTimeoutSec:     9,
+				GameStateDir:     "10",
+				SpectateDelaySec: 11,
+				LeaderboardSec:   12,
 			},
 		},
 	}
@@ -84,11 +97,12 @@ func TestNewFlags(t *testing.T) {
 
 func TestNewFlagsPortOverride(t *testing.T) {
 	envVars := map[string]string{
-		"HTTP_PORT":      "1",
-		"HTTPS_PORT":     "2",
-		"PORT":           "3",
-		"CACHE_SECONDS":  "0", // override default value
-		"DB_TIMEOUT_SEC": "0", // override default value
+		"HTTP_PORT":             "1",
+		"HTTPS_PORT":            "2",
+		"PORT":                  "3",
+		"CACHE_SECONDS":         "0", // override default value
+		"DB_TIMEOUT_SEC":        "0", // override default value
+		"LEADERBOARD_CACHE_SEC": "0", // override default value
 	}
 	osLookupEnvFunc := func(key string) (string, bool) {
 		v, ok := envVars[key]
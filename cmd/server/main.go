@@ -34,7 +34,9 @@ func runServer(ctx context.Context, log *log.Logger) error {
 	if err != nil {
 		return fmt.Errorf("creating database: %v", err)
 	}
-	server, err := f.CreateServer(ctx, log, ub, *e)
+	sb := f.CreateStateBackend(ub)
+	rb := f.CreateResultBackend(ub)
+	server, err := f.CreateServer(ctx, log, ub, sb, rb, *e)
 	if err != nil {
 		return fmt.Errorf("creating server: %v", err)
 	}
@@ -2,7 +2,6 @@
 package main_test
 
 import (
-	"bytes"
 	"context"
 	"database/sql"
 	"errors"
@@ -13,6 +12,8 @@ import (
 
 	main "github.com/jacobpatterson1549/selene-bananas/cmd/server"
 	"github.com/jacobpatterson1549/selene-bananas/db"
+	"github.com/jacobpatterson1549/selene-bananas/db/result"
+	"github.com/jacobpatterson1549/selene-bananas/db/state"
 	"github.com/jacobpatterson1549/selene-bananas/db/user"
 	"github.com/jacobpatterson1549/selene-bananas/game/word"
 	"github.com/jacobpatterson1549/selene-bananas/server/log/logtest"
@@ -30,17 +31,20 @@ func embeddedData(t *testing.T) main.EmbeddedData {
 	return *e
 }
 
-// TestNewWordValidator loads the embedded words, which should be the dump of the aspell en_US dictionary, Debian: aspell-en2018.04.16-0-1,Alpine: aspell-en=2020.12.07-r0
+// TestNewWordValidator loads the embedded English words, which should be the dump of the aspell en_US dictionary, Debian: aspell-en2018.04.16-0-1,Alpine: aspell-en=2020.12.07-r0
 func TestNewWordValidator(t *testing.T) {
 	t.Skip("test is flaky")
 	e := embeddedData(t)
-	r := bytes.NewReader(e.Words)
-	validator, err := word.NewValidator(r)
+	validators, err := word.NewValidators(e.WordsFS)
 	if err != nil {
-		t.Fatalf("unwanted new word validate error: %v", err)
+		t.Fatalf("unwanted new word validators error: %v", err)
+	}
+	validator, ok := validators["en"]
+	if !ok {
+		t.Fatalf("no English word validator")
 	}
 	want := 77976
-	got := len(*validator)
+	got := validator.Len()
 	if want != got {
 		note := "NOTE: this might be flaky, but it ensures that a large number of words can be loaded."
 		t.Errorf("wanted %v words, got %v\n%v", want, got, note)
@@ -53,11 +57,13 @@ func TestServerGetFiles(t *testing.T) {
 	ctx := context.Background()
 	log := logtest.DiscardLogger
 	var ub mockUserBackend
+	sb := new(state.MemoryBackend)
+	rb := new(result.MemoryBackend)
 	e := embeddedData(t)
 	f := main.Flags{
 		HTTPSPort: 8000, // not actually used, overridden by httptest
 	}
-	s, err := f.CreateServer(ctx, log, ub, e)
+	s, err := f.CreateServer(ctx, log, ub, sb, rb, e)
 	if err != nil {
 		t.Fatalf("unwanted create server error: %v", err)
 	}
@@ -124,3 +130,7 @@ func (m mockUserBackend) UpdatePointsIncrement(ctx context.Context, userPoints m
 func (m mockUserBackend) Delete(ctx context.Context, u user.User) error {
 	return errors.New("not implemented")
 }
+
+func (m mockUserBackend) ReadTopUsers(ctx context.Context, limit, offset int) ([]user.User, error) {
+	return nil, errors.New("not implemented")
+}
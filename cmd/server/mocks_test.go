@@ -31,6 +31,10 @@ func (m mockUserBackend) Delete(ctx context.Context, u user.User) error {
 	return errors.New("not implemented")
 }
 
+func (m mockUserBackend) ReadTopUsers(ctx context.Context, limit, offset int) ([]user.User, error) {
+	return nil, errors.New("not implemented")
+}
+
 // TestNoopDriver creates connections that have noop statements and transactions.
 var TestNoopDriver driver.Driver = &mockDriver{
 	OpenFunc: func(name string) (driver.Conn, error) {
@@ -66,7 +66,7 @@ func (f flags) createDomInitializers() []domInitializer {
 	board := new(board.Board)
 	canvas := canvasCfg.New(f.dom, log, board, ".game>.canvas")
 	game := game.New(f.dom, log, board, canvas, canvasCreator)
-	lobby := lobby.New(f.dom, log, game)
+	lobby := lobby.New(f.dom, log, game, httpClient)
 	socket := socket.New(f.dom, log, user, game, lobby)
 	user.Socket = socket   // [circular reference]
 	canvas.Socket = socket // [circular reference]
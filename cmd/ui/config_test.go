@@ -20,6 +20,8 @@ func TestInitDom(t *testing.T) {
 		"addEventListener": js.FuncOf(func(this js.Value, args []js.Value) any { return global }),
 	}
 	global.Set("document", global)
+	global.Set("location", map[string]any{"search": ""})
+	global.Set("URLSearchParams", js.Global().Get("URLSearchParams"))
 	f := flags{
 		dom: ui.NewDOM(global),
 	}
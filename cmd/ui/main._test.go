@@ -12,6 +12,8 @@ func TestMain(t *testing.T) {
 	global = js.ValueOf(map[string]any{})
 	global.Set("document", global)
 	global.Set("color", "for-canvas")
+	global.Set("location", map[string]any{"search": ""})
+	global.Set("URLSearchParams", js.Global().Get("URLSearchParams"))
 	beforeUnloadRegistered := false
 	inputsQueried := false
 	mockInput := js.ValueOf(map[string]any{"disabled": true})
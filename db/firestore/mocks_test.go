@@ -0,0 +1,132 @@
+package firestore
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"cloud.google.com/go/firestore"
+	pb "cloud.google.com/go/firestore/apiv1/firestorepb"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// mockFirestoreServer stores documents in memory to test the backends without a database.
+// Only the rpcs used by the backends to write and query documents are handled.
+type mockFirestoreServer struct {
+	pb.UnimplementedFirestoreServer
+	mu   sync.Mutex
+	docs map[string]*pb.Document
+	// err is returned by each rpc if it is set.
+	err error
+}
+
+// Commit stores the updated documents and removes the deleted ones.
+func (s *mockFirestoreServer) Commit(ctx context.Context, req *pb.CommitRequest) (*pb.CommitResponse, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := timestamppb.Now()
+	res := pb.CommitResponse{
+		CommitTime: now,
+	}
+	for _, w := range req.Writes {
+		switch {
+		case w.GetUpdate() != nil:
+			doc := w.GetUpdate()
+			doc.CreateTime = now
+			doc.UpdateTime = now
+			s.docs[doc.Name] = doc
+		case len(w.GetDelete()) != 0:
+			delete(s.docs, w.GetDelete())
+		default:
+			return nil, status.Errorf(codes.Unimplemented, "unknown write: %v", w)
+		}
+		res.WriteResults = append(res.WriteResults, &pb.WriteResult{UpdateTime: now})
+	}
+	return &res, nil
+}
+
+// RunQuery sends the documents in the collection of the query, ordered by name.
+// Only queries without a filter or with a single array-contains filter are handled.
+func (s *mockFirestoreServer) RunQuery(req *pb.RunQueryRequest, stream pb.Firestore_RunQueryServer) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	query := req.GetStructuredQuery()
+	prefix := req.Parent + "/" + query.From[0].CollectionId + "/"
+	filter := query.GetWhere().GetFieldFilter()
+	if query.GetWhere() != nil && filter.GetOp() != pb.StructuredQuery_FieldFilter_ARRAY_CONTAINS {
+		return status.Errorf(codes.Unimplemented, "unknown filter: %v", query.GetWhere())
+	}
+	var names []string
+	for name, doc := range s.docs {
+		if id, ok := strings.CutPrefix(name, prefix); ok && !strings.Contains(id, "/") && arrayContains(doc, filter) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	now := timestamppb.Now()
+	for _, name := range names {
+		res := pb.RunQueryResponse{
+			Document: s.docs[name],
+			ReadTime: now,
+		}
+		if err := stream.Send(&res); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// arrayContains determines if the array field of the document has the value of the filter.
+// All documents match a nil filter.
+func arrayContains(doc *pb.Document, filter *pb.StructuredQuery_FieldFilter) bool {
+	if filter == nil {
+		return true
+	}
+	for _, v := range doc.Fields[filter.Field.FieldPath].GetArrayValue().GetValues() {
+		if proto.Equal(v, filter.Value) {
+			return true
+		}
+	}
+	return false
+}
+
+// mockFirestoreClient creates a client that connects to the server.
+func mockFirestoreClient(t *testing.T, s *mockFirestoreServer) *firestore.Client {
+	t.Helper()
+	s.docs = make(map[string]*pb.Document)
+	lis := bufconn.Listen(1 << 20)
+	gs := grpc.NewServer()
+	pb.RegisterFirestoreServer(gs, s)
+	go gs.Serve(lis)
+	t.Cleanup(gs.Stop)
+	dialer := func(ctx context.Context, addr string) (net.Conn, error) {
+		return lis.DialContext(ctx)
+	}
+	conn, err := grpc.NewClient("passthrough:///firestore", grpc.WithContextDialer(dialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("creating connection to mock firestore server: %v", err)
+	}
+	ctx := context.Background()
+	client, err := firestore.NewClient(ctx, "test-project", option.WithGRPCConn(conn))
+	if err != nil {
+		t.Fatalf("creating firestore client: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
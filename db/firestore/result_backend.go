@@ -0,0 +1,97 @@
+package firestore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"cloud.google.com/go/firestore"
+	"github.com/jacobpatterson1549/selene-bananas/db"
+	"github.com/jacobpatterson1549/selene-bananas/db/result"
+)
+
+const (
+	playersField    = "players"
+	finishedAtField = "finishedAt"
+	resultField     = "result"
+)
+
+// ResultBackend is a backend manager for a game results collection.
+type ResultBackend struct {
+	client *firestore.Client
+	db.Config
+}
+
+// NewResultBackend creates a backend manager for game results that shares the client of the user backend.
+func NewResultBackend(ub *UserBackend) *ResultBackend {
+	rb := ResultBackend{
+		client: ub.client,
+		Config: ub.Config,
+	}
+	return &rb
+}
+
+func (rb *ResultBackend) resultsCollection() *firestore.CollectionRef {
+	return rb.client.Collection("services").Doc("selene-bananas").Collection("game_results")
+}
+
+// withTimeoutContext configures the context to timeout when running the function.
+func (rb *ResultBackend) withTimeoutContext(ctx context.Context, f func(ctx context.Context) error) error {
+	ctx, cancelFunc := context.WithTimeout(ctx, rb.QueryPeriod)
+	defer cancelFunc()
+	return f(ctx)
+}
+
+// Create adds the result of a finished game.
+// The players and finish time are stored alongside the encoded result so they can be queried.
+func (rb *ResultBackend) Create(ctx context.Context, r result.Result) error {
+	resultJSON, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("encoding game result: %w", err)
+	}
+	if err := rb.withTimeoutContext(ctx, func(ctx context.Context) error {
+		m := map[string]any{
+			playersField:    r.Players,
+			finishedAtField: r.FinishedAt,
+			resultField:     string(resultJSON),
+		}
+		_, _, err := rb.resultsCollection().Add(ctx, m)
+		return err
+	}); err != nil {
+		return fmt.Errorf("creating game result: %w", err)
+	}
+	return nil
+}
+
+// ReadAll gets the results of the games the user played in, newest first.
+func (rb *ResultBackend) ReadAll(ctx context.Context, username string) ([]result.Result, error) {
+	var results []result.Result
+	if err := rb.withTimeoutContext(ctx, func(ctx context.Context) error {
+		query := rb.resultsCollection().Where(playersField, "array-contains", username)
+		docs, err := query.Documents(ctx).GetAll()
+		if err != nil {
+			return err
+		}
+		results = make([]result.Result, len(docs))
+		for i, doc := range docs {
+			var m struct {
+				Result string `firestore:"result"`
+			}
+			if err := doc.DataTo(&m); err != nil {
+				return err
+			}
+			if err := json.Unmarshal([]byte(m.Result), &results[i]); err != nil {
+				return fmt.Errorf("parsing game result: %w", err)
+			}
+		}
+		// sort here rather than in the query so no composite index is needed
+		sort.SliceStable(results, func(i, j int) bool {
+			return results[i].FinishedAt > results[j].FinishedAt
+		})
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("reading game results: %w", err)
+	}
+	return results, nil
+}
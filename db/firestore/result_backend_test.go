@@ -0,0 +1,80 @@
+package firestore
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jacobpatterson1549/selene-bananas/db"
+	"github.com/jacobpatterson1549/selene-bananas/db/result"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func testResultBackend(t *testing.T, s *mockFirestoreServer) *ResultBackend {
+	rb := ResultBackend{
+		client: mockFirestoreClient(t, s),
+		Config: db.Config{
+			QueryPeriod: 5 * time.Second,
+		},
+	}
+	return &rb
+}
+
+func TestResultBackend(t *testing.T) {
+	var s mockFirestoreServer
+	rb := testResultBackend(t, &s)
+	ctx := context.Background()
+	results := []result.Result{
+		{GameID: 1, Players: []string{"selene", "fred"}, Winner: "fred", FinishedAt: 10, FinishOrder: []string{"fred"}},
+		{GameID: 2, Players: []string{"barney"}, Winner: "barney", FinishedAt: 15},
+		{GameID: 3, Players: []string{"selene"}, Winner: "selene", FinishedAt: 30},
+		{GameID: 4, Players: []string{"selene", "barney"}, Winner: "barney", FinishedAt: 20},
+	}
+	for i, r := range results {
+		if err := rb.Create(ctx, r); err != nil {
+			t.Fatalf("unwanted error creating result %v: %v", i, err)
+		}
+	}
+	readAllTests := []struct {
+		username string
+		want     []result.Result
+	}{
+		{
+			username: "selene",
+			want:     []result.Result{results[2], results[3], results[0]},
+		},
+		{
+			username: "barney",
+			want:     []result.Result{results[3], results[1]},
+		},
+		{
+			username: "wilma",
+		},
+	}
+	for i, test := range readAllTests {
+		got, err := rb.ReadAll(ctx, test.username)
+		switch {
+		case err != nil:
+			t.Errorf("Test %v: unwanted error: %v", i, err)
+		case len(test.want) == 0 && len(got) == 0:
+		case !reflect.DeepEqual(test.want, got):
+			t.Errorf("Test %v: results not equal: \n wanted: %v \n got:    %v", i, test.want, got)
+		}
+	}
+}
+
+func TestResultBackendErrors(t *testing.T) {
+	s := mockFirestoreServer{
+		err: status.Error(codes.PermissionDenied, "problem with game results"),
+	}
+	rb := testResultBackend(t, &s)
+	ctx := context.Background()
+	if err := rb.Create(ctx, result.Result{GameID: 1}); err == nil {
+		t.Errorf("wanted error creating game result")
+	}
+	if _, err := rb.ReadAll(ctx, "selene"); err == nil {
+		t.Errorf("wanted error reading game results")
+	}
+}
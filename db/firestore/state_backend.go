@@ -0,0 +1,107 @@
+package firestore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"cloud.google.com/go/firestore"
+	"github.com/jacobpatterson1549/selene-bananas/db"
+	"github.com/jacobpatterson1549/selene-bananas/game"
+)
+
+const (
+	stateField = "state"
+)
+
+// StateBackend is a backend manager for a game states collection.
+type StateBackend struct {
+	client *firestore.Client
+	db.Config
+}
+
+// NewStateBackend creates a backend manager for game states that shares the client of the user backend.
+func NewStateBackend(ub *UserBackend) *StateBackend {
+	sb := StateBackend{
+		client: ub.client,
+		Config: ub.Config,
+	}
+	return &sb
+}
+
+func (sb *StateBackend) statesCollection() *firestore.CollectionRef {
+	return sb.client.Collection("services").Doc("selene-bananas").Collection("game_states")
+}
+
+// withTimeoutContext configures the context to timeout when running the function.
+func (sb *StateBackend) withTimeoutContext(ctx context.Context, f func(ctx context.Context) error) error {
+	ctx, cancelFunc := context.WithTimeout(ctx, sb.QueryPeriod)
+	defer cancelFunc()
+	return f(ctx)
+}
+
+// Save creates or replaces the state of the game.
+func (sb *StateBackend) Save(ctx context.Context, id game.ID, state []byte) error {
+	if err := sb.withTimeoutContext(ctx, func(ctx context.Context) error {
+		states := sb.statesCollection()
+		docRef := states.Doc(strconv.Itoa(int(id)))
+		m := map[string]any{
+			stateField: string(state),
+		}
+		_, err := docRef.Set(ctx, m)
+		return err
+	}); err != nil {
+		return fmt.Errorf("saving game state: %w", err)
+	}
+	return nil
+}
+
+// ReadAll gets the states of all of the games, ordered by id.
+func (sb *StateBackend) ReadAll(ctx context.Context) ([][]byte, error) {
+	var states [][]byte
+	if err := sb.withTimeoutContext(ctx, func(ctx context.Context) error {
+		docs, err := sb.statesCollection().Documents(ctx).GetAll()
+		if err != nil {
+			return err
+		}
+		ids := make([]int, len(docs))
+		byID := make(map[int][]byte, len(docs))
+		for i, doc := range docs {
+			id, err := strconv.Atoi(doc.Ref.ID)
+			if err != nil {
+				return fmt.Errorf("parsing game id: %w", err)
+			}
+			var m struct {
+				State string `firestore:"state"`
+			}
+			if err := doc.DataTo(&m); err != nil {
+				return err
+			}
+			ids[i] = id
+			byID[id] = []byte(m.State)
+		}
+		sort.Ints(ids)
+		states = make([][]byte, len(ids))
+		for i, id := range ids {
+			states[i] = byID[id]
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("reading game states: %w", err)
+	}
+	return states, nil
+}
+
+// Delete removes the state of the game.
+func (sb *StateBackend) Delete(ctx context.Context, id game.ID) error {
+	if err := sb.withTimeoutContext(ctx, func(ctx context.Context) error {
+		states := sb.statesCollection()
+		docRef := states.Doc(strconv.Itoa(int(id)))
+		_, err := docRef.Delete(ctx)
+		return err
+	}); err != nil {
+		return fmt.Errorf("deleting game state: %w", err)
+	}
+	return nil
+}
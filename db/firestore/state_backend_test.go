@@ -0,0 +1,92 @@
+package firestore
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jacobpatterson1549/selene-bananas/db"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func testStateBackend(t *testing.T, s *mockFirestoreServer) *StateBackend {
+	sb := StateBackend{
+		client: mockFirestoreClient(t, s),
+		Config: db.Config{
+			QueryPeriod: 5 * time.Second,
+		},
+	}
+	return &sb
+}
+
+func TestStateBackend(t *testing.T) {
+	var s mockFirestoreServer
+	sb := testStateBackend(t, &s)
+	ctx := context.Background()
+	steps := []struct {
+		f    func() error
+		want [][]byte
+	}{
+		{
+			f:    func() error { return nil },
+			want: [][]byte{},
+		},
+		{
+			f:    func() error { return sb.Save(ctx, 2, []byte("two")) },
+			want: [][]byte{[]byte("two")},
+		},
+		{
+			f:    func() error { return sb.Save(ctx, 10, []byte("ten")) },
+			want: [][]byte{[]byte("two"), []byte("ten")}, // ordered by id, not by the name of the document
+		},
+		{
+			f:    func() error { return sb.Save(ctx, 1, []byte("one")) },
+			want: [][]byte{[]byte("one"), []byte("two"), []byte("ten")},
+		},
+		{
+			f:    func() error { return sb.Save(ctx, 2, []byte("TWO")) },
+			want: [][]byte{[]byte("one"), []byte("TWO"), []byte("ten")},
+		},
+		{
+			f:    func() error { return sb.Delete(ctx, 1) },
+			want: [][]byte{[]byte("TWO"), []byte("ten")},
+		},
+		{
+			f:    func() error { return sb.Delete(ctx, 1) },
+			want: [][]byte{[]byte("TWO"), []byte("ten")},
+		},
+	}
+	for i, step := range steps {
+		if err := step.f(); err != nil {
+			t.Errorf("Step %v: unwanted error: %v", i, err)
+			continue
+		}
+		got, err := sb.ReadAll(ctx)
+		switch {
+		case err != nil:
+			t.Errorf("Step %v: unwanted error reading states: %v", i, err)
+		case len(step.want) == 0 && len(got) == 0:
+		case !reflect.DeepEqual(step.want, got):
+			t.Errorf("Step %v: states not equal: \n wanted: %q \n got:    %q", i, step.want, got)
+		}
+	}
+}
+
+func TestStateBackendErrors(t *testing.T) {
+	s := mockFirestoreServer{
+		err: status.Error(codes.PermissionDenied, "problem with game states"),
+	}
+	sb := testStateBackend(t, &s)
+	ctx := context.Background()
+	if err := sb.Save(ctx, 1, []byte("one")); err == nil {
+		t.Errorf("wanted error saving game state")
+	}
+	if _, err := sb.ReadAll(ctx); err == nil {
+		t.Errorf("wanted error reading game states")
+	}
+	if err := sb.Delete(ctx, 1); err == nil {
+		t.Errorf("wanted error deleting game state")
+	}
+}
@@ -144,3 +144,35 @@ func (ub *UserBackend) Delete(ctx context.Context, u user.User) error {
 	}
 	return nil
 }
+
+// ReadTopUsers gets the usernames and points of the users with the most points.
+func (ub *UserBackend) ReadTopUsers(ctx context.Context, limit, offset int) ([]user.User, error) {
+	var users []user.User
+	if err := ub.withTimeoutContext(ctx, func(ctx context.Context) error {
+		query := ub.usersCollection().
+			OrderBy(pointsField, firestore.Desc).
+			Offset(offset).
+			Limit(limit)
+		docs, err := query.Documents(ctx).GetAll()
+		if err != nil {
+			return err
+		}
+		users = make([]user.User, len(docs))
+		for i, doc := range docs {
+			var m struct {
+				Points int `firestore:"points"`
+			}
+			if err := doc.DataTo(&m); err != nil {
+				return err
+			}
+			users[i] = user.User{
+				Username: doc.Ref.ID,
+				Points:   m.Points,
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("reading top users: %w", err)
+	}
+	return users, nil
+}
@@ -0,0 +1,81 @@
+package mongo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jacobpatterson1549/selene-bananas/db"
+	"github.com/jacobpatterson1549/selene-bananas/db/result"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	playersField    = "players"
+	finishedAtField = "finishedAt"
+	resultField     = "result"
+)
+
+// ResultBackend is a backend manager for a game results collection.
+type ResultBackend struct {
+	Results *mongo.Collection
+	db.Config
+}
+
+// NewResultBackend creates a backend manager for the game results collection in the database of the users.
+func NewResultBackend(ub *UserBackend) *ResultBackend {
+	database := ub.Users.Database()
+	results := database.Collection("game_results")
+	rb := ResultBackend{
+		Results: results,
+		Config:  ub.Config,
+	}
+	return &rb
+}
+
+// Create adds the result of a finished game.
+// The players and finish time are stored alongside the encoded result so they can be queried.
+func (rb *ResultBackend) Create(ctx context.Context, r result.Result) error {
+	resultJSON, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("encoding game result: %w", err)
+	}
+	document := d(
+		e(playersField, r.Players),
+		e(finishedAtField, r.FinishedAt),
+		e(resultField, string(resultJSON)),
+	)
+	ctx, cancelFunc := context.WithTimeout(ctx, rb.Config.QueryPeriod)
+	defer cancelFunc()
+	if _, err := rb.Results.InsertOne(ctx, document); err != nil {
+		return fmt.Errorf("creating game result: %w", err)
+	}
+	return nil
+}
+
+// ReadAll gets the results of the games the user played in, newest first.
+func (rb *ResultBackend) ReadAll(ctx context.Context, username string) ([]result.Result, error) {
+	filter := d(e(playersField, username))
+	findOptions := options.Find()
+	findOptions.SetSort(d(e(finishedAtField, -1), e(idField, -1)))
+	ctx, cancelFunc := context.WithTimeout(ctx, rb.Config.QueryPeriod)
+	defer cancelFunc()
+	cursor, err := rb.Results.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("reading game results: %w", err)
+	}
+	var documents []struct {
+		Result string `bson:"result"`
+	}
+	if err := cursor.All(ctx, &documents); err != nil {
+		return nil, fmt.Errorf("decoding game results: %w", err)
+	}
+	results := make([]result.Result, len(documents))
+	for i, doc := range documents {
+		if err := json.Unmarshal([]byte(doc.Result), &results[i]); err != nil {
+			return nil, fmt.Errorf("parsing game result %v: %w", i, err)
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,138 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jacobpatterson1549/selene-bananas/db"
+	"github.com/jacobpatterson1549/selene-bananas/db/result"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func testResultBackend(mt *mtest.T) *ResultBackend {
+	rb := ResultBackend{
+		Results: mt.Coll,
+		Config: db.Config{
+			QueryPeriod: time.Second,
+		},
+	}
+	return &rb
+}
+
+func TestResultBackendCreate(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	createTests := []struct {
+		response bson.D
+		wantOk   bool
+	}{
+		{
+			response: mtest.CreateWriteErrorsResponse(mtest.WriteError{Message: "problem creating game result"}),
+		},
+		{
+			response: mtest.CreateSuccessResponse(),
+			wantOk:   true,
+		},
+	}
+	r := result.Result{
+		GameID:      3,
+		Players:     []string{"alice", "bob"},
+		Winner:      "bob",
+		FinishedAt:  1234,
+		FinishOrder: []string{"bob"},
+	}
+	wantResult := `{"gameID":3,"config":{},"players":["alice","bob"],"winner":"bob","winPoints":0,"wordCount":0,"finishedAt":1234,"durationSec":0,"finishOrder":["bob"]}`
+	for i, test := range createTests {
+		mt.Run(fmt.Sprintf("Test %v", i), func(mt *mtest.T) {
+			mt.AddMockResponses(test.response)
+			rb := testResultBackend(mt)
+			ctx := context.Background()
+			err := rb.Create(ctx, r)
+			switch {
+			case !test.wantOk:
+				if err == nil {
+					t.Errorf("Test %v: wanted error", i)
+				}
+			case err != nil:
+				t.Errorf("Test %v: unwanted error: %v", i, err)
+			default:
+				doc := mt.GetStartedEvent().Command.Lookup("documents").Array().Index(0).Value().Document()
+				players, _ := doc.Lookup(playersField).Array().Values()
+				switch {
+				case len(players) != 2 || players[0].StringValue() != "alice" || players[1].StringValue() != "bob":
+					t.Errorf("Test %v: wanted players to be stored so results can be queried by username: %v", i, doc)
+				case doc.Lookup(finishedAtField).Int64() != 1234:
+					t.Errorf("Test %v: wanted finish time to be stored so results can be sorted: %v", i, doc)
+				case doc.Lookup(resultField).StringValue() != wantResult:
+					t.Errorf("Test %v: encoded results not equal: \n wanted: %v \n got:    %v", i, wantResult, doc.Lookup(resultField))
+				}
+			}
+		})
+	}
+}
+
+func TestResultBackendReadAll(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	readAllTests := []struct {
+		response bson.D
+		wantOk   bool
+		want     []result.Result
+	}{
+		{
+			response: mtest.CreateCommandErrorResponse(mtest.CommandError{Message: "problem reading game results"}),
+		},
+		{
+			response: mtest.CreateCursorResponse(0, "db.game_results", mtest.FirstBatch,
+				d(e(resultField, "{bad json")),
+			),
+		},
+		{
+			response: mtest.CreateCursorResponse(0, "db.game_results", mtest.FirstBatch),
+			wantOk:   true,
+			want:     []result.Result{},
+		},
+		{
+			response: mtest.CreateCursorResponse(0, "db.game_results", mtest.FirstBatch,
+				d(e(resultField, `{"gameID":2,"players":["selene"],"winner":"selene","finishedAt":20}`)),
+				d(e(resultField, `{"gameID":1,"players":["selene","fred"],"winner":"fred","finishedAt":10,"finishOrder":["fred"]}`)),
+			),
+			wantOk: true,
+			want: []result.Result{
+				{GameID: 2, Players: []string{"selene"}, Winner: "selene", FinishedAt: 20},
+				{GameID: 1, Players: []string{"selene", "fred"}, Winner: "fred", FinishedAt: 10, FinishOrder: []string{"fred"}},
+			},
+		},
+	}
+	for i, test := range readAllTests {
+		mt.Run(fmt.Sprintf("Test %v", i), func(mt *mtest.T) {
+			mt.AddMockResponses(test.response)
+			rb := testResultBackend(mt)
+			ctx := context.Background()
+			got, err := rb.ReadAll(ctx, "selene")
+			switch {
+			case !test.wantOk:
+				if err == nil {
+					t.Errorf("Test %v: wanted error", i)
+				}
+			case err != nil:
+				t.Errorf("Test %v: unwanted error: %v", i, err)
+			case !reflect.DeepEqual(test.want, got):
+				t.Errorf("Test %v: results not equal: \n wanted: %v \n got:    %v", i, test.want, got)
+			default:
+				command := mt.GetStartedEvent().Command
+				sort, _ := command.Lookup("sort").Document().Elements()
+				switch {
+				case command.Lookup("filter", playersField).StringValue() != "selene":
+					t.Errorf("Test %v: wanted results of games the user played in to be read: %v", i, command)
+				case len(sort) != 2,
+					sort[0].Key() != finishedAtField, sort[0].Value().Int32() != -1,
+					sort[1].Key() != idField, sort[1].Value().Int32() != -1:
+					t.Errorf("Test %v: wanted newest results first: %v", i, command)
+				}
+			}
+		})
+	}
+}
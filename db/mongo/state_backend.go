@@ -0,0 +1,85 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jacobpatterson1549/selene-bananas/db"
+	"github.com/jacobpatterson1549/selene-bananas/game"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	idField    = "_id"
+	stateField = "state"
+)
+
+// StateBackend is a backend manager for a game states collection.
+type StateBackend struct {
+	States *mongo.Collection
+	db.Config
+}
+
+// NewStateBackend creates a backend manager for the game states collection in the database of the users.
+func NewStateBackend(ub *UserBackend) *StateBackend {
+	database := ub.Users.Database()
+	states := database.Collection("game_states")
+	sb := StateBackend{
+		States: states,
+		Config: ub.Config,
+	}
+	return &sb
+}
+
+// Save creates or replaces the state of the game.
+func (sb *StateBackend) Save(ctx context.Context, id game.ID, state []byte) error {
+	filter := d(e(idField, int(id)))
+	document := d(
+		e(idField, int(id)),
+		e(stateField, string(state)),
+	)
+	replaceOptions := options.Replace()
+	replaceOptions.SetUpsert(true)
+	ctx, cancelFunc := context.WithTimeout(ctx, sb.Config.QueryPeriod)
+	defer cancelFunc()
+	if _, err := sb.States.ReplaceOne(ctx, filter, document, replaceOptions); err != nil {
+		return fmt.Errorf("saving game state: %w", err)
+	}
+	return nil
+}
+
+// ReadAll gets the states of all of the games, ordered by id.
+func (sb *StateBackend) ReadAll(ctx context.Context) ([][]byte, error) {
+	findOptions := options.Find()
+	findOptions.SetSort(d(e(idField, 1)))
+	ctx, cancelFunc := context.WithTimeout(ctx, sb.Config.QueryPeriod)
+	defer cancelFunc()
+	cursor, err := sb.States.Find(ctx, bson.D{}, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("reading game states: %w", err)
+	}
+	var documents []struct {
+		State string `bson:"state"`
+	}
+	if err := cursor.All(ctx, &documents); err != nil {
+		return nil, fmt.Errorf("decoding game states: %w", err)
+	}
+	states := make([][]byte, len(documents))
+	for i, doc := range documents {
+		states[i] = []byte(doc.State)
+	}
+	return states, nil
+}
+
+// Delete removes the state of the game.
+func (sb *StateBackend) Delete(ctx context.Context, id game.ID) error {
+	filter := d(e(idField, int(id)))
+	ctx, cancelFunc := context.WithTimeout(ctx, sb.Config.QueryPeriod)
+	defer cancelFunc()
+	if _, err := sb.States.DeleteOne(ctx, filter); err != nil {
+		return fmt.Errorf("deleting game state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jacobpatterson1549/selene-bananas/db"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func testStateBackend(mt *mtest.T) *StateBackend {
+	sb := StateBackend{
+		States: mt.Coll,
+		Config: db.Config{
+			QueryPeriod: time.Second,
+		},
+	}
+	return &sb
+}
+
+func TestStateBackendSave(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	saveTests := []struct {
+		response bson.D
+		wantOk   bool
+	}{
+		{
+			response: mtest.CreateWriteErrorsResponse(mtest.WriteError{Message: "problem saving game state"}),
+		},
+		{
+			response: mtest.CreateSuccessResponse(),
+			wantOk:   true,
+		},
+	}
+	for i, test := range saveTests {
+		mt.Run(fmt.Sprintf("Test %v", i), func(mt *mtest.T) {
+			mt.AddMockResponses(test.response)
+			sb := testStateBackend(mt)
+			ctx := context.Background()
+			err := sb.Save(ctx, 7, []byte("seven"))
+			switch {
+			case !test.wantOk:
+				if err == nil {
+					t.Errorf("Test %v: wanted error", i)
+				}
+			case err != nil:
+				t.Errorf("Test %v: unwanted error: %v", i, err)
+			default:
+				update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+				switch {
+				case !update.Lookup("upsert").Boolean():
+					t.Errorf("Test %v: wanted state to be inserted if it does not exist: %v", i, update)
+				case update.Lookup("q", idField).Int32() != 7:
+					t.Errorf("Test %v: wanted state of game 7 to be replaced: %v", i, update)
+				case update.Lookup("u", stateField).StringValue() != "seven":
+					t.Errorf("Test %v: wanted state to be saved: %v", i, update)
+				}
+			}
+		})
+	}
+}
+
+func TestStateBackendReadAll(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	readAllTests := []struct {
+		response bson.D
+		wantOk   bool
+		want     [][]byte
+	}{
+		{
+			response: mtest.CreateCommandErrorResponse(mtest.CommandError{Message: "problem reading game states"}),
+		},
+		{
+			response: mtest.CreateCursorResponse(0, "db.game_states", mtest.FirstBatch),
+			wantOk:   true,
+			want:     [][]byte{},
+		},
+		{
+			response: mtest.CreateCursorResponse(0, "db.game_states", mtest.FirstBatch,
+				d(e(idField, 1), e(stateField, "one")),
+				d(e(idField, 2), e(stateField, "two")),
+			),
+			wantOk: true,
+			want:   [][]byte{[]byte("one"), []byte("two")},
+		},
+	}
+	for i, test := range readAllTests {
+		mt.Run(fmt.Sprintf("Test %v", i), func(mt *mtest.T) {
+			mt.AddMockResponses(test.response)
+			sb := testStateBackend(mt)
+			ctx := context.Background()
+			got, err := sb.ReadAll(ctx)
+			switch {
+			case !test.wantOk:
+				if err == nil {
+					t.Errorf("Test %v: wanted error", i)
+				}
+			case err != nil:
+				t.Errorf("Test %v: unwanted error: %v", i, err)
+			case !reflect.DeepEqual(test.want, got):
+				t.Errorf("Test %v: states not equal: \n wanted: %q \n got:    %q", i, test.want, got)
+			default:
+				sort := mt.GetStartedEvent().Command.Lookup("sort", idField)
+				if sort.Int32() != 1 {
+					t.Errorf("Test %v: wanted states to be sorted by id, got %v", i, sort)
+				}
+			}
+		})
+	}
+}
+
+func TestStateBackendDelete(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	deleteTests := []struct {
+		response bson.D
+		wantOk   bool
+	}{
+		{
+			response: mtest.CreateCommandErrorResponse(mtest.CommandError{Message: "problem deleting game state"}),
+		},
+		{
+			response: mtest.CreateSuccessResponse(e("n", 1)),
+			wantOk:   true,
+		},
+	}
+	for i, test := range deleteTests {
+		mt.Run(fmt.Sprintf("Test %v", i), func(mt *mtest.T) {
+			mt.AddMockResponses(test.response)
+			sb := testStateBackend(mt)
+			ctx := context.Background()
+			err := sb.Delete(ctx, 7)
+			switch {
+			case !test.wantOk:
+				if err == nil {
+					t.Errorf("Test %v: wanted error", i)
+				}
+			case err != nil:
+				t.Errorf("Test %v: unwanted error: %v", i, err)
+			default:
+				filter := mt.GetStartedEvent().Command.Lookup("deletes").Array().Index(0).Value().Document()
+				if filter.Lookup("q", idField).Int32() != 7 {
+					t.Errorf("Test %v: wanted state of game 7 to be deleted: %v", i, filter)
+				}
+			}
+		})
+	}
+}
@@ -148,3 +148,23 @@ func d(e ...bson.E) bson.D {
 func e(key string, value any) bson.E {
 	return bson.E{Key: key, Value: value}
 }
+
+// ReadTopUsers gets the usernames and points of the users with the most points.
+func (ub *UserBackend) ReadTopUsers(ctx context.Context, limit, offset int) ([]user.User, error) {
+	findOptions := options.Find()
+	findOptions.SetSort(d(e(pointsField, -1), e(usernameField, 1)))
+	findOptions.SetSkip(int64(offset))
+	findOptions.SetLimit(int64(limit))
+	findOptions.SetProjection(d(e(passwordField, 0)))
+	ctx, cancelFunc := context.WithTimeout(ctx, ub.Config.QueryPeriod)
+	defer cancelFunc()
+	cursor, err := ub.Users.Find(ctx, bson.D{}, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("reading top users: %w", err)
+	}
+	var users []user.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, fmt.Errorf("decoding top users: %w", err)
+	}
+	return users, nil
+}
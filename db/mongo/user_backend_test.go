@@ -0,0 +1,69 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jacobpatterson1549/selene-bananas/db"
+	"github.com/jacobpatterson1549/selene-bananas/db/user"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestUserBackendReadTopUsers(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	readTopUsersTests := []struct {
+		response bson.D
+		wantOk   bool
+		want     []user.User
+	}{
+		{
+			response: mtest.CreateCommandErrorResponse(mtest.CommandError{Message: "problem reading top users"}),
+		},
+		{
+			response: mtest.CreateCursorResponse(0, "db.users", mtest.FirstBatch,
+				d(e(usernameField, "selene"), e(pointsField, 18)),
+				d(e(usernameField, "fred"), e(pointsField, 4)),
+			),
+			wantOk: true,
+			want: []user.User{
+				{Username: "selene", Points: 18},
+				{Username: "fred", Points: 4},
+			},
+		},
+	}
+	for i, test := range readTopUsersTests {
+		mt.Run(fmt.Sprintf("Test %v", i), func(mt *mtest.T) {
+			mt.AddMockResponses(test.response)
+			ub := UserBackend{
+				Users: mt.Coll,
+				Config: db.Config{
+					QueryPeriod: time.Second,
+				},
+			}
+			ctx := context.Background()
+			got, err := ub.ReadTopUsers(ctx, 2, 5)
+			switch {
+			case !test.wantOk:
+				if err == nil {
+					t.Errorf("Test %v: wanted error", i)
+				}
+			case err != nil:
+				t.Errorf("Test %v: unwanted error: %v", i, err)
+			case !reflect.DeepEqual(test.want, got):
+				t.Errorf("Test %v: users not equal: \n wanted: %v \n got:    %v", i, test.want, got)
+			default:
+				command := mt.GetStartedEvent().Command
+				switch {
+				case command.Lookup("limit").Int64() != 2, command.Lookup("skip").Int64() != 5:
+					t.Errorf("Test %v: wanted page of users to be read: %v", i, command)
+				case command.Lookup("projection", passwordField).Int32() != 0:
+					t.Errorf("Test %v: wanted passwords to not be read: %v", i, command)
+				}
+			}
+		})
+	}
+}
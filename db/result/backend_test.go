@@ -0,0 +1,47 @@
+package result
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestMemoryBackend(t *testing.T) {
+	b := new(MemoryBackend)
+	ctx := context.Background()
+	results := []Result{
+		{GameID: 1, Players: []string{"alice", "bob"}, Winner: "bob"},
+		{GameID: 2, Players: []string{"carol"}, Winner: "carol"},
+		{GameID: 3, Players: []string{"alice"}, Winner: "alice"},
+	}
+	for i, r := range results {
+		if err := b.Create(ctx, r); err != nil {
+			t.Fatalf("unwanted error creating result %v: %v", i, err)
+		}
+	}
+	tests := []struct {
+		username string
+		want     []Result
+	}{
+		{
+			username: "alice",
+			want:     []Result{results[2], results[0]},
+		},
+		{
+			username: "bob",
+			want:     []Result{results[0]},
+		},
+		{
+			username: "dave",
+		},
+	}
+	for i, test := range tests {
+		got, err := b.ReadAll(ctx, test.username)
+		switch {
+		case err != nil:
+			t.Errorf("Test %v: unwanted error: %v", i, err)
+		case !reflect.DeepEqual(test.want, got):
+			t.Errorf("Test %v: results not equal: \n wanted: %v \n got:    %v", i, test.want, got)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package result
+
+import (
+	"context"
+	"fmt"
+)
+
+type (
+	// Dao records and reads the results of finished games.
+	Dao struct {
+		backend Backend
+	}
+
+	// Backend stores the results of games.
+	Backend interface {
+		// Create adds the result of a finished game.
+		Create(ctx context.Context, r Result) error
+		// ReadAll gets the results of the games the user played in, newest first.
+		ReadAll(ctx context.Context, username string) ([]Result, error)
+	}
+)
+
+// NewDao creates a Dao using the specified backend.
+func NewDao(b Backend) (*Dao, error) {
+	if err := validate(b); err != nil {
+		return nil, fmt.Errorf("creating game result dao: validation: %w", err)
+	}
+	d := Dao{
+		backend: b,
+	}
+	return &d, nil
+}
+
+// validate checks fields to set up the dao.
+func validate(b Backend) error {
+	switch {
+	case b == nil:
+		return fmt.Errorf("backend required")
+	}
+	return nil
+}
+
+// Create records the result of a finished game.
+func (d Dao) Create(ctx context.Context, r Result) error {
+	switch {
+	case len(r.Players) == 0:
+		return fmt.Errorf("game result has no players")
+	case len(r.Winner) == 0:
+		return fmt.Errorf("game result has no winner")
+	}
+	if err := d.backend.Create(ctx, r); err != nil {
+		return d.formatBackendError("creating game result", err)
+	}
+	return nil
+}
+
+// ReadStats summarizes the results of the games the user played in, including up to recentLimit of the most recent results.
+func (d Dao) ReadStats(ctx context.Context, username string, recentLimit int) (*Stats, error) {
+	results, err := d.backend.ReadAll(ctx, username)
+	if err != nil {
+		return nil, d.formatBackendError("reading game results", err)
+	}
+	s := newStats(username, results, recentLimit)
+	return &s, nil
+}
+
+// formatBackendError includes the name of the backend in the error message.
+func (d Dao) formatBackendError(reason string, err error) error {
+	return fmt.Errorf("%v (%T): %w", reason, d.backend, err)
+}
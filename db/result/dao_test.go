@@ -0,0 +1,164 @@
+package result
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestNewDao(t *testing.T) {
+	newDaoTests := []struct {
+		backend Backend
+		wantOk  bool
+	}{
+		{},
+		{
+			backend: new(mockBackend),
+			wantOk:  true,
+		},
+	}
+	for i, test := range newDaoTests {
+		d, err := NewDao(test.backend)
+		switch {
+		case !test.wantOk:
+			if err == nil {
+				t.Errorf("Test %v: wanted error creating new dao", i)
+			}
+		case err != nil:
+			t.Errorf("Test %v: unwanted error creating new dao: %v", i, err)
+		case d.backend == nil:
+			t.Errorf("Test %v: backend not set", i)
+		}
+	}
+}
+
+func TestDaoCreate(t *testing.T) {
+	tests := []struct {
+		Result
+		createErr error
+		wantOk    bool
+	}{
+		{ // no players
+			Result: Result{
+				Winner: "alice",
+			},
+		},
+		{ // no winner
+			Result: Result{
+				Players: []string{"alice"},
+			},
+		},
+		{
+			Result: Result{
+				Players: []string{"alice"},
+				Winner:  "alice",
+			},
+			createErr: fmt.Errorf("problem creating game result"),
+		},
+		{
+			Result: Result{
+				Players: []string{"alice"},
+				Winner:  "alice",
+			},
+			wantOk: true,
+		},
+	}
+	for i, test := range tests {
+		b := mockBackend{
+			createFunc: func(ctx context.Context, r Result) error {
+				if !reflect.DeepEqual(test.Result, r) {
+					t.Errorf("Test %v: results not equal: \n wanted: %v \n got:    %v", i, test.Result, r)
+				}
+				return test.createErr
+			},
+		}
+		d := Dao{
+			backend: b,
+		}
+		ctx := context.Background()
+		err := d.Create(ctx, test.Result)
+		switch {
+		case !test.wantOk:
+			if err == nil {
+				t.Errorf("Test %v: wanted error", i)
+			}
+		case err != nil:
+			t.Errorf("Test %v: unwanted error: %v", i, err)
+		}
+	}
+}
+
+func TestDaoReadStats(t *testing.T) {
+	results := []Result{
+		{GameID: 4, Winner: "bob", WordCount: 9},
+		{GameID: 3, Winner: "alice", WordCount: 5},
+		{GameID: 2, Winner: "alice", WordCount: 2},
+		{GameID: 1, Winner: "carol", WordCount: 7},
+	}
+	tests := []struct {
+		readAllErr  error
+		results     []Result
+		recentLimit int
+		wantOk      bool
+		want        *Stats
+	}{
+		{
+			readAllErr: fmt.Errorf("problem reading game results"),
+		},
+		{
+			recentLimit: 5,
+			wantOk:      true,
+			want:        &Stats{},
+		},
+		{
+			results:     results,
+			recentLimit: 2,
+			wantOk:      true,
+			want: &Stats{
+				GamesPlayed:        4,
+				Wins:               2,
+				WinRate:            0.5,
+				AverageWordsPerWin: 3.5,
+				RecentGames:        results[:2],
+			},
+		},
+		{
+			results:     results,
+			recentLimit: 10,
+			wantOk:      true,
+			want: &Stats{
+				GamesPlayed:        4,
+				Wins:               2,
+				WinRate:            0.5,
+				AverageWordsPerWin: 3.5,
+				RecentGames:        results,
+			},
+		},
+	}
+	for i, test := range tests {
+		b := mockBackend{
+			readAllFunc: func(ctx context.Context, username string) ([]Result, error) {
+				if username != "alice" {
+					t.Errorf("Test %v: wanted results for alice to be read, got %v", i, username)
+				}
+				return test.results, test.readAllErr
+			},
+		}
+		d := Dao{
+			backend: b,
+		}
+		ctx := context.Background()
+		got, err := d.ReadStats(ctx, "alice", test.recentLimit)
+		switch {
+		case !test.wantOk:
+			if err == nil {
+				t.Errorf("Test %v: wanted error", i)
+			}
+		case err != nil:
+			t.Errorf("Test %v: unwanted error: %v", i, err)
+		case !reflect.DeepEqual(test.want, got):
+			t.Errorf("Test %v: stats not equal: \n wanted: %v \n got:    %v", i, test.want, got)
+		}
+	}
+}
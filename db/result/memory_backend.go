@@ -0,0 +1,39 @@
+package result
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryBackend stores game results in memory.  Results are lost when the server stops.
+type MemoryBackend struct {
+	mu      sync.Mutex
+	results []Result
+}
+
+// Create stores a copy of the result.
+func (b *MemoryBackend) Create(ctx context.Context, r Result) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	r.Players = append([]string{}, r.Players...)
+	b.results = append(b.results, r)
+	return nil
+}
+
+// ReadAll gets the results of the games the user played in, newest first.
+func (b *MemoryBackend) ReadAll(ctx context.Context, username string) ([]Result, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var results []Result
+	for i := len(b.results) - 1; i >= 0; i-- {
+		r := b.results[i]
+		for _, p := range r.Players {
+			if p == username {
+				r.Players = append([]string{}, r.Players...)
+				results = append(results, r)
+				break
+			}
+		}
+	}
+	return results, nil
+}
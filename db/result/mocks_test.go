@@ -0,0 +1,18 @@
+package result
+
+import (
+	"context"
+)
+
+type mockBackend struct {
+	createFunc  func(ctx context.Context, r Result) error
+	readAllFunc func(ctx context.Context, username string) ([]Result, error)
+}
+
+func (m mockBackend) Create(ctx context.Context, r Result) error {
+	return m.createFunc(ctx, r)
+}
+
+func (m mockBackend) ReadAll(ctx context.Context, username string) ([]Result, error) {
+	return m.readAllFunc(ctx, username)
+}
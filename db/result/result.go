@@ -0,0 +1,73 @@
+// Package result records the outcomes of finished games so players can view their statistics.
+package result
+
+import (
+	"github.com/jacobpatterson1549/selene-bananas/game"
+)
+
+type (
+	// Result is the outcome of a finished game.
+	Result struct {
+		// GameID is the id of the game.
+		GameID game.ID `json:"gameID"`
+		// Config is the specific options used to create the game.
+		Config game.Config `json:"config"`
+		// Players are the usernames of the players in the game.
+		Players []string `json:"players"`
+		// Winner is the username of the player who won the game.
+		Winner string `json:"winner"`
+		// WinPoints are the amount of points the winner was awarded.
+		WinPoints int `json:"winPoints"`
+		// WordCount is the number of words on the board of the winner.
+		WordCount int `json:"wordCount"`
+		// LongestWord is the longest word on the board of the winner.
+		LongestWord string `json:"longestWord,omitempty"`
+		// FinishedAt is the time the game was finished in seconds since the unix epoch.
+		FinishedAt int64 `json:"finishedAt"`
+		// DurationSec is the number of seconds the game was played for.
+		DurationSec int64 `json:"durationSec"`
+		// FinishOrder are the usernames of the players who tried to finish the game, in the order their requests were received.
+		FinishOrder []string `json:"finishOrder,omitempty"`
+	}
+
+	// Stats summarizes the results of the games a user has played in.
+	Stats struct {
+		// GamesPlayed is the number of finished games the user was in.
+		GamesPlayed int `json:"gamesPlayed"`
+		// Wins is the number of games the user won.
+		Wins int `json:"wins"`
+		// WinRate is the fraction of the games played that the user won.
+		WinRate float64 `json:"winRate"`
+		// AverageWordsPerWin is the mean number of words on the user's board in the games they won.
+		AverageWordsPerWin float64 `json:"averageWordsPerWin"`
+		// RecentGames are the results of the most recently finished games, newest first.
+		RecentGames []Result `json:"recentGames,omitempty"`
+	}
+)
+
+// newStats summarizes the results, which should be sorted newest first.
+// Only the first few results are kept as the recent games.
+func newStats(username string, results []Result, recentLimit int) Stats {
+	var s Stats
+	var winWordCount int
+	for _, r := range results {
+		s.GamesPlayed++
+		if r.Winner == username {
+			s.Wins++
+			winWordCount += r.WordCount
+		}
+	}
+	if s.GamesPlayed != 0 {
+		s.WinRate = float64(s.Wins) / float64(s.GamesPlayed)
+	}
+	if s.Wins != 0 {
+		s.AverageWordsPerWin = float64(winWordCount) / float64(s.Wins)
+	}
+	if recentLimit < len(results) {
+		results = results[:recentLimit]
+	}
+	if len(results) != 0 {
+		s.RecentGames = results
+	}
+	return s
+}
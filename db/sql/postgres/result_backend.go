@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jacobpatterson1549/selene-bananas/db/result"
+	"github.com/jacobpatterson1549/selene-bananas/db/sql"
+)
+
+// ResultBackend provides functions to manage game results on a Postgres SQL Database.
+type ResultBackend struct {
+	Database
+}
+
+// Create adds the result of a finished game.
+func (rb *ResultBackend) Create(ctx context.Context, r result.Result) error {
+	config, err := json.Marshal(r.Config)
+	if err != nil {
+		return fmt.Errorf("encoding game config: %w", err)
+	}
+	usernames, err := json.Marshal(r.Players)
+	if err != nil {
+		return fmt.Errorf("encoding game players: %w", err)
+	}
+	finishOrder, err := json.Marshal(r.FinishOrder)
+	if err != nil {
+		return fmt.Errorf("encoding game finish order: %w", err)
+	}
+	q := sql.NewExecFunction("game_result_create", int(r.GameID), string(config), string(usernames), r.Winner, r.WinPoints, r.WordCount, r.LongestWord, r.FinishedAt, r.DurationSec, string(finishOrder))
+	if err := rb.Database.Exec(ctx, q); err != nil {
+		return fmt.Errorf("creating game result: %w", err)
+	}
+	return nil
+}
+
+// ReadAll queries the database for the results of the games the user played in, newest first.
+func (rb *ResultBackend) ReadAll(ctx context.Context, username string) ([]result.Result, error) {
+	cols := []string{
+		"results",
+	}
+	q := sql.NewQueryFunction("game_result_read_all", cols, username)
+	var resultsJSON string
+	if err := rb.Database.Query(ctx, q, &resultsJSON); err != nil {
+		return nil, fmt.Errorf("querying game results: %w", err)
+	}
+	var results []result.Result
+	if err := json.Unmarshal([]byte(resultsJSON), &results); err != nil {
+		return nil, fmt.Errorf("parsing game results: %w", err)
+	}
+	return results, nil
+}
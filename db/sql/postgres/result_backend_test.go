@@ -0,0 +1,130 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/jacobpatterson1549/selene-bananas/db/result"
+	"github.com/jacobpatterson1549/selene-bananas/db/sql"
+	"github.com/jacobpatterson1549/selene-bananas/game"
+)
+
+func TestResultBackendCreate(t *testing.T) {
+	tests := []struct {
+		execErr error
+		wantOk  bool
+	}{
+		{
+			execErr: fmt.Errorf("could not create game result in mock"),
+		},
+		{
+			wantOk: true,
+		},
+	}
+	r := result.Result{
+		GameID: 3,
+		Config: game.Config{
+			MinLength: 4,
+		},
+		Players:     []string{"alice", "bob"},
+		Winner:      "bob",
+		WinPoints:   5,
+		WordCount:   6,
+		LongestWord: "BANANA",
+		FinishedAt:  1234,
+		DurationSec: 56,
+		FinishOrder: []string{"bob"},
+	}
+	wantCmd := "SELECT game_result_create($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)"
+	wantArgs := []any{3, `{"minLength":4}`, `["alice","bob"]`, "bob", 5, 6, "BANANA", int64(1234), int64(56), `["bob"]`}
+	for i, test := range tests {
+		d := mockDatabase{
+			ExecFunc: func(ctx context.Context, queries ...sql.Query) error {
+				switch {
+				case len(queries) != 1:
+					t.Errorf("Test %v: wanted 1 query, got %v", i, len(queries))
+				case wantCmd != queries[0].Cmd():
+					t.Errorf("Test %v: query commands not equal: \n wanted: %q \n got:    %q", i, wantCmd, queries[0].Cmd())
+				case !reflect.DeepEqual(wantArgs, queries[0].Args()):
+					t.Errorf("Test %v: query args not equal: \n wanted: %v \n got:    %v", i, wantArgs, queries[0].Args())
+				}
+				return test.execErr
+			},
+		}
+		rb := ResultBackend{
+			Database: d,
+		}
+		ctx := context.Background()
+		err := rb.Create(ctx, r)
+		switch {
+		case !test.wantOk:
+			if err == nil {
+				t.Errorf("Test %v: wanted error", i)
+			}
+		case err != nil:
+			t.Errorf("Test %v: unwanted error: %v", i, err)
+		}
+	}
+}
+
+func TestResultBackendReadAll(t *testing.T) {
+	tests := []struct {
+		resultsJSON string
+		QueryErr    error
+		wantOk      bool
+		want        []result.Result
+	}{
+		{
+			QueryErr: fmt.Errorf("could not read game results from mock"),
+		},
+		{
+			resultsJSON: "{bad json",
+		},
+		{
+			resultsJSON: "[]",
+			wantOk:      true,
+			want:        []result.Result{},
+		},
+		{
+			resultsJSON: `[{"gameID":2,"players":["selene"],"winner":"selene","wordCount":3,"finishOrder":["selene"]},{"gameID":1,"players":["selene","fred"],"winner":"fred"}]`,
+			wantOk:      true,
+			want: []result.Result{
+				{GameID: 2, Players: []string{"selene"}, Winner: "selene", WordCount: 3, FinishOrder: []string{"selene"}},
+				{GameID: 1, Players: []string{"selene", "fred"}, Winner: "fred"},
+			},
+		},
+	}
+	for i, test := range tests {
+		d := mockDatabase{
+			QueryFunc: func(ctx context.Context, q sql.Query, dest ...any) error {
+				wantCmd := "SELECT results FROM game_result_read_all($1)"
+				wantArgs := []any{"selene"}
+				switch {
+				case wantCmd != q.Cmd():
+					t.Errorf("Test %v: query commands not equal: \n wanted: %q \n got:    %q", i, wantCmd, q.Cmd())
+				case !reflect.DeepEqual(wantArgs, q.Args()):
+					t.Errorf("Test %v: query args not equal: \n wanted: %q \n got:    %q", i, wantArgs, q.Args())
+				}
+				*dest[0].(*string) = test.resultsJSON
+				return test.QueryErr
+			},
+		}
+		rb := ResultBackend{
+			Database: d,
+		}
+		ctx := context.Background()
+		got, err := rb.ReadAll(ctx, "selene")
+		switch {
+		case !test.wantOk:
+			if err == nil {
+				t.Errorf("Test %v: wanted error", i)
+			}
+		case err != nil:
+			t.Errorf("Test %v: unwanted error: %v", i, err)
+		case !reflect.DeepEqual(test.want, got):
+			t.Errorf("Test %v: results not equal: \n wanted: %v \n got:    %v", i, test.want, got)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jacobpatterson1549/selene-bananas/db/sql"
+	"github.com/jacobpatterson1549/selene-bananas/game"
+)
+
+// StateBackend provides functions to manage game states on a Postgres SQL Database.
+type StateBackend struct {
+	Database
+}
+
+// Save creates or replaces the state of the game.
+func (sb *StateBackend) Save(ctx context.Context, id game.ID, state []byte) error {
+	q := sql.NewExecFunction("game_state_save", int(id), string(state))
+	if err := sb.Database.Exec(ctx, q); err != nil {
+		return fmt.Errorf("saving game state: %w", err)
+	}
+	return nil
+}
+
+// ReadAll queries the database for the states of all of the games.
+func (sb *StateBackend) ReadAll(ctx context.Context) ([][]byte, error) {
+	cols := []string{
+		"states",
+	}
+	q := sql.NewQueryFunction("game_state_read_all", cols)
+	var statesJSON string
+	if err := sb.Database.Query(ctx, q, &statesJSON); err != nil {
+		return nil, fmt.Errorf("querying game states: %w", err)
+	}
+	var rawStates []json.RawMessage
+	if err := json.Unmarshal([]byte(statesJSON), &rawStates); err != nil {
+		return nil, fmt.Errorf("parsing game states: %w", err)
+	}
+	states := make([][]byte, len(rawStates))
+	for i, s := range rawStates {
+		states[i] = s
+	}
+	return states, nil
+}
+
+// Delete removes the state of the game.
+func (sb *StateBackend) Delete(ctx context.Context, id game.ID) error {
+	q := sql.NewExecFunction("game_state_delete", int(id))
+	if err := sb.Database.Exec(ctx, q); err != nil {
+		return fmt.Errorf("deleting game state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/jacobpatterson1549/selene-bananas/db/sql"
+)
+
+func TestStateBackendReadAll(t *testing.T) {
+	tests := []struct {
+		statesJSON string
+		QueryErr   error
+		wantOk     bool
+		want       [][]byte
+	}{
+		{
+			QueryErr: fmt.Errorf("could not read game states from mock"),
+		},
+		{
+			statesJSON: "{bad json",
+		},
+		{
+			statesJSON: "[]",
+			wantOk:     true,
+			want:       [][]byte{},
+		},
+		{
+			statesJSON: `[{"id":1},{"id":2}]`,
+			wantOk:     true,
+			want: [][]byte{
+				[]byte(`{"id":1}`),
+				[]byte(`{"id":2}`),
+			},
+		},
+	}
+	for i, test := range tests {
+		d := mockDatabase{
+			QueryFunc: func(ctx context.Context, q sql.Query, dest ...any) error {
+				wantCmd := "SELECT states FROM game_state_read_all()"
+				switch {
+				case wantCmd != q.Cmd():
+					t.Errorf("Test %v: query commands not equal: \n wanted: %q \n got:    %q", i, wantCmd, q.Cmd())
+				case len(q.Args()) != 0:
+					t.Errorf("Test %v: wanted no query args, got %q", i, q.Args())
+				}
+				*dest[0].(*string) = test.statesJSON
+				return test.QueryErr
+			},
+		}
+		sb := StateBackend{
+			Database: d,
+		}
+		ctx := context.Background()
+		got, err := sb.ReadAll(ctx)
+		switch {
+		case !test.wantOk:
+			if err == nil {
+				t.Errorf("Test %v: wanted error", i)
+			}
+		case err != nil:
+			t.Errorf("Test %v: unwanted error: %v", i, err)
+		case !reflect.DeepEqual(test.want, got):
+			t.Errorf("Test %v: states not equal: \n wanted: %q \n got:    %q", i, test.want, got)
+		}
+	}
+}
+
+func TestStateBackendExec(t *testing.T) {
+	tests := []struct {
+		execErr error
+		wantOk  bool
+	}{
+		{
+			wantOk: true,
+		},
+		{
+			execErr: fmt.Errorf("could not change game state in mock"),
+		},
+	}
+	funcs := []struct {
+		name     string
+		f        func(sb StateBackend, ctx context.Context) error
+		wantCmd  string
+		wantArgs []any
+	}{
+		{
+			name: "Save",
+			f: func(sb StateBackend, ctx context.Context) error {
+				return sb.Save(ctx, 3, []byte(`{"id":3}`))
+			},
+			wantCmd:  "SELECT game_state_save($1, $2)",
+			wantArgs: []any{3, `{"id":3}`},
+		},
+		{
+			name: "Delete",
+			f: func(sb StateBackend, ctx context.Context) error {
+				return sb.Delete(ctx, 3)
+			},
+			wantCmd:  "SELECT game_state_delete($1)",
+			wantArgs: []any{3},
+		},
+	}
+	for _, f := range funcs {
+		t.Run(f.name, func(t *testing.T) {
+			for i, test := range tests {
+				d := mockDatabase{
+					ExecFunc: func(ctx context.Context, queries ...sql.Query) error {
+						switch {
+						case len(queries) != 1:
+							t.Errorf("Test %v: wanted 1 query, got %v", i, len(queries))
+						case f.wantCmd != queries[0].Cmd():
+							t.Errorf("Test %v: query commands not equal: \n wanted: %q \n got:    %q", i, f.wantCmd, queries[0].Cmd())
+						case !reflect.DeepEqual(f.wantArgs, queries[0].Args()):
+							t.Errorf("Test %v: query args not equal: \n wanted: %q \n got:    %q", i, f.wantArgs, queries[0].Args())
+						}
+						return test.execErr
+					},
+				}
+				sb := StateBackend{
+					Database: d,
+				}
+				ctx := context.Background()
+				err := f.f(sb, ctx)
+				switch {
+				case !test.wantOk:
+					if err == nil {
+						t.Errorf("Test %v: wanted error", i)
+					}
+				case err != nil:
+					t.Errorf("Test %v: unwanted error: %v", i, err)
+				}
+			}
+		})
+	}
+}
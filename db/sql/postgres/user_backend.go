@@ -3,6 +3,7 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -87,3 +88,30 @@ func (ub *UserBackend) Delete(ctx context.Context, u user.User) error {
 	}
 	return nil
 }
+
+// ReadTopUsers queries the database for the usernames and points of the users with the most points.
+func (ub *UserBackend) ReadTopUsers(ctx context.Context, limit, offset int) ([]user.User, error) {
+	cols := []string{
+		"users",
+	}
+	q := sql.NewQueryFunction("user_read_top", cols, limit, offset)
+	var usersJSON string
+	if err := ub.Database.Query(ctx, q, &usersJSON); err != nil {
+		return nil, fmt.Errorf("querying top users: %w", err)
+	}
+	var rows []struct {
+		Username string `json:"username"`
+		Points   int    `json:"points"`
+	}
+	if err := json.Unmarshal([]byte(usersJSON), &rows); err != nil {
+		return nil, fmt.Errorf("parsing top users: %w", err)
+	}
+	users := make([]user.User, len(rows))
+	for i, r := range rows {
+		users[i] = user.User{
+			Username: r.Username,
+			Points:   r.Points,
+		}
+	}
+	return users, nil
+}
@@ -176,3 +176,63 @@ func TestUserBackendExecUser(t *testing.T) {
 		})
 	}
 }
+
+func TestUserBackendReadTopUsers(t *testing.T) {
+	tests := []struct {
+		usersJSON string
+		QueryErr  error
+		wantOk    bool
+		want      []user.User
+	}{
+		{
+			QueryErr: fmt.Errorf("could not read top users from mock"),
+		},
+		{
+			usersJSON: "{bad json",
+		},
+		{
+			usersJSON: "[]",
+			wantOk:    true,
+			want:      []user.User{},
+		},
+		{
+			usersJSON: `[{"username":"selene","points":12},{"username":"fred","points":3}]`,
+			wantOk:    true,
+			want: []user.User{
+				{Username: "selene", Points: 12},
+				{Username: "fred", Points: 3},
+			},
+		},
+	}
+	for i, test := range tests {
+		d := mockDatabase{
+			QueryFunc: func(ctx context.Context, q sql.Query, dest ...any) error {
+				wantCmd := "SELECT users FROM user_read_top($1, $2)"
+				wantArgs := []any{10, 20}
+				switch {
+				case wantCmd != q.Cmd():
+					t.Errorf("Test %v: query commands not equal: \n wanted: %q \n got:    %q", i, wantCmd, q.Cmd())
+				case !reflect.DeepEqual(wantArgs, q.Args()):
+					t.Errorf("Test %v: query args not equal: \n wanted: %v \n got:    %v", i, wantArgs, q.Args())
+				}
+				*dest[0].(*string) = test.usersJSON
+				return test.QueryErr
+			},
+		}
+		ub := UserBackend{
+			Database: d,
+		}
+		ctx := context.Background()
+		got, err := ub.ReadTopUsers(ctx, 10, 20)
+		switch {
+		case !test.wantOk:
+			if err == nil {
+				t.Errorf("Test %v: wanted error", i)
+			}
+		case err != nil:
+			t.Errorf("Test %v: unwanted error: %v", i, err)
+		case !reflect.DeepEqual(test.want, got):
+			t.Errorf("Test %v: users not equal: \n wanted: %v \n got:    %v", i, test.want, got)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package state
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestBackends(t *testing.T) {
+	backends := map[string]Backend{
+		"memory": new(MemoryBackend),
+		"file":   FileBackend{Dir: t.TempDir()},
+	}
+	for name, b := range backends {
+		ctx := context.Background()
+		steps := []struct {
+			f    func() error
+			want [][]byte
+		}{
+			{
+				f:    func() error { return nil },
+				want: [][]byte{},
+			},
+			{
+				f:    func() error { return b.Save(ctx, 2, []byte("two")) },
+				want: [][]byte{[]byte("two")},
+			},
+			{
+				f:    func() error { return b.Save(ctx, 1, []byte("one")) },
+				want: [][]byte{[]byte("one"), []byte("two")},
+			},
+			{
+				f:    func() error { return b.Save(ctx, 2, []byte("TWO")) },
+				want: [][]byte{[]byte("one"), []byte("TWO")},
+			},
+			{
+				f:    func() error { return b.Delete(ctx, 1) },
+				want: [][]byte{[]byte("TWO")},
+			},
+			{
+				f:    func() error { return b.Delete(ctx, 1) },
+				want: [][]byte{[]byte("TWO")},
+			},
+		}
+		for i, step := range steps {
+			if err := step.f(); err != nil {
+				t.Errorf("Test %v, step %v: unwanted error: %v", name, i, err)
+				continue
+			}
+			got, err := b.ReadAll(ctx)
+			switch {
+			case err != nil:
+				t.Errorf("Test %v, step %v: unwanted error reading states: %v", name, i, err)
+			case len(step.want) == 0 && len(got) == 0:
+			case !reflect.DeepEqual(step.want, got):
+				t.Errorf("Test %v, step %v: states not equal: \n wanted: %q \n got:    %q", name, i, step.want, got)
+			}
+		}
+	}
+}
+
+func TestFileBackendReadAllMissingDir(t *testing.T) {
+	b := FileBackend{Dir: t.TempDir() + "/missing"}
+	ctx := context.Background()
+	got, err := b.ReadAll(ctx)
+	switch {
+	case err != nil:
+		t.Errorf("unwanted error: %v", err)
+	case len(got) != 0:
+		t.Errorf("wanted no states, got %q", got)
+	}
+}
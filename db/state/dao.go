@@ -0,0 +1,86 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jacobpatterson1549/selene-bananas/game"
+)
+
+type (
+	// Dao saves, reads, and deletes snapshots of games.
+	Dao struct {
+		backend Backend
+	}
+
+	// Backend stores the encoded states of games.
+	Backend interface {
+		// Save creates or replaces the state of the game.
+		Save(ctx context.Context, id game.ID, state []byte) error
+		// ReadAll gets the states of all of the saved games.
+		ReadAll(ctx context.Context) ([][]byte, error)
+		// Delete removes the state of the game.
+		Delete(ctx context.Context, id game.ID) error
+	}
+)
+
+// NewDao creates a Dao using the specified backend.
+func NewDao(b Backend) (*Dao, error) {
+	if err := validate(b); err != nil {
+		return nil, fmt.Errorf("creating game state dao: validation: %w", err)
+	}
+	d := Dao{
+		backend: b,
+	}
+	return &d, nil
+}
+
+// validate checks fields to set up the dao.
+func validate(b Backend) error {
+	switch {
+	case b == nil:
+		return fmt.Errorf("backend required")
+	}
+	return nil
+}
+
+// Save stores the state of the game, replacing any previous state for it.
+func (d Dao) Save(ctx context.Context, g Game) error {
+	state, err := json.Marshal(g)
+	if err != nil {
+		return fmt.Errorf("encoding game state: %w", err)
+	}
+	if err := d.backend.Save(ctx, g.ID, state); err != nil {
+		return d.formatBackendError("saving game state", err)
+	}
+	return nil
+}
+
+// ReadAll reads the states of all of the saved games.
+func (d Dao) ReadAll(ctx context.Context) ([]Game, error) {
+	states, err := d.backend.ReadAll(ctx)
+	if err != nil {
+		return nil, d.formatBackendError("reading game states", err)
+	}
+	games := make([]Game, len(states))
+	for i, state := range states {
+		if err := json.Unmarshal(state, &games[i]); err != nil {
+			return nil, fmt.Errorf("decoding game state %v: %w", i, err)
+		}
+	}
+	return games, nil
+}
+
+// Delete removes the state of the game.
+func (d Dao) Delete(ctx context.Context, id game.ID) error {
+	if err := d.backend.Delete(ctx, id); err != nil {
+		return d.formatBackendError("deleting game state", err)
+	}
+	return nil
+}
+
+// formatBackendError includes the name of the backend in the error message.
+func (d Dao) formatBackendError(reason string, err error) error {
+	return fmt.Errorf("%v (%T): %w", reason, d.backend, err)
+}
@@ -0,0 +1,168 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/jacobpatterson1549/selene-bananas/game"
+	"github.com/jacobpatterson1549/selene-bananas/game/board"
+	"github.com/jacobpatterson1549/selene-bananas/game/player"
+	"github.com/jacobpatterson1549/selene-bananas/game/tile"
+)
+
+func TestNewDao(t *testing.T) {
+	newDaoTests := []struct {
+		backend Backend
+		wantOk  bool
+	}{
+		{},
+		{
+			backend: new(mockBackend),
+			wantOk:  true,
+		},
+	}
+	for i, test := range newDaoTests {
+		d, err := NewDao(test.backend)
+		switch {
+		case !test.wantOk:
+			if err == nil {
+				t.Errorf("Test %v: wanted error creating new dao", i)
+			}
+		case err != nil:
+			t.Errorf("Test %v: unwanted error creating new dao: %v", i, err)
+		case d.backend == nil:
+			t.Errorf("Test %v: backend not set", i)
+		}
+	}
+}
+
+func testGame() Game {
+	b := board.New([]tile.Tile{{ID: 1, Ch: 'A'}}, nil)
+	return Game{
+		ID:        7,
+		CreatedAt: 1234,
+		Status:    game.InProgress,
+		Players: map[player.Name]Player{
+			"alice": {
+				WinPoints: 10,
+				Board:     b,
+			},
+		},
+		UnusedTiles: []tile.Tile{{ID: 2, Ch: 'B'}},
+		Config: game.Config{
+			CheckOnSnag: true,
+			MinLength:   3,
+		},
+	}
+}
+
+func TestDaoSaveReadAll(t *testing.T) {
+	tests := []struct {
+		saveErr    error
+		readAllErr error
+		wantOk     bool
+	}{
+		{
+			saveErr: fmt.Errorf("problem saving game state"),
+		},
+		{
+			readAllErr: fmt.Errorf("problem reading game states"),
+		},
+		{
+			wantOk: true,
+		},
+	}
+	for i, test := range tests {
+		var saved [][]byte
+		b := mockBackend{
+			saveFunc: func(ctx context.Context, id game.ID, state []byte) error {
+				if id != 7 {
+					t.Errorf("Test %v: wanted id 7 to be saved, got %v", i, id)
+				}
+				saved = append(saved, state)
+				return test.saveErr
+			},
+			readAllFunc: func(ctx context.Context) ([][]byte, error) {
+				return saved, test.readAllErr
+			},
+		}
+		d := Dao{
+			backend: b,
+		}
+		ctx := context.Background()
+		want := testGame()
+		err := d.Save(ctx, want)
+		var got []Game
+		if err == nil {
+			got, err = d.ReadAll(ctx)
+		}
+		switch {
+		case !test.wantOk:
+			if err == nil {
+				t.Errorf("Test %v: wanted error", i)
+			}
+		case err != nil:
+			t.Errorf("Test %v: unwanted error: %v", i, err)
+		case len(got) != 1 || !reflect.DeepEqual(want, got[0]):
+			t.Errorf("Test %v: games not equal after saving and reading: \n wanted: %v \n got:    %v", i, want, got)
+		}
+	}
+}
+
+func TestDaoReadAllBadState(t *testing.T) {
+	b := mockBackend{
+		readAllFunc: func(ctx context.Context) ([][]byte, error) {
+			return [][]byte{[]byte("{bad json")}, nil
+		},
+	}
+	d := Dao{
+		backend: b,
+	}
+	ctx := context.Background()
+	if _, err := d.ReadAll(ctx); err == nil {
+		t.Error("wanted error reading bad game state")
+	}
+}
+
+func TestDaoDelete(t *testing.T) {
+	tests := []struct {
+		deleteErr error
+		wantOk    bool
+	}{
+		{
+			deleteErr: fmt.Errorf("problem deleting game state"),
+		},
+		{
+			wantOk: true,
+		},
+	}
+	for i, test := range tests {
+		deleteCalled := false
+		b := mockBackend{
+			deleteFunc: func(ctx context.Context, id game.ID) error {
+				deleteCalled = true
+				if id != 7 {
+					t.Errorf("Test %v: wanted id 7 to be deleted, got %v", i, id)
+				}
+				return test.deleteErr
+			},
+		}
+		d := Dao{
+			backend: b,
+		}
+		ctx := context.Background()
+		err := d.Delete(ctx, 7)
+		switch {
+		case !deleteCalled:
+			t.Errorf("Test %v: wanted backend delete to be called", i)
+		case !test.wantOk:
+			if err == nil {
+				t.Errorf("Test %v: wanted error", i)
+			}
+		case err != nil:
+			t.Errorf("Test %v: unwanted error: %v", i, err)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jacobpatterson1549/selene-bananas/game"
+)
+
+// FileBackend stores the state of each game in a json file in a directory.
+type FileBackend struct {
+	// Dir is the directory the game state files are stored in.
+	Dir string
+}
+
+// fileExt is the extension of game state files.
+const fileExt = ".json"
+
+// Save writes the state of the game to its file, replacing it atomically.
+func (b FileBackend) Save(ctx context.Context, id game.ID, state []byte) error {
+	if err := os.MkdirAll(b.Dir, 0o755); err != nil {
+		return fmt.Errorf("creating game state directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(b.Dir, "tmp-*")
+	if err != nil {
+		return fmt.Errorf("creating temporary game state file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(state); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing game state file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("closing game state file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), b.fileName(id)); err != nil {
+		return fmt.Errorf("replacing game state file: %w", err)
+	}
+	return nil
+}
+
+// ReadAll reads the states of all of the games in the directory, ordered by id.
+func (b FileBackend) ReadAll(ctx context.Context) ([][]byte, error) {
+	entries, err := os.ReadDir(b.Dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading game state directory: %w", err)
+	}
+	ids := make([]game.ID, 0, len(entries))
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, fileExt) {
+			continue
+		}
+		id, err := strconv.Atoi(strings.TrimSuffix(name, fileExt))
+		if err != nil {
+			continue
+		}
+		ids = append(ids, game.ID(id))
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+	states := make([][]byte, len(ids))
+	for i, id := range ids {
+		state, err := os.ReadFile(b.fileName(id))
+		if err != nil {
+			return nil, fmt.Errorf("reading game state file: %w", err)
+		}
+		states[i] = state
+	}
+	return states, nil
+}
+
+// Delete removes the file containing the state of the game.
+func (b FileBackend) Delete(ctx context.Context, id game.ID) error {
+	if err := os.Remove(b.fileName(id)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing game state file: %w", err)
+	}
+	return nil
+}
+
+// fileName is the path of the file for the game.
+func (b FileBackend) fileName(id game.ID) string {
+	return filepath.Join(b.Dir, strconv.Itoa(int(id))+fileExt)
+}
@@ -0,0 +1,52 @@
+package state
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/jacobpatterson1549/selene-bananas/game"
+)
+
+// MemoryBackend stores game states in memory.  States are lost when the server stops.
+type MemoryBackend struct {
+	mu     sync.Mutex
+	states map[game.ID][]byte
+}
+
+// Save stores a copy of the state of the game.
+func (b *MemoryBackend) Save(ctx context.Context, id game.ID, state []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.states == nil {
+		b.states = make(map[game.ID][]byte)
+	}
+	b.states[id] = append([]byte{}, state...)
+	return nil
+}
+
+// ReadAll gets the states of all of the games, ordered by id.
+func (b *MemoryBackend) ReadAll(ctx context.Context) ([][]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	ids := make([]game.ID, 0, len(b.states))
+	for id := range b.states {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+	states := make([][]byte, len(ids))
+	for i, id := range ids {
+		states[i] = append([]byte{}, b.states[id]...)
+	}
+	return states, nil
+}
+
+// Delete removes the state of the game.
+func (b *MemoryBackend) Delete(ctx context.Context, id game.ID) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.states, id)
+	return nil
+}
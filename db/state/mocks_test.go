@@ -0,0 +1,25 @@
+package state
+
+import (
+	"context"
+
+	"github.com/jacobpatterson1549/selene-bananas/game"
+)
+
+type mockBackend struct {
+	saveFunc    func(ctx context.Context, id game.ID, state []byte) error
+	readAllFunc func(ctx context.Context) ([][]byte, error)
+	deleteFunc  func(ctx context.Context, id game.ID) error
+}
+
+func (m mockBackend) Save(ctx context.Context, id game.ID, state []byte) error {
+	return m.saveFunc(ctx, id, state)
+}
+
+func (m mockBackend) ReadAll(ctx context.Context) ([][]byte, error) {
+	return m.readAllFunc(ctx)
+}
+
+func (m mockBackend) Delete(ctx context.Context, id game.ID) error {
+	return m.deleteFunc(ctx, id)
+}
@@ -0,0 +1,66 @@
+// Package state persists the state of games so they can be resumed after the server restarts.
+package state
+
+import (
+	"github.com/jacobpatterson1549/selene-bananas/game"
+	"github.com/jacobpatterson1549/selene-bananas/game/board"
+	"github.com/jacobpatterson1549/selene-bananas/game/player"
+	"github.com/jacobpatterson1549/selene-bananas/game/replay"
+	"github.com/jacobpatterson1549/selene-bananas/game/tile"
+)
+
+type (
+	// Game is a snapshot of a game that can be used to recreate it.
+	Game struct {
+		// ID is the id of the game.
+		ID game.ID `json:"id"`
+		// CreatedAt is the game's creation time in seconds since the unix epoch.
+		CreatedAt int64 `json:"createdAt"`
+		// Status is the state of the game.
+		Status game.Status `json:"status"`
+		// Players are the states of the players in the game.
+		Players map[player.Name]Player `json:"players,omitempty"`
+		// Host is the name of the player who can start and delete the game and kick other players from it.
+		Host player.Name `json:"host,omitempty"`
+		// UnusedTiles are the tiles no player has, in the order they will be given out.
+		UnusedTiles []tile.Tile `json:"unusedTiles,omitempty"`
+		// Config is the specific options used to create the game.
+		Config game.Config `json:"config"`
+		// Events are the log of what has happened in the game.
+		Events []replay.Event `json:"events,omitempty"`
+		// FinishOrder are the names of the players who tried to finish the game, in the order their requests were received.
+		FinishOrder []player.Name `json:"finishOrder,omitempty"`
+		// Challenge is the win claimed by a player while the game is challenging.
+		Challenge *Challenge `json:"challenge,omitempty"`
+	}
+
+	// Challenge is a snapshot of a claimed win that other players can challenge.
+	Challenge struct {
+		// Claimant is the name of the player who claimed to win.
+		Claimant player.Name `json:"claimant"`
+		// Words are the words on the board of the claimant.
+		Words []string `json:"words,omitempty"`
+		// EndsAt is when the win is accepted if it is not challenged, in seconds since the unix epoch.
+		EndsAt int64 `json:"endsAt"`
+		// Votes are the words that each player challenged, keyed by player name.
+		Votes map[player.Name][]string `json:"votes,omitempty"`
+	}
+
+	// Player is a snapshot of a player in a game.
+	Player struct {
+		// WinPoints are the amount of points the player gets if they win the game.
+		WinPoints int `json:"winPoints"`
+		// Board contains the player's used and unused tiles.
+		Board *board.Board `json:"board"`
+		// Ready is a flag for players who are ready for the game to start.
+		Ready bool `json:"ready,omitempty"`
+		// Forfeited is a flag for players who gave up the game.
+		Forfeited bool `json:"forfeited,omitempty"`
+		// Team is the number of the team of the player.  Teammates share a board.
+		Team int `json:"team,omitempty"`
+		// Swaps is the number of tiles the player has swapped.
+		Swaps int `json:"swaps,omitempty"`
+		// LastSwapTime is when the player last swapped a tile.
+		LastSwapTime int64 `json:"lastSwapTime,omitempty"`
+	}
+)
@@ -26,6 +26,8 @@ type (
 		UpdatePointsIncrement(ctx context.Context, usernamePoints map[string]int) error
 		// Delete removes the user.
 		Delete(ctx context.Context, u User) error
+		// ReadTopUsers gets the usernames and points of the users with the most points, skipping the first offset users.
+		ReadTopUsers(ctx context.Context, limit, offset int) ([]User, error)
 	}
 
 	passwordHandler interface {
@@ -127,6 +129,37 @@ func (d Dao) UpdatePointsIncrement(ctx context.Context, usernamePoints map[strin
 	return nil
 }
 
+// ReadPoints gets the points of the user with the username.
+func (d Dao) ReadPoints(ctx context.Context, username string) (int, error) {
+	u := User{
+		Username: username,
+	}
+	u2, err := d.backend.Read(ctx, u)
+	if err != nil {
+		return 0, d.formatBackendError("reading user points", err)
+	}
+	return u2.Points, nil
+}
+
+// ReadTopUsers gets the users with the most points, ordered by their points.
+// Up to limit users are returned after the first offset users are skipped.  The passwords of the users are not returned.
+func (d Dao) ReadTopUsers(ctx context.Context, limit, offset int) ([]User, error) {
+	switch {
+	case limit <= 0:
+		return nil, fmt.Errorf("positive limit required")
+	case offset < 0:
+		return nil, fmt.Errorf("offset cannot be negative")
+	}
+	users, err := d.backend.ReadTopUsers(ctx, limit, offset)
+	if err != nil {
+		return nil, d.formatBackendError("reading top users", err)
+	}
+	for i := range users {
+		users[i].Password = ""
+	}
+	return users, nil
+}
+
 // Delete removes a user.
 func (d Dao) Delete(ctx context.Context, u User) error {
 	if !u.IsOauth2 {
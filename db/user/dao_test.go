@@ -320,6 +320,105 @@ func TestDaoUpdatePointsIncrement(t *testing.T) {
 	}
 }
 
+func TestDaoReadPoints(t *testing.T) {
+	readPointsTests := []struct {
+		readErr error
+		wantOk  bool
+		want    int
+	}{
+		{
+			readErr: fmt.Errorf("problem reading user"),
+		},
+		{
+			wantOk: true,
+			want:   8,
+		},
+	}
+	for i, test := range readPointsTests {
+		b := mockBackend{
+			readFunc: func(ctx context.Context, u User) (*User, error) {
+				if u.Username != "selene" {
+					t.Errorf("Test %v: wanted selene to be read, got %v", i, u.Username)
+				}
+				u.Points = 8
+				return &u, test.readErr
+			},
+		}
+		d := Dao{
+			backend: b,
+		}
+		ctx := context.Background()
+		got, err := d.ReadPoints(ctx, "selene")
+		switch {
+		case !test.wantOk:
+			if err == nil {
+				t.Errorf("Test %v: wanted error reading user points", i)
+			}
+		case err != nil:
+			t.Errorf("Test %v: unwanted error reading user points: %v", i, err)
+		case test.want != got:
+			t.Errorf("Test %v: points not equal: wanted %v, got %v", i, test.want, got)
+		}
+	}
+}
+
+func TestDaoReadTopUsers(t *testing.T) {
+	readTopUsersTests := []struct {
+		limit      int
+		offset     int
+		backendErr error
+		wantOk     bool
+	}{
+		{}, // no limit
+		{
+			limit:  10,
+			offset: -1,
+		},
+		{
+			limit:      10,
+			backendErr: fmt.Errorf("problem reading top users"),
+		},
+		{
+			limit:  10,
+			offset: 20,
+			wantOk: true,
+		},
+	}
+	for i, test := range readTopUsersTests {
+		b := mockBackend{
+			readTopUsersFunc: func(ctx context.Context, limit, offset int) ([]User, error) {
+				if test.limit != limit || test.offset != offset {
+					t.Errorf("Test %v: wanted limit/offset to be %v/%v, got %v/%v", i, test.limit, test.offset, limit, offset)
+				}
+				users := []User{
+					{Username: "selene", Password: "hash1", Points: 9},
+					{Username: "fred", Password: "hash2", Points: 4},
+				}
+				return users, test.backendErr
+			},
+		}
+		d := Dao{
+			backend: b,
+		}
+		ctx := context.Background()
+		got, err := d.ReadTopUsers(ctx, test.limit, test.offset)
+		want := []User{
+			{Username: "selene", Points: 9},
+			{Username: "fred", Points: 4},
+		}
+		switch {
+		case !test.wantOk:
+			if err == nil {
+				t.Errorf("Test %v: wanted error reading top users", i)
+			}
+		case err != nil:
+			t.Errorf("Test %v: unwanted error reading top users: %v", i, err)
+		case !reflect.DeepEqual(want, got):
+			t.Errorf("Test %v: users not equal (passwords should be removed):\nwanted: %v\ngot:    %v", i, want, got)
+		}
+	}
+}
+
 func TestDaoDelete(t *testing.T) {
 	deleteTests := []struct {
 		dbQueryErr error
@@ -23,6 +23,7 @@ type mockBackend struct {
 	updatePasswordFunc        func(ctx context.Context, u User) error
 	updatePointsIncrementFunc func(ctx context.Context, userPoints map[string]int) error
 	deleteFunc                func(ctx context.Context, u User) error
+	readTopUsersFunc          func(ctx context.Context, limit, offset int) ([]User, error)
 }
 
 func (m mockBackend) Create(ctx context.Context, u User) error {
@@ -44,3 +45,7 @@ func (m mockBackend) UpdatePointsIncrement(ctx context.Context, userPoints map[s
 func (m mockBackend) Delete(ctx context.Context, u User) error {
 	return m.deleteFunc(ctx, u)
 }
+
+func (m mockBackend) ReadTopUsers(ctx context.Context, limit, offset int) ([]User, error) {
+	return m.readTopUsersFunc(ctx, limit, offset)
+}
@@ -31,3 +31,8 @@ func (b NoDatabaseBackend) UpdatePointsIncrement(ctx context.Context, usernamePo
 func (b NoDatabaseBackend) Delete(ctx context.Context, u User) error {
 	return fmt.Errorf("no database to delete user")
 }
+
+// ReadTopUsers returns no users.
+func (b NoDatabaseBackend) ReadTopUsers(ctx context.Context, limit, offset int) ([]User, error) {
+	return nil, nil
+}
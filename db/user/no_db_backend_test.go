@@ -68,3 +68,15 @@ func TestNoDatabaseBackendDelete(t *testing.T) {
 		t.Errorf("wanted error")
 	}
 }
+
+func TestNoDatabaseBackendReadTopUsers(t *testing.T) {
+	ctx := context.Background()
+	var b NoDatabaseBackend
+	got, err := b.ReadTopUsers(ctx, 10, 0)
+	switch {
+	case err != nil:
+		t.Errorf("unwanted error: %v", err)
+	case len(got) != 0:
+		t.Errorf("wanted no users, got %v", got)
+	}
+}
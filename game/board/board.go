@@ -18,6 +18,8 @@ type (
 		UnusedTileIDs []tile.ID
 		UsedTiles     map[tile.ID]tile.Position
 		UsedTileLocs  map[tile.X]map[tile.Y]tile.Tile
+		// Revision is incremented each time a diff is applied or the board is resized.
+		Revision int
 		Config
 	}
 
@@ -101,6 +103,15 @@ func (b Board) sortedUsedTiles() []tile.Position {
 	return usedTiles
 }
 
+// Tiles returns a new array of all of the tiles on the board.  The unused tiles are first, followed by the used tiles, sorted by position.
+func (b Board) Tiles() []tile.Tile {
+	tiles := b.sortedUnusedTiles()
+	for _, tp := range b.sortedUsedTiles() {
+		tiles = append(tiles, tp.Tile)
+	}
+	return tiles
+}
+
 // AddTile adds a tile to the board's unused tiles.
 // An error is returned and the tile is not added if the player already has it.
 func (b *Board) AddTile(t tile.Tile) error {
@@ -225,12 +236,12 @@ func (b Board) canLeaveUsedTiles(movedTileIDs map[tile.ID]struct{}, movedTilePos
 
 // CanBeFinished determines if all the bord's tiles are used and form a single group.
 func (b *Board) CanBeFinished() bool {
-	return len(b.UnusedTiles) == 0 && b.hasSingleUsedGroup()
+	return len(b.UnusedTiles) == 0 && b.HasSingleUsedGroup()
 }
 
-// hasSingleUsedGroup determines if the player's tiles form a single group (or none),
+// HasSingleUsedGroup determines if the player's tiles form a single group (or none),
 // with all tiles connected via immediate horizontal and vertical neighbors.
-func (b *Board) hasSingleUsedGroup() bool {
+func (b *Board) HasSingleUsedGroup() bool {
 	seenTileIds := make(map[tile.ID]struct{})
 	for x, yTiles := range b.UsedTileLocs {
 		for y, t := range yTiles {
@@ -310,16 +321,19 @@ func (b Board) keyedUsedWords(tiles map[int]map[int]tile.Tile, ord func(tp tile.
 		})
 		var buf bytes.Buffer
 		var zWords []string
+		wordLength := 0 // the number of letters in the buffer, which can be less than its length because letters can be multiple bytes
 		for i, tp := range tilePositions {
 			if i > 0 && ord(tilePositions[i-1]) < ord(tp)-1 {
-				if buf.Len() > 1 {
+				if wordLength > 1 {
 					zWords = append(zWords, buf.String())
 				}
 				buf.Reset()
+				wordLength = 0
 			}
 			buf.WriteRune(rune(tp.Tile.Ch))
+			wordLength++
 		}
-		if buf.Len() > 1 {
+		if wordLength > 1 {
 			zWords = append(zWords, buf.String())
 		}
 		keyedUsedWords[z] = zWords
@@ -380,7 +394,7 @@ func (b Board) hasTile(t tile.Tile) bool {
 	return false
 }
 
-// Resize resizes the board to use the new config.  Any board size change information is returned in the message.
+// Resize resizes the board to use the new config, incrementing its revision.  Any board size change information is returned in the message.
 func (b *Board) Resize(cfg Config) (*ResizeResult, error) {
 	usedTilePositions := make([]tile.Position, 0, len(b.UsedTiles))
 	var movedTiles []tile.Tile
@@ -399,6 +413,7 @@ func (b *Board) Resize(cfg Config) (*ResizeResult, error) {
 		}
 	}
 	b.Config = cfg
+	b.Revision++
 	sort.Slice(usedTilePositions, func(i, j int) bool {
 		a, b := usedTilePositions[i], usedTilePositions[j]
 		// top-bottom, left-right
@@ -86,6 +86,17 @@ func TestAddTile(t *testing.T) {
 	}
 }
 
+func TestTiles(t *testing.T) {
+	b := New([]tile.Tile{{ID: 3, Ch: 'C'}, {ID: 1, Ch: 'A'}}, []tile.Position{
+		{Tile: tile.Tile{ID: 4, Ch: 'D'}, X: 2, Y: 1},
+		{Tile: tile.Tile{ID: 2, Ch: 'B'}, X: 1, Y: 5},
+	})
+	want := []tile.Tile{{ID: 3, Ch: 'C'}, {ID: 1, Ch: 'A'}, {ID: 2, Ch: 'B'}, {ID: 4, Ch: 'D'}}
+	if got := b.Tiles(); !reflect.DeepEqual(want, got) {
+		t.Errorf("not equal:\nwanted: %v\ngot:    %v", want, got)
+	}
+}
+
 func TestUsedWords(t *testing.T) {
 	usedWordsTests := []struct {
 		usedTiles    map[tile.ID]tile.Position
@@ -195,6 +206,29 @@ func TestUsedWords(t *testing.T) {
 			},
 			want: []string{"\u0000\u0000"},
 		},
+		{
+			// AÑO
+			// Ñ
+			usedTiles: map[tile.ID]tile.Position{
+				1: {Tile: tile.Tile{ID: 1, Ch: 'A'}, X: 1, Y: 1},
+				2: {Tile: tile.Tile{ID: 2, Ch: 'Ñ'}, X: 2, Y: 1},
+				3: {Tile: tile.Tile{ID: 3, Ch: 'O'}, X: 3, Y: 1},
+				4: {Tile: tile.Tile{ID: 4, Ch: 'Ñ'}, X: 1, Y: 3},
+			},
+			usedTileLocs: map[tile.X]map[tile.Y]tile.Tile{
+				1: {
+					1: {ID: 1, Ch: 'A'},
+					3: {ID: 4, Ch: 'Ñ'},
+				},
+				2: {
+					1: {ID: 2, Ch: 'Ñ'},
+				},
+				3: {
+					1: {ID: 3, Ch: 'O'},
+				},
+			},
+			want: []string{"AÑO"},
+		},
 	}
 	for i, test := range usedWordsTests {
 		b := Board{
@@ -293,6 +327,26 @@ func TestCanBeFinished(t *testing.T) {
 	}
 }
 
+func TestHasSingleUsedGroupIgnoresUnusedTiles(t *testing.T) {
+	b := Board{
+		UnusedTiles: map[tile.ID]tile.Tile{1: {}},
+		UsedTiles: map[tile.ID]tile.Position{
+			5: {Tile: tile.Tile{ID: 5, Ch: 'A'}, X: 7, Y: 2},
+		},
+		UsedTileLocs: map[tile.X]map[tile.Y]tile.Tile{
+			7: {
+				2: {ID: 5, Ch: 'A'},
+			},
+		},
+	}
+	switch {
+	case !b.HasSingleUsedGroup():
+		t.Error("wanted used tiles to be a single group")
+	case b.CanBeFinished():
+		t.Error("wanted board with unused tiles to not be able to be finished")
+	}
+}
+
 func TestMoveTiles(t *testing.T) {
 	t.Run("swap", func(t *testing.T) {
 		b := Board{
@@ -552,6 +606,8 @@ func TestResize(t *testing.T) {
 			t.Errorf("Test %v: unwanted error resizing board: %v", i, err)
 		case b.Config.NumCols != cfg.NumCols, b.Config.NumRows != cfg.NumRows:
 			t.Errorf("resizing should update max board dimensions, wanted %v, got %v", cfg, b)
+		case b.Revision != 1:
+			t.Errorf("Test %v: wanted resizing to increment the revision of the board, got %v", i, b.Revision)
 		case test.wantTile2Unused:
 			switch {
 			case len(b.UnusedTileIDs) != 1, b.UnusedTileIDs[0] != t2.ID,
@@ -0,0 +1,108 @@
+package board
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/jacobpatterson1549/selene-bananas/game/tile"
+)
+
+type (
+	// Diff is a list of changes to a board.  Diffs are sent instead of whole boards to keep messages small.
+	Diff struct {
+		// Revision is the revision of the board that the changes are made to.
+		Revision int `json:"revision"`
+		// Changes are made in order.
+		Changes []Change `json:"changes,omitempty"`
+	}
+
+	// Change is an operation on a single tile of a board.
+	Change struct {
+		// Op is the kind of change.
+		Op Op `json:"op"`
+		// Position is the tile that is changed.  The location of the position is only used when moving the tile.
+		tile.Position
+	}
+
+	// Op is a kind of change to a tile.
+	Op int
+)
+
+const (
+	_ Op = iota
+	// OpAdd adds a tile to the unused tiles of the board.
+	OpAdd
+	// OpRemove removes a tile from the board.
+	OpRemove
+	// OpMove moves a tile to a location in the used area of the board.
+	OpMove
+)
+
+// NewDiff creates a diff for the current revision of the board with the same operation on each of the tile positions.
+func (b Board) NewDiff(op Op, tilePositions ...tile.Position) Diff {
+	d := Diff{
+		Revision: b.Revision,
+		Changes:  make([]Change, len(tilePositions)),
+	}
+	for i, tp := range tilePositions {
+		d.Changes[i] = Change{
+			Op:       op,
+			Position: tp,
+		}
+	}
+	return d
+}
+
+// Apply makes the changes of the diff to the board and increments the revision of the board.
+// Consecutive moves are made together so tiles can trade places.
+// No changes are made if the diff is not for the revision of the board or any of the changes cannot be made.
+func (b *Board) Apply(d Diff) error {
+	if d.Revision != b.Revision {
+		return errors.New("diff is for revision " + strconv.Itoa(d.Revision) + " of the board, but the board is at revision " + strconv.Itoa(b.Revision))
+	}
+	b2 := b.copy()
+	moves := make(map[tile.ID]tile.Position)
+	for i, c := range d.Changes {
+		var err error
+		switch c.Op {
+		case OpAdd:
+			err = b2.AddTile(c.Tile)
+		case OpRemove:
+			err = b2.RemoveTile(c.Tile)
+		case OpMove:
+			moves[c.Tile.ID] = c.Position
+			if i+1 < len(d.Changes) && d.Changes[i+1].Op == OpMove {
+				continue
+			}
+			err = b2.MoveTiles(moves)
+			moves = make(map[tile.ID]tile.Position)
+		default:
+			err = errors.New("unknown operation for change of tile " + strconv.Itoa(int(c.Tile.ID)) + ": " + strconv.Itoa(int(c.Op)))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	b2.Revision++
+	*b = *b2
+	return nil
+}
+
+// MovedTilePositions returns the positions that tiles are moved to in the diff.
+func (d Diff) MovedTilePositions() []tile.Position {
+	var tilePositions []tile.Position
+	for _, c := range d.Changes {
+		if c.Op == OpMove {
+			tilePositions = append(tilePositions, c.Position)
+		}
+	}
+	return tilePositions
+}
+
+// copy creates a board with the same tiles and config that can be changed without changing the board.
+func (b Board) copy() *Board {
+	b2 := New(b.sortedUnusedTiles(), b.sortedUsedTiles())
+	b2.Config = b.Config
+	b2.Revision = b.Revision
+	return b2
+}
@@ -0,0 +1,163 @@
+package board
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/jacobpatterson1549/selene-bananas/game/tile"
+)
+
+func TestApply(t *testing.T) {
+	t1 := tile.Tile{ID: 1, Ch: 'A'}
+	t2 := tile.Tile{ID: 2, Ch: 'B'}
+	t3 := tile.Tile{ID: 3, Ch: 'C'}
+	applyTests := []struct {
+		Diff
+		wantOk            bool
+		wantUnusedTileIDs []tile.ID
+		wantUsedTiles     map[tile.ID]tile.Position
+	}{
+		{ // stale revision
+			Diff: Diff{
+				Revision: 6,
+			},
+		},
+		{ // no changes
+			Diff: Diff{
+				Revision: 7,
+			},
+			wantOk:            true,
+			wantUnusedTileIDs: []tile.ID{1},
+			wantUsedTiles: map[tile.ID]tile.Position{
+				2: {Tile: t2, X: 4, Y: 5},
+			},
+		},
+		{ // unknown operation
+			Diff: Diff{
+				Revision: 7,
+				Changes: []Change{
+					{Position: tile.Position{Tile: t1}},
+				},
+			},
+		},
+		{ // add tile that the board has
+			Diff: Diff{
+				Revision: 7,
+				Changes: []Change{
+					{Op: OpAdd, Position: tile.Position{Tile: t3}},
+					{Op: OpAdd, Position: tile.Position{Tile: t1}},
+				},
+			},
+		},
+		{ // remove tile that the board does not have
+			Diff: Diff{
+				Revision: 7,
+				Changes: []Change{
+					{Op: OpRemove, Position: tile.Position{Tile: t3}},
+				},
+			},
+		},
+		{ // move to used location
+			Diff: Diff{
+				Revision: 7,
+				Changes: []Change{
+					{Op: OpMove, Position: tile.Position{Tile: t1, X: 4, Y: 5}},
+				},
+			},
+		},
+		{ // swap tiles
+			Diff: Diff{
+				Revision: 7,
+				Changes: []Change{
+					{Op: OpMove, Position: tile.Position{Tile: t1, X: 4, Y: 5}},
+					{Op: OpMove, Position: tile.Position{Tile: t2, X: 0, Y: 0}},
+				},
+			},
+			wantOk: true,
+			wantUsedTiles: map[tile.ID]tile.Position{
+				1: {Tile: t1, X: 4, Y: 5},
+				2: {Tile: t2, X: 0, Y: 0},
+			},
+		},
+		{ // move added tile after removing one
+			Diff: Diff{
+				Revision: 7,
+				Changes: []Change{
+					{Op: OpRemove, Position: tile.Position{Tile: t1}},
+					{Op: OpAdd, Position: tile.Position{Tile: t3}},
+					{Op: OpMove, Position: tile.Position{Tile: t3, X: 4, Y: 6}},
+				},
+			},
+			wantOk: true,
+			wantUsedTiles: map[tile.ID]tile.Position{
+				2: {Tile: t2, X: 4, Y: 5},
+				3: {Tile: t3, X: 4, Y: 6},
+			},
+		},
+	}
+	for i, test := range applyTests {
+		b := New([]tile.Tile{t1}, []tile.Position{{Tile: t2, X: 4, Y: 5}})
+		b.Config = Config{NumRows: 10, NumCols: 10}
+		b.Revision = 7
+		want := *b.copy()
+		err := b.Apply(test.Diff)
+		switch {
+		case !test.wantOk:
+			if err == nil {
+				t.Errorf("Test %v: wanted error", i)
+			}
+			if !reflect.DeepEqual(want, *b) {
+				t.Errorf("Test %v: wanted board to not change when changes cannot be made:\nwanted: %v\ngot:    %v", i, want, *b)
+			}
+		case err != nil:
+			t.Errorf("Test %v: unwanted error: %v", i, err)
+		case b.Revision != 8:
+			t.Errorf("Test %v: wanted revision to be incremented, got %v", i, b.Revision)
+		case len(test.wantUnusedTileIDs) != len(b.UnusedTileIDs),
+			len(test.wantUnusedTileIDs) != 0 && !reflect.DeepEqual(test.wantUnusedTileIDs, b.UnusedTileIDs):
+			t.Errorf("Test %v: unused tiles not equal:\nwanted: %v\ngot:    %v", i, test.wantUnusedTileIDs, b.UnusedTileIDs)
+		case !reflect.DeepEqual(test.wantUsedTiles, b.UsedTiles):
+			t.Errorf("Test %v: used tiles not equal:\nwanted: %v\ngot:    %v", i, test.wantUsedTiles, b.UsedTiles)
+		}
+	}
+}
+
+func TestNewDiff(t *testing.T) {
+	b := Board{Revision: 3}
+	tp := tile.Position{Tile: tile.Tile{ID: 4, Ch: 'D'}, X: 1, Y: 2}
+	want := Diff{
+		Revision: 3,
+		Changes: []Change{
+			{Op: OpMove, Position: tp},
+		},
+	}
+	got := b.NewDiff(OpMove, tp)
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("not equal:\nwanted: %v\ngot:    %v", want, got)
+	}
+	if want, got := []tile.Position{tp}, got.MovedTilePositions(); !reflect.DeepEqual(want, got) {
+		t.Errorf("moved tile positions not equal:\nwanted: %v\ngot:    %v", want, got)
+	}
+}
+
+func TestMarshalDiff(t *testing.T) {
+	d := Diff{
+		Revision: 2,
+		Changes: []Change{
+			{Op: OpMove, Position: tile.Position{Tile: tile.Tile{ID: 4, Ch: 'D'}, X: 1, Y: 2}},
+		},
+	}
+	want := `{"revision":2,"changes":[{"op":3,"t":{"id":4,"ch":"D"},"x":1,"y":2}]}`
+	got, err := json.Marshal(d)
+	switch {
+	case err != nil:
+		t.Errorf("unwanted error: %v", err)
+	case want != string(got):
+		t.Errorf("not equal:\nwanted: %v\ngot:    %s", want, got)
+	}
+	var d2 Diff
+	if err := json.Unmarshal(got, &d2); err != nil || !reflect.DeepEqual(d, d2) {
+		t.Errorf("wanted diff to be unmarshalled back to %v, got %v (error: %v)", d, d2, err)
+	}
+}
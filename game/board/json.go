@@ -11,6 +11,7 @@ type jsonBoard struct {
 	Tiles         []tile.Tile     `json:"tiles,omitempty"`
 	TilePositions []tile.Position `json:"tilePositions,omitempty"`
 	Config        *Config         `json:"config,omitempty"`
+	Revision      int             `json:"revision,omitempty"`
 }
 
 // New creates a new board with the tiles as unusedTiles and tilePositions as used tiles.
@@ -30,6 +31,7 @@ func (b Board) MarshalJSON() ([]byte, error) {
 	jb := jsonBoard{
 		Tiles:         unusedTiles,
 		TilePositions: usedTiles,
+		Revision:      b.Revision,
 	}
 	if b.Config.NumRows != 0 || b.Config.NumCols != 0 { // do not marshal zero value
 		jb.Config = &b.Config
@@ -69,5 +71,6 @@ func (jb jsonBoard) Board() *Board {
 	if jb.Config != nil {
 		b.Config = *jb.Config
 	}
+	b.Revision = jb.Revision
 	return &b
 }
@@ -90,12 +90,13 @@ func TestMarshal(t *testing.T) {
 					},
 				},
 			},
+			Revision: 5,
 			Config: Config{
 				NumRows: 17,
 				NumCols: 22,
 			},
 		}
-		want := `{"tiles":[{"id":1,"ch":"A"}],"tilePositions":[{"t":{"id":2,"ch":"B"},"x":3,"y":4}],"config":{"r":17,"c":22}}`
+		want := `{"tiles":[{"id":1,"ch":"A"}],"tilePositions":[{"t":{"id":2,"ch":"B"},"x":3,"y":4}],"config":{"r":17,"c":22},"revision":5}`
 		got, err := json.Marshal(b)
 		switch {
 		case err != nil:
@@ -180,7 +181,7 @@ func TestUnmarshal(t *testing.T) {
 			j: `{"tiles":"NOT_AN_ARRAY"}`,
 		},
 		{
-			j:      `{"tiles":[{"id":1,"ch":"A"}],"tilePositions":[{"t":{"id":2,"ch":"B"},"x":3,"y":4}],"config":{"r":17,"c":22}}`,
+			j:      `{"tiles":[{"id":1,"ch":"A"}],"tilePositions":[{"t":{"id":2,"ch":"B"},"x":3,"y":4}],"config":{"r":17,"c":22},"revision":5}`,
 			wantOk: true,
 			want: Board{
 				UnusedTiles: map[tile.ID]tile.Tile{
@@ -210,6 +211,7 @@ func TestUnmarshal(t *testing.T) {
 						},
 					},
 				},
+				Revision: 5,
 				Config: Config{
 					NumRows: 17,
 					NumCols: 22,
@@ -0,0 +1,103 @@
+package board
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/jacobpatterson1549/selene-bananas/game/tile"
+)
+
+type (
+	// Report describes the words formed by the used tiles of a board and whether or not the tiles are in a single group.
+	Report struct {
+		// Words are the words on the board, from top to bottom and left to right.
+		Words []WordReport `json:"words,omitempty"`
+		// Groups are the ids of the tiles of each group of connected used tiles, largest first.
+		// Groups are only reported if the used tiles are not in a single group.
+		Groups [][]tile.ID `json:"groups,omitempty"`
+	}
+
+	// WordReport is a word formed by used tiles on a board.
+	WordReport struct {
+		// Word is the upper case letters of the word.
+		Word string `json:"word"`
+		// X is the column of the first letter of the word.
+		X tile.X `json:"x"`
+		// Y is the row of the first letter of the word.
+		Y tile.Y `json:"y"`
+		// Down is set if the word is read from top to bottom instead of from left to right.
+		Down bool `json:"down,omitempty"`
+		// Valid is set if the word can be used in the game.
+		Valid bool `json:"valid,omitempty"`
+	}
+)
+
+// Report creates a report of the words and groups of used tiles on the board.
+// The validWord function is called once for each word, in the order of the words in the report.
+func (b Board) Report(validWord func(word string) bool) Report {
+	var r Report
+	for _, tp := range b.sortedUsedTilesByPosition() {
+		x, y := int(tp.X), int(tp.Y)
+		for _, d := range []direction{across, down} {
+			if b.isUsed(x-d.dx, y-d.dy) || !b.isUsed(x+d.dx, y+d.dy) {
+				continue // not the first letter of a word
+			}
+			var sb strings.Builder
+			for x2, y2 := x, y; b.isUsed(x2, y2); x2, y2 = x2+d.dx, y2+d.dy {
+				sb.WriteRune(rune(b.UsedTileLocs[tile.X(x2)][tile.Y(y2)].Ch))
+			}
+			w := WordReport{
+				Word: sb.String(),
+				X:    tp.X,
+				Y:    tp.Y,
+				Down: d == down,
+			}
+			w.Valid = validWord(w.Word)
+			r.Words = append(r.Words, w)
+		}
+	}
+	if groups := b.UsedGroups(); len(groups) > 1 {
+		r.Groups = groups
+	}
+	return r
+}
+
+// sortedUsedTilesByPosition returns the used tiles from top to bottom and left to right.
+func (b Board) sortedUsedTilesByPosition() []tile.Position {
+	usedTiles := b.sortedUsedTileIDs()
+	sort.SliceStable(usedTiles, func(i, j int) bool {
+		a, b := usedTiles[i], usedTiles[j]
+		if a.Y != b.Y {
+			return a.Y < b.Y
+		}
+		return a.X < b.X
+	})
+	return usedTiles
+}
+
+// UsedGroups finds the ids of the tiles in each group of connected used tiles, largest first.
+// Groups of the same size are ordered by the position of their top-left tile and the ids in each group are sorted.
+func (b Board) UsedGroups() [][]tile.ID {
+	var groups [][]tile.ID
+	seenTileIDs := make(map[tile.ID]struct{}, len(b.UsedTiles))
+	for _, tp := range b.sortedUsedTilesByPosition() {
+		if _, ok := seenTileIDs[tp.Tile.ID]; ok {
+			continue
+		}
+		groupTileIDs := make(map[tile.ID]struct{})
+		b.addSeenTileIDs(int(tp.X), int(tp.Y), tp.Tile, groupTileIDs)
+		group := make([]tile.ID, 0, len(groupTileIDs))
+		for id := range groupTileIDs {
+			seenTileIDs[id] = struct{}{}
+			group = append(group, id)
+		}
+		sort.Slice(group, func(i, j int) bool {
+			return group[i] < group[j]
+		})
+		groups = append(groups, group)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return len(groups[i]) > len(groups[j])
+	})
+	return groups
+}
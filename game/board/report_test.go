@@ -0,0 +1,63 @@
+package board
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jacobpatterson1549/selene-bananas/game/tile"
+)
+
+func TestReport(t *testing.T) {
+	reportTests := []struct {
+		usedTiles []tile.Position
+		want      Report
+	}{
+		{}, // no used tiles
+		{ // single letter
+			usedTiles: []tile.Position{
+				{Tile: tile.Tile{Ch: 'A'}, X: 2, Y: 2},
+			},
+		},
+		{
+			usedTiles: []tile.Position{
+				{Tile: tile.Tile{Ch: 'C'}, X: 2, Y: 2},
+				{Tile: tile.Tile{Ch: 'A'}, X: 3, Y: 2},
+				{Tile: tile.Tile{Ch: 'T'}, X: 4, Y: 2},
+				{Tile: tile.Tile{Ch: 'X'}, X: 4, Y: 3},
+			},
+			want: Report{
+				Words: []WordReport{
+					{Word: "CAT", X: 2, Y: 2, Valid: true},
+					{Word: "TX", X: 4, Y: 2, Down: true},
+				},
+			},
+		},
+		{
+			usedTiles: []tile.Position{
+				{Tile: tile.Tile{Ch: 'D'}, X: 1, Y: 1},
+				{Tile: tile.Tile{Ch: 'O'}, X: 1, Y: 2},
+				{Tile: tile.Tile{Ch: 'G'}, X: 1, Y: 3},
+				{Tile: tile.Tile{Ch: 'Q'}, X: 5, Y: 5},
+				{Tile: tile.Tile{Ch: 'Z'}, X: 7, Y: 0},
+			},
+			want: Report{
+				Words: []WordReport{
+					{Word: "DOG", X: 1, Y: 1, Down: true, Valid: true},
+				},
+				Groups: [][]tile.ID{
+					{1, 2, 3},
+					{5},
+					{4},
+				},
+			},
+		},
+	}
+	for i, test := range reportTests {
+		b := testBoard(t, "", test.usedTiles...)
+		d := testDictionary{"cat", "dog"}
+		got := b.Report(d.Validate)
+		if !reflect.DeepEqual(test.want, got) {
+			t.Errorf("Test %v: reports not equal:\nwanted: %v\ngot:    %v", i, test.want, got)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package board
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/jacobpatterson1549/selene-bananas/game/tile"
+)
+
+type (
+	// Solver finds where to place unused tiles on boards to form new words.
+	// Words are only placed where they do not touch other used tiles except the one they cross, so the used tiles stay in a single group of valid words.
+	Solver struct {
+		// dictionary is used to check the words the solver places.
+		dictionary Dictionary
+		// words are the upper case words the solver knows.
+		words [][]rune
+		// letterCounts are the number of times each letter is in the words.
+		letterCounts map[tile.Letter]int
+		// prohibitDuplicates is a flag to not place words that are already on the board.
+		prohibitDuplicates bool
+	}
+
+	// SolverConfig is used to create solvers.
+	SolverConfig struct {
+		// VocabularySize is the maximum number of words the solver knows.  All of the words of the dictionary are known if this is not positive.
+		VocabularySize int
+		// MinLength is the length of the shortest word that can be placed.  Single letters are never placed.
+		MinLength int
+		// ProhibitDuplicates is a flag to not place words that are already on the board.
+		ProhibitDuplicates bool
+	}
+
+	// Dictionary contains the words that can be placed.
+	Dictionary interface {
+		// Validate determines if the word is valid.
+		Validate(word string) bool
+		// Words returns all the valid words.
+		Words() []string
+	}
+
+	// direction is the offset to the next letter of a word on the board.
+	direction struct {
+		dx, dy int
+	}
+)
+
+var (
+	// across words are read from left to right.
+	across = direction{dx: 1}
+	// down words are read from top to bottom.
+	down = direction{dy: 1}
+)
+
+// NewSolver creates a solver that knows words of the dictionary.
+// If the vocabulary is smaller than the dictionary, the known words are spread evenly through it.
+func (cfg SolverConfig) NewSolver(d Dictionary) *Solver {
+	allWords := d.Words()
+	numWords := len(allWords)
+	if cfg.VocabularySize > 0 && cfg.VocabularySize < numWords {
+		numWords = cfg.VocabularySize
+	}
+	minLength := 2
+	if cfg.MinLength > minLength {
+		minLength = cfg.MinLength
+	}
+	s := Solver{
+		dictionary:         d,
+		words:              make([][]rune, 0, numWords),
+		letterCounts:       make(map[tile.Letter]int),
+		prohibitDuplicates: cfg.ProhibitDuplicates,
+	}
+	for i := 0; i < numWords; i++ {
+		w := allWords[i*len(allWords)/numWords]
+		if len([]rune(w)) < minLength {
+			continue
+		}
+		runes := []rune(strings.ToUpper(w))
+		for _, r := range runes {
+			s.letterCounts[tile.Letter(r)]++
+		}
+		s.words = append(s.words, runes)
+	}
+	return &s
+}
+
+// Placement finds the positions to move unused tiles to in order to make the longest new word on the board.
+// If the board has no used tiles, the word is placed in the middle of it.  Otherwise, the word crosses a used tile.
+// Nil is returned if no word can be placed.
+func (s Solver) Placement(b Board) []tile.Position {
+	unusedTiles := unusedLetterTiles(b)
+	usedWords := make(map[string]struct{})
+	if s.prohibitDuplicates {
+		for _, w := range b.UsedTileWords() {
+			usedWords[w] = struct{}{}
+		}
+	}
+	anchors := b.sortedUsedTileIDs()
+	var best []tile.Position
+	for _, w := range s.words {
+		if len(w) <= len(best) {
+			continue
+		}
+		switch {
+		case len(anchors) == 0:
+			if positions := s.center(b, w, unusedTiles); positions != nil && s.canPlace(w, usedWords) {
+				best = positions
+			}
+		case missingLetters(w, unusedTiles) <= 1:
+			if positions := s.cross(b, w, anchors, unusedTiles); positions != nil && s.canPlace(w, usedWords) {
+				best = positions
+			}
+		}
+	}
+	return best
+}
+
+// canPlace determines if the word is valid and not already on the board when duplicates are prohibited.
+func (s Solver) canPlace(w []rune, usedWords map[string]struct{}) bool {
+	word := string(w)
+	if _, ok := usedWords[word]; ok {
+		return false
+	}
+	return s.dictionary.Validate(word)
+}
+
+// center places the word across the middle of an empty board using only unused tiles.
+func (s Solver) center(b Board, w []rune, unusedTiles map[tile.Letter][]tile.Tile) []tile.Position {
+	if len(w) > b.NumCols {
+		return nil
+	}
+	x0, y0 := (b.NumCols-len(w))/2, b.NumRows/2
+	taken := make(map[tile.Letter]int, len(w))
+	positions := make([]tile.Position, 0, len(w))
+	for i, r := range w {
+		t, ok := takeTile(tile.Letter(r), unusedTiles, taken)
+		if !ok {
+			return nil
+		}
+		tp := tile.Position{
+			Tile: t,
+			X:    tile.X(x0 + i),
+			Y:    tile.Y(y0),
+		}
+		positions = append(positions, tp)
+	}
+	return positions
+}
+
+// cross places the word through one of the anchor tiles using unused tiles for the other letters.
+// The other letters must not touch any used tiles so no other words are changed.
+func (s Solver) cross(b Board, w []rune, anchors []tile.Position, unusedTiles map[tile.Letter][]tile.Tile) []tile.Position {
+	for _, anchor := range anchors {
+		for i, r := range w {
+			if tile.Letter(r) != anchor.Tile.Ch {
+				continue
+			}
+			for _, d := range []direction{across, down} {
+				if positions := s.crossAt(b, w, i, anchor, d, unusedTiles); positions != nil {
+					return positions
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// crossAt places the word in the direction with the letter at the index on the anchor tile.
+func (s Solver) crossAt(b Board, w []rune, anchorIndex int, anchor tile.Position, d direction, unusedTiles map[tile.Letter][]tile.Tile) []tile.Position {
+	x0, y0 := int(anchor.X)-anchorIndex*d.dx, int(anchor.Y)-anchorIndex*d.dy
+	if b.isUsed(x0-d.dx, y0-d.dy) || b.isUsed(x0+len(w)*d.dx, y0+len(w)*d.dy) {
+		return nil
+	}
+	taken := make(map[tile.Letter]int, len(w))
+	positions := make([]tile.Position, 0, len(w)-1)
+	for i, r := range w {
+		if i == anchorIndex {
+			continue
+		}
+		x, y := x0+i*d.dx, y0+i*d.dy
+		switch {
+		case x < 0, y < 0, x >= b.NumCols, y >= b.NumRows,
+			b.isUsed(x, y), b.isUsed(x+d.dy, y+d.dx), b.isUsed(x-d.dy, y-d.dx):
+			return nil
+		}
+		t, ok := takeTile(tile.Letter(r), unusedTiles, taken)
+		if !ok {
+			return nil
+		}
+		tp := tile.Position{
+			Tile: t,
+			X:    tile.X(x),
+			Y:    tile.Y(y),
+		}
+		positions = append(positions, tp)
+	}
+	return positions
+}
+
+// SwapTile chooses the unused tile with the letter that is least common in the known words.
+// False is returned if the board has no unused tiles.
+func (s Solver) SwapTile(b Board) (tile.Tile, bool) {
+	var swapTile tile.Tile
+	ok := false
+	for _, id := range b.UnusedTileIDs {
+		t := b.UnusedTiles[id]
+		if !ok || s.letterCounts[t.Ch] < s.letterCounts[swapTile.Ch] {
+			swapTile, ok = t, true
+		}
+	}
+	return swapTile, ok
+}
+
+// unusedLetterTiles groups the unused tiles of the board by letter.
+func unusedLetterTiles(b Board) map[tile.Letter][]tile.Tile {
+	unusedTiles := make(map[tile.Letter][]tile.Tile, len(b.UnusedTiles))
+	for _, id := range b.UnusedTileIDs {
+		t := b.UnusedTiles[id]
+		unusedTiles[t.Ch] = append(unusedTiles[t.Ch], t)
+	}
+	return unusedTiles
+}
+
+// sortedUsedTileIDs returns a new array of the used tiles, sorted by id.
+func (b Board) sortedUsedTileIDs() []tile.Position {
+	usedTiles := make([]tile.Position, 0, len(b.UsedTiles))
+	for _, tp := range b.UsedTiles {
+		usedTiles = append(usedTiles, tp)
+	}
+	sort.Slice(usedTiles, func(i, j int) bool {
+		return usedTiles[i].Tile.ID < usedTiles[j].Tile.ID
+	})
+	return usedTiles
+}
+
+// missingLetters counts the letters of the word that are not in the unused tiles.
+func missingLetters(w []rune, unusedTiles map[tile.Letter][]tile.Tile) int {
+	taken := make(map[tile.Letter]int, len(w))
+	missing := 0
+	for _, r := range w {
+		if _, ok := takeTile(tile.Letter(r), unusedTiles, taken); !ok {
+			missing++
+		}
+	}
+	return missing
+}
+
+// takeTile gets the next unused tile for the letter that has not been taken.
+func takeTile(l tile.Letter, unusedTiles map[tile.Letter][]tile.Tile, taken map[tile.Letter]int) (tile.Tile, bool) {
+	tiles := unusedTiles[l]
+	n := taken[l]
+	if n >= len(tiles) {
+		return tile.Tile{}, false
+	}
+	taken[l]++
+	return tiles[n], true
+}
+
+// isUsed determines if there is a used tile at the location on the board.
+func (b Board) isUsed(x, y int) bool {
+	_, ok := b.UsedTileLocs[tile.X(x)][tile.Y(y)]
+	return ok
+}
@@ -0,0 +1,222 @@
+package board
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/jacobpatterson1549/selene-bananas/game/tile"
+)
+
+// testDictionary is a dictionary of the lower case words.
+type testDictionary []string
+
+func (d testDictionary) Validate(word string) bool {
+	lowerWord := strings.ToLower(word)
+	for _, w := range d {
+		if w == lowerWord {
+			return true
+		}
+	}
+	return false
+}
+
+func (d testDictionary) Words() []string {
+	words := append([]string{}, d...)
+	sort.Strings(words)
+	return words
+}
+
+// testBoard creates a board with unused tiles for the letters and used tiles at the positions.
+// Tile ids are the indexes of the letters, followed by the indexes of the used tiles.
+func testBoard(t *testing.T, unusedLetters string, usedTiles ...tile.Position) *Board {
+	t.Helper()
+	unusedTiles := make([]tile.Tile, 0, len(unusedLetters))
+	for i, r := range unusedLetters {
+		unusedTiles = append(unusedTiles, tile.Tile{ID: tile.ID(i + 1), Ch: tile.Letter(r)})
+	}
+	b := New(unusedTiles, nil)
+	b.Config = Config{NumRows: 10, NumCols: 10}
+	for i, tp := range usedTiles {
+		tp.Tile.ID = tile.ID(len(unusedLetters) + i + 1)
+		if err := b.AddTile(tp.Tile); err != nil {
+			t.Fatalf("adding used tile: %v", err)
+		}
+		if err := b.MoveTiles(map[tile.ID]tile.Position{tp.Tile.ID: tp}); err != nil {
+			t.Fatalf("moving used tile: %v", err)
+		}
+	}
+	return b
+}
+
+// placedWords gets the words on the board after the tiles are moved to the positions.
+func placedWords(t *testing.T, b *Board, positions []tile.Position) []string {
+	t.Helper()
+	tilePositions := make(map[tile.ID]tile.Position, len(positions))
+	for _, tp := range positions {
+		tilePositions[tp.Tile.ID] = tp
+	}
+	if err := b.MoveTiles(tilePositions); err != nil {
+		t.Fatalf("moving tiles: %v", err)
+	}
+	words := b.UsedTileWords()
+	sort.Strings(words)
+	return words
+}
+
+func TestNewSolver(t *testing.T) {
+	d := testDictionary{"a", "at", "bat", "cat", "rat", "tab"}
+	newSolverTests := []struct {
+		SolverConfig
+		want [][]rune
+	}{
+		{
+			want: [][]rune{[]rune("AT"), []rune("BAT"), []rune("CAT"), []rune("RAT"), []rune("TAB")},
+		},
+		{
+			SolverConfig: SolverConfig{VocabularySize: 100},
+			want:         [][]rune{[]rune("AT"), []rune("BAT"), []rune("CAT"), []rune("RAT"), []rune("TAB")},
+		},
+		{
+			SolverConfig: SolverConfig{VocabularySize: 3},
+			want:         [][]rune{[]rune("BAT"), []rune("RAT")},
+		},
+		{
+			SolverConfig: SolverConfig{MinLength: 3},
+			want:         [][]rune{[]rune("BAT"), []rune("CAT"), []rune("RAT"), []rune("TAB")},
+		},
+	}
+	for i, test := range newSolverTests {
+		s := test.SolverConfig.NewSolver(d)
+		if !reflect.DeepEqual(test.want, s.words) {
+			t.Errorf("Test %v: words not equal:\nwanted: %q\ngot:    %q", i, test.want, s.words)
+		}
+	}
+}
+
+func TestPlacement(t *testing.T) {
+	placementTests := []struct {
+		name          string
+		words         testDictionary
+		unusedLetters string
+		usedTiles     []tile.Position
+		SolverConfig
+		wantWords []string
+	}{
+		{
+			name:          "no words",
+			words:         testDictionary{"xyz"},
+			unusedLetters: "CAT",
+		},
+		{
+			name:          "longest first word",
+			words:         testDictionary{"at", "cat", "cats", "tacos"},
+			unusedLetters: "TACSQ",
+			wantWords:     []string{"CATS"},
+		},
+		{
+			name:          "cross used tile",
+			words:         testDictionary{"cat", "tab"},
+			unusedLetters: "AB",
+			usedTiles: []tile.Position{
+				{Tile: tile.Tile{Ch: 'C'}, X: 4, Y: 5},
+				{Tile: tile.Tile{Ch: 'A'}, X: 5, Y: 5},
+				{Tile: tile.Tile{Ch: 'T'}, X: 6, Y: 5},
+			},
+			wantWords: []string{"CAT", "TAB"},
+		},
+		{
+			name:          "no room to cross",
+			words:         testDictionary{"cat", "tab"},
+			unusedLetters: "AB",
+			usedTiles: []tile.Position{
+				{Tile: tile.Tile{Ch: 'C'}, X: 7, Y: 9},
+				{Tile: tile.Tile{Ch: 'A'}, X: 8, Y: 9},
+				{Tile: tile.Tile{Ch: 'T'}, X: 9, Y: 9},
+			},
+		},
+		{
+			name:          "prohibit duplicates",
+			words:         testDictionary{"cat"},
+			unusedLetters: "CT",
+			usedTiles: []tile.Position{
+				{Tile: tile.Tile{Ch: 'C'}, X: 4, Y: 5},
+				{Tile: tile.Tile{Ch: 'A'}, X: 5, Y: 5},
+				{Tile: tile.Tile{Ch: 'T'}, X: 6, Y: 5},
+			},
+			SolverConfig: SolverConfig{ProhibitDuplicates: true},
+		},
+		{
+			name:          "allow duplicates",
+			words:         testDictionary{"cat"},
+			unusedLetters: "CT",
+			usedTiles: []tile.Position{
+				{Tile: tile.Tile{Ch: 'C'}, X: 4, Y: 5},
+				{Tile: tile.Tile{Ch: 'A'}, X: 5, Y: 5},
+				{Tile: tile.Tile{Ch: 'T'}, X: 6, Y: 5},
+			},
+			wantWords: []string{"CAT", "CAT"},
+		},
+		{
+			name:          "multibyte letters",
+			words:         testDictionary{"año"},
+			unusedLetters: "OÑA",
+			wantWords:     []string{"AÑO"},
+		},
+	}
+	for _, test := range placementTests {
+		s := test.SolverConfig.NewSolver(test.words)
+		b := testBoard(t, test.unusedLetters, test.usedTiles...)
+		positions := s.Placement(*b)
+		switch {
+		case len(test.wantWords) == 0:
+			if positions != nil {
+				t.Errorf("Test %v: wanted no placement, got %v", test.name, positions)
+			}
+		default:
+			if got := placedWords(t, b, positions); !reflect.DeepEqual(test.wantWords, got) {
+				t.Errorf("Test %v: words not equal after placement:\nwanted: %v\ngot:    %v", test.name, test.wantWords, got)
+			}
+			if !b.HasSingleUsedGroup() {
+				t.Errorf("Test %v: wanted tiles to be placed in a single group", test.name)
+			}
+		}
+	}
+}
+
+func TestSwapTile(t *testing.T) {
+	s := SolverConfig{}.NewSolver(testDictionary{"cat", "act", "tax"})
+	swapTileTests := []struct {
+		unusedLetters string
+		wantOk        bool
+		want          tile.Letter
+	}{
+		{},
+		{
+			unusedLetters: "TA",
+			wantOk:        true,
+			want:          'T',
+		},
+		{
+			unusedLetters: "AXC",
+			wantOk:        true,
+			want:          'X',
+		},
+		{
+			unusedLetters: "AQX",
+			wantOk:        true,
+			want:          'Q',
+		},
+	}
+	for i, test := range swapTileTests {
+		b := testBoard(t, test.unusedLetters)
+		got, ok := s.SwapTile(*b)
+		switch {
+		case test.wantOk != ok:
+			t.Errorf("Test %v: wanted ok to be %v, got %v", i, test.wantOk, ok)
+		case test.wantOk && test.want != got.Ch:
+			t.Errorf("Test %v: wanted %v to be swapped, got %v", i, string(test.want), string(got.Ch))
+		}
+	}
+}
@@ -17,19 +17,62 @@ type (
 		ProhibitDuplicates bool `json:"prohibitDuplicates,omitempty"`
 		// MinLength is the minimum allowed word length for each word on the board.
 		MinLength int `json:"minLength,omitempty"`
+		// Scoring is the scheme used to award points to players when the game is finished.
+		Scoring Scoring `json:"scoring,omitempty"`
+		// TimeLimitSec is the number of seconds the game can be played for after it is started.  Games are not timed if this is not positive.
+		TimeLimitSec int `json:"timeLimitSec,omitempty"`
+		// Language determines the words that are valid and the default tiles of the game.  Games are in English if this is empty.
+		Language Language `json:"language,omitempty"`
+		// Hints is a flag that allows players to ask where they could place their unused tiles to form a new word.
+		Hints bool `json:"hints,omitempty"`
+		// PenalizeHints is a flag to decrement a player's points each time they get a hint.
+		PenalizeHints bool `json:"penalizeHints,omitempty"`
+		// InviteCode is the secret that players must know to join or watch the game.  Games are public if this is empty.
+		InviteCode string `json:"inviteCode,omitempty"`
+		// AutoStart is a flag to start the game when every player is ready or no more players can join it.
+		AutoStart bool `json:"autoStart,omitempty"`
+		// TeamSize is the number of players on each team.  Teammates share a board.  Players do not play on teams if this is less than two.
+		TeamSize int `json:"teamSize,omitempty"`
+		// TileBag is the set of tiles that the game is played with.
+		TileBag TileBag `json:"tileBag,omitempty"`
+		// LetterCounts are the number of tiles of each letter in a custom tile bag, keyed by letter.
+		LetterCounts map[string]int `json:"letterCounts,omitempty"`
+		// NumNewTiles is the number of tiles each player starts the game with.  The default of the server is used if this is not positive.
+		NumNewTiles int `json:"numNewTiles,omitempty"`
+		// SwapRatio is the number of tiles a player gets for each tile they swap.  The default ratio is used if this is not positive.
+		SwapRatio int `json:"swapRatio,omitempty"`
+		// SwapLimit is the number of times each player can swap a tile.  Swaps are not limited if this is not positive.
+		SwapLimit int `json:"swapLimit,omitempty"`
+		// SwapCooldownSec is the number of seconds a player must wait after swapping a tile to swap another.
+		SwapCooldownSec int `json:"swapCooldownSec,omitempty"`
+		// SwapUsedTiles is a flag that allows players to swap tiles that are used in words on their boards.
+		SwapUsedTiles bool `json:"swapUsedTiles,omitempty"`
+		// StrictPeel is a flag for games where players can only snag when their boards are valid and every player gets a tile at the same time.
+		// When there are not enough tiles for every player to get one, the first player to finish wins the Bananas race.
+		StrictPeel bool `json:"strictPeel,omitempty"`
+		// ChallengeSec is the number of seconds the other players have to challenge the words of a player who claims to win.  Wins are not challenged if this is not positive.
+		ChallengeSec int `json:"challengeSec,omitempty"`
 	}
 )
 
+const (
+	// DefaultSwapRatio is the number of tiles players get for each tile they swap in games that do not choose a swap ratio.
+	DefaultSwapRatio = 3
+	// MaxSwapRatio is the most tiles that players can get for each tile they swap.
+	MaxSwapRatio = 5
+)
+
 // Rules gets the rules for the game.  Extra rules are added for customized configurations.
 func (cfg Config) Rules() []string {
+	language := cfg.Language.String()
 	rules := []string{
 		"Create or join a game from the Lobby after refreshing the games list.",
 		"Any player can join a game that is not started, but active games can only be joined by players who started in them.",
-		"After all players have joined the game, click the Start button to start the game.",
-		"Arrange unused tiles in the game area form vertical and horizontal English words.",
+		"After all players have joined the game, the host clicks the Start button to start the game.",
+		"Arrange unused tiles in the game area form vertical and horizontal " + language + " words.",
 		"Click the Snag button to get a new tile if all tiles are used in words. This also gives other players a new tile.",
-		"Click the Swap button and then a tile to exchange it for three others.",
-		"Click the Finish button to run the scoring function when there are no tiles left to use.  The scoring function determines if all of the player's tiles are used and form a continuous block of English words.  If successful, the player wins. Otherwise, the player's potential winning score is decremented and play continues.",
+		"Click the Swap button and then an unused tile to exchange it for three others.",
+		"Click the Finish button to run the scoring function when there are no tiles left to use.  The scoring function determines if all of the player's tiles are used and form a continuous block of " + language + " words.  If successful, the player wins. Otherwise, the player's potential winning score is decremented and play continues.",
 	}
 	if cfg.CheckOnSnag {
 		rules = append(rules, "Words are checked to be valid when a player tries to snag a new letter.")
@@ -43,5 +86,85 @@ func (cfg Config) Rules() []string {
 	if cfg.ProhibitDuplicates {
 		rules = append(rules, "Duplicate words are prohibited.")
 	}
+	if cfg.Hints {
+		rules = append(rules, "Click the Hint button to see where unused tiles could be placed to form a new word.")
+		if cfg.PenalizeHints {
+			rules = append(rules, "The amount of potential win points is decremented each time a player gets a hint.")
+		}
+	}
+	if cfg.TimeLimitSec > 0 {
+		rules = append(rules, "The game is timed, ending "+FormatSeconds(cfg.TimeLimitSec)+" (minutes:seconds) after it is started.  When time runs out, the player who used the most tiles in a single group of valid words wins.")
+	}
+	if cfg.AutoStart {
+		rules = append(rules, "The game starts automatically when every player has clicked the Ready button or the game is full.")
+	}
+	if cfg.TeamSize > 1 {
+		rules = append(rules, "Players are put on teams of "+strconv.Itoa(cfg.TeamSize)+" in the order they join.  Teammates share a board, so any of them can move its tiles, snag, or finish for the team.  Snagging gives each team a tile, and the points of each team are split between its players.")
+	}
+	switch cfg.TileBag {
+	case BananaSplitTileBag:
+		rules = append(rules, "The game is played with the Banana Split tile bag, which has half of the classic tiles.")
+	case DoubleTileBag:
+		rules = append(rules, "The game is played with a double tile bag, which has two of each classic tile.")
+	case CustomTileBag:
+		numTiles := 0
+		for _, n := range cfg.LetterCounts {
+			numTiles += n
+		}
+		rules = append(rules, "The game is played with a custom tile bag of "+strconv.Itoa(numTiles)+" tiles.")
+	}
+	if cfg.NumNewTiles > 0 {
+		rules = append(rules, "Each player starts the game with "+strconv.Itoa(cfg.NumNewTiles)+" tiles.")
+	}
+	if swapTiles := cfg.SwapTiles(); swapTiles != DefaultSwapRatio {
+		rules = append(rules, "Each swapped tile is exchanged for "+strconv.Itoa(swapTiles)+" tiles from the pile instead of three.")
+	}
+	if cfg.SwapLimit > 0 {
+		rules = append(rules, "Each player can swap at most "+strconv.Itoa(cfg.SwapLimit)+" tiles.")
+	}
+	if cfg.SwapCooldownSec > 0 {
+		rules = append(rules, "Players must wait "+FormatSeconds(cfg.SwapCooldownSec)+" (minutes:seconds) after swapping a tile to swap another.")
+	}
+	if cfg.SwapUsedTiles {
+		rules = append(rules, "Tiles that are used in words can also be swapped straight off the board.")
+	}
+	if cfg.StrictPeel {
+		rules = append(rules, "Peel mode: players can only snag when all of their tiles are used in valid words, and every player gets a tile at the same time.  When there are not enough tiles left for everyone to get one, the peel ends the round with a Bananas race: the first player to finish with a valid board wins.")
+	}
+	if cfg.ChallengeSec > 0 {
+		rules = append(rules, "When a player finishes, the other players have "+FormatSeconds(cfg.ChallengeSec)+" (minutes:seconds) to look at the board of the player on the 'Final Boards' tab and challenge its words.  If most of them challenge a word, the player gets a rotten banana: they are out of the game, their tiles go back to the pile, and play continues.")
+	}
+	if cfg.Private() {
+		rules = append(rules, "The game is private.  It is only shown in the lobbies of its players, so share the invite link of the game to let others join.")
+	}
+	if scorer, err := cfg.Scoring.Scorer(); err == nil {
+		rules = append(rules, scorer.Rule())
+	}
 	return rules
 }
+
+// SwapTiles is the number of tiles a player gets for each tile they swap.
+func (cfg Config) SwapTiles() int {
+	if cfg.SwapRatio <= 0 {
+		return DefaultSwapRatio
+	}
+	return cfg.SwapRatio
+}
+
+// Private determines if the game can only be joined by players who know its invite code.
+func (cfg Config) Private() bool {
+	return len(cfg.InviteCode) != 0
+}
+
+// FormatSeconds formats the number of seconds as minutes and seconds, such as "2:05".
+func FormatSeconds(seconds int) string {
+	if seconds < 0 {
+		seconds = 0
+	}
+	s := seconds % 60
+	text := strconv.Itoa(seconds/60) + ":"
+	if s < 10 {
+		text += "0"
+	}
+	return text + strconv.Itoa(s)
+}
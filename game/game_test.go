@@ -1,6 +1,7 @@
 package game
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -32,6 +33,52 @@ func TestConfigRules(t *testing.T) {
 			{
 				ProhibitDuplicates: true,
 			},
+			{
+				TimeLimitSec: 300,
+			},
+			{
+				Hints: true,
+			},
+			{
+				InviteCode: "b4n4n4",
+			},
+			{
+				AutoStart: true,
+			},
+			{
+				TeamSize: 2,
+			},
+			{
+				TileBag: BananaSplitTileBag,
+			},
+			{
+				TileBag: DoubleTileBag,
+			},
+			{
+				TileBag:      CustomTileBag,
+				LetterCounts: map[string]int{"A": 10},
+			},
+			{
+				NumNewTiles: 15,
+			},
+			{
+				SwapRatio: 2,
+			},
+			{
+				SwapLimit: 4,
+			},
+			{
+				SwapCooldownSec: 30,
+			},
+			{
+				SwapUsedTiles: true,
+			},
+			{
+				StrictPeel: true,
+			},
+			{
+				ChallengeSec: 30,
+			},
 		}
 		differentRules := make(map[string]struct{}, len(singleChangeConfigs))
 		for i, cfg := range singleChangeConfigs {
@@ -54,6 +101,54 @@ func TestConfigRules(t *testing.T) {
 			}
 		}
 	})
+	t.Run("testPenalizeHints", func(t *testing.T) {
+		hintsRules := Config{Hints: true}.Rules()
+		penalizedHintsRules := Config{Hints: true, PenalizeHints: true}.Rules()
+		unusedPenalizedHintsRules := Config{PenalizeHints: true}.Rules()
+		var defaultConfig Config
+		switch {
+		case len(hintsRules)+1 != len(penalizedHintsRules):
+			t.Errorf("wanted extra rule when hints are penalized")
+		case len(defaultConfig.Rules()) != len(unusedPenalizedHintsRules):
+			t.Errorf("wanted no hint rules when hints are not allowed")
+		}
+	})
+	t.Run("testScoring", func(t *testing.T) {
+		var defaultConfig Config
+		defaultRules := defaultConfig.Rules()
+		scorings := []Scoring{LetterValueScoring, WordLengthScoring, RankScoring}
+		for i, s := range scorings {
+			cfg := Config{
+				Scoring: s,
+			}
+			rules := cfg.Rules()
+			switch {
+			case len(defaultRules) != len(rules):
+				t.Errorf("Test %v: wanted %v rules, got %v", i, len(defaultRules), len(rules))
+			case defaultRules[len(defaultRules)-1] == rules[len(rules)-1]:
+				t.Errorf("Test %v: wanted last rule to describe %v scoring, got '%v'", i, s, rules[len(rules)-1])
+			}
+		}
+	})
+	t.Run("testLanguage", func(t *testing.T) {
+		var defaultConfig Config
+		defaultRules := defaultConfig.Rules()
+		englishConfig := Config{
+			Language: English,
+		}
+		if want, got := defaultRules, englishConfig.Rules(); !reflect.DeepEqual(want, got) {
+			t.Errorf("wanted default rules to be in English:\nwanted: %v\ngot:    %v", want, got)
+		}
+		germanConfig := Config{
+			Language: German,
+		}
+		for _, r := range germanConfig.Rules() {
+			if strings.Contains(r, "German words") {
+				return
+			}
+		}
+		t.Errorf("language not included in rules")
+	})
 	t.Run("TestMinLengthNumber", func(t *testing.T) {
 		cfg := Config{
 			MinLength: 1337,
@@ -67,3 +162,42 @@ func TestConfigRules(t *testing.T) {
 		t.Errorf("abnormal minlength not included in rules")
 	})
 }
+
+func TestConfigSwapTiles(t *testing.T) {
+	swapTilesTests := []struct {
+		swapRatio int
+		want      int
+	}{
+		{0, DefaultSwapRatio},
+		{-2, DefaultSwapRatio},
+		{1, 1},
+		{5, 5},
+	}
+	for i, test := range swapTilesTests {
+		cfg := Config{
+			SwapRatio: test.swapRatio,
+		}
+		if got := cfg.SwapTiles(); test.want != got {
+			t.Errorf("Test %v: wanted %v tiles for each swapped tile, got %v", i, test.want, got)
+		}
+	}
+}
+
+func TestFormatSeconds(t *testing.T) {
+	formatSecondsTests := []struct {
+		seconds int
+		want    string
+	}{
+		{-5, "0:00"},
+		{0, "0:00"},
+		{9, "0:09"},
+		{60, "1:00"},
+		{125, "2:05"},
+		{3600, "60:00"},
+	}
+	for i, test := range formatSecondsTests {
+		if want, got := test.want, FormatSeconds(test.seconds); want != got {
+			t.Errorf("Test %v: wanted %v, got %v", i, want, got)
+		}
+	}
+}
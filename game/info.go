@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/jacobpatterson1549/selene-bananas/game/board"
+	"github.com/jacobpatterson1549/selene-bananas/game/player"
 )
 
 // Info contains information about a game.
@@ -27,6 +28,36 @@ type Info struct {
 	FinalBoards map[string]board.Board `json:"finalBoards,omitempty"`
 	// Capacity is the maximum number of players that can play the game
 	Capacity int `json:"capacity,omitempty"`
+	// PlayerBoards are the boards of all of the players, sent to spectators who are watching the game.
+	PlayerBoards map[string]board.Board `json:"playerBoards,omitempty"`
+	// PlayerPoints are the total points the users of the players have earned across all games.
+	PlayerPoints map[string]int `json:"playerPoints,omitempty"`
+	// SecondsLeft is the amount of time left before a timed game that is in progress ends.
+	SecondsLeft int `json:"secondsLeft,omitempty"`
+	// BotDifficulty is how well a bot that is requested to be added to the game should play.
+	BotDifficulty player.Difficulty `json:"botDifficulty,omitempty"`
+	// Report describes the words and groups of tiles on the board of the player.
+	Report *board.Report `json:"report,omitempty"`
+	// Diff is the changes to the board of the player.  Diffs are sent instead of whole boards when tiles are moved, swapped, or added.
+	Diff *board.Diff `json:"diff,omitempty"`
+	// Private is a flag for games that are only shown to the players in them.
+	Private bool `json:"private,omitempty"`
+	// InviteCode is sent by players to join or watch private games.
+	InviteCode string `json:"inviteCode,omitempty"`
+	// Host is the name of the player who can start and delete the game and kick other players from it.
+	Host string `json:"host,omitempty"`
+	// ReadyPlayers are the names of the players who are ready for the game to start.
+	ReadyPlayers []string `json:"readyPlayers,omitempty"`
+	// ForfeitedPlayers are the names of the players who gave up the game.  Their boards do not change.
+	ForfeitedPlayers []string `json:"forfeitedPlayers,omitempty"`
+	// Teams are the numbers of the teams of the players, keyed by player name.  Players are only on teams in games that are played in teams.
+	Teams map[string]int `json:"teams,omitempty"`
+	// BananasRace is a flag for strict peel games that have too few tiles left for every player to get one.  The first player to finish wins.
+	BananasRace bool `json:"bananasRace,omitempty"`
+	// Claimant is the name of the player who claimed to win a game that is being challenged.
+	Claimant string `json:"claimant,omitempty"`
+	// ChallengedWords are the words on the board of the claimant that a player challenges or that have been challenged.
+	ChallengedWords []string `json:"challengedWords,omitempty"`
 }
 
 // CanJoin indicates whether or not a player can join the game.
@@ -36,6 +67,18 @@ func (i Info) CanJoin(playerName string) bool {
 		(i.Status == NotStarted && len(i.Players) < i.Capacity)
 }
 
+// CanSpectate indicates whether or not a player can watch the game without playing in it.
+// Only games that are in progress can be watched by players who are not part of them.
+func (i Info) CanSpectate(playerName string) bool {
+	return i.Status == InProgress && !i.isInGame(playerName)
+}
+
+// IsVisibleTo indicates whether or not the game should be shown to the player in the lobby.
+// Private games are only shown to the players in them.
+func (i Info) IsVisibleTo(playerName string) bool {
+	return !i.Private || i.isInGame(playerName)
+}
+
 // isInGame determines if a player with the specified game is in the players slice.
 func (i Info) isInGame(playerName string) bool {
 	return slices.Contains(i.Players, playerName)
@@ -45,3 +88,25 @@ func (i Info) isInGame(playerName string) bool {
 func (i Info) CapacityRatio() string {
 	return strconv.Itoa(len(i.Players)) + "/" + strconv.Itoa(i.Capacity)
 }
+
+// PlayerLabels creates the names of the players to display, including the points of players whose points are known and the teams of players on teams.
+// Players who are ready for the game to start are marked with a check.  Players who forfeited the game are marked with a flag.
+func (i Info) PlayerLabels() []string {
+	labels := make([]string, len(i.Players))
+	for j, pn := range i.Players {
+		labels[j] = pn
+		if points, ok := i.PlayerPoints[pn]; ok {
+			labels[j] += " (" + strconv.Itoa(points) + ")"
+		}
+		if team, ok := i.Teams[pn]; ok {
+			labels[j] += " [team " + strconv.Itoa(team) + "]"
+		}
+		if slices.Contains(i.ReadyPlayers, pn) {
+			labels[j] += " ✓"
+		}
+		if slices.Contains(i.ForfeitedPlayers, pn) {
+			labels[j] += " 🏳"
+		}
+	}
+	return labels
+}
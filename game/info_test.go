@@ -1,6 +1,9 @@
 package game
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
 func TestInfoCanJoin(t *testing.T) {
 	canJoinTests := []struct {
@@ -64,6 +67,90 @@ func TestInfoCanJoin(t *testing.T) {
 	}
 }
 
+func TestInfoCanSpectate(t *testing.T) {
+	canSpectateTests := []struct {
+		info       Info
+		playerName string
+		want       bool
+	}{
+		{},
+		{
+			info: Info{
+				Status: NotStarted,
+			},
+			playerName: "fred",
+		},
+		{
+			info: Info{
+				Status:  InProgress,
+				Players: []string{"selene"},
+			},
+			playerName: "fred",
+			want:       true,
+		},
+		{
+			info: Info{
+				Status:  InProgress,
+				Players: []string{"fred", "selene"},
+			},
+			playerName: "selene",
+		},
+		{
+			info: Info{
+				Status:  Finished,
+				Players: []string{"selene"},
+			},
+			playerName: "fred",
+		},
+	}
+	for i, test := range canSpectateTests {
+		got := test.info.CanSpectate(test.playerName)
+		if test.want != got {
+			t.Errorf("Test %v: wanted CanSpectate() = %v, got %v when info is %v", i, test.want, got, test.info)
+		}
+	}
+}
+
+func TestInfoIsVisibleTo(t *testing.T) {
+	isVisibleToTests := []struct {
+		info       Info
+		playerName string
+		want       bool
+	}{
+		{
+			want: true,
+		},
+		{
+			info: Info{
+				Players: []string{"selene"},
+			},
+			playerName: "fred",
+			want:       true,
+		},
+		{
+			info: Info{
+				Private: true,
+				Players: []string{"selene"},
+			},
+			playerName: "fred",
+		},
+		{
+			info: Info{
+				Private: true,
+				Players: []string{"fred", "selene"},
+			},
+			playerName: "selene",
+			want:       true,
+		},
+	}
+	for i, test := range isVisibleToTests {
+		got := test.info.IsVisibleTo(test.playerName)
+		if test.want != got {
+			t.Errorf("Test %v: wanted IsVisibleTo() = %v, got %v when info is %v", i, test.want, got, test.info)
+		}
+	}
+}
+
 func TestCapacityRatio(t *testing.T) {
 	capacityRatioTests := []struct {
 		capacity int
@@ -108,3 +195,71 @@ func TestCapacityRatio(t *testing.T) {
 		}
 	}
 }
+
+func TestPlayerLabels(t *testing.T) {
+	playerLabelsTests := []struct {
+		Info
+		want []string
+	}{
+		{
+			want: []string{},
+		},
+		{
+			Info: Info{
+				Players: []string{"barney", "fred", "selene"},
+			},
+			want: []string{"barney", "fred", "selene"},
+		},
+		{
+			Info: Info{
+				Players: []string{"barney", "fred", "selene"},
+				PlayerPoints: map[string]int{
+					"fred":   0,
+					"selene": 17,
+				},
+			},
+			want: []string{"barney", "fred (0)", "selene (17)"},
+		},
+		{
+			Info: Info{
+				Players: []string{"barney", "fred", "selene"},
+				PlayerPoints: map[string]int{
+					"selene": 17,
+				},
+				ReadyPlayers: []string{"barney", "selene"},
+			},
+			want: []string{"barney ✓", "fred", "selene (17) ✓"},
+		},
+		{
+			Info: Info{
+				Players: []string{"barney", "fred"},
+				PlayerPoints: map[string]int{
+					"fred": 3,
+				},
+				ForfeitedPlayers: []string{"fred"},
+			},
+			want: []string{"barney", "fred (3) 🏳"},
+		},
+		{
+			Info: Info{
+				Players: []string{"barney", "fred", "wilma"},
+				PlayerPoints: map[string]int{
+					"fred": 3,
+				},
+				ReadyPlayers: []string{"fred"},
+				Teams: map[string]int{
+					"barney": 1,
+					"fred":   2,
+					"wilma":  1,
+				},
+			},
+			want: []string{"barney [team 1]", "fred (3) [team 2] ✓", "wilma [team 1]"},
+		},
+	}
+	for i, test := range playerLabelsTests {
+		got := test.Info.PlayerLabels()
+		if !reflect.DeepEqual(test.want, got) {
+			t.Errorf("Test %v: player labels not equal:\nwanted: %v\ngot:    %v", i, test.want, got)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package game
+
+import "errors"
+
+// Language identifies the words and tiles used in a game.
+type Language string
+
+const (
+	// English games use the English alphabet.  Games are in English if the language is not specified.
+	English Language = "en"
+	// Spanish games include Ñ tiles.
+	Spanish Language = "es"
+	// German games include Ä, Ö, and Ü tiles.
+	German Language = "de"
+)
+
+// Languages are the languages games can be played in.
+var Languages = []Language{English, Spanish, German}
+
+// tileLetters are the default distributions of the 144 tiles for each language.
+var tileLetters = map[Language]string{
+	English: "AAAAAAAAAAAAABBBCCCDDDDDDEEEEEEEEEEEEEEEEEEFFFGGGGHHHIIIIIIIIIIIIJJKKLLLLLMMMNNNNNNNNOOOOOOOOOOOPPPQQRRRRRRRRRSSSSSSTTTTTTTTTUUUUUUVVVWWWXXYYYZZ",
+	Spanish: "AAAAAAAAAAAAAAAAAABBBCCCCCCDDDDDDDEEEEEEEEEEEEEEEEEEFFGGGHHHIIIIIIIIIJJKLLLLLLMMMNNNNNNNÑÑOOOOOOOOOOOOPPPQQRRRRRRRSSSSSSSSSTTTTTTUUUUUUUVVWXYYZZ",
+	German:  "AAAAAAAÄÄBBBCCCDDDDDDEEEEEEEEEEEEEEEEEEEEEFFFGGGGHHHHHHIIIIIIIIIJKKKLLLLMMMMMMNNNNNNNNNNNNOOOOÖÖPPQRRRRRRRRRSSSSSSSSSTTTTTTTTTUUUUUUUUUÜÜVWWXYZZ",
+}
+
+// OrDefault returns the language, or English if the language is not specified.
+func (l Language) OrDefault() Language {
+	if len(l) == 0 {
+		return English
+	}
+	return l
+}
+
+// TileLetters gets the default tile letters for games in the language.
+func (l Language) TileLetters() (string, error) {
+	letters, ok := tileLetters[l.OrDefault()]
+	if !ok {
+		return "", errors.New("unknown language: " + string(l))
+	}
+	return letters, nil
+}
+
+// String returns the display value for the language.
+func (l Language) String() string {
+	switch l.OrDefault() {
+	case English:
+		return "English"
+	case Spanish:
+		return "Spanish"
+	case German:
+		return "German"
+	}
+	return "?"
+}
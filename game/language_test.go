@@ -0,0 +1,40 @@
+package game
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestLanguageTileLetters(t *testing.T) {
+	for i, l := range Languages {
+		letters, err := l.TileLetters()
+		switch {
+		case err != nil:
+			t.Errorf("Test %v: unwanted error for %v: %v", i, l, err)
+		case utf8.RuneCountInString(letters) != 144:
+			t.Errorf("Test %v: wanted 144 %v tiles, got %v", i, l, utf8.RuneCountInString(letters))
+		case l.String() == "?":
+			t.Errorf("Test %v: wanted display value for %v", i, l)
+		}
+	}
+	t.Run("default", func(t *testing.T) {
+		var l Language
+		want, err := English.TileLetters()
+		if err != nil {
+			t.Fatalf("unwanted error: %v", err)
+		}
+		got, err := l.TileLetters()
+		switch {
+		case err != nil:
+			t.Errorf("unwanted error: %v", err)
+		case want != got:
+			t.Errorf("wanted English tile letters when language is empty, got %v", got)
+		}
+	})
+	t.Run("unknown", func(t *testing.T) {
+		l := Language("xx")
+		if _, err := l.TileLetters(); err == nil {
+			t.Error("wanted error for unknown language")
+		}
+	})
+}
@@ -0,0 +1,674 @@
+package message
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/jacobpatterson1549/selene-bananas/game"
+	"github.com/jacobpatterson1549/selene-bananas/game/board"
+	"github.com/jacobpatterson1549/selene-bananas/game/player"
+	"github.com/jacobpatterson1549/selene-bananas/game/tile"
+)
+
+type (
+	// binaryWriter appends values to a byte slice in a compact binary form.
+	binaryWriter struct {
+		buf []byte
+		err error
+	}
+
+	// binaryReader reads values that were written by a binaryWriter.
+	// After the first error, reads return zero values and the error is kept.
+	binaryReader struct {
+		buf []byte
+		err error
+	}
+)
+
+// BinarySubprotocol is the websocket subprotocol for connections that send messages encoded with MarshalBinary.
+// Messages are sent as json on connections without a subprotocol.
+const BinarySubprotocol = "selene-bananas.binary.v1"
+
+// binaryVersion is the first byte of binary messages, changed when the encoding changes.
+const binaryVersion = 1
+
+// flags of the optional fields of messages that are encoded.
+const (
+	messageInfo = 1 << iota
+	messageCode
+	messageDetails
+	messageGame
+	messageGames
+	messageReplay
+)
+
+// flags of the optional fields of game infos that are encoded.
+const (
+	infoID = 1 << iota
+	infoStatus
+	infoBoard
+	infoTilesLeft
+	infoPlayers
+	infoCreatedAt
+	infoConfig
+	infoFinalBoards
+	infoCapacity
+	infoPlayerBoards
+	infoPlayerPoints
+	infoSecondsLeft
+	infoBotDifficulty
+	infoReport
+	infoDiff
+	infoPrivate
+	infoInviteCode
+	infoHost
+	infoReadyPlayers
+	infoForfeitedPlayers
+	infoTeams
+	infoBananasRace
+	infoClaimant
+	infoChallengedWords
+)
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+// Numbers are written as varints and fields that are empty are skipped, so the messages are much smaller than json.
+// Boards, diffs, and tile positions are encoded field by field.  Details, game configs, reports, and replays are rarely sent, so they are encoded as json.
+func (m Message) MarshalBinary() ([]byte, error) {
+	w := binaryWriter{
+		buf: []byte{binaryVersion},
+	}
+	w.message(m)
+	if w.err != nil {
+		return nil, w.err
+	}
+	return w.buf, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+// Tiles must have uppercase letters and boards cannot have tiles with the same id or location.
+func (m *Message) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 || data[0] != binaryVersion {
+		return errors.New("unknown binary message version")
+	}
+	r := binaryReader{
+		buf: data[1:],
+	}
+	m2 := r.message()
+	switch {
+	case r.err != nil:
+		return r.err
+	case len(r.buf) != 0:
+		return errors.New("binary message has " + strconv.Itoa(len(r.buf)) + " extra bytes")
+	}
+	*m = m2
+	return nil
+}
+
+// message writes the type of the message and the fields that are set.
+func (w *binaryWriter) message(m Message) {
+	var flags int
+	setFlag := func(flag int, isSet bool) {
+		if isSet {
+			flags |= flag
+		}
+	}
+	setFlag(messageInfo, len(m.Info) != 0)
+	setFlag(messageCode, m.Code != 0)
+	setFlag(messageDetails, m.Details != nil)
+	setFlag(messageGame, m.Game != nil)
+	setFlag(messageGames, len(m.Games) != 0)
+	setFlag(messageReplay, m.Replay != nil)
+	w.int(int(m.Type))
+	w.int(flags)
+	if flags&messageInfo != 0 {
+		w.string(m.Info)
+	}
+	if flags&messageCode != 0 {
+		w.int(int(m.Code))
+	}
+	if flags&messageDetails != 0 {
+		w.json(m.Details)
+	}
+	if flags&messageGame != 0 {
+		w.info(*m.Game)
+	}
+	if flags&messageGames != 0 {
+		w.int(len(m.Games))
+		for _, i := range m.Games {
+			w.info(i)
+		}
+	}
+	if flags&messageReplay != 0 {
+		w.json(m.Replay)
+	}
+}
+
+// info writes the fields of the game info that are set.
+func (w *binaryWriter) info(i game.Info) {
+	var flags int
+	setFlag := func(flag int, isSet bool) {
+		if isSet {
+			flags |= flag
+		}
+	}
+	setFlag(infoID, i.ID != 0)
+	setFlag(infoStatus, i.Status != 0)
+	setFlag(infoBoard, i.Board != nil)
+	setFlag(infoTilesLeft, i.TilesLeft != 0)
+	setFlag(infoPlayers, len(i.Players) != 0)
+	setFlag(infoCreatedAt, i.CreatedAt != 0)
+	setFlag(infoConfig, i.Config != nil)
+	setFlag(infoFinalBoards, len(i.FinalBoards) != 0)
+	setFlag(infoCapacity, i.Capacity != 0)
+	setFlag(infoPlayerBoards, len(i.PlayerBoards) != 0)
+	setFlag(infoPlayerPoints, len(i.PlayerPoints) != 0)
+	setFlag(infoSecondsLeft, i.SecondsLeft != 0)
+	setFlag(infoBotDifficulty, i.BotDifficulty != 0)
+	setFlag(infoReport, i.Report != nil)
+	setFlag(infoDiff, i.Diff != nil)
+	setFlag(infoPrivate, i.Private)
+	setFlag(infoInviteCode, len(i.InviteCode) != 0)
+	setFlag(infoHost, len(i.Host) != 0)
+	setFlag(infoReadyPlayers, len(i.ReadyPlayers) != 0)
+	setFlag(infoForfeitedPlayers, len(i.ForfeitedPlayers) != 0)
+	setFlag(infoTeams, len(i.Teams) != 0)
+	setFlag(infoBananasRace, i.BananasRace)
+	setFlag(infoClaimant, len(i.Claimant) != 0)
+	setFlag(infoChallengedWords, len(i.ChallengedWords) != 0)
+	w.int(flags)
+	if flags&infoID != 0 {
+		w.int(int(i.ID))
+	}
+	if flags&infoStatus != 0 {
+		w.int(int(i.Status))
+	}
+	if flags&infoBoard != 0 {
+		w.board(*i.Board)
+	}
+	if flags&infoTilesLeft != 0 {
+		w.int(i.TilesLeft)
+	}
+	if flags&infoPlayers != 0 {
+		w.int(len(i.Players))
+		for _, pn := range i.Players {
+			w.string(pn)
+		}
+	}
+	if flags&infoCreatedAt != 0 {
+		w.int64(i.CreatedAt)
+	}
+	if flags&infoConfig != 0 {
+		w.json(i.Config)
+	}
+	if flags&infoFinalBoards != 0 {
+		w.boards(i.FinalBoards)
+	}
+	if flags&infoCapacity != 0 {
+		w.int(i.Capacity)
+	}
+	if flags&infoPlayerBoards != 0 {
+		w.boards(i.PlayerBoards)
+	}
+	if flags&infoPlayerPoints != 0 {
+		w.int(len(i.PlayerPoints))
+		for _, pn := range sortedKeys(i.PlayerPoints) {
+			w.string(pn)
+			w.int(i.PlayerPoints[pn])
+		}
+	}
+	if flags&infoSecondsLeft != 0 {
+		w.int(i.SecondsLeft)
+	}
+	if flags&infoBotDifficulty != 0 {
+		w.int(int(i.BotDifficulty))
+	}
+	if flags&infoReport != 0 {
+		w.json(i.Report)
+	}
+	if flags&infoDiff != 0 {
+		w.diff(*i.Diff)
+	}
+	if flags&infoInviteCode != 0 {
+		w.string(i.InviteCode)
+	}
+	if flags&infoHost != 0 {
+		w.string(i.Host)
+	}
+	if flags&infoReadyPlayers != 0 {
+		w.int(len(i.ReadyPlayers))
+		for _, pn := range i.ReadyPlayers {
+			w.string(pn)
+		}
+	}
+	if flags&infoForfeitedPlayers != 0 {
+		w.int(len(i.ForfeitedPlayers))
+		for _, pn := range i.ForfeitedPlayers {
+			w.string(pn)
+		}
+	}
+	if flags&infoTeams != 0 {
+		w.int(len(i.Teams))
+		for _, pn := range sortedKeys(i.Teams) {
+			w.string(pn)
+			w.int(i.Teams[pn])
+		}
+	}
+	if flags&infoClaimant != 0 {
+		w.string(i.Claimant)
+	}
+	if flags&infoChallengedWords != 0 {
+		w.int(len(i.ChallengedWords))
+		for _, word := range i.ChallengedWords {
+			w.string(word)
+		}
+	}
+}
+
+// boards writes the boards of the players, sorted by player name.
+func (w *binaryWriter) boards(boards map[string]board.Board) {
+	w.int(len(boards))
+	for _, pn := range sortedKeys(boards) {
+		w.string(pn)
+		w.board(boards[pn])
+	}
+}
+
+// board writes the revision, config, unused tiles, and used tile positions of the board.
+// The unused tiles are written in the order that they were added and the used tiles are sorted by location.
+func (w *binaryWriter) board(b board.Board) {
+	w.int(b.Revision)
+	w.int(b.NumRows)
+	w.int(b.NumCols)
+	w.int(len(b.UnusedTileIDs))
+	for _, id := range b.UnusedTileIDs {
+		w.tile(b.UnusedTiles[id])
+	}
+	usedTiles := make([]tile.Position, 0, len(b.UsedTiles))
+	for _, tp := range b.UsedTiles {
+		usedTiles = append(usedTiles, tp)
+	}
+	sort.Slice(usedTiles, func(i, j int) bool {
+		a, b := usedTiles[i], usedTiles[j]
+		switch {
+		case a.X != b.X:
+			return a.X < b.X
+		case a.Y != b.Y:
+			return a.Y < b.Y
+		default:
+			return a.Tile.ID < b.Tile.ID
+		}
+	})
+	w.int(len(usedTiles))
+	for _, tp := range usedTiles {
+		w.position(tp)
+	}
+}
+
+// diff writes the revision and changes of the diff.
+func (w *binaryWriter) diff(d board.Diff) {
+	w.int(d.Revision)
+	w.int(len(d.Changes))
+	for _, c := range d.Changes {
+		w.int(int(c.Op))
+		w.position(c.Position)
+	}
+}
+
+// position writes the tile and location of the tile position.
+func (w *binaryWriter) position(tp tile.Position) {
+	w.tile(tp.Tile)
+	w.int(int(tp.X))
+	w.int(int(tp.Y))
+}
+
+// tile writes the id and letter of the tile.
+func (w *binaryWriter) tile(t tile.Tile) {
+	w.int(int(t.ID))
+	w.int(int(t.Ch))
+}
+
+// int writes the number as a varint.
+func (w *binaryWriter) int(i int) {
+	w.int64(int64(i))
+}
+
+// int64 writes the number as a varint.
+func (w *binaryWriter) int64(i int64) {
+	w.buf = binary.AppendVarint(w.buf, i)
+}
+
+// string writes the length of the text followed by its bytes.
+func (w *binaryWriter) string(s string) {
+	w.int(len(s))
+	w.buf = append(w.buf, s...)
+}
+
+// json writes the value as length-prefixed json.
+func (w *binaryWriter) json(v any) {
+	if w.err != nil {
+		return
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		w.err = err
+		return
+	}
+	w.string(string(data))
+}
+
+// message reads a message written by binaryWriter.message.
+func (r *binaryReader) message() Message {
+	var m Message
+	m.Type = Type(r.int())
+	flags := r.int()
+	if flags&messageInfo != 0 {
+		m.Info = r.string()
+	}
+	if flags&messageCode != 0 {
+		m.Code = Code(r.int())
+	}
+	if flags&messageDetails != 0 {
+		r.json(&m.Details)
+	}
+	if flags&messageGame != 0 {
+		i := r.info()
+		m.Game = &i
+	}
+	if flags&messageGames != 0 {
+		n := r.length()
+		if n != 0 {
+			m.Games = make([]game.Info, n)
+		}
+		for j := range m.Games {
+			m.Games[j] = r.info()
+		}
+	}
+	if flags&messageReplay != 0 {
+		r.json(&m.Replay)
+	}
+	return m
+}
+
+// info reads a game info written by binaryWriter.info.
+func (r *binaryReader) info() game.Info {
+	var i game.Info
+	flags := r.int()
+	if flags&infoID != 0 {
+		i.ID = game.ID(r.int())
+	}
+	if flags&infoStatus != 0 {
+		i.Status = game.Status(r.int())
+	}
+	if flags&infoBoard != 0 {
+		i.Board = r.board()
+	}
+	if flags&infoTilesLeft != 0 {
+		i.TilesLeft = r.int()
+	}
+	if flags&infoPlayers != 0 {
+		n := r.length()
+		if n != 0 {
+			i.Players = make([]string, n)
+		}
+		for j := range i.Players {
+			i.Players[j] = r.string()
+		}
+	}
+	if flags&infoCreatedAt != 0 {
+		i.CreatedAt = r.int64()
+	}
+	if flags&infoConfig != 0 {
+		r.json(&i.Config)
+	}
+	if flags&infoFinalBoards != 0 {
+		i.FinalBoards = r.boards()
+	}
+	if flags&infoCapacity != 0 {
+		i.Capacity = r.int()
+	}
+	if flags&infoPlayerBoards != 0 {
+		i.PlayerBoards = r.boards()
+	}
+	if flags&infoPlayerPoints != 0 {
+		n := r.length()
+		if n != 0 {
+			i.PlayerPoints = make(map[string]int, n)
+		}
+		for j := 0; j < n && r.err == nil; j++ {
+			pn := r.string()
+			i.PlayerPoints[pn] = r.int()
+		}
+	}
+	if flags&infoSecondsLeft != 0 {
+		i.SecondsLeft = r.int()
+	}
+	if flags&infoBotDifficulty != 0 {
+		i.BotDifficulty = player.Difficulty(r.int())
+	}
+	if flags&infoReport != 0 {
+		r.json(&i.Report)
+	}
+	if flags&infoDiff != 0 {
+		d := r.diff()
+		i.Diff = &d
+	}
+	i.Private = flags&infoPrivate != 0
+	i.BananasRace = flags&infoBananasRace != 0
+	if flags&infoInviteCode != 0 {
+		i.InviteCode = r.string()
+	}
+	if flags&infoHost != 0 {
+		i.Host = r.string()
+	}
+	if flags&infoReadyPlayers != 0 {
+		n := r.length()
+		if n != 0 {
+			i.ReadyPlayers = make([]string, n)
+		}
+		for j := range i.ReadyPlayers {
+			i.ReadyPlayers[j] = r.string()
+		}
+	}
+	if flags&infoForfeitedPlayers != 0 {
+		n := r.length()
+		if n != 0 {
+			i.ForfeitedPlayers = make([]string, n)
+		}
+		for j := range i.ForfeitedPlayers {
+			i.ForfeitedPlayers[j] = r.string()
+		}
+	}
+	if flags&infoTeams != 0 {
+		n := r.length()
+		if n != 0 {
+			i.Teams = make(map[string]int, n)
+		}
+		for j := 0; j < n && r.err == nil; j++ {
+			pn := r.string()
+			i.Teams[pn] = r.int()
+		}
+	}
+	if flags&infoClaimant != 0 {
+		i.Claimant = r.string()
+	}
+	if flags&infoChallengedWords != 0 {
+		n := r.length()
+		if n != 0 {
+			i.ChallengedWords = make([]string, n)
+		}
+		for j := range i.ChallengedWords {
+			i.ChallengedWords[j] = r.string()
+		}
+	}
+	return i
+}
+
+// boards reads the boards of players written by binaryWriter.boards.
+func (r *binaryReader) boards() map[string]board.Board {
+	n := r.length()
+	if n == 0 {
+		return nil
+	}
+	boards := make(map[string]board.Board, n)
+	for j := 0; j < n && r.err == nil; j++ {
+		pn := r.string()
+		if b := r.board(); b != nil {
+			boards[pn] = *b
+		}
+	}
+	return boards
+}
+
+// board reads a board written by binaryWriter.board.
+// An error is set if multiple tiles have the same id or multiple used tiles have the same location.
+func (r *binaryReader) board() *board.Board {
+	revision := r.int()
+	cfg := board.Config{
+		NumRows: r.int(),
+		NumCols: r.int(),
+	}
+	ids := make(map[tile.ID]struct{})
+	addID := func(id tile.ID) {
+		if _, ok := ids[id]; ok && r.err == nil {
+			r.err = errors.New("board has multiple tiles with id " + strconv.Itoa(int(id)))
+		}
+		ids[id] = struct{}{}
+	}
+	tiles := make([]tile.Tile, r.length())
+	for j := range tiles {
+		tiles[j] = r.tile()
+		addID(tiles[j].ID)
+	}
+	locations := make(map[tile.X]map[tile.Y]struct{})
+	tilePositions := make([]tile.Position, r.length())
+	for j := range tilePositions {
+		tp := r.position()
+		addID(tp.Tile.ID)
+		if _, ok := locations[tp.X][tp.Y]; ok && r.err == nil {
+			r.err = errors.New("board has multiple tiles at column " + strconv.Itoa(int(tp.X)) + ", row " + strconv.Itoa(int(tp.Y)))
+		}
+		if _, ok := locations[tp.X]; !ok {
+			locations[tp.X] = make(map[tile.Y]struct{})
+		}
+		locations[tp.X][tp.Y] = struct{}{}
+		tilePositions[j] = tp
+	}
+	if r.err != nil {
+		return nil
+	}
+	b := board.New(tiles, tilePositions)
+	b.Config = cfg
+	b.Revision = revision
+	return b
+}
+
+// diff reads a diff written by binaryWriter.diff.
+func (r *binaryReader) diff() board.Diff {
+	var d board.Diff
+	d.Revision = r.int()
+	n := r.length()
+	if n != 0 {
+		d.Changes = make([]board.Change, n)
+	}
+	for j := range d.Changes {
+		d.Changes[j].Op = board.Op(r.int())
+		d.Changes[j].Position = r.position()
+	}
+	return d
+}
+
+// position reads a tile position written by binaryWriter.position.
+func (r *binaryReader) position() tile.Position {
+	var tp tile.Position
+	tp.Tile = r.tile()
+	tp.X = tile.X(r.int())
+	tp.Y = tile.Y(r.int())
+	return tp
+}
+
+// tile reads a tile written by binaryWriter.tile, setting an error if the letter of the tile is not valid.
+func (r *binaryReader) tile() tile.Tile {
+	id := tile.ID(r.int())
+	ch := r.int()
+	if r.err != nil {
+		return tile.Tile{}
+	}
+	t, err := tile.New(id, rune(ch))
+	if err != nil || int(rune(ch)) != ch {
+		r.err = errors.New("invalid letter for tile " + strconv.Itoa(int(id)))
+		return tile.Tile{}
+	}
+	return *t
+}
+
+// int reads a varint.
+func (r *binaryReader) int() int {
+	return int(r.int64())
+}
+
+// int64 reads a varint.
+func (r *binaryReader) int64() int64 {
+	if r.err != nil {
+		return 0
+	}
+	i, n := binary.Varint(r.buf)
+	if n <= 0 {
+		r.err = errors.New("invalid number in binary message")
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return i
+}
+
+// length reads the number of items or bytes that follow.
+// Each item is at least one byte, so an error is set if the length is more than the number of bytes that are left.
+func (r *binaryReader) length() int {
+	n := r.int()
+	if r.err == nil && (n < 0 || n > len(r.buf)) {
+		r.err = errors.New("invalid length in binary message: " + strconv.Itoa(n))
+	}
+	if r.err != nil {
+		return 0
+	}
+	return n
+}
+
+// string reads text written by binaryWriter.string, setting an error if it is not valid utf-8.
+func (r *binaryReader) string() string {
+	n := r.length()
+	if r.err != nil {
+		return ""
+	}
+	s := string(r.buf[:n])
+	r.buf = r.buf[n:]
+	if !utf8.ValidString(s) {
+		r.err = errors.New("invalid text in binary message")
+		return ""
+	}
+	return s
+}
+
+// json reads length-prefixed json into the value.
+func (r *binaryReader) json(v any) {
+	data := r.string()
+	if r.err != nil {
+		return
+	}
+	if err := json.Unmarshal([]byte(data), v); err != nil {
+		r.err = err
+	}
+}
+
+// sortedKeys returns the keys of the map in order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
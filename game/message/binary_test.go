@@ -0,0 +1,196 @@
+package message
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/jacobpatterson1549/selene-bananas/game"
+	"github.com/jacobpatterson1549/selene-bananas/game/board"
+	"github.com/jacobpatterson1549/selene-bananas/game/replay"
+	"github.com/jacobpatterson1549/selene-bananas/game/tile"
+)
+
+// binaryTestMessages are messages that are sent between the ui and server.
+func binaryTestMessages() []Message {
+	b := board.New([]tile.Tile{{ID: 3, Ch: 'A'}, {ID: 1, Ch: 'B'}}, []tile.Position{{Tile: tile.Tile{ID: 8, Ch: 'R'}, X: 4, Y: 46}, {Tile: tile.Tile{ID: 2, Ch: 'Ñ'}, X: -1, Y: 0}})
+	b.Config = board.Config{NumRows: 20, NumCols: 30}
+	b.Revision = 4
+	d := b.NewDiff(board.OpMove, tile.Position{Tile: tile.Tile{ID: 3, Ch: 'A'}, X: 5, Y: 46})
+	return []Message{
+		{},
+		{Type: JoinGame, Game: &game.Info{ID: 6}},
+		{Type: MoveGameTile, Game: &game.Info{Diff: &d}},
+		{Type: JoinGame, Game: &game.Info{ID: 6, Board: b, Status: game.InProgress, TilesLeft: 12, Players: []string{"fred", "barney"}, PlayerPoints: map[string]int{"fred": 3, "barney": -1}, SecondsLeft: 60, Config: &game.Config{MinLength: 3, Hints: true}}},
+		{Type: GameInfos, Games: []game.Info{{ID: 7, Status: game.Finished, Players: []string{"selene"}, CreatedAt: 1257894000, Capacity: 4}, {ID: 8}}},
+		{Type: SocketWarning, Info: "board has multiple groups", Code: CodeMultipleGroups, Details: &Details{TileIDs: []tile.ID{8, 2}, Penalty: 1}},
+		{Type: BoardReport, Game: &game.Info{Report: &board.Report{Words: []board.WordReport{{Word: "AB", X: 1, Y: 2, Valid: true}}}}},
+		{Type: SpectateGame, Game: &game.Info{PlayerBoards: map[string]board.Board{"fred": *b, "barney": {}}, FinalBoards: map[string]board.Board{"fred": *b}}},
+		{Type: GameReplay, Replay: &replay.Replay{GameID: 2, Events: []replay.Event{{Time: 9, Type: replay.Snag, PlayerName: "fred", Tiles: []tile.Tile{{ID: 4, Ch: 'Z'}}}}}},
+		{Type: AddBot, Game: &game.Info{BotDifficulty: 3}},
+		{Type: JoinGame, Game: &game.Info{ID: 9, InviteCode: "b4n4n4"}},
+		{Type: GameInfos, Games: []game.Info{{ID: 9, Private: true, Players: []string{"selene"}, Host: "selene"}}},
+		{Type: KickPlayer, Game: &game.Info{Players: []string{"fred"}}},
+		{Type: ChangeGameTiles, Game: &game.Info{Players: []string{"barney", "fred"}, ReadyPlayers: []string{"fred"}}},
+		{Type: ChangeGameTiles, Game: &game.Info{Players: []string{"barney", "fred"}, ForfeitedPlayers: []string{"barney"}}},
+		{Type: GameInfos, Games: []game.Info{{ID: 4, Players: []string{"barney", "fred", "wilma"}, Teams: map[string]int{"barney": 1, "fred": 2, "wilma": 1}}}},
+		{Type: ChangeGameTiles, Info: "last peel", Game: &game.Info{TilesLeft: 1, BananasRace: true}},
+		{Type: ChangeGameStatus, Game: &game.Info{Status: game.Challenging, Claimant: "fred", SecondsLeft: 30, FinalBoards: map[string]board.Board{"fred": *b}}},
+		{Type: ChallengeWords, Game: &game.Info{ChallengedWords: []string{"AB", "RÑ"}}},
+	}
+}
+
+// jsonRoundTrip marshals and unmarshals the message as json.
+func jsonRoundTrip(t *testing.T, m Message) Message {
+	t.Helper()
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("marshalling json: %v", err)
+	}
+	var m2 Message
+	if err := json.Unmarshal(data, &m2); err != nil {
+		t.Fatalf("unmarshalling json %s: %v", data, err)
+	}
+	return m2
+}
+
+// binaryRoundTrip marshals and unmarshals the message as binary.
+func binaryRoundTrip(t *testing.T, m Message) Message {
+	t.Helper()
+	data, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatalf("marshalling binary: %v", err)
+	}
+	var m2 Message
+	if err := m2.UnmarshalBinary(data); err != nil {
+		t.Fatalf("unmarshalling binary %v: %v", data, err)
+	}
+	return m2
+}
+
+func TestMessageBinary(t *testing.T) {
+	for i, m := range binaryTestMessages() {
+		want := jsonRoundTrip(t, m)
+		got := binaryRoundTrip(t, m)
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("Test %v: binary round trip not equal to json round trip:\nwanted: %#v\ngot:    %#v", i, want, got)
+		}
+		binaryData, _ := m.MarshalBinary()
+		jsonData, _ := json.Marshal(m)
+		if len(binaryData) >= len(jsonData) {
+			t.Errorf("Test %v: wanted binary message (%v bytes) to be smaller than json message (%v bytes)", i, len(binaryData), len(jsonData))
+		}
+	}
+}
+
+func TestMessageUnmarshalBinaryErrors(t *testing.T) {
+	valid, err := Message{Type: JoinGame, Game: &game.Info{ID: 6, Board: board.New([]tile.Tile{{ID: 1, Ch: 'A'}}, nil)}}.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unwanted error: %v", err)
+	}
+	boardMessage := func(tiles []tile.Tile, tilePositions []tile.Position) []byte {
+		w := binaryWriter{
+			buf: []byte{binaryVersion},
+		}
+		w.int(int(JoinGame))
+		w.int(messageGame)
+		w.int(infoBoard)
+		w.int(0) // revision
+		w.int(0) // rows
+		w.int(0) // cols
+		w.int(len(tiles))
+		for _, t := range tiles {
+			w.tile(t)
+		}
+		w.int(len(tilePositions))
+		for _, tp := range tilePositions {
+			w.position(tp)
+		}
+		return w.buf
+	}
+	unmarshalBinaryTests := [][]byte{
+		{},                            // no version
+		{2, 4, 0},                     // unknown version
+		{binaryVersion, 4},            // no flags
+		{binaryVersion, 4, 0, 0},      // extra byte
+		{binaryVersion, 4, 2, 10, 97}, // info text shorter than length
+		{binaryVersion, 4, 2, 2, 255}, // info text not utf-8
+		valid[:len(valid)-1],          // truncated
+		boardMessage([]tile.Tile{{ID: 1, Ch: 'a'}}, nil),                                                         // lowercase letter
+		boardMessage([]tile.Tile{{ID: 1, Ch: 'A'}, {ID: 1, Ch: 'B'}}, nil),                                       // duplicate unused tile ids
+		boardMessage([]tile.Tile{{ID: 1, Ch: 'A'}}, []tile.Position{{Tile: tile.Tile{ID: 1, Ch: 'B'}}}),          // duplicate used tile id
+		boardMessage(nil, []tile.Position{{Tile: tile.Tile{ID: 1, Ch: 'A'}}, {Tile: tile.Tile{ID: 2, Ch: 'B'}}}), // duplicate used tile locations
+	}
+	for i, data := range unmarshalBinaryTests {
+		var m Message
+		if err := m.UnmarshalBinary(data); err == nil {
+			t.Errorf("Test %v: wanted error unmarshalling %v, got %#v", i, data, m)
+		}
+	}
+}
+
+func FuzzMessageBinary(f *testing.F) {
+	for _, m := range binaryTestMessages() {
+		data, err := m.MarshalBinary()
+		if err != nil {
+			f.Fatalf("unwanted error: %v", err)
+		}
+		f.Add(data)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var m Message
+		if err := m.UnmarshalBinary(data); err != nil {
+			return // only valid messages are compared
+		}
+		jsonData, err := json.Marshal(m)
+		if err != nil || json.Unmarshal(jsonData, new(Message)) != nil {
+			return // the json of the details, configs, reports, and replays in the message cannot be round tripped
+		}
+		m2 := binaryRoundTrip(t, m)
+		want := jsonRoundTrip(t, m2)
+		got := binaryRoundTrip(t, m2)
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("binary round trip not equal to json round trip:\nwanted: %#v\ngot:    %#v", want, got)
+		}
+	})
+}
+
+func FuzzMessageBinaryRoundTrip(f *testing.F) {
+	f.Add(int(MoveGameTile), "moved tiles", 3, 7, 'Q', -2, 15, "selene", 9)
+	f.Add(0, "", 0, 0, 'Ñ', 0, 0, "", 0)
+	f.Fuzz(func(t *testing.T, typ int, info string, gameID, tileID int, ch rune, x, y int, playerName string, points int) {
+		if !utf8.ValidString(info) || !utf8.ValidString(playerName) {
+			t.Skip("json replaces invalid utf-8")
+		}
+		t1, err := tile.New(tile.ID(tileID), ch)
+		if err != nil {
+			t.Skip("tiles must have uppercase letters")
+		}
+		t2 := tile.Tile{ID: t1.ID + 1, Ch: t1.Ch}
+		tp := tile.Position{Tile: t2, X: tile.X(x), Y: tile.Y(y)}
+		b := board.New([]tile.Tile{*t1}, []tile.Position{tp})
+		b.Config = board.Config{NumRows: y, NumCols: x}
+		b.Revision = points
+		d := b.NewDiff(board.OpMove, tp)
+		m := Message{
+			Type: Type(typ),
+			Info: info,
+			Code: Code(points),
+			Game: &game.Info{
+				ID:           game.ID(gameID),
+				Board:        b,
+				Players:      []string{playerName},
+				PlayerPoints: map[string]int{playerName: points},
+				PlayerBoards: map[string]board.Board{playerName: *b},
+				Diff:         &d,
+			},
+			Details: &Details{Penalty: points},
+		}
+		want := jsonRoundTrip(t, m)
+		got := binaryRoundTrip(t, m)
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("binary round trip not equal to json round trip:\nwanted: %#v\ngot:    %#v", want, got)
+		}
+	})
+}
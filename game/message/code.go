@@ -0,0 +1,91 @@
+package message
+
+import (
+	"github.com/jacobpatterson1549/selene-bananas/game/tile"
+)
+
+type (
+	// Code identifies why a warning or error was sent so the ui can react to it without reading the info text.
+	Code int
+
+	// Details are facts about a warning or error that the ui can use to show the problem.
+	Details struct {
+		// InvalidWords are the words on the board of the player that cannot be used.
+		InvalidWords []string `json:"invalidWords,omitempty"`
+		// TileIDs are the ids of the tiles that caused the problem.
+		TileIDs []tile.ID `json:"tileIds,omitempty"`
+		// Penalty is the number of possible win points that were taken from the player.
+		Penalty int `json:"penalty,omitempty"`
+	}
+)
+
+const (
+	// CodeNone is the code of messages that are not warnings or errors.
+	CodeNone Code = iota
+	// CodeServerError is the code of unexpected errors.
+	CodeServerError
+	// CodeUnknownMessage is the code when the type of a message cannot be handled.
+	CodeUnknownMessage
+	// CodeNotInGame is the code when a player sends a message for a game that the player is not in.
+	CodeNotInGame
+	// CodeGameNotFound is the code when a message is for a game that does not exist.
+	CodeGameNotFound
+	// CodeTooManyGames is the code when no more games can be created.
+	CodeTooManyGames
+	// CodeInvalidConfig is the code when a game cannot be created with the requested options.
+	CodeInvalidConfig
+	// CodeGameStarted is the code when a player tries to join a game that has already started.
+	CodeGameStarted
+	// CodeGameFull is the code when a player tries to join a game that has no room for another player.
+	CodeGameFull
+	// CodeNotEnoughTiles is the code when there are not enough tiles left for another player to join a game.
+	CodeNotEnoughTiles
+	// CodeNotInProgress is the code when a player tries to play a game that has not started or is finished.
+	CodeNotInProgress
+	// CodeInvalidStatus is the code when the requested status of a game cannot be set.
+	CodeInvalidStatus
+	// CodeSnagFirst is the code when a player tries to finish a game that still has tiles to snag.
+	CodeSnagFirst
+	// CodeNoTilesLeft is the code when a player tries to snag or swap a tile when there are no tiles left.
+	CodeNoTilesLeft
+	// CodeNoSwapTile is the code when a player does not say which tile to swap.
+	CodeNoSwapTile
+	// CodeUnusedTiles is the code when a player tries to finish a game without using all tiles.  The unused tile ids are in the details.
+	CodeUnusedTiles
+	// CodeMultipleGroups is the code when the used tiles of a player are not in a single group.  The ids of the tiles that are not in the largest group are in the details.
+	CodeMultipleGroups
+	// CodeDuplicateWords is the code when a word is used multiple times in a game that prohibits it.  The duplicate words are in the details.
+	CodeDuplicateWords
+	// CodeShortWords is the code when a word is shorter than the minimum length of words for the game.  The short words are in the details.
+	CodeShortWords
+	// CodeInvalidWords is the code when the board of a player has words that are not valid.  The invalid words are in the details.
+	CodeInvalidWords
+	// CodeHintsNotAllowed is the code when a player asks for a hint in a game that does not allow them.
+	CodeHintsNotAllowed
+	// CodeNoHint is the code when no hint could be found for a player.
+	CodeNoHint
+	// CodeReplayUnavailable is the code when a player asks for the replay of a game that is not finished.
+	CodeReplayUnavailable
+	// CodeCannotSpectate is the code when a player cannot watch a game.
+	CodeCannotSpectate
+	// CodeBotNotAdded is the code when a bot could not be added to a game.
+	CodeBotNotAdded
+	// CodeStaleBoard is the code when a player changes an old revision of their board.  The whole board is also sent to the player.
+	CodeStaleBoard
+	// CodeWrongInviteCode is the code when a player tries to join or watch a private game without its invite code.
+	CodeWrongInviteCode
+	// CodeNotHost is the code when a player tries to do something that only the host of the game can do.
+	CodeNotHost
+	// CodeForfeited is the code when a player who forfeited a game tries to play it.
+	CodeForfeited
+	// CodeSwapLimit is the code when a player tries to swap a tile after using all of their swaps.
+	CodeSwapLimit
+	// CodeSwapCooldown is the code when a player tries to swap a tile too soon after their last swap.
+	CodeSwapCooldown
+	// CodeUsedTileSwap is the code when a player tries to swap a tile that is used in a word in a game that does not allow it.
+	CodeUsedTileSwap
+	// CodeBananasRace is the code when a player tries to snag in a strict peel game that has too few tiles left for every player to get one.
+	CodeBananasRace
+	// CodeCannotChallenge is the code when a player cannot challenge the words of the player who claimed to win a game.
+	CodeCannotChallenge
+)
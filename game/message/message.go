@@ -4,6 +4,7 @@ package message
 import (
 	"github.com/jacobpatterson1549/selene-bananas/game"
 	"github.com/jacobpatterson1549/selene-bananas/game/player"
+	"github.com/jacobpatterson1549/selene-bananas/game/replay"
 )
 
 type (
@@ -16,10 +17,16 @@ type (
 		Type Type `json:"type"`
 		// Info is a message to show to the player.
 		Info string `json:"info,omitempty"`
+		// Code identifies the reason for warning and error messages.
+		Code Code `json:"code,omitempty"`
+		// Details are facts about the problem of warning and error messages.
+		Details *Details `json:"details,omitempty"`
 		// Game is the info for the current game the player is in.
 		Game *game.Info `json:"game,omitempty"`
 		// Games contains the information about all the available games.
 		Games []game.Info `json:"games,omitempty"`
+		// Replay is the log of the events of a finished game.
+		Replay *replay.Replay `json:"replay,omitempty"`
 		// PlayerName is the name of the player the message is to/from.
 		PlayerName player.Name `json:"-"`
 		// Addr is the socket remote address text the message is from.
@@ -43,19 +50,42 @@ const (
 	// GameChat is a MessageType that users send to communicate with ether players through the server.
 	GameChat
 	// RefreshGameBoard refreshes the board size for the current game by reading the NumCols and NumRows fields.
+	// The server also sends it with the whole board when a diff from a user is for an old revision of the board.
 	RefreshGameBoard
 	// ChangeGameStatus is a MessageType that users and servers send to request or inform of a game status change.
 	ChangeGameStatus
 	// ChangeGameTiles is a MessageType that the server sends to users to indicate that tiles have been changed for any player.
+	// The new tiles of the user are in the diff of the game.
 	ChangeGameTiles
 	// SnagGameTile is a MessageType that users send to the server to request a new tile when they have none left to use.
 	SnagGameTile
 	// SwapGameTile is a MessageType that users send to the server to exchange a tile for three new ones.
+	// The tile is removed in the diff of the game.
 	SwapGameTile
 	// MoveGameTile is a MessageType that users send to the server whenever they change the state of their boards.
+	// The moves are in the diff of the game.
 	MoveGameTile
+	// GameReplay is a MessageType that users send to request the replay of a finished game and the server sends with the replay.
+	GameReplay
+	// SpectateGame is a MessageType that users send to watch a game they are not playing in and the server sends to spectators with snapshots of the game.
+	SpectateGame
 	// GameInfos is a MessageType that the server sends to report changes in the games in a lobby.
 	GameInfos
+	// AddBot is a MessageType that players send to add a computer player to the game they are in.
+	AddBot
+	// Hint is a MessageType that players send to ask where to place unused tiles and the server sends with the suggested tile positions.
+	Hint
+	// BoardReport is a MessageType that the server sends to players after they move tiles to describe the words and groups of tiles on their boards.
+	BoardReport
+	// KickPlayer is a MessageType that the host of a game sends to remove the players of the game from it.
+	KickPlayer
+	// ToggleReady is a MessageType that players send to change whether or not they are ready for the game to start.
+	ToggleReady
+	// Forfeit is a MessageType that players send to give up a game that is in progress.  The board of the player is frozen.
+	Forfeit
+	// ChallengeWords is a MessageType that players send to vote that words on the board of the player who claimed to win are not allowed.
+	// The words are the challenged words of the game.  Players can send no words to accept the win.
+	ChallengeWords
 	// SocketWarning is a MessageType that servers send to inform users that a request is invalid.
 	SocketWarning
 	// SocketError is a MessageType that servers send to users to report an unexpected state.
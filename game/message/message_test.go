@@ -57,6 +57,10 @@ func TestMessageJSON(t *testing.T) {
 			m:    Message{Type: 11},
 			want: `{"type":11}`,
 		},
+		{
+			m:    Message{Type: 22, Info: "invalid words", Code: CodeInvalidWords, Details: &Details{InvalidWords: []string{"QX"}, TileIDs: []tile.ID{4, 2}, Penalty: 1}},
+			want: `{"type":22,"info":"invalid words","code":19,"details":{"invalidWords":["QX"],"tileIds":[4,2],"penalty":1}}`,
+		},
 		{
 			m:    Message{Type: 1, Game: &game.Info{Board: newBoard(nil, nil, &board.Config{NumRows: 23, NumCols: 21}), Config: &game.Config{CheckOnSnag: true, Penalize: true, MinLength: 3}}},
 			want: `{"type":1,"game":{"board":{"config":{"r":23,"c":21}},"config":{"checkOnSnag":true,"penalize":true,"minLength":3}}}`,
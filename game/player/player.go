@@ -1,5 +1,51 @@
 // Package player identifies game players.
 package player
 
-// Name uniquely identifies a player.
-type Name string
+import (
+	"strconv"
+	"strings"
+)
+
+type (
+	// Name uniquely identifies a player.
+	Name string
+
+	// Difficulty is how well a bot player plays.
+	Difficulty int
+)
+
+const (
+	// Easy bots think slowly and know few words.
+	Easy Difficulty = iota
+	// Medium bots think faster and know more words than easy bots.
+	Medium
+	// Hard bots think quickly and know many words.
+	Hard
+)
+
+// botPrefix starts the names of bot players.
+// Usernames can only contain lowercase letters, so the names of bots and users are different.
+const botPrefix = "bot-"
+
+// BotName creates the name for a bot of the difficulty.  The number should be different for each bot.
+func BotName(d Difficulty, number int) Name {
+	return Name(botPrefix + strings.ToLower(d.String()) + "-" + strconv.Itoa(number))
+}
+
+// IsBot determines if the player is a bot rather than a user.
+func (n Name) IsBot() bool {
+	return strings.HasPrefix(string(n), botPrefix)
+}
+
+// String returns the display value for the difficulty.
+func (d Difficulty) String() string {
+	switch d {
+	case Easy:
+		return "Easy"
+	case Medium:
+		return "Medium"
+	case Hard:
+		return "Hard"
+	}
+	return "?"
+}
@@ -0,0 +1,42 @@
+package player
+
+import "testing"
+
+func TestBotName(t *testing.T) {
+	botNameTests := []struct {
+		Difficulty
+		number int
+		want   Name
+	}{
+		{Easy, 1, "bot-easy-1"},
+		{Medium, 2, "bot-medium-2"},
+		{Hard, 37, "bot-hard-37"},
+	}
+	for i, test := range botNameTests {
+		got := BotName(test.Difficulty, test.number)
+		switch {
+		case test.want != got:
+			t.Errorf("Test %v: bot names not equal: wanted %v, got %v", i, test.want, got)
+		case !got.IsBot():
+			t.Errorf("Test %v: wanted %v to be a bot", i, got)
+		}
+	}
+}
+
+func TestIsBot(t *testing.T) {
+	isBotTests := []struct {
+		Name
+		want bool
+	}{
+		{},
+		{"selene", false},
+		{"robot", false},
+		{"bot", false},
+		{"bot-easy-1", true},
+	}
+	for i, test := range isBotTests {
+		if want, got := test.want, test.Name.IsBot(); want != got {
+			t.Errorf("Test %v: wanted IsBot to be %v for %q, got %v", i, want, test.Name, got)
+		}
+	}
+}
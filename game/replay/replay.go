@@ -0,0 +1,192 @@
+// Package replay records the moves of a game so the boards of players can be recreated after it is finished.
+package replay
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/jacobpatterson1549/selene-bananas/game"
+	"github.com/jacobpatterson1549/selene-bananas/game/board"
+	"github.com/jacobpatterson1549/selene-bananas/game/player"
+	"github.com/jacobpatterson1549/selene-bananas/game/tile"
+)
+
+type (
+	// Replay is the log of events that happened in a game.
+	Replay struct {
+		// GameID is the id of the game that was played.
+		GameID game.ID `json:"gameID"`
+		// Events are the things that happened in the game, in the order they occurred.
+		Events []Event `json:"events,omitempty"`
+	}
+
+	// Event is a change to a game that the game accepted.
+	Event struct {
+		// Time is the time the event happened, in seconds since the unix epoch.
+		Time int64 `json:"time"`
+		// Type is the kind of event.
+		Type Type `json:"type"`
+		// PlayerName is the name of the player the event is for.
+		PlayerName player.Name `json:"player,omitempty"`
+		// Tiles are the tiles the player received.
+		Tiles []tile.Tile `json:"tiles,omitempty"`
+		// SwappedTile is the tile the player gave back when swapping.
+		SwappedTile *tile.Tile `json:"swappedTile,omitempty"`
+		// TilePositions are the tiles the player moved on their board.
+		TilePositions []tile.Position `json:"tilePositions,omitempty"`
+		// BoardConfig is the size of the player's board after joining or refreshing it.
+		BoardConfig *board.Config `json:"boardConfig,omitempty"`
+		// Teammate is the player whose board is shared by a player who joins their team.
+		Teammate player.Name `json:"teammate,omitempty"`
+	}
+
+	// Type is the kind of event.
+	Type int
+)
+
+const (
+	_ Type = iota
+	// Join is the event of a player joining the game and receiving their starting tiles.
+	// Players who join the team of a teammate share the board of the teammate instead.
+	Join
+	// Resize is the event of a player changing the size of their board.
+	Resize
+	// Start is the event of a player starting the game.
+	Start
+	// Snag is the event of a player receiving a tile because a player snagged one.
+	Snag
+	// Swap is the event of a player exchanging a tile for others.
+	Swap
+	// Move is the event of a player moving tiles on their board.
+	Move
+	// Finish is the event of a player winning the game.
+	Finish
+	// Kick is the event of a player being removed from the game by the host.
+	Kick
+	// Leave is the event of a player leaving the game before it is started.
+	Leave
+	// Forfeit is the event of a player giving up the game.  The board of the player does not change after it.
+	Forfeit
+	// RottenBanana is the event of a player who claimed to win being put out of the game because other players challenged their words.
+	// The tiles on the board of the player are returned to the unused tiles.
+	RottenBanana
+)
+
+// String describes the event type.
+func (t Type) String() string {
+	switch t {
+	case Join:
+		return "Join"
+	case Resize:
+		return "Resize"
+	case Start:
+		return "Start"
+	case Snag:
+		return "Snag"
+	case Swap:
+		return "Swap"
+	case Move:
+		return "Move"
+	case Finish:
+		return "Finish"
+	case Kick:
+		return "Kick"
+	case Leave:
+		return "Leave"
+	case Forfeit:
+		return "Forfeit"
+	case RottenBanana:
+		return "Rotten Banana"
+	}
+	return "?"
+}
+
+// Players gets the names of the players in the game in the order they joined.  Players who were kicked from or left the game are not included.
+func (r Replay) Players() []player.Name {
+	var playerNames []player.Name
+	for _, e := range r.Events {
+		switch e.Type {
+		case Join:
+			playerNames = append(playerNames, e.PlayerName)
+		case Kick, Leave:
+			for i, pn := range playerNames {
+				if pn == e.PlayerName {
+					playerNames = append(playerNames[:i], playerNames[i+1:]...)
+					break
+				}
+			}
+		}
+	}
+	return playerNames
+}
+
+// Boards recreates the boards of the players after the first numEvents of the replay have happened.
+func (r Replay) Boards(numEvents int) (map[player.Name]*board.Board, error) {
+	if numEvents < 0 || numEvents > len(r.Events) {
+		return nil, errors.New("replay only has " + strconv.Itoa(len(r.Events)) + " events")
+	}
+	boards := make(map[player.Name]*board.Board)
+	for i, e := range r.Events[:numEvents] {
+		if err := e.apply(boards); err != nil {
+			return nil, errors.New("applying replay event " + strconv.Itoa(i) + " (" + e.Type.String() + "): " + err.Error())
+		}
+	}
+	return boards, nil
+}
+
+// apply changes the board of the player for the event.
+func (e Event) apply(boards map[player.Name]*board.Board) error {
+	if e.Type == Join {
+		b := board.New(nil, nil)
+		if len(e.Teammate) != 0 {
+			teammateBoard, ok := boards[e.Teammate]
+			if !ok {
+				return errors.New("no board for teammate " + string(e.Teammate))
+			}
+			b = teammateBoard
+		}
+		boards[e.PlayerName] = b
+	}
+	b, ok := boards[e.PlayerName]
+	switch {
+	case e.Type == Start, e.Type == Finish:
+		return nil
+	case !ok:
+		return errors.New("no board for " + string(e.PlayerName))
+	case e.Type == Forfeit:
+		return nil
+	case e.Type == RottenBanana:
+		cfg := b.Config
+		*b = *board.New(nil, nil)
+		b.Config = cfg
+		return nil
+	case e.Type == Kick, e.Type == Leave:
+		delete(boards, e.PlayerName)
+		return nil
+	}
+	if e.BoardConfig != nil {
+		if _, err := b.Resize(*e.BoardConfig); err != nil {
+			return err
+		}
+	}
+	if e.SwappedTile != nil {
+		if err := b.RemoveTile(*e.SwappedTile); err != nil {
+			return err
+		}
+	}
+	for _, t := range e.Tiles {
+		if err := b.AddTile(t); err != nil {
+			return err
+		}
+	}
+	if len(e.TilePositions) > 0 {
+		tilePositions := make(map[tile.ID]tile.Position, len(e.TilePositions))
+		for _, tp := range e.TilePositions {
+			tilePositions[tp.Tile.ID] = tp
+		}
+		if err := b.MoveTiles(tilePositions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+package replay
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/jacobpatterson1549/selene-bananas/game/board"
+	"github.com/jacobpatterson1549/selene-bananas/game/player"
+	"github.com/jacobpatterson1549/selene-bananas/game/tile"
+)
+
+func testReplay() Replay {
+	a := tile.Tile{ID: 1, Ch: 'A'}
+	b := tile.Tile{ID: 2, Ch: 'B'}
+	c := tile.Tile{ID: 3, Ch: 'C'}
+	d := tile.Tile{ID: 4, Ch: 'D'}
+	e := tile.Tile{ID: 5, Ch: 'E'}
+	return Replay{
+		GameID: 8,
+		Events: []Event{
+			{Time: 1, Type: Join, PlayerName: "selene", Tiles: []tile.Tile{a, b}, BoardConfig: &board.Config{NumRows: 10, NumCols: 12}},
+			{Time: 2, Type: Join, PlayerName: "fred", Tiles: []tile.Tile{c}, BoardConfig: &board.Config{NumRows: 15, NumCols: 15}},
+			{Time: 3, Type: Start, PlayerName: "fred"},
+			{Time: 4, Type: Move, PlayerName: "selene", TilePositions: []tile.Position{{Tile: a, X: 3, Y: 4}}},
+			{Time: 5, Type: Snag, PlayerName: "selene", Tiles: []tile.Tile{d}},
+			{Time: 5, Type: Swap, PlayerName: "fred", SwappedTile: &c, Tiles: []tile.Tile{e}},
+			{Time: 6, Type: Finish, PlayerName: "selene"},
+		},
+	}
+}
+
+func TestTypeString(t *testing.T) {
+	types := []Type{Join, Resize, Start, Snag, Swap, Move, Finish, Kick, Leave, Forfeit, RottenBanana}
+	typeStrings := make(map[string]struct{}, len(types))
+	for i, typ := range types {
+		s := typ.String()
+		if s == "?" {
+			t.Errorf("Test %v: wanted type %v to have a name", i, int(typ))
+		}
+		typeStrings[s] = struct{}{}
+	}
+	if want, got := len(types), len(typeStrings); want != got {
+		t.Errorf("wanted %v unique type strings, got %v", want, got)
+	}
+	if want, got := "?", Type(0).String(); want != got {
+		t.Errorf("wanted unknown type string to be %q, got %q", want, got)
+	}
+}
+
+func TestPlayers(t *testing.T) {
+	r := testReplay()
+	want := []player.Name{"selene", "fred"}
+	got := r.Players()
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("not equal:\nwanted: %v\ngot:    %v", want, got)
+	}
+}
+
+func TestBoards(t *testing.T) {
+	r := testReplay()
+	boardsTests := []struct {
+		numEvents  int
+		wantOk     bool
+		wantUnused map[player.Name][]tile.ID
+		wantUsed   map[player.Name][]tile.Position
+	}{
+		{
+			numEvents: -1,
+		},
+		{
+			numEvents: 8,
+		},
+		{
+			wantOk:     true,
+			wantUnused: map[player.Name][]tile.ID{},
+		},
+		{
+			numEvents: 3,
+			wantOk:    true,
+			wantUnused: map[player.Name][]tile.ID{
+				"selene": {1, 2},
+				"fred":   {3},
+			},
+		},
+		{
+			numEvents: 7,
+			wantOk:    true,
+			wantUnused: map[player.Name][]tile.ID{
+				"selene": {2, 4},
+				"fred":   {5},
+			},
+			wantUsed: map[player.Name][]tile.Position{
+				"selene": {{Tile: tile.Tile{ID: 1, Ch: 'A'}, X: 3, Y: 4}},
+			},
+		},
+	}
+	for i, test := range boardsTests {
+		got, err := r.Boards(test.numEvents)
+		switch {
+		case !test.wantOk:
+			if err == nil {
+				t.Errorf("Test %v: wanted error", i)
+			}
+			continue
+		case err != nil:
+			t.Errorf("Test %v: unwanted error: %v", i, err)
+			continue
+		case len(test.wantUnused) != len(got):
+			t.Errorf("Test %v: wanted %v boards, got %v", i, len(test.wantUnused), len(got))
+			continue
+		}
+		for pn, wantUnused := range test.wantUnused {
+			b := got[pn]
+			switch {
+			case b == nil:
+				t.Errorf("Test %v: no board for %v", i, pn)
+			case !reflect.DeepEqual(wantUnused, b.UnusedTileIDs):
+				t.Errorf("Test %v: unused tile ids for %v not equal:\nwanted: %v\ngot:    %v", i, pn, wantUnused, b.UnusedTileIDs)
+			case len(test.wantUsed[pn]) != len(b.UsedTiles):
+				t.Errorf("Test %v: wanted %v used tiles for %v, got %v", i, len(test.wantUsed[pn]), pn, len(b.UsedTiles))
+			default:
+				for _, tp := range test.wantUsed[pn] {
+					if want, got := tp, b.UsedTiles[tp.Tile.ID]; want != got {
+						t.Errorf("Test %v: used tile for %v not equal:\nwanted: %v\ngot:    %v", i, pn, want, got)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestKick(t *testing.T) {
+	r := Replay{
+		Events: []Event{
+			{Type: Join, PlayerName: "selene", Tiles: []tile.Tile{{ID: 1, Ch: 'A'}}},
+			{Type: Join, PlayerName: "fred", Tiles: []tile.Tile{{ID: 2, Ch: 'B'}}},
+			{Type: Join, PlayerName: "barney", Tiles: []tile.Tile{{ID: 3, Ch: 'C'}}},
+			{Type: Kick, PlayerName: "fred"},
+		},
+	}
+	if want, got := []player.Name{"selene", "barney"}, r.Players(); !reflect.DeepEqual(want, got) {
+		t.Errorf("players not equal:\nwanted: %v\ngot:    %v", want, got)
+	}
+	boards, err := r.Boards(len(r.Events))
+	switch {
+	case err != nil:
+		t.Errorf("unwanted error: %v", err)
+	case len(boards) != 2, boards["fred"] != nil:
+		t.Errorf("wanted the board of the kicked player to be removed, got %v", boards)
+	}
+}
+
+func TestLeaveAndForfeit(t *testing.T) {
+	r := Replay{
+		Events: []Event{
+			{Type: Join, PlayerName: "selene", Tiles: []tile.Tile{{ID: 1, Ch: 'A'}}},
+			{Type: Join, PlayerName: "fred", Tiles: []tile.Tile{{ID: 2, Ch: 'B'}}},
+			{Type: Leave, PlayerName: "fred"},
+			{Type: Join, PlayerName: "barney", Tiles: []tile.Tile{{ID: 2, Ch: 'B'}}},
+			{Type: Start},
+			{Type: Forfeit, PlayerName: "barney"},
+		},
+	}
+	if want, got := []player.Name{"selene", "barney"}, r.Players(); !reflect.DeepEqual(want, got) {
+		t.Errorf("players not equal:\nwanted: %v\ngot:    %v", want, got)
+	}
+	boards, err := r.Boards(len(r.Events))
+	switch {
+	case err != nil:
+		t.Errorf("unwanted error: %v", err)
+	case len(boards) != 2, boards["fred"] != nil:
+		t.Errorf("wanted the board of the player who left to be removed, got %v", boards)
+	case len(boards["barney"].UnusedTiles) != 1:
+		t.Errorf("wanted the board of the player who forfeited to be kept, got %v", boards["barney"])
+	}
+}
+
+func TestJoinTeam(t *testing.T) {
+	r := Replay{
+		Events: []Event{
+			{Type: Join, PlayerName: "selene", Tiles: []tile.Tile{{ID: 1, Ch: 'A'}}, BoardConfig: &board.Config{NumRows: 10, NumCols: 10}},
+			{Type: Join, PlayerName: "fred", Teammate: "selene"},
+			{Type: Move, PlayerName: "fred", TilePositions: []tile.Position{{Tile: tile.Tile{ID: 1, Ch: 'A'}, X: 2, Y: 3}}},
+		},
+	}
+	boards, err := r.Boards(len(r.Events))
+	switch {
+	case err != nil:
+		t.Errorf("unwanted error: %v", err)
+	case boards["selene"] != boards["fred"]:
+		t.Errorf("wanted teammates to share a board, got %v", boards)
+	case len(boards["selene"].UsedTiles) != 1:
+		t.Errorf("wanted tile moved by teammate to be used, got %v", boards["selene"])
+	}
+	r.Events[1].Teammate = "barney"
+	if _, err := r.Boards(len(r.Events)); err == nil {
+		t.Error("wanted error joining the team of a player who has not joined")
+	}
+}
+
+func TestRottenBanana(t *testing.T) {
+	r := Replay{
+		Events: []Event{
+			{Type: Join, PlayerName: "selene", Tiles: []tile.Tile{{ID: 1, Ch: 'A'}, {ID: 2, Ch: 'T'}}, BoardConfig: &board.Config{NumRows: 10, NumCols: 10}},
+			{Type: Join, PlayerName: "fred", Teammate: "selene"},
+			{Type: Start},
+			{Type: Move, PlayerName: "selene", TilePositions: []tile.Position{{Tile: tile.Tile{ID: 1, Ch: 'A'}, X: 2, Y: 3}}},
+			{Type: RottenBanana, PlayerName: "selene"},
+		},
+	}
+	boards, err := r.Boards(len(r.Events))
+	switch {
+	case err != nil:
+		t.Errorf("unwanted error: %v", err)
+	case len(boards) != 2, boards["selene"] != boards["fred"]:
+		t.Errorf("wanted teammates to keep sharing a board, got %v", boards)
+	case len(boards["selene"].Tiles()) != 0:
+		t.Errorf("wanted tiles of the rotten banana to be returned, got %v", boards["selene"])
+	case boards["selene"].Config.NumRows != 10:
+		t.Errorf("wanted board size to be kept, got %v", boards["selene"].Config)
+	}
+}
+
+func TestBoardsBadEvent(t *testing.T) {
+	r := Replay{
+		Events: []Event{
+			{Type: Snag, PlayerName: "barney", Tiles: []tile.Tile{{ID: 1, Ch: 'A'}}},
+		},
+	}
+	if _, err := r.Boards(1); err == nil {
+		t.Error("wanted error applying event for player that has not joined")
+	}
+}
+
+func TestReplayJSON(t *testing.T) {
+	want := testReplay()
+	b, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("unwanted error marshalling replay: %v", err)
+	}
+	var got Replay
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("unwanted error unmarshalling replay: %v", err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("replays not equal after json round trip:\nwanted: %v\ngot:    %v", want, got)
+	}
+}
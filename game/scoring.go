@@ -0,0 +1,186 @@
+package game
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+
+	"github.com/jacobpatterson1549/selene-bananas/game/tile"
+)
+
+type (
+	// Scoring identifies the scheme used to award points to players when a game is finished.
+	Scoring int
+
+	// Scorer awards points to the players of a finished game.
+	Scorer interface {
+		// Score computes the points each player gets, keyed by player name.
+		Score(players []PlayerResult) map[string]int
+		// Rule describes how points are awarded.
+		Rule() string
+	}
+
+	// PlayerResult is the state of a player when a game is finished.
+	PlayerResult struct {
+		// Name is the name of the player.
+		Name string
+		// Winner is true for the player who finished the game.
+		Winner bool
+		// WinPoints are the points the player would get for winning the game.
+		WinPoints int
+		// Letters are the letters of the tiles the player has used on their board.
+		Letters []tile.Letter
+		// Words are the valid words formed on the player's board.
+		Words []string
+	}
+
+	// flatScorer gives the winner their win points and each other player a consolation point.
+	flatScorer struct{}
+
+	// letterValueScorer gives each player the sum of the values of the tiles they used, with the winner also getting their win points.
+	letterValueScorer struct{}
+
+	// wordLengthScorer gives players a bonus for long words in addition to the points of the flat scorer.
+	wordLengthScorer struct{}
+
+	// rankScorer gives points to players based on how many tiles they used compared to other players.
+	rankScorer struct{}
+)
+
+const (
+	// FlatScoring gives the winner their win points and each other player one point.
+	FlatScoring Scoring = iota
+	// LetterValueScoring gives each player the value of the letters of their used tiles, similar to Scrabble tile values.
+	LetterValueScoring
+	// WordLengthScoring gives bonus points to players for each long word.
+	WordLengthScoring
+	// RankScoring gives points to players based on their ranking by the number of tiles used.
+	RankScoring
+)
+
+// wordLengthBonusMin is the shortest word length that gets a bonus when using WordLengthScoring.
+const wordLengthBonusMin = 5
+
+// letterValues are the points for each letter when using LetterValueScoring.
+// The letters that are not in English are valued like they are in Spanish and German Scrabble.
+var letterValues = map[tile.Letter]int{
+	'A': 1, 'B': 3, 'C': 3, 'D': 2, 'E': 1, 'F': 4, 'G': 2, 'H': 4, 'I': 1,
+	'J': 8, 'K': 5, 'L': 1, 'M': 3, 'N': 1, 'O': 1, 'P': 3, 'Q': 10, 'R': 1,
+	'S': 1, 'T': 1, 'U': 1, 'V': 4, 'W': 4, 'X': 8, 'Y': 4, 'Z': 10,
+	'Ñ': 8, 'Ä': 6, 'Ö': 8, 'Ü': 6,
+}
+
+// Scorer creates the scorer for the scoring scheme.
+func (s Scoring) Scorer() (Scorer, error) {
+	switch s {
+	case FlatScoring:
+		return flatScorer{}, nil
+	case LetterValueScoring:
+		return letterValueScorer{}, nil
+	case WordLengthScoring:
+		return wordLengthScorer{}, nil
+	case RankScoring:
+		return rankScorer{}, nil
+	}
+	return nil, errors.New("unknown scoring: " + strconv.Itoa(int(s)))
+}
+
+// String returns the display value for the scoring.
+func (s Scoring) String() string {
+	switch s {
+	case FlatScoring:
+		return "Flat"
+	case LetterValueScoring:
+		return "Letter Value"
+	case WordLengthScoring:
+		return "Word Length"
+	case RankScoring:
+		return "Rank"
+	}
+	return "?"
+}
+
+// Score gives the winner their win points and the other players one point.
+func (flatScorer) Score(players []PlayerResult) map[string]int {
+	points := make(map[string]int, len(players))
+	for _, p := range players {
+		points[p.Name] = 1
+		if p.Winner {
+			points[p.Name] = p.WinPoints
+		}
+	}
+	return points
+}
+
+// Rule describes flat scoring.
+func (flatScorer) Rule() string {
+	return "The winner gets their potential win points.  Other players each get 1 point."
+}
+
+// Score gives each player the total value of the letters of their used tiles.  The winner also gets their win points.
+func (letterValueScorer) Score(players []PlayerResult) map[string]int {
+	points := make(map[string]int, len(players))
+	for _, p := range players {
+		for _, l := range p.Letters {
+			points[p.Name] += letterValues[l]
+		}
+		if p.Winner {
+			points[p.Name] += p.WinPoints
+		}
+	}
+	return points
+}
+
+// Rule describes letter value scoring.
+func (letterValueScorer) Rule() string {
+	return "Each player gets points for the tiles they used, with rare letters worth more, such as 10 points for Q and Z.  The winner also gets their potential win points."
+}
+
+// Score gives each player a bonus for each long word on top of the flat score.
+func (wordLengthScorer) Score(players []PlayerResult) map[string]int {
+	points := flatScorer{}.Score(players)
+	for _, p := range players {
+		for _, w := range p.Words {
+			if n := len([]rune(w)); n >= wordLengthBonusMin {
+				points[p.Name] += n - wordLengthBonusMin + 1
+			}
+		}
+	}
+	return points
+}
+
+// Rule describes word length scoring.
+func (wordLengthScorer) Rule() string {
+	return "The winner gets their potential win points.  Other players each get 1 point.  Players get a bonus point for each valid word of " + strconv.Itoa(wordLengthBonusMin) + " letters and another for each additional letter."
+}
+
+// Score ranks the winner first and other players by the number of tiles they used.
+// Each player gets a point for themselves and each player ranked below them.  Players who used the same number of tiles share the higher rank.
+// The winner gets their win points if it is more than their rank points.
+func (rankScorer) Score(players []PlayerResult) map[string]int {
+	ranked := append([]PlayerResult{}, players...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.Winner != b.Winner {
+			return a.Winner
+		}
+		return len(a.Letters) > len(b.Letters)
+	})
+	points := make(map[string]int, len(ranked))
+	rank := 0
+	for i, p := range ranked {
+		if i == 0 || ranked[i-1].Winner || len(p.Letters) != len(ranked[i-1].Letters) {
+			rank = i
+		}
+		points[p.Name] = len(ranked) - rank
+		if p.Winner && p.WinPoints > points[p.Name] {
+			points[p.Name] = p.WinPoints
+		}
+	}
+	return points
+}
+
+// Rule describes rank scoring.
+func (rankScorer) Rule() string {
+	return "Players are ranked by the number of tiles they used, with the winner first.  Each player gets 1 point plus 1 point for each player ranked below them.  The winner gets their potential win points if that is more."
+}
@@ -0,0 +1,167 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jacobpatterson1549/selene-bananas/game/tile"
+)
+
+func TestScoringScorer(t *testing.T) {
+	scorerTests := []struct {
+		Scoring
+		want   Scorer
+		wantOk bool
+	}{
+		{FlatScoring, flatScorer{}, true},
+		{LetterValueScoring, letterValueScorer{}, true},
+		{WordLengthScoring, wordLengthScorer{}, true},
+		{RankScoring, rankScorer{}, true},
+		{Scoring: -1},
+		{Scoring: 4},
+	}
+	for i, test := range scorerTests {
+		got, err := test.Scoring.Scorer()
+		switch {
+		case !test.wantOk:
+			if err == nil {
+				t.Errorf("Test %v: wanted error", i)
+			}
+		case err != nil:
+			t.Errorf("Test %v: unwanted error: %v", i, err)
+		case test.want != got:
+			t.Errorf("Test %v: scorers not equal: wanted %T, got %T", i, test.want, got)
+		}
+	}
+}
+
+func TestScoringString(t *testing.T) {
+	stringTests := []struct {
+		Scoring
+		want string
+	}{
+		{FlatScoring, "Flat"},
+		{LetterValueScoring, "Letter Value"},
+		{WordLengthScoring, "Word Length"},
+		{RankScoring, "Rank"},
+		{-1, "?"},
+	}
+	for i, test := range stringTests {
+		if want, got := test.want, test.Scoring.String(); want != got {
+			t.Errorf("Test %v: wanted %v, got %v", i, want, got)
+		}
+	}
+}
+
+func TestScorerScore(t *testing.T) {
+	letters := func(s string) []tile.Letter {
+		runes := []rune(s)
+		l := make([]tile.Letter, len(runes))
+		for i, r := range runes {
+			l[i] = tile.Letter(r)
+		}
+		return l
+	}
+	players := []PlayerResult{
+		{
+			Name:    "barney",
+			Letters: letters("AÑO"),
+			Words:   []string{"AÑO"},
+		},
+		{
+			Name:      "selene",
+			Winner:    true,
+			WinPoints: 7,
+			Letters:   letters("QUARTZ"),
+			Words:     []string{"QUARTZ"},
+		},
+		{
+			Name:    "fred",
+			Letters: letters("DOGS"),
+			Words:   []string{"DOGS"},
+		},
+		{
+			Name:    "wilma",
+			Letters: letters("FROGS"),
+			Words:   []string{"FROGS", "FRO"},
+		},
+		{
+			Name:    "betty",
+			Letters: letters("BIRD"),
+			Words:   []string{"BIRD"},
+		},
+	}
+	scoreTests := []struct {
+		Scoring
+		want map[string]int
+	}{
+		{
+			Scoring: FlatScoring,
+			want: map[string]int{
+				"barney": 1,
+				"selene": 7,
+				"fred":   1,
+				"wilma":  1,
+				"betty":  1,
+			},
+		},
+		{
+			Scoring: LetterValueScoring,
+			want: map[string]int{
+				"barney": 10,     // 1+8+1
+				"selene": 24 + 7, // 10+1+1+1+1+10
+				"fred":   6,      // 2+1+2+1
+				"wilma":  9,      // 4+1+1+2+1
+				"betty":  7,      // 3+1+1+2
+			},
+		},
+		{
+			Scoring: WordLengthScoring,
+			want: map[string]int{
+				"barney": 1,
+				"selene": 7 + 2,
+				"fred":   1,
+				"wilma":  1 + 1,
+				"betty":  1,
+			},
+		},
+		{
+			Scoring: RankScoring,
+			want: map[string]int{
+				"selene": 7,
+				"wilma":  4,
+				"fred":   3,
+				"betty":  3,
+				"barney": 1,
+			},
+		},
+	}
+	for i, test := range scoreTests {
+		s, err := test.Scoring.Scorer()
+		if err != nil {
+			t.Errorf("Test %v: unwanted error: %v", i, err)
+			continue
+		}
+		got := s.Score(players)
+		if !reflect.DeepEqual(test.want, got) {
+			t.Errorf("Test %v: %v scores not equal:\nwanted: %v\ngot:    %v", i, test.Scoring, test.want, got)
+		}
+	}
+}
+
+func TestRankScorerWinPointsMinimum(t *testing.T) {
+	players := []PlayerResult{
+		{Name: "a", Winner: true, WinPoints: 2},
+		{Name: "b"},
+		{Name: "c"},
+	}
+	want := map[string]int{
+		"a": 3,
+		"b": 2,
+		"c": 2,
+	}
+	got := rankScorer{}.Score(players)
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("wanted winner to get rank points if more than win points:\nwanted: %v\ngot:    %v", want, got)
+	}
+}
@@ -13,6 +13,8 @@ const (
 	Finished
 	// Deleted is the status of a game that has been deleted from the server.
 	Deleted
+	// Challenging is the status of a game that a player has claimed to win while the other players can challenge the words on the board of the player.
+	Challenging
 )
 
 // String returns the display value for the status.
@@ -24,6 +26,8 @@ func (s Status) String() string {
 		return "In Progress"
 	case Finished:
 		return "Finished"
+	case Challenging:
+		return "Challenging"
 	}
 	return "?"
 }
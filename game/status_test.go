@@ -13,13 +13,14 @@ func TestStatusString(t *testing.T) {
 			InProgress,
 			Finished,
 			Deleted,
+			Challenging,
 			-1,
 		}
 		statusStrings := make(map[string]struct{})
 		for _, s := range statuses {
 			statusStrings[s.String()] = struct{}{}
 		}
-		want := 4
+		want := 5
 		got := len(statusStrings)
 		if want != got {
 			t.Errorf("wanted %v unique status strings, got %v", want, got)
@@ -18,12 +18,11 @@ func (l *Letter) UnmarshalJSON(b []byte) error {
 	if err != nil {
 		return err
 	}
-	if len(s) != 1 {
-		return errors.New("letter longer than 1 character: " + s)
+	runes := []rune(s)
+	if len(runes) != 1 {
+		return errors.New("letter not 1 character: " + s)
 	}
-	b0 := s[0]
-	r := rune(b0)
-	l2, err := newLetter(r)
+	l2, err := newLetter(runes[0])
 	if err != nil {
 		return err
 	}
@@ -17,6 +17,10 @@ func TestMarshalLetter(t *testing.T) {
 			Letter: 'X',
 			want:   `"X"`,
 		},
+		{
+			Letter: 'Ö',
+			want:   `"Ö"`,
+		},
 	}
 	for i, test := range marshalLetterTests {
 		got, err := json.Marshal(test.Letter)
@@ -62,6 +66,25 @@ func TestUnmarshalLetter(t *testing.T) {
 			want:   'X',
 			wantOk: true,
 		},
+		{
+			json:   `"Ñ"`,
+			want:   'Ñ',
+			wantOk: true,
+		},
+		{
+			json:   `"\u00dc"`,
+			want:   'Ü',
+			wantOk: true,
+		},
+		{
+			json: `"ÄB"`,
+		},
+		{
+			json: `"ä"`,
+		},
+		{
+			json: `""`,
+		},
 	}
 	for i, test := range unmarshalLetterTests {
 		var got Letter
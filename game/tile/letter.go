@@ -1,14 +1,17 @@
 package tile
 
-import "errors"
+import (
+	"errors"
+	"unicode"
+)
 
 // Letter is the value of a tile.
 type Letter rune
 
-// newLetter creates a letter from the rune.
+// newLetter creates a letter from the rune, which can be any uppercase letter, such as Ñ.
 func newLetter(r rune) (*Letter, error) {
-	if r < 'A' || 'Z' < r {
-		return nil, errors.New("letter must be uppercase and between A and Z: " + string(r))
+	if !unicode.IsLetter(r) || !unicode.IsUpper(r) {
+		return nil, errors.New("letter must be uppercase: " + string(r))
 	}
 	l := Letter(r)
 	return &l, nil
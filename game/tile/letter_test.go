@@ -32,6 +32,17 @@ func TestNewLetter(t *testing.T) {
 			want:   'L',
 			wantOk: true,
 		},
+		{
+			ch:     'Ñ',
+			want:   'Ñ',
+			wantOk: true,
+		},
+		{
+			ch: 'ü',
+		},
+		{
+			ch: '1',
+		},
 	}
 	for i, test := range newLetterTests {
 		got, err := newLetter(test.ch)
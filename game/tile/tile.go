@@ -23,7 +23,7 @@ type (
 	Y int
 )
 
-// New creates a new Tile, throwing an error if the letter is not uppercase in the A-Z range.
+// New creates a new Tile, throwing an error if the letter is not an uppercase letter.
 func New(id ID, r rune) (*Tile, error) {
 	ch, err := newLetter(r)
 	if err != nil {
@@ -0,0 +1,88 @@
+package game
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jacobpatterson1549/selene-bananas/game/tile"
+)
+
+// TileBag identifies the tiles that a game is played with.
+type TileBag int
+
+const (
+	// ClassicTileBag has the 144 default tiles of the language of the game.
+	ClassicTileBag TileBag = iota
+	// BananaSplitTileBag has half of the classic tiles, for short games.
+	BananaSplitTileBag
+	// DoubleTileBag has two of each classic tile, for long games.
+	DoubleTileBag
+	// CustomTileBag has the letter counts chosen by the creator of the game.
+	CustomTileBag
+)
+
+// MaxLetterCount is the most tiles of a single letter that a custom tile bag can have.
+const MaxLetterCount = 99
+
+// TileLetters gets the letters of the tiles in the bag, one letter for each tile.
+// The presets are made from the classic tile letters.  The letter counts are only used for custom tile bags.
+func (b TileBag) TileLetters(classicTileLetters string, letterCounts map[string]int) (string, error) {
+	switch b {
+	case ClassicTileBag:
+		return classicTileLetters, nil
+	case BananaSplitTileBag:
+		letters := []rune(classicTileLetters)
+		half := make([]rune, 0, (len(letters)+1)/2)
+		for i := 0; i < len(letters); i += 2 { // the classic tile letters are sorted, so this keeps about half of each letter
+			half = append(half, letters[i])
+		}
+		return string(half), nil
+	case DoubleTileBag:
+		return strings.Repeat(classicTileLetters, 2), nil
+	case CustomTileBag:
+		return customTileLetters(letterCounts)
+	}
+	return "", errors.New("unknown tile bag: " + strconv.Itoa(int(b)))
+}
+
+// customTileLetters creates the sorted letters for the counts of each letter.
+func customTileLetters(letterCounts map[string]int) (string, error) {
+	letters := make([]string, 0, len(letterCounts))
+	for l, n := range letterCounts {
+		r, size := utf8.DecodeRuneInString(l)
+		switch {
+		case size != len(l), len(l) == 0:
+			return "", errors.New("letter counts must be for single letters: " + l)
+		case n < 0, n > MaxLetterCount:
+			return "", errors.New("count of " + l + " tiles must be between 0 and " + strconv.Itoa(MaxLetterCount) + ": " + strconv.Itoa(n))
+		}
+		if _, err := tile.New(0, r); err != nil {
+			return "", err
+		}
+		letters = append(letters, l)
+	}
+	sort.Strings(letters)
+	var sb strings.Builder
+	for _, l := range letters {
+		sb.WriteString(strings.Repeat(l, letterCounts[l]))
+	}
+	return sb.String(), nil
+}
+
+// String returns the display value for the tile bag.
+func (b TileBag) String() string {
+	switch b {
+	case ClassicTileBag:
+		return "Classic"
+	case BananaSplitTileBag:
+		return "Banana Split"
+	case DoubleTileBag:
+		return "Double"
+	case CustomTileBag:
+		return "Custom"
+	}
+	return "?"
+}
@@ -0,0 +1,87 @@
+package game
+
+import (
+	"testing"
+)
+
+func TestTileBagTileLetters(t *testing.T) {
+	tileLettersTests := []struct {
+		TileBag
+		letterCounts    map[string]int
+		wantOk          bool
+		wantTileLetters string
+	}{
+		{
+			TileBag: -1,
+		},
+		{
+			TileBag:         ClassicTileBag,
+			letterCounts:    map[string]int{"Z": 4}, // ignored
+			wantOk:          true,
+			wantTileLetters: "AAABBC",
+		},
+		{
+			TileBag:         BananaSplitTileBag,
+			wantOk:          true,
+			wantTileLetters: "AAB",
+		},
+		{
+			TileBag:         DoubleTileBag,
+			wantOk:          true,
+			wantTileLetters: "AAABBCAAABBC",
+		},
+		{
+			TileBag:         CustomTileBag,
+			letterCounts:    map[string]int{"Ñ": 1, "Q": 2, "E": 3, "X": 0},
+			wantOk:          true,
+			wantTileLetters: "EEEQQÑ",
+		},
+		{
+			TileBag: CustomTileBag,
+		},
+		{ // lowercase
+			TileBag:      CustomTileBag,
+			letterCounts: map[string]int{"a": 1},
+		},
+		{ // multiple letters
+			TileBag:      CustomTileBag,
+			letterCounts: map[string]int{"AB": 1},
+		},
+		{ // empty letter
+			TileBag:      CustomTileBag,
+			letterCounts: map[string]int{"": 1},
+		},
+		{ // negative count
+			TileBag:      CustomTileBag,
+			letterCounts: map[string]int{"A": -1},
+		},
+		{ // too many of a letter
+			TileBag:      CustomTileBag,
+			letterCounts: map[string]int{"A": MaxLetterCount + 1},
+		},
+	}
+	for i, test := range tileLettersTests {
+		got, err := test.TileBag.TileLetters("AAABBC", test.letterCounts)
+		switch {
+		case !test.wantOk:
+			if err == nil && len(got) != 0 {
+				t.Errorf("Test %v: wanted error or no tiles, got %q", i, got)
+			}
+		case err != nil:
+			t.Errorf("Test %v: unwanted error: %v", i, err)
+		case test.wantTileLetters != got:
+			t.Errorf("Test %v: tile letters not equal: wanted %q, got %q", i, test.wantTileLetters, got)
+		}
+	}
+}
+
+func TestTileBagString(t *testing.T) {
+	for i, b := range []TileBag{ClassicTileBag, BananaSplitTileBag, DoubleTileBag, CustomTileBag} {
+		if b.String() == "?" {
+			t.Errorf("Test %v: wanted display value for %v", i, int(b))
+		}
+	}
+	if want, got := "?", TileBag(-1).String(); want != got {
+		t.Errorf("wanted %q for unknown tile bag, got %q", want, got)
+	}
+}
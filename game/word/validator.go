@@ -5,42 +5,332 @@ import (
 	"bufio"
 	"errors"
 	"io"
+	"io/fs"
+	"sort"
+	"strconv"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
-// Validator determines if words are valid.
-type Validator map[string]struct{}
+type (
+	// Validator determines if words are valid.
+	// The words are stored in a directed acyclic word graph (DAWG): a trie where words with the same suffixes share nodes.
+	Validator struct {
+		// nodes are the states of the graph.  The first node is the root, the state of the empty prefix.
+		nodes []node
+		// edges are the letters from each node to the next, sorted by letter for each node.
+		edges []edge
+		// numWords is the number of words in the graph.
+		numWords int
+	}
+
+	// node is a state in the graph.
+	node struct {
+		// firstEdge is the index of the first edge from the node.
+		firstEdge int32
+		// numEdges is the number of edges from the node.
+		numEdges int32
+		// final is set if the letters to reach the node form a word.
+		final bool
+	}
+
+	// edge is a letter from one node to another.
+	edge struct {
+		letter rune
+		node   int32
+	}
+
+	// builder creates a minimal graph by adding words in sorted order.
+	// Each time a word is added, the nodes of the previous word that are not shared with it are merged with equivalent nodes that have already been added.
+	builder struct {
+		nodes     []builderNode
+		register  map[string]int32
+		unchecked []uncheckedEdge
+		previous  []rune
+		numWords  int
+		// key is reused to create signatures of nodes.
+		key []byte
+	}
+
+	// builderNode is a node of the graph while it is being built.
+	builderNode struct {
+		edges []edge
+		final bool
+	}
+
+	// uncheckedEdge is an edge to a node that has not been merged with an equivalent node yet.
+	uncheckedEdge struct {
+		parent int32
+		child  int32
+	}
+)
+
+const (
+	// WildcardLetter is the character in patterns that matches any letter.
+	WildcardLetter = '?'
+)
 
 // NewValidator consumes the lower case words in the reader to use for validating.
 func NewValidator(r io.Reader) (*Validator, error) {
 	if r == nil {
 		return nil, errors.New("reader required to initialize word validator from")
 	}
-	v := make(Validator)
+	var words []string
 	scanner := bufio.NewScanner(r)
 	scanner.Split(bufio.ScanWords)
 	for scanner.Scan() {
 		rawWord := scanner.Text()
-		v[rawWord] = struct{}{}
+		for _, r := range rawWord {
+			if !unicode.IsLower(r) {
+				return nil, errors.New("wanted only lower case words, got " + rawWord)
+			}
+		}
+		words = append(words, rawWord)
 	}
 	if err := scanner.Err(); err != nil {
 		return nil, err
 	}
-	for w := range v {
-		for _, r := range w {
-			if !unicode.IsLower(r) {
-				return nil, errors.New("wanted only lower case words, got " + w)
-			}
-		}
+	sort.Strings(words)
+	b := newBuilder()
+	for _, w := range words {
+		b.add(w)
 	}
+	v := b.validator()
 	return &v, nil
 }
 
+// NewValidators creates a validator for each ".txt" file of lower case words in the file system.
+// The validators are keyed by the file names without extensions, such as "en" for "en.txt".
+func NewValidators(fsys fs.FS) (map[string]*Validator, error) {
+	if fsys == nil {
+		return nil, errors.New("file system required to initialize word validators from")
+	}
+	fileNames, err := fs.Glob(fsys, "*.txt")
+	if err != nil {
+		return nil, err
+	}
+	if len(fileNames) == 0 {
+		return nil, errors.New("no word files found")
+	}
+	validators := make(map[string]*Validator, len(fileNames))
+	for _, n := range fileNames {
+		f, err := fsys.Open(n)
+		if err != nil {
+			return nil, err
+		}
+		v, err := NewValidator(f)
+		f.Close()
+		if err != nil {
+			return nil, errors.New("reading words from " + n + ": " + err.Error())
+		}
+		validators[strings.TrimSuffix(n, ".txt")] = v
+	}
+	return validators, nil
+}
+
 // Validate determines whether or not the word is valid.
 // Words are converted to lowercase before checking.
 func (v Validator) Validate(word string) bool {
-	lowerWord := strings.ToLower(word)
-	_, ok := v[lowerWord]
+	n, ok := v.walk(word)
+	return ok && v.nodes[n].final
+}
+
+// HasPrefix determines whether or not any valid word starts with the prefix.
+// The prefix is converted to lowercase before checking.  The empty prefix is only valid if there are words.
+func (v Validator) HasPrefix(prefix string) bool {
+	if v.numWords == 0 {
+		return false
+	}
+	_, ok := v.walk(prefix)
 	return ok
 }
+
+// Match returns the sorted lower case words that match the pattern.
+// The pattern is converted to lowercase.  Each WildcardLetter in the pattern matches any single letter, so "C?T" matches "cat" and "cut".
+func (v Validator) Match(pattern string) []string {
+	if v.numWords == 0 {
+		return nil
+	}
+	letters := []rune(strings.ToLower(pattern))
+	var words []string
+	prefix := make([]rune, 0, len(letters))
+	var match func(n int32, i int)
+	match = func(n int32, i int) {
+		if i == len(letters) {
+			if v.nodes[n].final {
+				words = append(words, string(prefix))
+			}
+			return
+		}
+		for _, e := range v.nodeEdges(n) {
+			if letters[i] != WildcardLetter && letters[i] != e.letter {
+				continue
+			}
+			prefix = append(prefix, e.letter)
+			match(e.node, i+1)
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	match(0, 0)
+	return words
+}
+
+// Len returns the number of words of the validator.
+func (v Validator) Len() int {
+	return v.numWords
+}
+
+// Words returns the sorted lower case words of the validator.
+func (v Validator) Words() []string {
+	words := make([]string, 0, v.numWords)
+	if v.numWords == 0 {
+		return words
+	}
+	var prefix []rune
+	var visit func(n int32)
+	visit = func(n int32) {
+		if v.nodes[n].final {
+			words = append(words, string(prefix))
+		}
+		for _, e := range v.nodeEdges(n) {
+			prefix = append(prefix, e.letter)
+			visit(e.node)
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	visit(0)
+	return words
+}
+
+// walk follows the lower case letters of the word from the root, returning the node that is reached.
+// False is returned if no word starts with the letters.
+func (v Validator) walk(word string) (int32, bool) {
+	if len(v.nodes) == 0 {
+		return 0, false
+	}
+	var n int32
+	for _, r := range strings.ToLower(word) {
+		next, ok := v.next(n, r)
+		if !ok {
+			return 0, false
+		}
+		n = next
+	}
+	return n, true
+}
+
+// next finds the node reached from the node by the letter.
+func (v Validator) next(n int32, letter rune) (int32, bool) {
+	edges := v.nodeEdges(n)
+	i := sort.Search(len(edges), func(i int) bool {
+		return edges[i].letter >= letter
+	})
+	if i < len(edges) && edges[i].letter == letter {
+		return edges[i].node, true
+	}
+	return 0, false
+}
+
+// nodeEdges returns the edges from the node, sorted by letter.
+func (v Validator) nodeEdges(n int32) []edge {
+	nd := v.nodes[n]
+	return v.edges[nd.firstEdge : nd.firstEdge+nd.numEdges]
+}
+
+// newBuilder creates a builder with only a root node.
+func newBuilder() *builder {
+	b := builder{
+		nodes:    make([]builderNode, 1),
+		register: make(map[string]int32),
+	}
+	return &b
+}
+
+// add adds the word to the graph.  Words must be added in sorted order.  Duplicate words are ignored.
+func (b *builder) add(word string) {
+	letters := []rune(word)
+	commonPrefixLength := 0
+	for commonPrefixLength < len(letters) && commonPrefixLength < len(b.previous) && letters[commonPrefixLength] == b.previous[commonPrefixLength] {
+		commonPrefixLength++
+	}
+	if b.previous != nil && commonPrefixLength == len(letters) && commonPrefixLength == len(b.previous) {
+		return
+	}
+	b.minimize(commonPrefixLength)
+	var n int32
+	if len(b.unchecked) > 0 {
+		n = b.unchecked[len(b.unchecked)-1].child
+	}
+	for _, r := range letters[commonPrefixLength:] {
+		child := int32(len(b.nodes))
+		b.nodes = append(b.nodes, builderNode{})
+		b.nodes[n].edges = append(b.nodes[n].edges, edge{letter: r, node: child})
+		b.unchecked = append(b.unchecked, uncheckedEdge{parent: n, child: child})
+		n = child
+	}
+	b.nodes[n].final = true
+	b.previous = letters
+	b.numWords++
+}
+
+// minimize merges the unchecked nodes deeper than the depth with equivalent registered nodes, registering nodes that have no equivalent.
+func (b *builder) minimize(depth int) {
+	for i := len(b.unchecked) - 1; i >= depth; i-- {
+		u := b.unchecked[i]
+		b.key = b.signature(b.key[:0], u.child)
+		if n, ok := b.register[string(b.key)]; ok {
+			parentEdges := b.nodes[u.parent].edges
+			parentEdges[len(parentEdges)-1].node = n
+			b.nodes[u.child] = builderNode{}
+		} else {
+			b.register[string(b.key)] = u.child
+		}
+	}
+	b.unchecked = b.unchecked[:depth]
+}
+
+// signature appends a key for the node to the buffer that is the same for all equivalent nodes.
+// Nodes are equivalent if they are both final or not final and have the same edges to the same nodes.
+func (b builder) signature(buf []byte, n int32) []byte {
+	if b.nodes[n].final {
+		buf = append(buf, '!')
+	}
+	for _, e := range b.nodes[n].edges {
+		buf = utf8.AppendRune(buf, e.letter)
+		buf = strconv.AppendInt(buf, int64(e.node), 10)
+		buf = append(buf, ',')
+	}
+	return buf
+}
+
+// validator finishes the graph and copies the nodes that can be reached from the root into the compact form of the validator.
+func (b *builder) validator() Validator {
+	b.minimize(0)
+	ids := map[int32]int32{0: 0}
+	order := []int32{0}
+	for i := 0; i < len(order); i++ {
+		for _, e := range b.nodes[order[i]].edges {
+			if _, ok := ids[e.node]; !ok {
+				ids[e.node] = int32(len(order))
+				order = append(order, e.node)
+			}
+		}
+	}
+	v := Validator{
+		nodes:    make([]node, len(order)),
+		numWords: b.numWords,
+	}
+	for i, id := range order {
+		bn := b.nodes[id]
+		v.nodes[i] = node{
+			firstEdge: int32(len(v.edges)),
+			numEdges:  int32(len(bn.edges)),
+			final:     bn.final,
+		}
+		for _, e := range bn.edges {
+			v.edges = append(v.edges, edge{letter: e.letter, node: ids[e.node]})
+		}
+	}
+	return v
+}
@@ -4,8 +4,10 @@ import (
 	"errors"
 	"io"
 	"reflect"
+	"runtime"
 	"strings"
 	"testing"
+	"testing/fstest"
 	"testing/iotest"
 )
 
@@ -14,17 +16,13 @@ func reader(words string) io.Reader {
 }
 
 func TestNewValidator(t *testing.T) {
-	wantWords := func(words ...string) *Validator {
-		validator := Validator(make(map[string]struct{}, len(words)))
-		for _, w := range words {
-			validator[w] = struct{}{}
-		}
-		return &validator
+	wantWords := func(words ...string) []string {
+		return append([]string{}, words...)
 	}
 	newValidatorTests := []struct {
 		wantOk bool
 		words  io.Reader
-		want   *Validator
+		want   []string
 	}{
 		{},
 		{
@@ -40,6 +38,11 @@ func TestNewValidator(t *testing.T) {
 			words:  reader("a bad cat"),
 			want:   wantWords("a", "bad", "cat"),
 		},
+		{
+			wantOk: true,
+			words:  reader("cat bad a cat"),
+			want:   wantWords("a", "bad", "cat"),
+		},
 		{
 			words: reader("A man, a plan, a canal, panama!"),
 		},
@@ -59,12 +62,75 @@ func TestNewValidator(t *testing.T) {
 			}
 		case err != nil:
 			t.Errorf("Test %v: unwanted error: %v", i, err)
-		case !reflect.DeepEqual(test.want, got):
-			t.Errorf("Test %v:\nwanted: %v\ngot:    %v", i, test.want, got)
+		case !reflect.DeepEqual(test.want, got.Words()):
+			t.Errorf("Test %v:\nwanted: %v\ngot:    %v", i, test.want, got.Words())
 		}
 	}
 }
 
+func TestNewValidators(t *testing.T) {
+	newValidatorsTests := []struct {
+		fsys   fstest.MapFS
+		wantOk bool
+		want   map[string][]string
+	}{
+		{ // no files
+			fsys: fstest.MapFS{},
+		},
+		{ // no word files
+			fsys: fstest.MapFS{
+				"README.md": &fstest.MapFile{Data: []byte("words")},
+			},
+		},
+		{ // bad words
+			fsys: fstest.MapFS{
+				"en.txt": &fstest.MapFile{Data: []byte("apple")},
+				"es.txt": &fstest.MapFile{Data: []byte("Año")},
+			},
+		},
+		{
+			fsys: fstest.MapFS{
+				"en.txt":    &fstest.MapFile{Data: []byte("apple bat")},
+				"es.txt":    &fstest.MapFile{Data: []byte("año niño")},
+				"de.txt":    &fstest.MapFile{Data: []byte("über")},
+				"README.md": &fstest.MapFile{Data: []byte("not words")},
+			},
+			wantOk: true,
+			want: map[string][]string{
+				"en": {"APPLE", "bat"},
+				"es": {"AÑO", "niño"},
+				"de": {"ÜBER"},
+			},
+		},
+	}
+	for i, test := range newValidatorsTests {
+		got, err := NewValidators(test.fsys)
+		switch {
+		case !test.wantOk:
+			if err == nil {
+				t.Errorf("Test %v: wanted error", i)
+			}
+		case err != nil:
+			t.Errorf("Test %v: unwanted error: %v", i, err)
+		case len(test.want) != len(got):
+			t.Errorf("Test %v: wanted %v validators, got %v", i, len(test.want), len(got))
+		default:
+			for n, words := range test.want {
+				for _, w := range words {
+					if v, ok := got[n]; !ok || !v.Validate(w) {
+						t.Errorf("Test %v: wanted %v to be valid for %v validator", i, w, n)
+					}
+				}
+			}
+		}
+	}
+	t.Run("nil file system", func(t *testing.T) {
+		if _, err := NewValidators(nil); err == nil {
+			t.Error("wanted error")
+		}
+	})
+}
+
 func TestValidate(t *testing.T) {
 	validateTests := []struct {
 		word string
@@ -100,3 +166,259 @@ func TestValidate(t *testing.T) {
 		}
 	}
 }
+
+func TestWords(t *testing.T) {
+	v, err := NewValidator(reader("cat apple bat apple"))
+	if err != nil {
+		t.Fatalf("unwanted error: %v", err)
+	}
+	want := []string{"apple", "bat", "cat"}
+	if got := v.Words(); !reflect.DeepEqual(want, got) {
+		t.Errorf("words not equal: wanted %v, got %v", want, got)
+	}
+	if want, got := len(want), v.Len(); want != got {
+		t.Errorf("word counts not equal: wanted %v, got %v", want, got)
+	}
+}
+
+func TestWordsEmpty(t *testing.T) {
+	var v Validator
+	if got := v.Words(); len(got) != 0 {
+		t.Errorf("wanted no words, got %v", got)
+	}
+	if got := v.Len(); got != 0 {
+		t.Errorf("wanted no words, got %v", got)
+	}
+}
+
+func TestHasPrefix(t *testing.T) {
+	hasPrefixTests := []struct {
+		words  string
+		prefix string
+		want   bool
+	}{
+		{},
+		{
+			words: "apple bat car",
+			want:  true,
+		},
+		{
+			words:  "apple bat car",
+			prefix: "ap",
+			want:   true,
+		},
+		{
+			words:  "apple bat car",
+			prefix: "BA",
+			want:   true,
+		},
+		{
+			words:  "apple bat car",
+			prefix: "car",
+			want:   true,
+		},
+		{
+			words:  "apple bat car",
+			prefix: "cart",
+		},
+		{
+			words:  "apple bat car",
+			prefix: "d",
+		},
+		{
+			words:  "año",
+			prefix: "AÑ",
+			want:   true,
+		},
+	}
+	for i, test := range hasPrefixTests {
+		v, err := NewValidator(reader(test.words))
+		switch {
+		case err != nil:
+			t.Errorf("Test %v: unwanted error: %v", i, err)
+		case test.want != v.HasPrefix(test.prefix):
+			t.Errorf("Test %v: wanted %q to be a prefix of %q: %v", i, test.prefix, test.words, test.want)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	matchTests := []struct {
+		pattern string
+		want    []string
+	}{
+		{},
+		{
+			pattern: "cat",
+			want:    []string{"cat"},
+		},
+		{
+			pattern: "C?T",
+			want:    []string{"cat", "cot", "cut"},
+		},
+		{
+			pattern: "?a?",
+			want:    []string{"bat", "cat"},
+		},
+		{
+			pattern: "???",
+			want:    []string{"bat", "cat", "cot", "cut"},
+		},
+		{
+			pattern: "????",
+			want:    []string{"cats"},
+		},
+		{
+			pattern: "c?",
+		},
+		{
+			pattern: "z??",
+		},
+	}
+	v, err := NewValidator(reader("cat cot cut cats bat"))
+	if err != nil {
+		t.Fatalf("unwanted error: %v", err)
+	}
+	for i, test := range matchTests {
+		if got := v.Match(test.pattern); !reflect.DeepEqual(test.want, got) {
+			t.Errorf("Test %v: words matching %q not equal:\nwanted: %v\ngot:    %v", i, test.pattern, test.want, got)
+		}
+	}
+}
+
+func TestValidatorSharesSuffixes(t *testing.T) {
+	v, err := NewValidator(reader("bats cats hats"))
+	if err != nil {
+		t.Fatalf("unwanted error: %v", err)
+	}
+	// root -> (b|c|h) -> a -> t -> s
+	if want, got := 5, len(v.nodes); want != got {
+		t.Errorf("wanted words with the same suffix to share nodes: wanted %v nodes, got %v", want, got)
+	}
+	for _, w := range []string{"bats", "cats", "hats"} {
+		if !v.Validate(w) {
+			t.Errorf("wanted %v to be valid", w)
+		}
+	}
+	for _, w := range []string{"bat", "mats", "bats "} {
+		if v.Validate(w) {
+			t.Errorf("wanted %v to be invalid", w)
+		}
+	}
+}
+
+// benchmarkWords creates a deterministic list of lower case words to benchmark with.
+func benchmarkWords(numWords int) []string {
+	const letters = "abcdefghijklmnopqrstuvwxyz"
+	words := make([]string, numWords)
+	seed := uint32(1)
+	for i := range words {
+		var sb strings.Builder
+		seed = seed*1664525 + 1013904223
+		length := 2 + int(seed>>28)%10
+		for j := 0; j < length; j++ {
+			seed = seed*1664525 + 1013904223
+			sb.WriteByte(letters[int(seed>>24)%len(letters)])
+		}
+		words[i] = sb.String()
+	}
+	return words
+}
+
+func BenchmarkNewValidator(b *testing.B) {
+	words := strings.Join(benchmarkWords(100000), " ")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := NewValidator(reader(words)); err != nil {
+			b.Fatalf("unwanted error: %v", err)
+		}
+	}
+}
+
+func BenchmarkNewMap(b *testing.B) {
+	words := strings.Join(benchmarkWords(100000), " ")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		m := make(map[string]struct{})
+		for _, w := range strings.Fields(words) {
+			m[w] = struct{}{}
+		}
+	}
+}
+
+// retainedBytes measures how much memory is still used after creating a value.
+func retainedBytes(create func() any) uint64 {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	v := create()
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+	runtime.KeepAlive(v)
+	if after.HeapAlloc < before.HeapAlloc {
+		return 0
+	}
+	return after.HeapAlloc - before.HeapAlloc
+}
+
+func BenchmarkValidatorMemory(b *testing.B) {
+	words := strings.Join(benchmarkWords(100000), " ")
+	var retained uint64
+	for i := 0; i < b.N; i++ {
+		retained = retainedBytes(func() any {
+			v, _ := NewValidator(reader(words))
+			return v
+		})
+	}
+	b.ReportMetric(float64(retained), "retained-B")
+}
+
+func BenchmarkMapMemory(b *testing.B) {
+	words := strings.Join(benchmarkWords(100000), " ")
+	var retained uint64
+	for i := 0; i < b.N; i++ {
+		retained = retainedBytes(func() any {
+			m := make(map[string]struct{})
+			for _, w := range strings.Fields(words) {
+				m[w] = struct{}{}
+			}
+			return m
+		})
+	}
+	b.ReportMetric(float64(retained), "retained-B")
+}
+
+func BenchmarkValidate(b *testing.B) {
+	words := benchmarkWords(100000)
+	v, err := NewValidator(reader(strings.Join(words, " ")))
+	if err != nil {
+		b.Fatalf("unwanted error: %v", err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v.Validate(words[i%len(words)])
+	}
+}
+
+func BenchmarkValidateMap(b *testing.B) {
+	words := benchmarkWords(100000)
+	m := make(map[string]struct{}, len(words))
+	for _, w := range words {
+		m[w] = struct{}{}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = m[strings.ToLower(words[i%len(words)])]
+	}
+}
+
+func BenchmarkMatch(b *testing.B) {
+	v, err := NewValidator(reader(strings.Join(benchmarkWords(100000), " ")))
+	if err != nil {
+		b.Fatalf("unwanted error: %v", err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v.Match("c?t??")
+	}
+}
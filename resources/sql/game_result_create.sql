@@ -0,0 +1,42 @@
+CREATE OR REPLACE FUNCTION game_result_create
+	( IN game_id INT
+	, IN config TEXT
+	, IN usernames TEXT
+	, IN winner VARCHAR
+	, IN win_points INT
+	, IN word_count INT
+	, IN longest_word VARCHAR
+	, IN finished_at BIGINT
+	, IN duration_sec BIGINT
+	, IN finish_order TEXT
+	, OUT id INT
+	) RETURNS SETOF INT
+AS
+$$
+	INSERT
+	INTO game_results
+		( game_id
+		, config
+		, usernames
+		, winner
+		, win_points
+		, word_count
+		, longest_word
+		, finished_at
+		, duration_sec
+		, finish_order
+		)
+	SELECT
+		game_result_create.game_id
+		, game_result_create.config
+		, ARRAY(SELECT json_array_elements_text(game_result_create.usernames::json))
+		, game_result_create.winner
+		, game_result_create.win_points
+		, game_result_create.word_count
+		, game_result_create.longest_word
+		, game_result_create.finished_at
+		, game_result_create.duration_sec
+		, ARRAY(SELECT json_array_elements_text(NULLIF(game_result_create.finish_order, 'null')::json))
+	RETURNING id
+$$
+LANGUAGE SQL;
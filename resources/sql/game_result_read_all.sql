@@ -0,0 +1,23 @@
+CREATE OR REPLACE FUNCTION game_result_read_all
+	( IN username VARCHAR
+	, OUT results TEXT
+	)
+AS
+$$
+	SELECT COALESCE(json_agg(json_build_object
+		( 'gameID', gr.game_id
+		, 'config', gr.config::json
+		, 'players', gr.usernames
+		, 'winner', gr.winner
+		, 'winPoints', gr.win_points
+		, 'wordCount', gr.word_count
+		, 'longestWord', gr.longest_word
+		, 'finishedAt', gr.finished_at
+		, 'durationSec', gr.duration_sec
+		, 'finishOrder', NULLIF(gr.finish_order, '{}')
+		) ORDER BY gr.finished_at DESC, gr.id DESC), '[]')::TEXT
+	FROM game_results
+	AS gr
+	WHERE game_result_read_all.username = ANY(gr.usernames)
+$$
+LANGUAGE SQL;
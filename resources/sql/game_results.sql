@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS game_results
+    ( id SERIAL PRIMARY KEY
+    , game_id INT NOT NULL
+    , config TEXT NOT NULL
+    , usernames VARCHAR(32)[] NOT NULL
+    , winner VARCHAR(32) NOT NULL
+    , win_points INT NOT NULL
+    , word_count INT NOT NULL
+    , longest_word VARCHAR(64) NOT NULL
+    , finished_at BIGINT NOT NULL
+    , duration_sec BIGINT NOT NULL
+    , finish_order VARCHAR(32)[] NOT NULL DEFAULT '{}'
+    )
+;
+ALTER TABLE game_results
+    ADD COLUMN IF NOT EXISTS finish_order VARCHAR(32)[] NOT NULL DEFAULT '{}'
+;
@@ -0,0 +1,12 @@
+CREATE OR REPLACE FUNCTION game_state_delete
+	( INOUT id INT
+	) RETURNS SETOF INT
+AS
+$$
+	DELETE
+	FROM game_states
+	AS gs
+	WHERE gs.id = game_state_delete.id
+	RETURNING gs.id
+$$
+LANGUAGE SQL;
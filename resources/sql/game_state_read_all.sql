@@ -0,0 +1,10 @@
+CREATE OR REPLACE FUNCTION game_state_read_all
+	( OUT states TEXT
+	)
+AS
+$$
+	SELECT COALESCE(json_agg(gs.state::json ORDER BY gs.id), '[]')::TEXT
+	FROM game_states
+	AS gs
+$$
+LANGUAGE SQL;
@@ -0,0 +1,19 @@
+CREATE OR REPLACE FUNCTION game_state_save
+	( INOUT id INT
+	, IN state TEXT
+	) RETURNS SETOF INT
+AS
+$$
+	INSERT
+	INTO game_states
+		( id
+		, state
+		)
+	SELECT
+		game_state_save.id
+		, game_state_save.state
+	ON CONFLICT (id) DO UPDATE
+	SET state = EXCLUDED.state
+	RETURNING id
+$$
+LANGUAGE SQL;
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS game_states
+    ( id INT PRIMARY KEY
+    , state TEXT NOT NULL
+    )
+;
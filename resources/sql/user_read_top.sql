@@ -0,0 +1,23 @@
+CREATE OR REPLACE FUNCTION user_read_top
+	( IN row_limit INT
+	, IN row_offset INT
+	, OUT users TEXT
+	)
+AS
+$$
+	SELECT COALESCE(json_agg(json_build_object
+		( 'username', tu.username
+		, 'points', tu.points
+		) ORDER BY tu.points DESC, tu.username), '[]')::TEXT
+	FROM
+		( SELECT u.username
+			, u.points
+		FROM users
+		AS u
+		ORDER BY u.points DESC, u.username
+		LIMIT user_read_top.row_limit
+		OFFSET user_read_top.row_offset
+		)
+	AS tu
+$$
+LANGUAGE SQL;
@@ -0,0 +1,342 @@
+// Package bot contains computer players that join and play games like players using sockets.
+package bot
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jacobpatterson1549/selene-bananas/game"
+	"github.com/jacobpatterson1549/selene-bananas/game/board"
+	"github.com/jacobpatterson1549/selene-bananas/game/message"
+	"github.com/jacobpatterson1549/selene-bananas/game/player"
+	"github.com/jacobpatterson1549/selene-bananas/game/tile"
+	"github.com/jacobpatterson1549/selene-bananas/server/log"
+)
+
+type (
+	// Bot is a computer player that plays a single game.
+	// It sends and receives the same messages a player's socket does.
+	Bot struct {
+		log        log.Logger
+		PlayerName player.Name
+		Addr       message.Addr
+		// InviteCode is sent to join private games.
+		InviteCode string
+		gameID     game.ID
+		skill      Skill
+		board      *board.Board
+		status     game.Status
+		tilesLeft  int
+		solver     *board.Solver
+		// bananasRace is set when there are not enough tiles left for every player to snag, so the bot tries to finish instead.
+		bananasRace bool
+		// waiting is set when the bot has requested tiles but not received them.
+		waiting bool
+		// finishing is set when the bot has tried to finish the game.
+		finishing bool
+		// closing is set when the bot has stopped playing and is waiting to be removed.
+		closing bool
+		Config
+	}
+
+	// Config contains the properties to create bots with.
+	Config struct {
+		// Debug is a flag that causes the bot to log the warnings it receives.
+		Debug bool
+		// Dictionaries are the words the bots can play, keyed by the language of the game.
+		Dictionaries map[game.Language]board.Dictionary
+		// Skills are how well bots play, keyed by their difficulty.
+		Skills map[player.Difficulty]Skill
+		// BoardConfig is the size of the board the bots play on.
+		BoardConfig board.Config
+	}
+
+	// Skill is how well a bot plays.
+	Skill struct {
+		// ThinkTime is how long the bot waits between moves.
+		ThinkTime time.Duration
+		// VocabularySize is the maximum number of words the bot knows.
+		VocabularySize int
+	}
+)
+
+// NewBot creates a bot of the difficulty that plays the game.
+func (cfg Config) NewBot(log log.Logger, pn player.Name, id game.ID, d player.Difficulty) (*Bot, error) {
+	if err := cfg.validate(log, pn, id); err != nil {
+		return nil, fmt.Errorf("creating bot: validation: %w", err)
+	}
+	skill, ok := cfg.Skills[d]
+	if !ok {
+		return nil, fmt.Errorf("no bots with %v difficulty", d)
+	}
+	b := Bot{
+		log:        log,
+		PlayerName: pn,
+		Addr:       message.Addr(pn),
+		gameID:     id,
+		skill:      skill,
+		Config:     cfg,
+	}
+	return &b, nil
+}
+
+// validate ensures the configuration has no errors.
+func (cfg Config) validate(log log.Logger, pn player.Name, id game.ID) error {
+	switch {
+	case log == nil:
+		return fmt.Errorf("log required")
+	case !pn.IsBot():
+		return fmt.Errorf("bot name required")
+	case id <= 0:
+		return fmt.Errorf("game id required")
+	case len(cfg.Dictionaries) == 0:
+		return fmt.Errorf("dictionaries required")
+	}
+	for d, s := range cfg.Skills {
+		switch {
+		case s.ThinkTime <= 0:
+			return fmt.Errorf("positive think time required for %v bots", d)
+		case s.VocabularySize <= 0:
+			return fmt.Errorf("positive vocabulary size required for %v bots", d)
+		}
+	}
+	if err := cfg.BoardConfig.Validate(); err != nil {
+		return fmt.Errorf("board config: %w", err)
+	}
+	return nil
+}
+
+// Run joins the game and plays it in a separate goroutine.
+// Messages from the game are read from the "in" channel and requests are sent on the "out" channel.
+// When the bot stops playing, it sends a SocketClose message, but consumes and ignores messages from the in channel until it is closed.
+func (b *Bot) Run(ctx context.Context, wg *sync.WaitGroup, in <-chan message.Message, out chan<- message.Message) {
+	thinkTicker := time.NewTicker(b.skill.ThinkTime)
+	wg.Add(1)
+	run := func() {
+		defer wg.Done()
+		defer thinkTicker.Stop()
+		b.runSync(ctx, in, out, thinkTicker.C)
+	}
+	go run()
+}
+
+// runSync plays the game until the in channel is closed.
+// Messages to send are queued so the bot can always read from the in channel, even when the out channel is busy.
+func (b *Bot) runSync(ctx context.Context, in <-chan message.Message, out chan<- message.Message, thinkTicks <-chan time.Time) {
+	pending := []message.Message{b.joinGameMessage()}
+	for { // BLOCKING
+		var sendOut chan<- message.Message
+		var next message.Message
+		if len(pending) > 0 {
+			sendOut, next = out, pending[0]
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case sendOut <- next:
+			pending = pending[1:]
+		case m, ok := <-in:
+			if !ok {
+				return
+			}
+			pending = append(pending, b.handleMessage(m)...)
+		case <-thinkTicks:
+			if len(pending) == 0 {
+				pending = b.think()
+			}
+		}
+	}
+}
+
+// message creates a message from the bot for its game.
+func (b Bot) message(t message.Type) message.Message {
+	m := message.Message{
+		Type:       t,
+		PlayerName: b.PlayerName,
+		Addr:       b.Addr,
+		Game: &game.Info{
+			ID: b.gameID,
+		},
+	}
+	return m
+}
+
+// joinGameMessage creates the message to join the game with a board of the configured size.
+func (b Bot) joinGameMessage() message.Message {
+	m := b.message(message.JoinGame)
+	m.Game.Board = &board.Board{
+		Config: b.BoardConfig,
+	}
+	m.Game.InviteCode = b.InviteCode
+	return m
+}
+
+// handleMessage updates the state of the bot from a message from its game, returning messages to send in response.
+func (b *Bot) handleMessage(m message.Message) []message.Message {
+	if b.closing {
+		return nil
+	}
+	switch m.Type {
+	case message.JoinGame, message.RefreshGameBoard:
+		return b.handleBoard(m)
+	case message.ChangeGameStatus:
+		return b.handleStatus(m)
+	case message.ChangeGameTiles:
+		return b.handleTiles(m)
+	case message.SocketWarning, message.SocketError:
+		if b.Debug {
+			b.log.Printf("bot %v received %v", b.PlayerName, m.Info)
+		}
+		b.waiting = false
+		b.finishing = false // the game might have rejected the finish
+		return b.refreshBoard()
+	case message.LeaveGame:
+		return b.close()
+	}
+	return nil
+}
+
+// handleBoard replaces the board of the bot, choosing the words it knows if it has not yet done so.
+func (b *Bot) handleBoard(m message.Message) []message.Message {
+	if m.Game == nil || m.Game.Board == nil {
+		return nil
+	}
+	b.board = m.Game.Board
+	b.board.Config = b.BoardConfig
+	b.status = m.Game.Status
+	b.tilesLeft = m.Game.TilesLeft
+	b.bananasRace = m.Game.BananasRace
+	if b.solver == nil {
+		var cfg game.Config
+		if m.Game.Config != nil {
+			cfg = *m.Game.Config
+		}
+		d, ok := b.Dictionaries[cfg.Language.OrDefault()]
+		if !ok {
+			b.log.Printf("bot %v cannot play games in %v", b.PlayerName, cfg.Language)
+			return b.close()
+		}
+		solverCfg := board.SolverConfig{
+			VocabularySize:     b.skill.VocabularySize,
+			MinLength:          cfg.MinLength,
+			ProhibitDuplicates: cfg.ProhibitDuplicates,
+		}
+		b.solver = solverCfg.NewSolver(d)
+	}
+	if b.status == game.Finished {
+		return b.close()
+	}
+	return nil
+}
+
+// handleStatus updates the status of the game, leaving it when it is finished.
+func (b *Bot) handleStatus(m message.Message) []message.Message {
+	if m.Game == nil {
+		return nil
+	}
+	b.status = m.Game.Status
+	b.tilesLeft = m.Game.TilesLeft
+	b.bananasRace = m.Game.BananasRace
+	if b.status == game.Finished {
+		return b.close()
+	}
+	return nil
+}
+
+// handleTiles adds new tiles to the board of the bot.
+// The whole board is requested if the new tiles are for a different revision of the board.
+func (b *Bot) handleTiles(m message.Message) []message.Message {
+	if m.Game == nil {
+		return nil
+	}
+	b.tilesLeft = m.Game.TilesLeft
+	b.bananasRace = m.Game.BananasRace
+	if m.Game.Diff == nil || b.board == nil {
+		return nil
+	}
+	b.waiting = false
+	if err := b.board.Apply(*m.Game.Diff); err != nil {
+		b.log.Printf("bot %v adding tiles: %v", b.PlayerName, err)
+		return b.refreshBoard()
+	}
+	return nil
+}
+
+// refreshBoard creates the message to request the whole board of the bot.
+func (b *Bot) refreshBoard() []message.Message {
+	m := b.message(message.RefreshGameBoard)
+	m.Game.Board = &board.Board{
+		Config: b.BoardConfig,
+	}
+	return []message.Message{m}
+}
+
+// think chooses the next action of the bot.
+// The bot places a word if it can.  Otherwise, it snags a tile when it has used all of its tiles or swaps one that it cannot use.
+// When all of its tiles are used and there are no tiles left to snag, the bot tries to finish the game.
+func (b *Bot) think() []message.Message {
+	if b.closing || b.waiting || b.board == nil || b.solver == nil || b.status != game.InProgress {
+		return nil
+	}
+	if positions := b.solver.Placement(*b.board); len(positions) > 0 {
+		return b.moveTiles(positions)
+	}
+	switch {
+	case len(b.board.UnusedTiles) == 0 && b.tilesLeft > 0 && !b.bananasRace:
+		b.waiting = true
+		return []message.Message{b.message(message.SnagGameTile)}
+	case len(b.board.UnusedTiles) == 0:
+		if b.finishing {
+			return nil
+		}
+		b.finishing = true
+		m := b.message(message.ChangeGameStatus)
+		m.Game.Status = game.Finished
+		return []message.Message{m}
+	case b.tilesLeft > 0:
+		return b.swapTile()
+	}
+	return nil
+}
+
+// moveTiles moves the tiles on the board of the bot and creates the message to move them in the game.
+func (b *Bot) moveTiles(positions []tile.Position) []message.Message {
+	d := b.board.NewDiff(board.OpMove, positions...)
+	if err := b.board.Apply(d); err != nil {
+		b.log.Printf("bot %v moving tiles: %v", b.PlayerName, err)
+		return nil
+	}
+	m := b.message(message.MoveGameTile)
+	m.Game.Diff = &d
+	return []message.Message{m}
+}
+
+// swapTile removes a tile that the bot cannot use from its board and creates the message to swap it.
+func (b *Bot) swapTile() []message.Message {
+	t, ok := b.solver.SwapTile(*b.board)
+	if !ok {
+		return nil
+	}
+	d := b.board.NewDiff(board.OpRemove, tile.Position{Tile: t})
+	if err := b.board.Apply(d); err != nil {
+		b.log.Printf("bot %v swapping tile: %v", b.PlayerName, err)
+		return nil
+	}
+	b.waiting = true
+	m := b.message(message.SwapGameTile)
+	m.Game.Diff = &d
+	return []message.Message{m}
+}
+
+// close stops the bot from playing and creates the message to remove it.
+func (b *Bot) close() []message.Message {
+	b.closing = true
+	m := message.Message{
+		Type:       message.SocketClose,
+		PlayerName: b.PlayerName,
+		Addr:       b.Addr,
+	}
+	return []message.Message{m}
+}
@@ -0,0 +1,493 @@
+package bot
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jacobpatterson1549/selene-bananas/game"
+	"github.com/jacobpatterson1549/selene-bananas/game/board"
+	"github.com/jacobpatterson1549/selene-bananas/game/message"
+	"github.com/jacobpatterson1549/selene-bananas/game/player"
+	"github.com/jacobpatterson1549/selene-bananas/game/tile"
+	"github.com/jacobpatterson1549/selene-bananas/server/log"
+	"github.com/jacobpatterson1549/selene-bananas/server/log/logtest"
+)
+
+// testDictionary is a dictionary of the lower case words.
+type testDictionary []string
+
+func (d testDictionary) Validate(word string) bool {
+	lowerWord := strings.ToLower(word)
+	for _, w := range d {
+		if w == lowerWord {
+			return true
+		}
+	}
+	return false
+}
+
+func (d testDictionary) Words() []string {
+	words := append([]string{}, d...)
+	sort.Strings(words)
+	return words
+}
+
+// testBoard creates a board with unused tiles for the letters and used tiles at the positions.
+func testBoard(t *testing.T, unusedLetters string, usedTiles ...tile.Position) *board.Board {
+	t.Helper()
+	unusedTiles := make([]tile.Tile, 0, len(unusedLetters))
+	for i, r := range unusedLetters {
+		unusedTiles = append(unusedTiles, tile.Tile{ID: tile.ID(i + 1), Ch: tile.Letter(r)})
+	}
+	b := board.New(unusedTiles, nil)
+	b.Config = board.Config{NumRows: 10, NumCols: 10}
+	for i, tp := range usedTiles {
+		tp.Tile.ID = tile.ID(len(unusedLetters) + i + 1)
+		if err := b.AddTile(tp.Tile); err != nil {
+			t.Fatalf("adding used tile: %v", err)
+		}
+		if err := b.MoveTiles(map[tile.ID]tile.Position{tp.Tile.ID: tp}); err != nil {
+			t.Fatalf("moving used tile: %v", err)
+		}
+	}
+	return b
+}
+
+func testConfig() Config {
+	cfg := Config{
+		Dictionaries: map[game.Language]board.Dictionary{
+			game.English: testDictionary{"cat", "tab"},
+		},
+		Skills: map[player.Difficulty]Skill{
+			player.Easy: {
+				ThinkTime:      time.Hour,
+				VocabularySize: 10,
+			},
+		},
+		BoardConfig: board.Config{
+			NumRows: 10,
+			NumCols: 10,
+		},
+	}
+	return cfg
+}
+
+func TestNewBot(t *testing.T) {
+	testLog := logtest.DiscardLogger
+	newBotTests := []struct {
+		Config
+		log.Logger
+		player.Name
+		game.ID
+		player.Difficulty
+		wantOk bool
+	}{
+		{}, // no log
+		{ // not a bot
+			Logger: testLog,
+			Name:   "selene",
+		},
+		{ // no game id
+			Logger: testLog,
+			Name:   "bot-easy-1",
+		},
+		{ // no dictionaries
+			Logger: testLog,
+			Name:   "bot-easy-1",
+			ID:     1,
+		},
+		{ // bad think time
+			Config: Config{
+				Dictionaries: testConfig().Dictionaries,
+				Skills: map[player.Difficulty]Skill{
+					player.Easy: {VocabularySize: 10},
+				},
+			},
+			Logger: testLog,
+			Name:   "bot-easy-1",
+			ID:     1,
+		},
+		{ // bad vocabulary size
+			Config: Config{
+				Dictionaries: testConfig().Dictionaries,
+				Skills: map[player.Difficulty]Skill{
+					player.Easy: {ThinkTime: time.Hour},
+				},
+			},
+			Logger: testLog,
+			Name:   "bot-easy-1",
+			ID:     1,
+		},
+		{ // bad board config
+			Config: Config{
+				Dictionaries: testConfig().Dictionaries,
+				Skills:       testConfig().Skills,
+			},
+			Logger: testLog,
+			Name:   "bot-easy-1",
+			ID:     1,
+		},
+		{ // no skill for difficulty
+			Config:     testConfig(),
+			Logger:     testLog,
+			Name:       "bot-hard-1",
+			ID:         1,
+			Difficulty: player.Hard,
+		},
+		{
+			Config: testConfig(),
+			Logger: testLog,
+			Name:   "bot-easy-1",
+			ID:     1,
+			wantOk: true,
+		},
+	}
+	for i, test := range newBotTests {
+		got, err := test.Config.NewBot(test.Logger, test.Name, test.ID, test.Difficulty)
+		switch {
+		case !test.wantOk:
+			if err == nil {
+				t.Errorf("Test %v: wanted error", i)
+			}
+		case err != nil:
+			t.Errorf("Test %v: unwanted error: %v", i, err)
+		case got.PlayerName != test.Name, got.Addr != message.Addr(test.Name), got.gameID != test.ID:
+			t.Errorf("Test %v: bot not created with name, address, and game id: %v", i, got)
+		}
+	}
+}
+
+func TestRunBot(t *testing.T) {
+	b, err := testConfig().NewBot(logtest.DiscardLogger, "bot-easy-1", 3, player.Easy)
+	if err != nil {
+		t.Fatalf("unwanted error creating bot: %v", err)
+	}
+	b.InviteCode = "b4n4n4"
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	in := make(chan message.Message)
+	out := make(chan message.Message)
+	thinkTicks := make(chan time.Time)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		b.runSync(ctx, in, out, thinkTicks)
+	}()
+	m := <-out
+	switch {
+	case m.Type != message.JoinGame,
+		m.PlayerName != "bot-easy-1",
+		m.Addr != "bot-easy-1",
+		m.Game == nil,
+		m.Game.ID != 3,
+		m.Game.Board == nil,
+		m.Game.Board.Config != testConfig().BoardConfig,
+		m.Game.InviteCode != "b4n4n4":
+		t.Errorf("wanted join game message with board config and invite code, got %v", m)
+	}
+	in <- message.Message{
+		Type: message.JoinGame,
+		Game: &game.Info{
+			Board: board.New([]tile.Tile{
+				{ID: 1, Ch: 'C'},
+				{ID: 2, Ch: 'A'},
+				{ID: 3, Ch: 'T'},
+			}, nil),
+			Status:    game.InProgress,
+			TilesLeft: 5,
+		},
+	}
+	thinkTicks <- time.Time{}
+	m = <-out
+	switch {
+	case m.Type != message.MoveGameTile,
+		m.Game == nil,
+		m.Game.Diff == nil,
+		len(m.Game.Diff.MovedTilePositions()) != 3:
+		t.Errorf("wanted tiles to be moved to make a word, got %v", m)
+	}
+	thinkTicks <- time.Time{}
+	if m = <-out; m.Type != message.SnagGameTile {
+		t.Errorf("wanted tile to be snagged after all tiles are used, got %v", m)
+	}
+	in <- message.Message{
+		Type: message.LeaveGame,
+	}
+	if m = <-out; m.Type != message.SocketClose {
+		t.Errorf("wanted socket to close after leaving game, got %v", m)
+	}
+	in <- message.Message{ // ignored
+		Type: message.ChangeGameTiles,
+	}
+	close(in)
+	wg.Wait()
+}
+
+func TestBotThink(t *testing.T) {
+	thinkTests := []struct {
+		name          string
+		unusedLetters string
+		usedTiles     []tile.Position
+		status        game.Status
+		tilesLeft     int
+		bananasRace   bool
+		waiting       bool
+		finishing     bool
+		wantType      message.Type
+	}{
+		{
+			name:          "not started",
+			unusedLetters: "CAT",
+			status:        game.NotStarted,
+		},
+		{
+			name:          "waiting for tiles",
+			unusedLetters: "CAT",
+			status:        game.InProgress,
+			waiting:       true,
+		},
+		{
+			name:          "place word",
+			unusedLetters: "CAT",
+			status:        game.InProgress,
+			wantType:      message.MoveGameTile,
+		},
+		{
+			name:          "swap unusable tile",
+			unusedLetters: "Q",
+			usedTiles: []tile.Position{
+				{Tile: tile.Tile{Ch: 'C'}, X: 4, Y: 5},
+				{Tile: tile.Tile{Ch: 'A'}, X: 5, Y: 5},
+				{Tile: tile.Tile{Ch: 'T'}, X: 6, Y: 5},
+			},
+			status:    game.InProgress,
+			tilesLeft: 3,
+			wantType:  message.SwapGameTile,
+		},
+		{
+			name:          "stuck with no tiles to swap",
+			unusedLetters: "Q",
+			status:        game.InProgress,
+		},
+		{
+			name:   "snag",
+			status: game.InProgress,
+			usedTiles: []tile.Position{
+				{Tile: tile.Tile{Ch: 'C'}, X: 4, Y: 5},
+				{Tile: tile.Tile{Ch: 'A'}, X: 5, Y: 5},
+				{Tile: tile.Tile{Ch: 'T'}, X: 6, Y: 5},
+			},
+			tilesLeft: 1,
+			wantType:  message.SnagGameTile,
+		},
+		{
+			name:   "finish",
+			status: game.InProgress,
+			usedTiles: []tile.Position{
+				{Tile: tile.Tile{Ch: 'C'}, X: 4, Y: 5},
+				{Tile: tile.Tile{Ch: 'A'}, X: 5, Y: 5},
+				{Tile: tile.Tile{Ch: 'T'}, X: 6, Y: 5},
+			},
+			wantType: message.ChangeGameStatus,
+		},
+		{
+			name:   "finish in bananas race",
+			status: game.InProgress,
+			usedTiles: []tile.Position{
+				{Tile: tile.Tile{Ch: 'C'}, X: 4, Y: 5},
+				{Tile: tile.Tile{Ch: 'A'}, X: 5, Y: 5},
+				{Tile: tile.Tile{Ch: 'T'}, X: 6, Y: 5},
+			},
+			tilesLeft:   1,
+			bananasRace: true,
+			wantType:    message.ChangeGameStatus,
+		},
+		{
+			name:   "already tried to finish",
+			status: game.InProgress,
+			usedTiles: []tile.Position{
+				{Tile: tile.Tile{Ch: 'C'}, X: 4, Y: 5},
+				{Tile: tile.Tile{Ch: 'A'}, X: 5, Y: 5},
+				{Tile: tile.Tile{Ch: 'T'}, X: 6, Y: 5},
+			},
+			finishing: true,
+		},
+	}
+	for _, test := range thinkTests {
+		cfg := testConfig()
+		b, err := cfg.NewBot(logtest.DiscardLogger, "bot-easy-1", 1, player.Easy)
+		if err != nil {
+			t.Fatalf("Test %v: unwanted error creating bot: %v", test.name, err)
+		}
+		b.board = testBoard(t, test.unusedLetters, test.usedTiles...)
+		b.status = test.status
+		b.tilesLeft = test.tilesLeft
+		b.bananasRace = test.bananasRace
+		b.waiting = test.waiting
+		b.finishing = test.finishing
+		b.solver = board.SolverConfig{}.NewSolver(cfg.Dictionaries[game.English])
+		got := b.think()
+		switch {
+		case test.wantType == 0:
+			if len(got) != 0 {
+				t.Errorf("Test %v: wanted no messages, got %v", test.name, got)
+			}
+		case len(got) != 1:
+			t.Errorf("Test %v: wanted one message, got %v", test.name, got)
+		case test.wantType != got[0].Type:
+			t.Errorf("Test %v: message types not equal: wanted %v, got %v", test.name, test.wantType, got[0].Type)
+		case got[0].Game == nil, got[0].Game.ID != 1:
+			t.Errorf("Test %v: wanted message for game, got %v", test.name, got[0])
+		}
+	}
+}
+
+func TestBotFinishRejected(t *testing.T) {
+	cfg := testConfig()
+	b, err := cfg.NewBot(logtest.DiscardLogger, "bot-easy-1", 1, player.Easy)
+	if err != nil {
+		t.Fatalf("unwanted error creating bot: %v", err)
+	}
+	b.board = testBoard(t, "",
+		tile.Position{Tile: tile.Tile{Ch: 'C'}, X: 4, Y: 5},
+		tile.Position{Tile: tile.Tile{Ch: 'A'}, X: 5, Y: 5},
+		tile.Position{Tile: tile.Tile{Ch: 'T'}, X: 6, Y: 5},
+	)
+	b.status = game.InProgress
+	b.tilesLeft = 1
+	b.bananasRace = true
+	b.solver = board.SolverConfig{}.NewSolver(cfg.Dictionaries[game.English])
+	if got := b.think(); len(got) != 1 || got[0].Type != message.ChangeGameStatus {
+		t.Fatalf("wanted bot to try to finish, got %v", got)
+	}
+	if got := b.think(); len(got) != 0 {
+		t.Errorf("wanted bot to wait for the game to handle the finish, got %v", got)
+	}
+	warning := message.Message{
+		Type: message.SocketWarning,
+		Info: "invalid board",
+	}
+	if got := b.handleMessage(warning); len(got) != 1 || got[0].Type != message.RefreshGameBoard {
+		t.Errorf("wanted bot to refresh its board after the finish is rejected, got %v", got)
+	}
+	if got := b.think(); len(got) != 1 || got[0].Type != message.ChangeGameStatus {
+		t.Fatalf("wanted bot to try to finish again, got %v", got)
+	}
+	finished := message.Message{
+		Type: message.ChangeGameStatus,
+		Game: &game.Info{
+			Status: game.Finished,
+		},
+	}
+	if got := b.handleMessage(finished); len(got) != 1 || got[0].Type != message.SocketClose {
+		t.Errorf("wanted bot to close after the game is finished, got %v", got)
+	}
+}
+
+func TestBotHandleMessage(t *testing.T) {
+	handleMessageTests := []struct {
+		name string
+		message.Message
+		closing   bool
+		wantType  message.Type
+		wantTiles int
+	}{
+		{
+			name: "closing",
+			Message: message.Message{
+				Type: message.LeaveGame,
+			},
+			closing: true,
+		},
+		{
+			name: "leave",
+			Message: message.Message{
+				Type: message.LeaveGame,
+			},
+			wantType: message.SocketClose,
+		},
+		{
+			name: "finished",
+			Message: message.Message{
+				Type: message.ChangeGameStatus,
+				Game: &game.Info{
+					Status: game.Finished,
+				},
+			},
+			wantType: message.SocketClose,
+		},
+		{
+			name: "unknown language",
+			Message: message.Message{
+				Type: message.JoinGame,
+				Game: &game.Info{
+					Board:  board.New(nil, nil),
+					Config: &game.Config{Language: game.German},
+				},
+			},
+			wantType: message.SocketClose,
+		},
+		{
+			name: "warning",
+			Message: message.Message{
+				Type: message.SocketWarning,
+			},
+			wantType: message.RefreshGameBoard,
+		},
+		{
+			name: "new tiles",
+			Message: message.Message{
+				Type: message.ChangeGameTiles,
+				Game: &game.Info{
+					Diff: &board.Diff{
+						Changes: []board.Change{
+							{Op: board.OpAdd, Position: tile.Position{Tile: tile.Tile{ID: 7, Ch: 'X'}}},
+						},
+					},
+				},
+			},
+			wantTiles: 1,
+		},
+		{
+			name: "new tiles for other revision",
+			Message: message.Message{
+				Type: message.ChangeGameTiles,
+				Game: &game.Info{
+					Diff: &board.Diff{
+						Revision: 4,
+						Changes: []board.Change{
+							{Op: board.OpAdd, Position: tile.Position{Tile: tile.Tile{ID: 7, Ch: 'X'}}},
+						},
+					},
+				},
+			},
+			wantType: message.RefreshGameBoard,
+		},
+	}
+	for _, test := range handleMessageTests {
+		b, err := testConfig().NewBot(logtest.DiscardLogger, "bot-easy-1", 1, player.Easy)
+		if err != nil {
+			t.Fatalf("Test %v: unwanted error creating bot: %v", test.name, err)
+		}
+		b.board = testBoard(t, "")
+		b.closing = test.closing
+		got := b.handleMessage(test.Message)
+		switch {
+		case test.wantType == 0:
+			if len(got) != 0 {
+				t.Errorf("Test %v: wanted no messages, got %v", test.name, got)
+			}
+		case len(got) != 1:
+			t.Errorf("Test %v: wanted one message, got %v", test.name, got)
+		case test.wantType != got[0].Type:
+			t.Errorf("Test %v: message types not equal: wanted %v, got %v", test.name, test.wantType, got[0].Type)
+		}
+		if want, got := test.wantTiles, len(b.board.UnusedTiles); want != got {
+			t.Errorf("Test %v: wanted %v unused tiles, got %v", test.name, want, got)
+		}
+	}
+}
@@ -1,9 +1,38 @@
 package game
 
-// gameWarning is an error that represents a user error.
-type gameWarning string
+import (
+	"github.com/jacobpatterson1549/selene-bananas/game/message"
+)
+
+type (
+	// gameWarning is an error that represents a user error.
+	gameWarning struct {
+		// code identifies the reason for the warning.
+		code message.Code
+		// text is the description of the warning for the player.
+		text string
+		// details are facts about the warning.
+		details *message.Details
+	}
+
+	// gameError is an unexpected error with a code that identifies it.
+	gameError struct {
+		code message.Code
+		err  error
+	}
+)
 
 // Error returns the string of the error.
 func (w gameWarning) Error() string {
-	return string(w)
+	return w.text
+}
+
+// Error returns the string of the wrapped error.
+func (e gameError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e gameError) Unwrap() error {
+	return e.err
 }
@@ -1,12 +1,26 @@
 package game
 
-import "testing"
+import (
+	"errors"
+	"testing"
+)
 
 func TestGameWarningError(t *testing.T) {
 	want := "x"
-	err := gameWarning(want)
+	err := gameWarning{text: want}
 	got := err.Error()
 	if want != got {
 		t.Errorf("wanted gameWarning error string to be '%v', but was '%v'", want, got)
 	}
 }
+
+func TestGameErrorError(t *testing.T) {
+	want := errors.New("x")
+	err := gameError{err: want}
+	switch {
+	case want.Error() != err.Error():
+		t.Errorf("wanted gameError error string to be '%v', but was '%v'", want, err.Error())
+	case !errors.Is(err, want):
+		t.Errorf("wanted gameError to wrap %v", want)
+	}
+}
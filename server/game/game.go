@@ -3,15 +3,22 @@ package game
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"sort"
+	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
+	"github.com/jacobpatterson1549/selene-bananas/db/result"
+	"github.com/jacobpatterson1549/selene-bananas/db/state"
 	"github.com/jacobpatterson1549/selene-bananas/game"
 	"github.com/jacobpatterson1549/selene-bananas/game/board"
 	"github.com/jacobpatterson1549/selene-bananas/game/message"
 	"github.com/jacobpatterson1549/selene-bananas/game/player"
+	"github.com/jacobpatterson1549/selene-bananas/game/replay"
 	"github.com/jacobpatterson1549/selene-bananas/game/tile"
 	playerController "github.com/jacobpatterson1549/selene-bananas/server/game/player"
 	"github.com/jacobpatterson1549/selene-bananas/server/log"
@@ -20,14 +27,27 @@ import (
 type (
 	// Game contains the logic to play a tile-base word-forming game between users.
 	Game struct {
-		log           log.Logger
-		id            game.ID
-		createdAt     int64
-		status        game.Status
-		players       map[player.Name]*playerController.Player
-		unusedTiles   []tile.Tile
+		log       log.Logger
+		id        game.ID
+		createdAt int64
+		status    game.Status
+		players   map[player.Name]*playerController.Player
+		// host is the player who can start and delete the game and kick other players from it.  The first player to join the game is the host.
+		host        player.Name
+		userPoints  map[player.Name]int
+		unusedTiles []tile.Tile
+		events      []replay.Event
+		// finishOrder are the players who tried to finish the game, in the order that their requests were received.
+		finishOrder []player.Name
+		// challenge is the win claimed by a player while the game is challenging.
+		challenge     *challenge
+		hasSpectators bool
+		// solver is used to find hints.  It is created when the first hint is requested.
+		solver        *board.Solver
 		WordValidator WordValidator
 		userDao       UserDao
+		stateStore    StateStore
+		resultStore   ResultStore
 		Config
 	}
 
@@ -46,12 +66,20 @@ type (
 		// NumNewTiles is the number of new tiles each player starts the game with.
 		NumNewTiles int
 		// TileLetters is a string of all the upper case letters that can be used in the game.
-		// If not specified, the default 144 letters will be used.
+		// If not specified, the default 144 letters for the language of the game will be used.
 		// If a letter should occur on multiple tiles, it sh be present multiple times.
 		// For example, the TileLetters "AABCCC" will be used to initialize a game with two As, 1 B, and 3 Cs.
 		TileLetters string
 		// IdlePeriod is the amount of time that can pass between non-BoardRefresh messages before the game is idle and will delete itself.
 		IdlePeriod time.Duration
+		// SpectateDelay is the amount of time the boards sent to spectators lag behind the boards of the players.
+		// Spectators are sent boards as soon as they change if the delay is not positive.
+		SpectateDelay time.Duration
+		// ClockPeriod is how often timed games and games with challenges check if time has run out and send the time left to players.
+		ClockPeriod time.Duration
+		// SaveDelay is how long the game waits to save its state after tiles are moved, so many moves are saved together.
+		// The state is saved after each move if the delay is not positive.
+		SaveDelay time.Duration
 		// ShuffleUnusedTilesFunc is used to shuffle unused tiles when initializing the game and after tiles are swapped.
 		ShuffleUnusedTilesFunc func(tiles []tile.Tile)
 		// ShufflePlayersFunc is used to shuffle the order of players when giving tiles after a snag
@@ -61,6 +89,18 @@ type (
 		game.Config
 	}
 
+	// challenge is a win claimed by a player that the other players can challenge before the game is finished.
+	challenge struct {
+		// claimant is the player who claimed to win.
+		claimant player.Name
+		// words are the words on the board of the claimant.
+		words []string
+		// endsAt is when the win is accepted if it is not challenged, in seconds since the unix epoch.
+		endsAt int64
+		// votes are the words that each player challenged.  Players who accept the win challenge no words.
+		votes map[player.Name][]string
+	}
+
 	// messageHandler is a function which handles message.Messages, returning responses to the output channel.
 	messageHandler func(ctx context.Context, m message.Message, send messageSender) error
 
@@ -69,15 +109,22 @@ type (
 )
 
 const (
+	// maxInviteCodeLength is the maximum number of characters in the invite codes of private games.
+	maxInviteCodeLength = 32
+)
+
+var (
 	// gameWarningNotInProgress is a shared warning to alert users of an invalid game state.
-	gameWarningNotInProgress gameWarning = "game has not started or is finished"
-	// defaultTileLetters is the default if not specified
-	defaultTileLetters = "AAAAAAAAAAAAABBBCCCDDDDDDEEEEEEEEEEEEEEEEEEFFFGGGGHHHIIIIIIIIIIIIJJKKLLLLLMMMNNNNNNNNOOOOOOOOOOOPPPQQRRRRRRRRRSSSSSSTTTTTTTTTUUUUUUVVVWWWXXYYYZZ"
+	gameWarningNotInProgress = gameWarning{code: message.CodeNotInProgress, text: "game has not started or is finished"}
+	// gameWarningNotHost is a shared warning to alert users that only the host can do something.
+	gameWarningNotHost = gameWarning{code: message.CodeNotHost, text: "only the host of the game can do that"}
+	// gameWarningForfeited is a shared warning to alert users that their board cannot change after they forfeit the game.
+	gameWarningForfeited = gameWarning{code: message.CodeForfeited, text: "cannot play after forfeiting the game"}
 )
 
 // NewGame creates a new game and runs it.
-func (cfg Config) NewGame(log log.Logger, id game.ID, WordValidator WordValidator, userDao UserDao) (*Game, error) {
-	if err := cfg.validate(log, id, WordValidator, userDao); err != nil {
+func (cfg Config) NewGame(log log.Logger, id game.ID, WordValidator WordValidator, userDao UserDao, stateStore StateStore, resultStore ResultStore) (*Game, error) {
+	if err := cfg.validate(log, id, WordValidator, userDao, stateStore, resultStore); err != nil {
 		return nil, fmt.Errorf("creating game: validation: %w", err)
 	}
 	g := Game{
@@ -86,8 +133,11 @@ func (cfg Config) NewGame(log log.Logger, id game.ID, WordValidator WordValidato
 		createdAt:     cfg.TimeFunc(),
 		status:        game.NotStarted,
 		players:       make(map[player.Name]*playerController.Player),
+		userPoints:    make(map[player.Name]int),
 		WordValidator: WordValidator,
 		userDao:       userDao,
+		stateStore:    stateStore,
+		resultStore:   resultStore,
 		Config:        cfg,
 	}
 	if err := g.initializeUnusedTiles(); err != nil {
@@ -96,12 +146,118 @@ func (cfg Config) NewGame(log log.Logger, id game.ID, WordValidator WordValidato
 	return &g, nil
 }
 
+// restoreGame recreates a game from a snapshot of its state.
+func (cfg Config) restoreGame(log log.Logger, s state.Game, WordValidator WordValidator, userDao UserDao, stateStore StateStore, resultStore ResultStore) (*Game, error) {
+	cfg.Config = s.Config
+	if err := cfg.validate(log, s.ID, WordValidator, userDao, stateStore, resultStore); err != nil {
+		return nil, fmt.Errorf("restoring game: validation: %w", err)
+	}
+	if s.Status == game.Challenging && s.Challenge == nil {
+		return nil, fmt.Errorf("restoring game: no challenge for challenging game")
+	}
+	players := make(map[player.Name]*playerController.Player, len(s.Players))
+	for pn, p := range s.Players {
+		if p.Board == nil {
+			return nil, fmt.Errorf("restoring game: no board for player %v", pn)
+		}
+		players[pn] = &playerController.Player{
+			WinPoints:    p.WinPoints,
+			Board:        p.Board,
+			Ready:        p.Ready,
+			Forfeited:    p.Forfeited,
+			Team:         p.Team,
+			Swaps:        p.Swaps,
+			LastSwapTime: p.LastSwapTime,
+		}
+	}
+	shareTeamBoards(players)
+	host := s.Host
+	if _, ok := players[host]; !ok {
+		host = firstPlayer(players, "")
+	}
+	g := Game{
+		log:           log,
+		id:            s.ID,
+		createdAt:     s.CreatedAt,
+		status:        s.Status,
+		players:       players,
+		host:          host,
+		userPoints:    make(map[player.Name]int, len(players)),
+		unusedTiles:   s.UnusedTiles,
+		events:        s.Events,
+		finishOrder:   s.FinishOrder,
+		challenge:     restoreChallenge(s.Challenge),
+		WordValidator: WordValidator,
+		userDao:       userDao,
+		stateStore:    stateStore,
+		resultStore:   resultStore,
+		Config:        cfg,
+	}
+	return &g, nil
+}
+
+// restoreChallenge recreates the claimed win of a game from a snapshot of it, if it has one.
+func restoreChallenge(s *state.Challenge) *challenge {
+	if s == nil {
+		return nil
+	}
+	votes := s.Votes
+	if votes == nil {
+		votes = make(map[player.Name][]string)
+	}
+	c := challenge{
+		claimant: s.Claimant,
+		words:    s.Words,
+		endsAt:   s.EndsAt,
+		votes:    votes,
+	}
+	return &c
+}
+
+// shareTeamBoards makes the players on each team share a single board.
+// Snapshots of games store a copy of the board of the team for each of its players.
+func shareTeamBoards(players map[player.Name]*playerController.Player) {
+	teamBoards := make(map[int]*board.Board)
+	for _, p := range players {
+		if p.Team == 0 {
+			continue
+		}
+		if b, ok := teamBoards[p.Team]; ok {
+			p.Board = b
+			continue
+		}
+		teamBoards[p.Team] = p.Board
+	}
+}
+
 // validate ensures the configuration has no errors.
-// the config is modified to use the default tile letters if the tile letters are empty.
-func (cfg *Config) validate(log log.Logger, id game.ID, wordValidator WordValidator, userDao UserDao) error {
+// the config is modified to use the default tile letters of the language if the tile letters are empty.
+// The tile letters are then replaced by the tiles of the tile bag of the game, and the number of new tiles is replaced by the number of the game, if it has one.
+// Otherwise, the default number of new tiles is lowered if the tile bag is too small for each board to start with it.
+func (cfg *Config) validate(log log.Logger, id game.ID, wordValidator WordValidator, userDao UserDao, stateStore StateStore, resultStore ResultStore) error {
+	defaultTileLetters, err := cfg.Config.Language.TileLetters()
+	if err != nil {
+		return err
+	}
 	if len(cfg.TileLetters) == 0 {
 		cfg.TileLetters = defaultTileLetters
 	}
+	tileLetters, err := cfg.Config.TileBag.TileLetters(cfg.TileLetters, cfg.Config.LetterCounts)
+	if err != nil {
+		return err
+	}
+	cfg.TileLetters = tileLetters
+	numTiles := utf8.RuneCountInString(cfg.TileLetters)
+	numBoards := cfg.MaxPlayers
+	if cfg.Config.TeamSize > 1 { // teammates share a board
+		numBoards = (cfg.MaxPlayers + cfg.Config.TeamSize - 1) / cfg.Config.TeamSize
+	}
+	switch {
+	case cfg.Config.NumNewTiles > 0:
+		cfg.NumNewTiles = cfg.Config.NumNewTiles
+	case numBoards > 0 && numTiles >= numBoards && numTiles < cfg.NumNewTiles*numBoards:
+		cfg.NumNewTiles = numTiles / numBoards
+	}
 	switch {
 	case log == nil:
 		return fmt.Errorf("log required")
@@ -111,6 +267,10 @@ func (cfg *Config) validate(log log.Logger, id game.ID, wordValidator WordValida
 		return fmt.Errorf("word validator required")
 	case userDao == nil:
 		return fmt.Errorf("user dao required")
+	case stateStore == nil:
+		return fmt.Errorf("state store required")
+	case resultStore == nil:
+		return fmt.Errorf("result store required")
 	case cfg.TimeFunc == nil:
 		return fmt.Errorf("time func required")
 	case cfg.MaxPlayers <= 0:
@@ -123,16 +283,40 @@ func (cfg *Config) validate(log log.Logger, id game.ID, wordValidator WordValida
 		return fmt.Errorf("function to shuffle tiles required")
 	case cfg.ShufflePlayersFunc == nil:
 		return fmt.Errorf("function to shuffle player draw order required")
-	case len(cfg.TileLetters) < cfg.NumNewTiles:
-		return fmt.Errorf("not enough tiles for a single player to join the game")
+	case numTiles < cfg.NumNewTiles*numBoards:
+		return fmt.Errorf("not enough tiles (%v) for %v boards to start with %v tiles each", numTiles, numBoards, cfg.NumNewTiles)
+	case cfg.Config.TimeLimitSec < 0:
+		return fmt.Errorf("nonnegative time limit required")
+	case cfg.Config.TimeLimitSec > 0 && cfg.ClockPeriod <= 0:
+		return fmt.Errorf("positive clock period required for timed games")
+	case utf8.RuneCountInString(cfg.Config.InviteCode) > maxInviteCodeLength:
+		return fmt.Errorf("invite code cannot be longer than %v characters", maxInviteCodeLength)
+	case cfg.Config.TeamSize < 0:
+		return fmt.Errorf("nonnegative team size required")
+	case cfg.Config.TeamSize > cfg.MaxPlayers:
+		return fmt.Errorf("team size cannot be larger than the max player count (%v)", cfg.MaxPlayers)
+	case cfg.Config.SwapRatio > game.MaxSwapRatio:
+		return fmt.Errorf("swap ratio cannot be larger than %v", game.MaxSwapRatio)
+	case cfg.Config.SwapLimit < 0:
+		return fmt.Errorf("nonnegative swap limit required")
+	case cfg.Config.SwapCooldownSec < 0:
+		return fmt.Errorf("nonnegative swap cooldown required")
+	case cfg.Config.ChallengeSec < 0:
+		return fmt.Errorf("nonnegative challenge time required")
+	case cfg.Config.ChallengeSec > 0 && cfg.ClockPeriod <= 0:
+		return fmt.Errorf("positive clock period required for games with challenges")
+	}
+	if _, err := cfg.Config.Scoring.Scorer(); err != nil {
+		return err
 	}
 	return nil
 }
 
-// initialize unusedTiles from tileLetters or defaultTileLetters and shuffles them.
+// initialize unusedTiles from tileLetters and shuffles them.
 func (g *Game) initializeUnusedTiles() error {
-	g.unusedTiles = make([]tile.Tile, len(g.TileLetters))
-	for i, ch := range g.TileLetters {
+	tileLetters := []rune(g.TileLetters)
+	g.unusedTiles = make([]tile.Tile, len(tileLetters))
+	for i, ch := range tileLetters {
 		id := tile.ID(i + 1)
 		t, err := tile.New(id, ch)
 		if err != nil {
@@ -145,19 +329,48 @@ func (g *Game) initializeUnusedTiles() error {
 }
 
 // Run runs the game asynchronously until the context is closed.
-func (g *Game) Run(ctx context.Context, wg *sync.WaitGroup, in <-chan message.Message, out chan<- message.Message) {
+// The returned channel is closed when the game stops running.
+func (g *Game) Run(ctx context.Context, wg *sync.WaitGroup, in <-chan message.Message, out chan<- message.Message) <-chan struct{} {
 	idleTicker := time.NewTicker(g.IdlePeriod)
+	var spectateTicks <-chan time.Time
+	if g.SpectateDelay > 0 {
+		spectateTicker := time.NewTicker(g.SpectateDelay)
+		spectateTicks = spectateTicker.C
+	}
+	var clockTicks <-chan time.Time
+	if g.Config.Config.TimeLimitSec > 0 || g.Config.Config.ChallengeSec > 0 {
+		clockTicker := time.NewTicker(g.ClockPeriod)
+		clockTicks = clockTicker.C
+	}
+	done := make(chan struct{})
 	wg.Add(1)
-	go g.runSync(ctx, wg, in, out, idleTicker)
+	go func() {
+		defer close(done)
+		g.runSync(ctx, wg, in, out, idleTicker, spectateTicks, clockTicks)
+	}()
+	return done
 }
 
 // runSync runs the game until the context is closed or the input channel closes.
-func (g *Game) runSync(ctx context.Context, wg *sync.WaitGroup, in <-chan message.Message, out chan<- message.Message, idleTicker *time.Ticker) {
+// Delayed snapshots of the game are sent to spectators whenever the spectateTicks channel is written to.
+// The time left in timed games and to challenge claimed wins is checked whenever the clockTicks channel is written to.
+func (g *Game) runSync(ctx context.Context, wg *sync.WaitGroup, in <-chan message.Message, out chan<- message.Message, idleTicker *time.Ticker, spectateTicks, clockTicks <-chan time.Time) {
 	defer wg.Done()
 	active := false
+	var saveTicks <-chan time.Time // set when moved tiles have not been saved
+	defer func() {
+		if saveTicks != nil {
+			g.saveState(context.WithoutCancel(ctx)) // save moved tiles when the game stops running
+		}
+	}()
 	send := g.sendMessage(out)
 	messageHandlers := map[message.Type]messageHandler{
 		message.JoinGame:         g.handleGameJoin,
+		message.LeaveGame:        g.handleGameLeave,
+		message.KickPlayer:       g.handleGameKick,
+		message.ToggleReady:      g.handleToggleReady,
+		message.Forfeit:          g.handleGameForfeit,
+		message.ChallengeWords:   g.handleChallengeWords,
 		message.DeleteGame:       g.handleGameDelete,
 		message.ChangeGameStatus: g.handleGameStatusChange,
 		message.SnagGameTile:     g.handleGameSnag,
@@ -165,6 +378,9 @@ func (g *Game) runSync(ctx context.Context, wg *sync.WaitGroup, in <-chan messag
 		message.MoveGameTile:     g.handleGameTilesMoved,
 		message.GameChat:         g.handleGameChat,
 		message.RefreshGameBoard: g.handleBoardRefresh,
+		message.GameReplay:       g.handleGameReplay,
+		message.SpectateGame:     g.handleGameSpectate,
+		message.Hint:             g.handleGameHint,
 	}
 	for { // BLOCKING
 		select {
@@ -175,14 +391,44 @@ func (g *Game) runSync(ctx context.Context, wg *sync.WaitGroup, in <-chan messag
 				return
 			}
 			g.handleMessage(ctx, m, send, &active, messageHandlers)
-			if m.Type == message.DeleteGame {
+			if g.status == game.Deleted {
+				saveTicks = nil
+				g.deleteState(ctx)
 				return
 			}
+			if (changesState(m.Type) || m.Type == message.MoveGameTile) && (g.SpectateDelay <= 0 || g.status != game.InProgress) {
+				g.updateSpectators(send)
+			}
+			switch {
+			case changesState(m.Type):
+				g.saveState(ctx)
+				saveTicks = nil
+			case changesBoard(m.Type) && g.SaveDelay <= 0:
+				g.saveState(ctx)
+			case changesBoard(m.Type) && saveTicks == nil:
+				saveTicks = time.After(g.SaveDelay)
+			}
+		case <-saveTicks:
+			saveTicks = nil
+			g.saveState(ctx)
+		case <-spectateTicks:
+			if g.status == game.InProgress {
+				g.updateSpectators(send)
+			}
+		case <-clockTicks:
+			switch {
+			case g.status == game.Challenging:
+				g.handleChallengeTick(ctx, send)
+			case g.status == game.InProgress && g.Config.Config.TimeLimitSec > 0:
+				g.handleClockTick(ctx, send)
+			}
 		case <-idleTicker.C:
-			var m message.Message
 			if !active {
+				var m message.Message
 				g.log.Printf("deleted game %v due to inactivity", g.id)
-				g.handleGameDelete(ctx, m, send)
+				g.deleteGame(m, send)
+				saveTicks = nil
+				g.deleteState(ctx)
 				return
 			}
 			active = false
@@ -202,6 +448,86 @@ func (g *Game) sendMessage(out chan<- message.Message) messageSender {
 	}
 }
 
+// changesState determines if handling a message with the type can change the state of the game.
+func changesState(mt message.Type) bool {
+	switch mt {
+	case message.JoinGame,
+		message.LeaveGame,
+		message.KickPlayer,
+		message.ToggleReady,
+		message.Forfeit,
+		message.ChallengeWords,
+		message.ChangeGameStatus,
+		message.SnagGameTile,
+		message.SwapGameTile,
+		message.Hint:
+		return true
+	}
+	return false
+}
+
+// changesBoard determines if handling a message with the type can change the board of a player without changing the rest of the game.
+// The state of the game is saved after a delay for these messages so it is not written each time a tile is moved.
+func changesBoard(mt message.Type) bool {
+	switch mt {
+	case message.MoveGameTile,
+		message.RefreshGameBoard:
+		return true
+	}
+	return false
+}
+
+// state creates a snapshot of the game that can be used to restore it.
+func (g Game) state() state.Game {
+	players := make(map[player.Name]state.Player, len(g.players))
+	for pn, p := range g.players {
+		players[pn] = state.Player{
+			WinPoints:    p.WinPoints,
+			Board:        p.Board,
+			Ready:        p.Ready,
+			Forfeited:    p.Forfeited,
+			Team:         p.Team,
+			Swaps:        p.Swaps,
+			LastSwapTime: p.LastSwapTime,
+		}
+	}
+	s := state.Game{
+		ID:          g.id,
+		CreatedAt:   g.createdAt,
+		Status:      g.status,
+		Players:     players,
+		Host:        g.host,
+		UnusedTiles: g.unusedTiles,
+		Config:      g.Config.Config,
+		Events:      g.events,
+		FinishOrder: g.finishOrder,
+	}
+	if c := g.challenge; c != nil {
+		s.Challenge = &state.Challenge{
+			Claimant: c.claimant,
+			Words:    c.words,
+			EndsAt:   c.endsAt,
+			Votes:    c.votes,
+		}
+	}
+	return s
+}
+
+// saveState stores a snapshot of the game so it can be restored if the server restarts.
+// Errors are logged because the game can continue to be played without being persisted.
+func (g *Game) saveState(ctx context.Context) {
+	if err := g.stateStore.Save(ctx, g.state()); err != nil {
+		g.log.Printf("saving state of game %v: %v", g.id, err)
+	}
+}
+
+// deleteState removes the stored snapshot of the game.
+func (g *Game) deleteState(ctx context.Context) {
+	if err := g.stateStore.Delete(ctx, g.id); err != nil {
+		g.log.Printf("deleting state of game %v: %v", g.id, err)
+	}
+}
+
 // handleMessage handles the message with the appropriate message handler.
 func (g *Game) handleMessage(ctx context.Context, m message.Message, send messageSender, active *bool, messageHandlers map[message.Type]messageHandler) {
 	if g.Debug {
@@ -209,18 +535,22 @@ func (g *Game) handleMessage(ctx context.Context, m message.Message, send messag
 	}
 	err := g.handleMessageHelper(ctx, m, send, active, messageHandlers)
 	if err != nil {
-		var mt message.Type
-		switch err.(type) {
-		case gameWarning:
-			mt = message.SocketWarning
-		default:
-			mt = message.SocketError
-		}
 		m2 := message.Message{
-			Type:       mt,
+			Type:       message.SocketError,
 			PlayerName: m.PlayerName,
 			Game:       m.Game,
 			Info:       err.Error(),
+			Code:       message.CodeServerError,
+		}
+		var w gameWarning
+		var ge gameError
+		switch {
+		case errors.As(err, &w):
+			m2.Type = message.SocketWarning
+			m2.Code = w.code
+			m2.Details = w.details
+		case errors.As(err, &ge):
+			m2.Code = ge.code
 		}
 		send(m2)
 	}
@@ -230,11 +560,11 @@ func (g *Game) handleMessage(ctx context.Context, m message.Message, send messag
 func (g *Game) handleMessageHelper(ctx context.Context, m message.Message, send messageSender, active *bool, messageHandlers map[message.Type]messageHandler) error {
 	handler, handlerExists := messageHandlers[m.Type]
 	if !handlerExists {
-		return fmt.Errorf("game does not know how to handle MessageType %v", m.Type)
+		return gameError{code: message.CodeUnknownMessage, err: fmt.Errorf("game does not know how to handle MessageType %v", m.Type)}
 	}
 	_, playerInGame := g.players[m.PlayerName]
-	if !playerInGame && m.Type != message.JoinGame {
-		return fmt.Errorf("game does not have player named '%v'", m.PlayerName)
+	if !playerInGame && m.Type != message.JoinGame && m.Type != message.SpectateGame {
+		return gameError{code: message.CodeNotInGame, err: fmt.Errorf("game does not have player named '%v'", m.PlayerName)}
 	}
 	*active = true
 	return handler(ctx, m, send)
@@ -246,13 +576,21 @@ func (g *Game) handleGameJoin(ctx context.Context, m message.Message, send messa
 	var err error
 	switch {
 	case ok:
+		g.readUserPoints(ctx, m.PlayerName)
+		if m.Game.Board == nil { // the game is being resumed on a new socket
+			m.Game.Board = &board.Board{
+				Config: g.players[m.PlayerName].Board.Config,
+			}
+		}
 		err = g.handleBoardRefresh(ctx, m, send)
+	case !g.hasInviteCode(m):
+		err = gameWarning{code: message.CodeWrongInviteCode, text: "the invite code of the private game is required to join it"}
 	case g.status != game.NotStarted:
-		err = gameWarning("cannot join game that has been started")
+		err = gameWarning{code: message.CodeGameStarted, text: "cannot join game that has been started"}
 	case len(g.players) >= g.MaxPlayers:
-		err = gameWarning("no room for another player in game")
-	case len(g.unusedTiles) < g.NumNewTiles:
-		err = gameWarning("not enough tiles to join the game")
+		err = gameWarning{code: message.CodeGameFull, text: "no room for another player in game"}
+	case len(g.unusedTiles) < g.NumNewTiles && !g.hasOpenTeam():
+		err = gameWarning{code: message.CodeNotEnoughTiles, text: "not enough tiles to join the game"}
 	default:
 		err = g.handleAddPlayer(ctx, m, send)
 	}
@@ -270,26 +608,60 @@ func (g *Game) handleGameJoin(ctx context.Context, m message.Message, send messa
 	return nil
 }
 
+// hasInviteCode determines if the message has the invite code of the game.  Messages for public games do not need an invite code.
+func (g Game) hasInviteCode(m message.Message) bool {
+	return !g.Private() || (m.Game != nil && m.Game.InviteCode == g.InviteCode)
+}
+
 // handleAddPlayer adds the player to the game.
+// In games played in teams, the player shares the board of the first team that has room for them.  Players only get new tiles when they start a new team.
 func (g *Game) handleAddPlayer(ctx context.Context, m message.Message, send messageSender) error {
-	newTiles := g.unusedTiles[:g.NumNewTiles]
-	g.unusedTiles = g.unusedTiles[g.NumNewTiles:]
-	b, err := m.Game.Board.Config.New(newTiles)
-	if err != nil {
-		return err
+	team, teammate := g.openTeam()
+	var newTiles []tile.Tile
+	var b *board.Board
+	switch {
+	case len(teammate) != 0:
+		b = g.players[teammate].Board
+	default:
+		newTiles = g.unusedTiles[:g.NumNewTiles]
+		g.unusedTiles = g.unusedTiles[g.NumNewTiles:]
+		var err error
+		b, err = m.Game.Board.Config.New(newTiles)
+		if err != nil {
+			return err
+		}
 	}
 	p, err := g.PlayerCfg.New(b)
 	if err != nil {
 		return fmt.Errorf("creating player: %w", err)
 	}
+	p.Ready = m.PlayerName.IsBot() // bots are always ready to play
+	p.Team = team
 	g.players[m.PlayerName] = p
+	if len(g.host) == 0 {
+		g.host = m.PlayerName
+	}
+	g.readUserPoints(ctx, m.PlayerName)
+	boardConfig := g.boardConfig(m.PlayerName, m.Game.Board.Config)
+	e := replay.Event{
+		Type:        replay.Join,
+		PlayerName:  m.PlayerName,
+		BoardConfig: &boardConfig,
+		Teammate:    teammate,
+	}
+	if len(newTiles) != 0 {
+		e.Tiles = append([]tile.Tile{}, newTiles...)
+	}
+	g.record(e)
 	m2, err := g.resizeBoard(m)
 	if err != nil {
 		return fmt.Errorf("creating board message: %w", err)
 	}
 	m2.Info = "joining game"
 	send(*m2)
+	g.sendTeamBoard(*m2, send)
 	gamePlayers := m2.Game.Players
+	gamePlayerPoints := m2.Game.PlayerPoints
 	for n := range g.players { // send info to other players
 		if n == m.PlayerName {
 			continue
@@ -299,18 +671,342 @@ func (g *Game) handleAddPlayer(ctx context.Context, m message.Message, send mess
 			PlayerName: n,
 			Info:       fmt.Sprintf("%v joined the game", m.PlayerName),
 			Game: &game.Info{
-				TilesLeft: len(g.unusedTiles),
-				Players:   gamePlayers,
+				TilesLeft:    len(g.unusedTiles),
+				Players:      gamePlayers,
+				PlayerPoints: gamePlayerPoints,
+				Host:         string(g.host),
+				ReadyPlayers: m2.Game.ReadyPlayers,
+				Teams:        m2.Game.Teams,
 			},
 		}
 		send(m3)
 	}
 	g.handleInfoChanged(send)
+	g.autoStart(send)
+	return nil
+}
+
+// handleGameLeave removes the player from the game if it has not started, returning their tiles to the unused tiles.
+// Players stay in games that have started so they can rejoin them.  Another player becomes the host if the host leaves the game.
+func (g *Game) handleGameLeave(ctx context.Context, m message.Message, send messageSender) error {
+	removed := g.status == game.NotStarted
+	if removed {
+		g.removePlayer(m.PlayerName, replay.Leave)
+		g.ShuffleUnusedTilesFunc(g.unusedTiles)
+	}
+	info := fmt.Sprintf("%v left the game", m.PlayerName)
+	changed := removed
+	if m.PlayerName == g.host {
+		host := firstPlayer(g.players, m.PlayerName)
+		switch {
+		case len(host) != 0:
+			g.host = host
+			info = fmt.Sprintf("%v, %v is now the host", info, host)
+			changed = true
+		case removed:
+			g.host = "" // the next player to join the game is the host
+		}
+	}
+	if !changed {
+		return nil
+	}
+	g.sendPlayersChanged(info, send)
+	g.autoStart(send)
+	return nil
+}
+
+// handleGameForfeit freezes the board of the player, who gives up the game.
+// The game is finished without a winner when every player has forfeited it.
+func (g *Game) handleGameForfeit(ctx context.Context, m message.Message, send messageSender) error {
+	p := g.players[m.PlayerName]
+	switch {
+	case g.status != game.InProgress:
+		return gameWarningNotInProgress
+	case p.Forfeited:
+		return gameWarningForfeited
+	}
+	p.Forfeited = true
+	g.record(replay.Event{
+		Type:       replay.Forfeit,
+		PlayerName: m.PlayerName,
+	})
+	if len(g.forfeitedPlayers()) < len(g.players) {
+		g.sendPlayersChanged(fmt.Sprintf("%v forfeited the game", m.PlayerName), send)
+		return nil
+	}
+	g.status = game.Finished
+	g.record(replay.Event{
+		Type: replay.Finish,
+	})
+	g.sendFinalBoards("GAME OVER! - every player forfeited, so no one won.  View other player's boards on the 'Final Boards' tab,", send)
+	g.handleInfoChanged(send)
+	return nil
+}
+
+// removePlayer takes the player out of the game, recording the type of event that removed them.
+// The tiles of the player are returned to the unused tiles if the game has not started and no teammates share them.  The unused tiles should be shuffled afterwards.
+func (g *Game) removePlayer(pn player.Name, t replay.Type) {
+	if g.status == game.NotStarted && len(g.teammates(pn)) == 1 {
+		g.unusedTiles = append(g.unusedTiles, g.players[pn].Board.Tiles()...)
+	}
+	delete(g.players, pn)
+	delete(g.userPoints, pn)
+	g.record(replay.Event{
+		Type:       t,
+		PlayerName: pn,
+	})
+}
+
+// handleGameKick removes the players in the message from the game.  Only the host can kick players.
+// The tiles of kicked players are returned to the unused tiles if the game has not started.
+func (g *Game) handleGameKick(ctx context.Context, m message.Message, send messageSender) error {
+	if m.PlayerName != g.host {
+		return gameWarningNotHost
+	}
+	if g.status == game.Challenging {
+		return gameWarning{code: message.CodeInvalidStatus, text: "cannot kick players while a win is being challenged"}
+	}
+	if m.Game == nil || len(m.Game.Players) == 0 {
+		return gameWarning{code: message.CodeNotInGame, text: "no player to kick"}
+	}
+	kicked := make([]string, 0, len(m.Game.Players))
+	for _, n := range m.Game.Players {
+		pn := player.Name(n)
+		if _, ok := g.players[pn]; !ok || pn == g.host {
+			return gameWarning{code: message.CodeNotInGame, text: fmt.Sprintf("cannot kick %v: not a player other than the host", n)}
+		}
+		if !slices.Contains(kicked, n) { // players can only be kicked once
+			kicked = append(kicked, n)
+		}
+	}
+	for _, n := range kicked {
+		pn := player.Name(n)
+		g.removePlayer(pn, replay.Kick)
+		m2 := message.Message{
+			Type:       message.LeaveGame,
+			PlayerName: pn,
+			Info:       fmt.Sprintf("kicked from the game by %v", m.PlayerName),
+		}
+		send(m2)
+	}
+	if g.status == game.NotStarted {
+		g.ShuffleUnusedTilesFunc(g.unusedTiles)
+	}
+	g.sendPlayersChanged(fmt.Sprintf("%v kicked %v from the game", m.PlayerName, strings.Join(kicked, ", ")), send)
+	g.autoStart(send)
+	return nil
+}
+
+// handleToggleReady changes whether or not the player is ready for the game to start.
+// Games that start automatically are started if every player is ready.
+func (g *Game) handleToggleReady(ctx context.Context, m message.Message, send messageSender) error {
+	if g.status != game.NotStarted {
+		return gameWarning{code: message.CodeInvalidStatus, text: "can only change readiness before the game starts"}
+	}
+	p := g.players[m.PlayerName]
+	p.Ready = !p.Ready
+	info := fmt.Sprintf("%v is ready", m.PlayerName)
+	if !p.Ready {
+		info = fmt.Sprintf("%v is not ready", m.PlayerName)
+	}
+	g.sendPlayersChanged(info, send)
+	g.autoStart(send)
 	return nil
 }
 
-// handleGameDelete sends game leave messages to all players in the game.
+// autoStart starts games that start automatically when every player is ready or no more players can join.
+func (g *Game) autoStart(send messageSender) {
+	if !g.AutoStart || g.status != game.NotStarted || len(g.players) == 0 {
+		return
+	}
+	if len(g.players) < g.MaxPlayers && len(g.readyPlayers()) < len(g.players) {
+		return
+	}
+	g.start("", "the game started automatically", send)
+	g.handleInfoChanged(send)
+}
+
+// readyPlayers returns the sorted names of the players who are ready for the game to start.
+// Readiness is only reported before the game starts.
+func (g Game) readyPlayers() []string {
+	if g.status != game.NotStarted {
+		return nil
+	}
+	var readyPlayers []string
+	for n, p := range g.players {
+		if p.Ready {
+			readyPlayers = append(readyPlayers, string(n))
+		}
+	}
+	sort.Strings(readyPlayers)
+	return readyPlayers
+}
+
+// forfeitedPlayers returns the sorted names of the players who forfeited the game.
+// Players can only forfeit games that have started.
+func (g Game) forfeitedPlayers() []string {
+	if g.status == game.NotStarted {
+		return nil
+	}
+	var forfeitedPlayers []string
+	for n, p := range g.players {
+		if p.Forfeited {
+			forfeitedPlayers = append(forfeitedPlayers, string(n))
+		}
+	}
+	sort.Strings(forfeitedPlayers)
+	return forfeitedPlayers
+}
+
+// sendPlayersChanged sends the players, tiles left, and host of the game to each player and updates the info of the game.
+func (g Game) sendPlayersChanged(info string, send messageSender) {
+	gamePlayers := g.playerNames()
+	gamePlayerPoints := g.playerPoints()
+	readyPlayers := g.readyPlayers()
+	forfeitedPlayers := g.forfeitedPlayers()
+	teams := g.teams()
+	for n := range g.players {
+		m := message.Message{
+			Type:       message.ChangeGameTiles,
+			PlayerName: n,
+			Info:       info,
+			Game: &game.Info{
+				TilesLeft:        len(g.unusedTiles),
+				Players:          gamePlayers,
+				PlayerPoints:     gamePlayerPoints,
+				Host:             string(g.host),
+				ReadyPlayers:     readyPlayers,
+				ForfeitedPlayers: forfeitedPlayers,
+				Teams:            teams,
+			},
+		}
+		send(m)
+	}
+	g.handleInfoChanged(send)
+}
+
+// teammates returns the sorted names of the players on the team of the player, including the player.
+// Players who are not on a team are their only teammate.
+func (g Game) teammates(pn player.Name) []player.Name {
+	p, ok := g.players[pn]
+	if g.TeamSize < 2 || !ok || p.Team == 0 {
+		return []player.Name{pn}
+	}
+	var teammates []player.Name
+	for n, p2 := range g.players {
+		if p2.Team == p.Team {
+			teammates = append(teammates, n)
+		}
+	}
+	sort.Slice(teammates, func(i, j int) bool {
+		return teammates[i] < teammates[j]
+	})
+	return teammates
+}
+
+// teams returns the numbers of the teams of the players, keyed by player name.
+// Nil is returned if the game is not played in teams.
+func (g Game) teams() map[string]int {
+	if g.TeamSize < 2 {
+		return nil
+	}
+	teams := make(map[string]int, len(g.players))
+	for n, p := range g.players {
+		teams[string(n)] = p.Team
+	}
+	return teams
+}
+
+// openTeam returns the number of the first team that has room for another player and the first player on it, who has the board of the team.
+// The number of a new team and no player are returned if every team is full.  Zero is returned if the game is not played in teams.
+func (g Game) openTeam() (int, player.Name) {
+	if g.TeamSize < 2 {
+		return 0, ""
+	}
+	teamSizes := make(map[int]int)
+	for _, p := range g.players {
+		teamSizes[p.Team]++
+	}
+	openTeam := 0
+	for team, size := range teamSizes {
+		if size < g.TeamSize && (openTeam == 0 || team < openTeam) {
+			openTeam = team
+		}
+	}
+	if openTeam == 0 {
+		for openTeam = 1; teamSizes[openTeam] != 0; openTeam++ {
+			// NOOP
+		}
+		return openTeam, ""
+	}
+	var teammate player.Name
+	for n, p := range g.players {
+		if p.Team == openTeam && (len(teammate) == 0 || n < teammate) {
+			teammate = n
+		}
+	}
+	return openTeam, teammate
+}
+
+// hasOpenTeam determines if another player can join a team that already has a board.
+func (g Game) hasOpenTeam() bool {
+	_, teammate := g.openTeam()
+	return len(teammate) != 0
+}
+
+// boardConfig is the size of the board of the player after it is resized to the config.
+// Boards that are shared by teammates are only made smaller so they fit on the screens of every teammate.
+func (g Game) boardConfig(pn player.Name, cfg board.Config) board.Config {
+	if len(g.teammates(pn)) < 2 {
+		return cfg
+	}
+	b := g.players[pn].Board
+	return board.Config{
+		NumRows: min(cfg.NumRows, b.Config.NumRows),
+		NumCols: min(cfg.NumCols, b.Config.NumCols),
+	}
+}
+
+// sendTeamBoard sends the board in the message to the teammates of the player the message is for, who share the board.
+func (g Game) sendTeamBoard(m message.Message, send messageSender) {
+	for _, pn := range g.teammates(m.PlayerName) {
+		if pn == m.PlayerName {
+			continue
+		}
+		m2 := message.Message{
+			Type:       message.ChangeGameTiles,
+			PlayerName: pn,
+			Game: &game.Info{
+				Board:     m.Game.Board,
+				TilesLeft: m.Game.TilesLeft,
+			},
+		}
+		send(m2)
+	}
+}
+
+// firstPlayer returns the first of the sorted names of the players that is not the excluded player, or an empty name if there is none.
+func firstPlayer(players map[player.Name]*playerController.Player, excluded player.Name) player.Name {
+	var first player.Name
+	for pn := range players {
+		if pn != excluded && (len(first) == 0 || pn < first) {
+			first = pn
+		}
+	}
+	return first
+}
+
+// handleGameDelete deletes the game if the host requests it.
 func (g *Game) handleGameDelete(ctx context.Context, m message.Message, send messageSender) error {
+	if m.PlayerName != g.host {
+		return gameWarningNotHost
+	}
+	g.deleteGame(m, send)
+	return nil
+}
+
+// deleteGame sends game leave messages to all players in the game and marks it as deleted.
+func (g *Game) deleteGame(m message.Message, send messageSender) {
 	for n := range g.players {
 		m := message.Message{
 			Type:       message.LeaveGame,
@@ -319,9 +1015,15 @@ func (g *Game) handleGameDelete(ctx context.Context, m message.Message, send mes
 		}
 		send(m)
 	}
+	if g.hasSpectators {
+		m := message.Message{
+			Type: message.LeaveGame,
+			Info: "game deleted",
+		}
+		send(m)
+	}
 	g.status = game.Deleted
 	g.handleInfoChanged(send)
-	return nil
 }
 
 // handleGameStatusChange changes the status of the game.
@@ -336,7 +1038,7 @@ func (g *Game) handleGameStatusChange(ctx context.Context, m message.Message, se
 			return err
 		}
 	default:
-		return fmt.Errorf("cannot change game state from %v", g.status)
+		return gameError{code: message.CodeInvalidStatus, err: fmt.Errorf("cannot change game state from %v", g.status)}
 	}
 	g.handleInfoChanged(send)
 	return nil
@@ -344,78 +1046,131 @@ func (g *Game) handleGameStatusChange(ctx context.Context, m message.Message, se
 
 // handleGameStart starts the game.
 func (g *Game) handleGameStart(ctx context.Context, m message.Message, send messageSender) error {
+	if m.PlayerName != g.host {
+		return gameWarningNotHost
+	}
 	if g.status != game.NotStarted {
-		return gameWarning("can only set game status to started")
+		return gameWarning{code: message.CodeInvalidStatus, text: "can only set game status to started"}
 	}
+	g.start(m.PlayerName, fmt.Sprintf("%v started the game", m.PlayerName), send)
+	return nil
+}
+
+// start starts the game for the player, notifying the players with the info.
+// The player name is empty if the game is started automatically.
+func (g *Game) start(pn player.Name, info string, send messageSender) {
 	g.status = game.InProgress
-	info := fmt.Sprintf("%v started the game", m.PlayerName)
+	g.record(replay.Event{
+		Type:       replay.Start,
+		PlayerName: pn,
+	})
 	for n := range g.players {
 		m := message.Message{
 			Type:       message.ChangeGameStatus,
 			PlayerName: n,
 			Info:       info,
 			Game: &game.Info{
-				Status:    g.status,
-				TilesLeft: len(g.unusedTiles),
+				Status:      g.status,
+				TilesLeft:   len(g.unusedTiles),
+				SecondsLeft: g.secondsLeft(),
 			},
 		}
 		send(m)
 	}
-	return nil
 }
 
 // checkPlayerBoard checks the player board to ensure all tiles are in a group, words are valid, and other tests prescribed by the config.
 // The words and a possible game warning error are returned.
 // The player's winPoints are decremented if an error is returned if and only if the config wants to.
 func (g *Game) checkPlayerBoard(pn player.Name, checkWords bool) ([]string, error) {
-	var usedWords []string
-	var err error
+	var w gameWarning
 	p := g.players[pn]
 	switch {
 	case len(p.Board.UnusedTiles) != 0:
-		err = fmt.Errorf("not all tiles used")
-	case !p.Board.CanBeFinished():
-		err = fmt.Errorf("not all used tiles form a single group")
-	case checkWords:
-		usedWords, err = g.checkWords(pn)
+		w = gameWarning{
+			code: message.CodeUnusedTiles,
+			text: "not all tiles used",
+			details: &message.Details{
+				TileIDs: append([]tile.ID{}, p.Board.UnusedTileIDs...),
+			},
+		}
+	default:
+		usedWords, err := g.checkUsedTiles(pn, checkWords)
+		if err == nil {
+			return usedWords, nil
+		}
+		if !errors.As(err, &w) {
+			return nil, err
+		}
 	}
-	if err != nil {
-		errText := "invalid board: " + err.Error()
-		if g.Config.Penalize && p.WinPoints > 2 {
-			p.WinPoints--
-			errText = errText + ", possible win points decremented"
+	w.text = "invalid board: " + w.text
+	if g.Config.Penalize && p.WinPoints > 2 {
+		p.WinPoints--
+		w.text = w.text + ", possible win points decremented"
+		if w.details == nil {
+			w.details = new(message.Details)
 		}
-		return nil, gameWarning(errText)
+		w.details.Penalty = 1
 	}
-	return usedWords, nil
+	return nil, w
+}
+
+// checkUsedTiles checks that the used tiles of the player's board are in a single group and, if specified, form valid words.
+// Unlike checkPlayerBoard, tiles that are not used are ignored and players are not penalized.
+func (g Game) checkUsedTiles(pn player.Name, checkWords bool) ([]string, error) {
+	p := g.players[pn]
+	switch {
+	case !p.Board.HasSingleUsedGroup():
+		var strayTileIDs []tile.ID
+		for _, group := range p.Board.UsedGroups()[1:] {
+			strayTileIDs = append(strayTileIDs, group...)
+		}
+		w := gameWarning{
+			code: message.CodeMultipleGroups,
+			text: "not all used tiles form a single group",
+			details: &message.Details{
+				TileIDs: strayTileIDs,
+			},
+		}
+		return nil, w
+	case checkWords:
+		return g.checkWords(pn)
+	}
+	return nil, nil
 }
 
-// checkWords returns the used words from the game and an error if the game board is not valid.
+// checkWords returns the used words from the game and a game warning if the game board is not valid.
 func (g Game) checkWords(pn player.Name) ([]string, error) {
 	p := g.players[pn]
 	usedWords := p.Board.UsedTileWords()
 	var invalidWords []string
 	uniqueWords := make(map[string]struct{}, len(usedWords))
-	var err error
 	for _, w := range usedWords {
 		if _, ok := uniqueWords[w]; g.Config.ProhibitDuplicates && ok {
-			err = fmt.Errorf("duplicate words are prohibited")
-			break
+			return nil, gameWarning{
+				code:    message.CodeDuplicateWords,
+				text:    "duplicate words are prohibited",
+				details: &message.Details{InvalidWords: []string{w}},
+			}
 		}
 		uniqueWords[w] = struct{}{}
-		if len(w) < g.Config.MinLength {
-			err = fmt.Errorf("short word detected, all must be at least %v characters", g.Config.MinLength)
-			break
+		if utf8.RuneCountInString(w) < g.Config.MinLength {
+			return nil, gameWarning{
+				code:    message.CodeShortWords,
+				text:    fmt.Sprintf("short word detected, all must be at least %v characters", g.Config.MinLength),
+				details: &message.Details{InvalidWords: []string{w}},
+			}
 		}
 		if !g.WordValidator.Validate(w) {
 			invalidWords = append(invalidWords, w)
 		}
 	}
 	if len(invalidWords) > 0 {
-		err = fmt.Errorf("invalid words: %v", invalidWords)
-	}
-	if err != nil {
-		return nil, err
+		return nil, gameWarning{
+			code:    message.CodeInvalidWords,
+			text:    fmt.Sprintf("invalid words: %v", invalidWords),
+			details: &message.Details{InvalidWords: invalidWords},
+		}
 	}
 	return usedWords, nil
 }
@@ -423,55 +1178,390 @@ func (g Game) checkWords(pn player.Name) ([]string, error) {
 // handleGameStart tries to finish the game for the player sending the message by checking to see if the player wins.
 // If the player has won, game cleanup logic is triggered.
 func (g *Game) handleGameFinish(ctx context.Context, m message.Message, send messageSender) error {
-	p := g.players[m.PlayerName]
 	switch {
 	case g.status != game.InProgress:
 		return gameWarningNotInProgress
-	case len(g.unusedTiles) != 0:
-		return gameWarning("snag first")
+	case len(g.unusedTiles) != 0 && !g.bananasRace():
+		return gameWarning{code: message.CodeSnagFirst, text: "snag first"}
+	case g.players[m.PlayerName].Forfeited:
+		return gameWarningForfeited
 	}
+	g.finishOrder = append(g.finishOrder, m.PlayerName)
 	usedWords, boardErr := g.checkPlayerBoard(m.PlayerName, true)
 	if boardErr != nil {
 		return boardErr
 	}
+	if g.ChallengeSec > 0 && len(g.challengers(m.PlayerName)) != 0 {
+		g.startChallenge(m.PlayerName, usedWords, send)
+		return nil
+	}
+	g.finish(ctx, m.PlayerName, usedWords, send)
+	return nil
+}
+
+// finish ends the game, which the winning player won with the used words.
+func (g *Game) finish(ctx context.Context, winningPlayerName player.Name, usedWords []string, send messageSender) {
 	g.status = game.Finished
+	g.record(replay.Event{
+		Type:       replay.Finish,
+		PlayerName: winningPlayerName,
+	})
+	userPoints, err := g.updateUserPoints(ctx, winningPlayerName)
 	info := fmt.Sprintf(
-		"WINNER! - %v won, creating %v words, getting %v points.  Other players each get 1 point.  View other player's boards on the 'Final Boards' tab,",
-		m.PlayerName,
+		"WINNER! - %v won, creating %v words, getting %v points.  View other player's boards on the 'Final Boards' tab,",
+		winningPlayerName,
 		len(usedWords),
-		p.WinPoints,
+		userPoints[string(winningPlayerName)],
 	)
-	err := g.updateUserPoints(ctx, m.PlayerName)
 	if err != nil {
 		g.log.Printf("updating user points: %v", err)
 		info = err.Error()
 	}
-	finalBoards := g.playerFinalBoards()
-	for n := range g.players {
-		m := message.Message{
-			Type:       message.ChangeGameStatus,
-			PlayerName: n,
-			Info:       info,
-			Game: &game.Info{
-				Status:      game.Finished,
+	if err := g.resultStore.Create(ctx, g.result(winningPlayerName, usedWords)); err != nil {
+		g.log.Printf("recording game result: %v", err)
+	}
+	g.sendFinalBoards(info, send)
+}
+
+// challengers are the players who can challenge the words of the player who claims to win.
+// Bots, players who forfeited the game, and teammates of the claimant cannot challenge the words.
+func (g Game) challengers(claimant player.Name) []player.Name {
+	teammates := g.teammates(claimant)
+	var challengers []player.Name
+	for _, n := range g.playerNames() {
+		pn := player.Name(n)
+		if pn.IsBot() || g.players[pn].Forfeited || slices.Contains(teammates, pn) {
+			continue
+		}
+		challengers = append(challengers, pn)
+	}
+	return challengers
+}
+
+// startChallenge gives the other players time to challenge the words of the player who claims to win before the game is finished.
+// The boards of all players are sent so the words of the claimant can be checked.
+func (g *Game) startChallenge(claimant player.Name, usedWords []string, send messageSender) {
+	g.status = game.Challenging
+	g.challenge = &challenge{
+		claimant: claimant,
+		words:    usedWords,
+		endsAt:   g.TimeFunc() + int64(g.ChallengeSec),
+		votes:    make(map[player.Name][]string),
+	}
+	info := fmt.Sprintf(
+		"%v claims to have won, creating %v words.  View their board on the 'Final Boards' tab and challenge any words that should not be allowed in the next %v (minutes:seconds).",
+		claimant,
+		len(usedWords),
+		game.FormatSeconds(g.ChallengeSec),
+	)
+	g.sendChallenge(info, g.playerFinalBoards(), send)
+}
+
+// sendChallenge tells each player about the claimed win that is being challenged.
+// The final boards are only sent when the challenge starts.
+func (g Game) sendChallenge(info string, finalBoards map[string]board.Board, send messageSender) {
+	for n := range g.players {
+		m := message.Message{
+			Type:       message.ChangeGameStatus,
+			PlayerName: n,
+			Info:       info,
+			Game: &game.Info{
+				Status:      game.Challenging,
+				TilesLeft:   len(g.unusedTiles),
+				SecondsLeft: g.challengeSecondsLeft(),
+				Claimant:    string(g.challenge.claimant),
 				FinalBoards: finalBoards,
 			},
 		}
 		send(m)
 	}
+}
+
+// challengeSecondsLeft is the amount of time left to challenge the claimed win.
+// Zero is returned if the game is not challenging.
+func (g Game) challengeSecondsLeft() int {
+	if g.status != game.Challenging {
+		return 0
+	}
+	secondsLeft := int(g.challenge.endsAt - g.TimeFunc())
+	if secondsLeft < 0 {
+		return 0
+	}
+	return secondsLeft
+}
+
+// handleChallengeWords records the words on the board of the claimant that a player challenges.
+// Players who send no words accept the win.  The challenge is resolved when every player who can challenge it has voted.
+func (g *Game) handleChallengeWords(ctx context.Context, m message.Message, send messageSender) error {
+	if g.status != game.Challenging {
+		return gameWarning{code: message.CodeCannotChallenge, text: "no win to challenge"}
+	}
+	c := g.challenge
+	challengers := g.challengers(c.claimant)
+	if !slices.Contains(challengers, m.PlayerName) {
+		return gameWarning{code: message.CodeCannotChallenge, text: fmt.Sprintf("cannot challenge the words of %v", c.claimant)}
+	}
+	var words []string
+	if m.Game != nil {
+		for _, w := range m.Game.ChallengedWords {
+			switch {
+			case !slices.Contains(c.words, w):
+				return gameWarning{code: message.CodeCannotChallenge, text: fmt.Sprintf("%v is not a word on the board of %v", w, c.claimant)}
+			case !slices.Contains(words, w):
+				words = append(words, w)
+			}
+		}
+	}
+	c.votes[m.PlayerName] = words
+	info := fmt.Sprintf("%v accepted the win of %v", m.PlayerName, c.claimant)
+	if len(words) != 0 {
+		info = fmt.Sprintf("%v challenged %v", m.PlayerName, strings.Join(words, ", "))
+	}
+	for _, pn := range challengers {
+		if _, ok := c.votes[pn]; !ok {
+			g.sendChallenge(info, nil, send)
+			return nil
+		}
+	}
+	g.resolveChallenge(ctx, info, send)
+	g.handleInfoChanged(send)
 	return nil
 }
 
-// handleGameSnag adds a tile to all the players.
+// handleChallengeTick sends the time left to challenge the claimed win to the players or resolves the challenge if time has run out.
+func (g *Game) handleChallengeTick(ctx context.Context, send messageSender) {
+	if g.challengeSecondsLeft() > 0 {
+		g.sendChallenge("", nil, send)
+		return
+	}
+	g.resolveChallenge(ctx, "time is up to challenge the win", send)
+	g.handleInfoChanged(send)
+	g.saveState(ctx)
+	g.updateSpectators(send)
+}
+
+// challengedWords are the words of the claimant that were challenged by most of the players who can challenge them.
+func (g Game) challengedWords() []string {
+	c := g.challenge
+	challengers := g.challengers(c.claimant)
+	votes := make(map[string]int, len(c.words))
+	for _, pn := range challengers {
+		for _, w := range c.votes[pn] {
+			votes[w]++
+		}
+	}
+	var challengedWords []string
+	for _, w := range c.words {
+		if votes[w]*2 > len(challengers) && !slices.Contains(challengedWords, w) {
+			challengedWords = append(challengedWords, w)
+		}
+	}
+	return challengedWords
+}
+
+// resolveChallenge finishes the game if the words of the claimant were not challenged by most of the players who can challenge them.
+// Otherwise, the claimant gets a rotten banana: the team of the claimant is out of the game, the tiles on their board are returned to the unused tiles, and the game continues.
+func (g *Game) resolveChallenge(ctx context.Context, info string, send messageSender) {
+	challengedWords := g.challengedWords()
+	c := g.challenge
+	g.challenge = nil
+	if len(challengedWords) == 0 {
+		g.finish(ctx, c.claimant, c.words, send)
+		return
+	}
+	b := g.players[c.claimant].Board
+	g.unusedTiles = append(g.unusedTiles, b.Tiles()...)
+	g.ShuffleUnusedTilesFunc(g.unusedTiles)
+	cfg, revision := b.Config, b.Revision
+	*b = *board.New(nil, nil) // teammates share the board
+	b.Config = cfg
+	b.Revision = revision + 1
+	rottenPlayers := g.teammates(c.claimant)
+	rottenPlayerNames := make([]string, len(rottenPlayers))
+	for i, pn := range rottenPlayers {
+		g.players[pn].Forfeited = true
+		rottenPlayerNames[i] = string(pn)
+	}
+	g.record(replay.Event{
+		Type:       replay.RottenBanana,
+		PlayerName: c.claimant,
+	})
+	g.status = game.InProgress
+	info = fmt.Sprintf(
+		"%v.  ROTTEN BANANA! - most players challenged %v, so %v is out of the game and their tiles were returned to the pile.",
+		info,
+		strings.Join(challengedWords, ", "),
+		strings.Join(rottenPlayerNames, ", "),
+	)
+	gamePlayers := g.playerNames()
+	forfeitedPlayers := g.forfeitedPlayers()
+	for n := range g.players {
+		m := message.Message{
+			Type:       message.ChangeGameStatus,
+			PlayerName: n,
+			Info:       info,
+			Game: &game.Info{
+				Status:           g.status,
+				TilesLeft:        len(g.unusedTiles),
+				SecondsLeft:      g.secondsLeft(),
+				Players:          gamePlayers,
+				ForfeitedPlayers: forfeitedPlayers,
+				BananasRace:      g.bananasRace(),
+				ChallengedWords:  challengedWords,
+			},
+		}
+		if slices.Contains(rottenPlayers, n) {
+			m.Game.Board = b
+		}
+		send(m)
+	}
+}
+
+// sendFinalBoards tells each player the game is finished, sending the boards of all players.
+func (g Game) sendFinalBoards(info string, send messageSender) {
+	finalBoards := g.playerFinalBoards()
+	for n := range g.players {
+		m := message.Message{
+			Type:       message.ChangeGameStatus,
+			PlayerName: n,
+			Info:       info,
+			Game: &game.Info{
+				Status:      game.Finished,
+				FinalBoards: finalBoards,
+			},
+		}
+		send(m)
+	}
+}
+
+// handleClockTick sends the time left to the players of a timed game or ends the game if time has run out.
+func (g *Game) handleClockTick(ctx context.Context, send messageSender) {
+	secondsLeft := g.secondsLeft()
+	if secondsLeft > 0 {
+		for n := range g.players {
+			m := message.Message{
+				Type:       message.ChangeGameStatus,
+				PlayerName: n,
+				Game: &game.Info{
+					Status:      g.status,
+					TilesLeft:   len(g.unusedTiles),
+					SecondsLeft: secondsLeft,
+				},
+			}
+			send(m)
+		}
+		return
+	}
+	g.handleTimeUp(ctx, send)
+	g.handleInfoChanged(send)
+	g.saveState(ctx)
+	g.updateSpectators(send)
+}
+
+// handleTimeUp finishes a timed game when time has run out.
+// Every board of the players who have not forfeited is checked and the player who used the most tiles in a single group of valid words wins.
+// There is no winner if no player has a valid board.
+func (g *Game) handleTimeUp(ctx context.Context, send messageSender) {
+	var winningPlayerName player.Name
+	var winningWords []string
+	mostTiles := 0
+	for _, pn := range g.playerNames() { // sorted so ties are won by the first player alphabetically
+		if g.players[player.Name(pn)].Forfeited {
+			continue
+		}
+		usedWords, err := g.checkUsedTiles(player.Name(pn), true)
+		if err != nil {
+			continue
+		}
+		if numTiles := len(g.players[player.Name(pn)].Board.UsedTiles); numTiles > mostTiles {
+			winningPlayerName = player.Name(pn)
+			winningWords = usedWords
+			mostTiles = numTiles
+		}
+	}
+	g.status = game.Finished
+	g.record(replay.Event{
+		Type:       replay.Finish,
+		PlayerName: winningPlayerName,
+	})
+	info := "TIME IS UP! - no player has a single group of valid words.  View other player's boards on the 'Final Boards' tab,"
+	if len(winningPlayerName) != 0 {
+		userPoints, err := g.updateUserPoints(ctx, winningPlayerName)
+		info = fmt.Sprintf(
+			"TIME IS UP! - %v won, using %v tiles to create %v words, getting %v points.  View other player's boards on the 'Final Boards' tab,",
+			winningPlayerName,
+			mostTiles,
+			len(winningWords),
+			userPoints[string(winningPlayerName)],
+		)
+		if err != nil {
+			g.log.Printf("updating user points: %v", err)
+			info = err.Error()
+		}
+		if err := g.resultStore.Create(ctx, g.result(winningPlayerName, winningWords)); err != nil {
+			g.log.Printf("recording game result: %v", err)
+		}
+	}
+	g.sendFinalBoards(info, send)
+}
+
+// startedAt is when the game was started, or when it was created if no start was recorded.
+func (g Game) startedAt() int64 {
+	for _, e := range g.events {
+		if e.Type == replay.Start {
+			return e.Time
+		}
+	}
+	return g.createdAt
+}
+
+// secondsLeft is the amount of time left in a timed game that is in progress.
+// Zero is returned for games that are not timed or are not in progress.
+func (g Game) secondsLeft() int {
+	if g.Config.Config.TimeLimitSec <= 0 || g.status != game.InProgress {
+		return 0
+	}
+	endsAt := g.startedAt() + int64(g.Config.Config.TimeLimitSec)
+	secondsLeft := int(endsAt - g.TimeFunc())
+	if secondsLeft < 0 {
+		return 0
+	}
+	return secondsLeft
+}
+
+// peelBoards is the number of boards that get a tile when a player snags.
+func (g Game) peelBoards() int {
+	boards := make(map[*board.Board]struct{}, len(g.players))
+	for _, p := range g.players {
+		if !p.Forfeited {
+			boards[p.Board] = struct{}{}
+		}
+	}
+	return len(boards)
+}
+
+// bananasRace determines if a strict peel game has too few tiles left for every player to get one.
+// Players race to finish with the tiles they have when this happens.
+func (g Game) bananasRace() bool {
+	return g.StrictPeel && len(g.unusedTiles) < g.peelBoards()
+}
+
+// handleGameSnag adds a tile to all the players who have not forfeited the game.
 // The order that the players receive their tiles is randomized, some players may not receive tiles if there are none left.
+// Teammates share a board, so each team receives a single tile, unless all of its players forfeited.
+// Strict peel games only allow snags from players with valid boards, and only when every player can get a tile.
 func (g *Game) handleGameSnag(ctx context.Context, m message.Message, send messageSender) error {
 	switch {
 	case g.status != game.InProgress:
 		return gameWarningNotInProgress
 	case len(g.unusedTiles) == 0:
-		return gameWarning("no tiles left to snag, use what you have to finish")
+		return gameWarning{code: message.CodeNoTilesLeft, text: "no tiles left to snag, use what you have to finish"}
+	case g.bananasRace():
+		return gameWarning{code: message.CodeBananasRace, text: "not enough tiles left for every player to snag, finish your board to call Bananas!"}
+	case g.players[m.PlayerName].Forfeited:
+		return gameWarningForfeited
 	}
-	if _, err := g.checkPlayerBoard(m.PlayerName, g.Config.CheckOnSnag); err != nil {
+	if _, err := g.checkPlayerBoard(m.PlayerName, g.Config.CheckOnSnag || g.StrictPeel); err != nil {
 		return err
 	}
 	snagPlayerMessages := make(map[player.Name]message.Message, len(g.players))
@@ -483,68 +1573,107 @@ func (g *Game) handleGameSnag(ctx context.Context, m message.Message, send messa
 		}
 	}
 	g.ShufflePlayersFunc(snagPlayerNames[1:])
+	boardDiffs := make(map[*board.Board]*board.Diff, len(g.players))
+	for _, n2 := range snagPlayerNames {
+		b := g.players[n2].Board
+		if _, ok := boardDiffs[b]; ok || len(g.unusedTiles) == 0 || g.players[n2].Forfeited {
+			continue
+		}
+		t := g.unusedTiles[0]
+		d := b.NewDiff(board.OpAdd, tile.Position{Tile: t})
+		if err := b.Apply(d); err != nil {
+			return err
+		}
+		g.unusedTiles = g.unusedTiles[1:]
+		g.record(replay.Event{
+			Type:       replay.Snag,
+			PlayerName: n2,
+			Tiles:      []tile.Tile{t},
+		})
+		boardDiffs[b] = &d
+	}
 	for _, n2 := range snagPlayerNames {
 		m2 := message.Message{
 			Type:       message.ChangeGameTiles,
 			PlayerName: n2,
 		}
-		var tiles []tile.Tile
+		m2.Game = new(game.Info)
+		m2.Game.Diff = boardDiffs[g.players[n2].Board]
 		switch {
 		case n2 == m.PlayerName:
-			tiles = g.unusedTiles[:1]
 			m2.Info = "snagged a tile"
-			if err := g.players[n2].Board.AddTile(g.unusedTiles[0]); err != nil {
-				return err
-			}
-			g.unusedTiles = g.unusedTiles[1:]
-		case len(g.unusedTiles) == 0:
+		case m2.Game.Diff == nil:
 			m2.Info = fmt.Sprintf("%v snagged a tile", m.PlayerName)
 		default:
 			m2.Info = fmt.Sprintf("%v snagged a tile, adding a tile to your pile", m.PlayerName)
-			tiles = g.unusedTiles[:1]
-			if err := g.players[n2].Board.AddTile(g.unusedTiles[0]); err != nil {
-				return err
-			}
-			g.unusedTiles = g.unusedTiles[1:]
-		}
-		m2.Game = &game.Info{
-			Board: board.New(tiles, nil),
 		}
 		snagPlayerMessages[n2] = m2
 	}
+	bananasRace := g.bananasRace()
 	for _, m := range snagPlayerMessages {
 		m.Game.TilesLeft = len(g.unusedTiles)
+		if bananasRace {
+			m.Info += ".  That was the last peel, the first player to finish wins: Bananas!"
+			m.Game.BananasRace = true
+		}
 		send(m)
 	}
 	return nil
 }
 
-// handleGameSwap swaps a tile for the player for three others, if possible.
+// handleGameSwap swaps a tile for the player for others from the unused tiles, if possible.
+// The whole board is sent back to the player if the swap is not allowed by the config of the game, undoing the removal of the tile.
 func (g *Game) handleGameSwap(ctx context.Context, m message.Message, send messageSender) error {
 	switch {
 	case g.status != game.InProgress:
 		return gameWarningNotInProgress
-	case len(m.Game.Board.UnusedTiles) != 1:
-		return gameWarning("no tile specified for swap")
+	case m.Game.Diff == nil, len(m.Game.Diff.Changes) != 1, m.Game.Diff.Changes[0].Op != board.OpRemove:
+		return gameWarning{code: message.CodeNoSwapTile, text: "no tile specified for swap"}
 	case len(g.unusedTiles) == 0:
-		return gameWarning("no tiles left to swap, user what you have to finish")
+		return gameWarning{code: message.CodeNoTilesLeft, text: "no tiles left to swap, user what you have to finish"}
+	case g.players[m.PlayerName].Forfeited:
+		return gameWarningForfeited
 	}
-	tid := m.Game.Board.UnusedTileIDs[0]
-	t := m.Game.Board.UnusedTiles[tid]
 	p := g.players[m.PlayerName]
-	if err := p.Board.RemoveTile(t); err != nil {
+	if m.Game.Diff.Revision != p.Board.Revision {
+		return g.handleStaleBoard(m, send)
+	}
+	t := m.Game.Diff.Changes[0].Tile
+	now := g.TimeFunc()
+	if err := g.checkSwap(*p, t, now); err != nil {
+		if err2 := g.refreshBoard(m, send); err2 != nil {
+			return err2
+		}
 		return err
 	}
+	if err := p.Board.Apply(*m.Game.Diff); err != nil {
+		return err
+	}
+	p.Swaps++
+	p.LastSwapTime = now
 	g.unusedTiles = append(g.unusedTiles, t)
 	g.ShuffleUnusedTilesFunc(g.unusedTiles)
 	var newTiles []tile.Tile
-	for i := 0; i < 3 && len(g.unusedTiles) > 0; i++ {
+	var newTilePositions []tile.Position
+	for i := 0; i < g.SwapTiles() && len(g.unusedTiles) > 0; i++ {
 		newTiles = append(newTiles, g.unusedTiles[0])
-		if err := p.Board.AddTile(g.unusedTiles[0]); err != nil {
-			return err
-		}
+		newTilePositions = append(newTilePositions, tile.Position{Tile: g.unusedTiles[0]})
 		g.unusedTiles = g.unusedTiles[1:]
 	}
+	d := p.Board.NewDiff(board.OpAdd, newTilePositions...)
+	if err := p.Board.Apply(d); err != nil {
+		return err
+	}
+	g.record(replay.Event{
+		Type:        replay.Swap,
+		PlayerName:  m.PlayerName,
+		Tiles:       newTiles,
+		SwappedTile: &t,
+	})
+	teammates := make(map[player.Name]bool)
+	for _, n := range g.teammates(m.PlayerName) {
+		teammates[n] = true
+	}
 	for n := range g.players {
 		m2 := message.Message{
 			Type:       message.ChangeGameTiles,
@@ -556,7 +1685,10 @@ func (g *Game) handleGameSwap(ctx context.Context, m message.Message, send messa
 		switch {
 		case n == m.PlayerName:
 			m2.Info = fmt.Sprintf("swapping %v tile", string(t.Ch))
-			m2.Game.Board = board.New(newTiles, nil)
+			m2.Game.Diff = &d
+		case teammates[n]:
+			m2.Info = fmt.Sprintf("%v swapped a %v tile for your team", m.PlayerName, string(t.Ch))
+			m2.Game.Board = p.Board // the swap changed the revision of the board twice
 		default:
 			m2.Info = fmt.Sprintf("%v swapped a tile", m.PlayerName)
 		}
@@ -565,14 +1697,164 @@ func (g *Game) handleGameSwap(ctx context.Context, m message.Message, send messa
 	return nil
 }
 
-// handleGameTilesMoved updates the player's board.
+// checkSwap returns a warning if the swap rules of the game do not allow the player to swap the tile now.
+func (g Game) checkSwap(p playerController.Player, t tile.Tile, now int64) error {
+	_, used := p.Board.UsedTiles[t.ID]
+	cooldownEnd := p.LastSwapTime + int64(g.SwapCooldownSec)
+	switch {
+	case g.SwapLimit > 0 && p.Swaps >= g.SwapLimit:
+		return gameWarning{code: message.CodeSwapLimit, text: fmt.Sprintf("no swaps left, each player can only swap %v tiles", g.SwapLimit)}
+	case p.Swaps > 0 && now < cooldownEnd:
+		return gameWarning{code: message.CodeSwapCooldown, text: fmt.Sprintf("wait %v (minutes:seconds) to swap another tile", game.FormatSeconds(int(cooldownEnd-now)))}
+	case used && !g.SwapUsedTiles:
+		return gameWarning{code: message.CodeUsedTileSwap, text: "only unused tiles can be swapped, move the tile off of the board first"}
+	}
+	return nil
+}
+
+// handleGameTilesMoved updates the player's board.  The moves are sent to the teammates of the player who share the board.
 func (g *Game) handleGameTilesMoved(ctx context.Context, m message.Message, send messageSender) error {
 	switch {
 	case g.status != game.InProgress:
 		return gameWarningNotInProgress
+	case m.Game.Diff == nil:
+		return fmt.Errorf("no diff of moved tiles")
+	case g.players[m.PlayerName].Forfeited:
+		return gameWarningForfeited
+	}
+	for _, c := range m.Game.Diff.Changes {
+		if c.Op != board.OpMove {
+			return fmt.Errorf("tiles can only be moved, got change %v", c.Op)
+		}
 	}
 	p := g.players[m.PlayerName]
-	return p.Board.MoveTiles(m.Game.Board.UsedTiles)
+	if m.Game.Diff.Revision != p.Board.Revision {
+		return g.handleStaleBoard(m, send)
+	}
+	if err := p.Board.Apply(*m.Game.Diff); err != nil {
+		return err
+	}
+	tilePositions := m.Game.Diff.MovedTilePositions()
+	sort.Slice(tilePositions, func(i, j int) bool {
+		return tilePositions[i].Tile.ID < tilePositions[j].Tile.ID
+	})
+	g.record(replay.Event{
+		Type:          replay.Move,
+		PlayerName:    m.PlayerName,
+		TilePositions: tilePositions,
+	})
+	for _, pn := range g.teammates(m.PlayerName) {
+		if pn == m.PlayerName {
+			continue
+		}
+		m3 := message.Message{
+			Type:       message.ChangeGameTiles,
+			PlayerName: pn,
+			Game: &game.Info{
+				Diff:      m.Game.Diff,
+				TilesLeft: len(g.unusedTiles),
+			},
+		}
+		send(m3)
+	}
+	r := g.boardReport(*p.Board)
+	m2 := message.Message{
+		Type:       message.BoardReport,
+		PlayerName: m.PlayerName,
+		Game: &game.Info{
+			Report: &r,
+		},
+	}
+	send(m2)
+	return nil
+}
+
+// handleStaleBoard sends the whole board to the player when the player sends changes for an old revision of the board.
+// The changes are not made, so a warning is returned.
+func (g *Game) handleStaleBoard(m message.Message, send messageSender) error {
+	if err := g.refreshBoard(m, send); err != nil {
+		return err
+	}
+	return gameWarning{code: message.CodeStaleBoard, text: "board changed before the tiles were changed, refreshing board"}
+}
+
+// refreshBoard sends the whole board of the player to them and the teammates who share it, replacing changes that the server did not make.
+func (g *Game) refreshBoard(m message.Message, send messageSender) error {
+	p := g.players[m.PlayerName]
+	m2 := message.Message{
+		Type:       message.RefreshGameBoard,
+		PlayerName: m.PlayerName,
+		Game: &game.Info{
+			Board: &board.Board{
+				Config: p.Board.Config,
+			},
+		},
+		Addr: m.Addr,
+	}
+	m3, err := g.resizeBoard(m2)
+	if err != nil {
+		return err
+	}
+	send(*m3)
+	g.sendTeamBoard(*m3, send)
+	return nil
+}
+
+// boardReport describes the words and groups of used tiles on the board.
+// Words are invalid if they are too short, not in the dictionary, or are repeated when duplicates are prohibited.
+func (g Game) boardReport(b board.Board) board.Report {
+	usedWords := make(map[string]struct{})
+	validWord := func(w string) bool {
+		if _, ok := usedWords[w]; g.Config.ProhibitDuplicates && ok {
+			return false
+		}
+		usedWords[w] = struct{}{}
+		return utf8.RuneCountInString(w) >= g.Config.MinLength && g.WordValidator.Validate(w)
+	}
+	return b.Report(validWord)
+}
+
+// handleGameHint sends the player the positions to move some unused tiles to in order to form a new word.
+// The tiles are not moved, so the player can choose to use the hint or not.
+func (g *Game) handleGameHint(ctx context.Context, m message.Message, send messageSender) error {
+	switch {
+	case g.status != game.InProgress:
+		return gameWarningNotInProgress
+	case !g.Config.Hints:
+		return gameWarning{code: message.CodeHintsNotAllowed, text: "hints are not allowed in this game"}
+	case g.players[m.PlayerName].Forfeited:
+		return gameWarningForfeited
+	}
+	if g.solver == nil {
+		solverCfg := board.SolverConfig{
+			MinLength:          g.Config.MinLength,
+			ProhibitDuplicates: g.Config.ProhibitDuplicates,
+		}
+		g.solver = solverCfg.NewSolver(g.WordValidator)
+	}
+	p := g.players[m.PlayerName]
+	positions := g.solver.Placement(*p.Board)
+	if len(positions) == 0 {
+		return gameWarning{code: message.CodeNoHint, text: "no hint found, try swapping a tile"}
+	}
+	info := "hint: move the highlighted tiles to form a word"
+	var details *message.Details
+	if g.Config.PenalizeHints && p.WinPoints > 2 {
+		p.WinPoints--
+		info = info + ", possible win points decremented"
+		details = &message.Details{Penalty: 1}
+	}
+	m2 := message.Message{
+		Type:       message.Hint,
+		PlayerName: m.PlayerName,
+		Info:       info,
+		Details:    details,
+		Game: &game.Info{
+			Board: board.New(nil, positions),
+		},
+	}
+	send(m2)
+	return nil
 }
 
 // handleBoardRefresh sends the player's board back to the player.
@@ -581,10 +1863,41 @@ func (g *Game) handleBoardRefresh(ctx context.Context, m message.Message, send m
 	if err != nil {
 		return err
 	}
+	boardConfig := g.players[m.PlayerName].Board.Config
+	g.record(replay.Event{
+		Type:        replay.Resize,
+		PlayerName:  m.PlayerName,
+		BoardConfig: &boardConfig,
+	})
 	send(*m2)
+	g.sendTeamBoard(*m2, send)
 	return nil
 }
 
+// handleGameReplay sends the log of the events of the game to the player if the game is finished.
+func (g *Game) handleGameReplay(ctx context.Context, m message.Message, send messageSender) error {
+	if g.status != game.Finished {
+		return gameWarning{code: message.CodeReplayUnavailable, text: "replays are only available after the game is finished"}
+	}
+	r := replay.Replay{
+		GameID: g.id,
+		Events: g.events,
+	}
+	m2 := message.Message{
+		Type:       message.GameReplay,
+		PlayerName: m.PlayerName,
+		Replay:     &r,
+	}
+	send(m2)
+	return nil
+}
+
+// record appends the event to the log of the game.
+func (g *Game) record(e replay.Event) {
+	e.Time = g.TimeFunc()
+	g.events = append(g.events, e)
+}
+
 // handleGameChat sends a chat message from a player to everyone in the game.
 func (g *Game) handleGameChat(ctx context.Context, m message.Message, send messageSender) error {
 	info := fmt.Sprintf("%v : %v", m.PlayerName, m.Info)
@@ -596,21 +1909,270 @@ func (g *Game) handleGameChat(ctx context.Context, m message.Message, send messa
 		}
 		send(m2)
 	}
+	if g.hasSpectators {
+		m2 := message.Message{
+			Type: message.GameChat,
+			Info: info,
+		}
+		send(m2)
+	}
 	return nil
 }
 
+// handleGameSpectate sends a snapshot of the game to a player who wants to watch it without playing in it.
+func (g *Game) handleGameSpectate(ctx context.Context, m message.Message, send messageSender) error {
+	_, playerInGame := g.players[m.PlayerName]
+	var err error
+	switch {
+	case playerInGame:
+		err = gameWarning{code: message.CodeCannotSpectate, text: "cannot spectate a game while playing in it"}
+	case !g.hasInviteCode(m):
+		err = gameWarning{code: message.CodeWrongInviteCode, text: "the invite code of the private game is required to spectate it"}
+	case g.status != game.InProgress:
+		err = gameWarning{code: message.CodeCannotSpectate, text: "can only spectate games that are in progress"}
+	}
+	if err != nil {
+		// stop the socket from spectating the game
+		m2 := message.Message{
+			Type:       message.LeaveGame,
+			PlayerName: m.PlayerName,
+			Info:       err.Error(),
+			Addr:       m.Addr,
+		}
+		send(m2)
+		return err
+	}
+	i, err := g.spectatorInfo()
+	if err != nil {
+		return err
+	}
+	g.hasSpectators = true
+	m2 := message.Message{
+		Type:       message.SpectateGame,
+		PlayerName: m.PlayerName,
+		Info:       "spectating game",
+		Game:       i,
+		Addr:       m.Addr,
+	}
+	send(m2)
+	return nil
+}
+
+// updateSpectators sends a snapshot of the game to all players who are watching it.
+// Errors are logged because they do not affect the players in the game.
+func (g Game) updateSpectators(send messageSender) {
+	if !g.hasSpectators {
+		return
+	}
+	i, err := g.spectatorInfo()
+	if err != nil {
+		g.log.Printf("creating snapshot of game %v for spectators: %v", g.id, err)
+		return
+	}
+	m := message.Message{
+		Type: message.SpectateGame,
+		Game: i,
+	}
+	send(m)
+}
+
+// spectatorInfo creates the info about the game that is shown to spectators.
+func (g Game) spectatorInfo() (*game.Info, error) {
+	playerBoards, err := g.spectatorBoards()
+	if err != nil {
+		return nil, err
+	}
+	i := game.Info{
+		Status:           g.status,
+		TilesLeft:        len(g.unusedTiles),
+		Players:          g.playerNames(),
+		PlayerBoards:     playerBoards,
+		PlayerPoints:     g.playerPoints(),
+		SecondsLeft:      g.secondsLeft(),
+		ForfeitedPlayers: g.forfeitedPlayers(),
+		Teams:            g.teams(),
+	}
+	return &i, nil
+}
+
+// spectatorBoards gets the boards of the players to show to spectators.
+// While the game is in progress, the boards are recreated from the events that happened before the spectate delay.
+func (g Game) spectatorBoards() (map[string]board.Board, error) {
+	if g.SpectateDelay <= 0 || g.status != game.InProgress {
+		return g.playerFinalBoards(), nil
+	}
+	maxTime := g.TimeFunc() - int64(g.SpectateDelay/time.Second)
+	numEvents := sort.Search(len(g.events), func(i int) bool {
+		return g.events[i].Time > maxTime
+	})
+	r := replay.Replay{
+		GameID: g.id,
+		Events: g.events,
+	}
+	boards, err := r.Boards(numEvents)
+	if err != nil {
+		return nil, fmt.Errorf("recreating delayed boards: %w", err)
+	}
+	playerBoards := make(map[string]board.Board, len(g.players))
+	for pn := range g.players {
+		b, ok := boards[pn]
+		if !ok {
+			b = board.New(nil, nil)
+		}
+		playerBoards[string(pn)] = *b
+	}
+	return playerBoards, nil
+}
+
 // updateUserPoints updates the points for users in the game after a player has won.
-// The winning player gets their winpoints, which should be at least 2.  Other players in the game get a consolation point.
-func (g *Game) updateUserPoints(ctx context.Context, winningPlayerName player.Name) error {
-	userPoints := make(map[string]int, len(g.players))
-	for pn, p := range g.players {
-		points := 1
-		if pn == winningPlayerName {
-			points = p.WinPoints
+// The points each player gets are determined by the scoring of the game.  The points are returned, keyed by player name.
+func (g *Game) updateUserPoints(ctx context.Context, winningPlayerName player.Name) (map[string]int, error) {
+	scorer, err := g.Config.Config.Scoring.Scorer()
+	if err != nil {
+		return nil, err
+	}
+	userPoints := g.score(scorer, winningPlayerName)
+	botlessUserPoints := make(map[string]int, len(userPoints))
+	for pn, points := range userPoints {
+		if !player.Name(pn).IsBot() { // bots are not users
+			botlessUserPoints[pn] = points
 		}
-		userPoints[string(pn)] = points
 	}
-	return g.userDao.UpdatePointsIncrement(ctx, userPoints)
+	if err := g.userDao.UpdatePointsIncrement(ctx, botlessUserPoints); err != nil {
+		return userPoints, err
+	}
+	for pn := range g.userPoints {
+		g.userPoints[pn] += userPoints[string(pn)]
+	}
+	return userPoints, nil
+}
+
+// score determines the points each player gets, keyed by player name.
+// When the game is played in teams, each team is scored as its first player and the points of the team are split between its players.
+// Points that cannot be split evenly go to the first players on the team.
+func (g Game) score(scorer game.Scorer, winningPlayerName player.Name) map[string]int {
+	playerResults := g.playerResults(winningPlayerName)
+	if g.TeamSize < 2 {
+		return scorer.Score(playerResults)
+	}
+	teamResults := make([]game.PlayerResult, 0, len(playerResults))
+	teams := make(map[string][]player.Name, len(playerResults))
+	winners := g.teammates(winningPlayerName)
+	for _, pr := range playerResults {
+		teammates := g.teammates(player.Name(pr.Name))
+		if _, ok := teams[string(teammates[0])]; ok {
+			continue
+		}
+		if teammates[0] == winners[0] {
+			pr.Winner = true
+			pr.WinPoints = g.players[winningPlayerName].WinPoints
+		}
+		teamResults = append(teamResults, pr) // the results are sorted, so the first teammate is scored
+		teams[pr.Name] = teammates
+	}
+	teamPoints := scorer.Score(teamResults)
+	points := make(map[string]int, len(playerResults))
+	for teamName, teammates := range teams {
+		n := len(teammates)
+		for i, pn := range teammates {
+			points[string(pn)] = teamPoints[teamName] / n
+			if i < teamPoints[teamName]%n {
+				points[string(pn)]++
+			}
+		}
+	}
+	return points
+}
+
+// playerResults creates the final states of the players to score, sorted by player name.
+// Only the words on the player's boards that are valid are included.
+func (g Game) playerResults(winningPlayerName player.Name) []game.PlayerResult {
+	playerResults := make([]game.PlayerResult, 0, len(g.players))
+	for _, pn := range g.playerNames() {
+		p := g.players[player.Name(pn)]
+		pr := game.PlayerResult{
+			Name:      pn,
+			Winner:    player.Name(pn) == winningPlayerName,
+			WinPoints: p.WinPoints,
+		}
+		if p.Board != nil {
+			for _, tp := range p.Board.UsedTiles {
+				pr.Letters = append(pr.Letters, tp.Tile.Ch)
+			}
+			for _, w := range p.Board.UsedTileWords() {
+				if g.WordValidator.Validate(w) {
+					pr.Words = append(pr.Words, w)
+				}
+			}
+		}
+		playerResults = append(playerResults, pr)
+	}
+	return playerResults
+}
+
+// readUserPoints gets the points of the user for the player if they are not known.
+// Errors are logged because the points are only shown to other players.  Bots do not have points.
+func (g *Game) readUserPoints(ctx context.Context, pn player.Name) {
+	if _, ok := g.userPoints[pn]; ok || pn.IsBot() {
+		return
+	}
+	points, err := g.userDao.ReadPoints(ctx, string(pn))
+	if err != nil {
+		g.log.Printf("reading points for %v: %v", pn, err)
+		return
+	}
+	g.userPoints[pn] = points
+}
+
+// playerPoints creates a map of the known user points of the players.
+// Nil is returned if the points of no players are known.
+func (g Game) playerPoints() map[string]int {
+	if len(g.userPoints) == 0 {
+		return nil
+	}
+	playerPoints := make(map[string]int, len(g.userPoints))
+	for pn, points := range g.userPoints {
+		playerPoints[string(pn)] = points
+	}
+	return playerPoints
+}
+
+// result creates the outcome of the game after the winning player has finished it with the used words.
+// The duration is measured from when the game was started, or when it was created if no start was recorded.
+func (g Game) result(winningPlayerName player.Name, usedWords []string) result.Result {
+	var longestWord string
+	for _, w := range usedWords {
+		if utf8.RuneCountInString(w) > utf8.RuneCountInString(longestWord) {
+			longestWord = w
+		}
+	}
+	startedAt := g.startedAt()
+	finishedAt := g.TimeFunc()
+	r := result.Result{
+		GameID:      g.id,
+		Config:      g.Config.Config,
+		Players:     g.playerNames(),
+		Winner:      string(winningPlayerName),
+		FinishOrder: g.finishedPlayerNames(),
+		WinPoints:   g.players[winningPlayerName].WinPoints,
+		WordCount:   len(usedWords),
+		LongestWord: longestWord,
+		FinishedAt:  finishedAt,
+		DurationSec: finishedAt - startedAt,
+	}
+	return r
+}
+
+// finishedPlayerNames returns the names of the players who tried to finish the game, in the order their requests were received.
+func (g Game) finishedPlayerNames() []string {
+	if len(g.finishOrder) == 0 {
+		return nil
+	}
+	finishedPlayerNames := make([]string, len(g.finishOrder))
+	for i, pn := range g.finishOrder {
+		finishedPlayerNames[i] = string(pn)
+	}
+	return finishedPlayerNames
 }
 
 // playerNames returns an array of the player name strings.
@@ -626,11 +2188,17 @@ func (g Game) playerNames() []string {
 // handleInfoChanged sends the game's info in a message.
 func (g Game) handleInfoChanged(send messageSender) {
 	i := game.Info{
-		ID:        g.id,
-		Status:    g.status,
-		Players:   g.playerNames(),
-		CreatedAt: g.createdAt,
-		Capacity:  g.MaxPlayers,
+		ID:               g.id,
+		Status:           g.status,
+		Players:          g.playerNames(),
+		PlayerPoints:     g.playerPoints(),
+		CreatedAt:        g.createdAt,
+		Capacity:         g.MaxPlayers,
+		Private:          g.Private(),
+		Host:             string(g.host),
+		ReadyPlayers:     g.readyPlayers(),
+		ForfeitedPlayers: g.forfeitedPlayers(),
+		Teams:            g.teams(),
 	}
 	m := message.Message{
 		Type: message.GameInfos,
@@ -643,7 +2211,7 @@ func (g Game) handleInfoChanged(send messageSender) {
 func (g *Game) resizeBoard(m message.Message) (*message.Message, error) {
 	p := g.players[m.PlayerName]
 	b := p.Board
-	rr, err := b.Resize(m.Game.Board.Config)
+	rr, err := b.Resize(g.boardConfig(m.PlayerName, m.Game.Board.Config))
 	if err != nil {
 		return nil, err
 	}
@@ -652,17 +2220,30 @@ func (g *Game) resizeBoard(m message.Message) (*message.Message, error) {
 		Type:       m.Type,
 		PlayerName: m.PlayerName,
 		Game: &game.Info{
-			Board:     board.New(rr.Tiles, rr.TilePositions),
-			TilesLeft: len(g.unusedTiles),
-			Status:    g.status,
-			Players:   g.playerNames(),
-			ID:        g.id,
-			Config:    &g.Config.Config,
+			Board:            board.New(rr.Tiles, rr.TilePositions),
+			TilesLeft:        len(g.unusedTiles),
+			Status:           g.status,
+			Players:          g.playerNames(),
+			PlayerPoints:     g.playerPoints(),
+			SecondsLeft:      g.secondsLeft(),
+			ID:               g.id,
+			Config:           &g.Config.Config,
+			Host:             string(g.host),
+			ReadyPlayers:     g.readyPlayers(),
+			ForfeitedPlayers: g.forfeitedPlayers(),
+			Teams:            g.teams(),
+			BananasRace:      g.status == game.InProgress && g.bananasRace(),
 		},
 		Addr: m.Addr,
 	}
-	if g.status == game.Finished {
+	m2.Game.Board.Revision = b.Revision
+	switch g.status {
+	case game.Finished:
+		m2.Game.FinalBoards = g.playerFinalBoards()
+	case game.Challenging:
 		m2.Game.FinalBoards = g.playerFinalBoards()
+		m2.Game.SecondsLeft = g.challengeSecondsLeft()
+		m2.Game.Claimant = string(g.challenge.claimant)
 	}
 	return &m2, nil
 }
@@ -11,10 +11,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/jacobpatterson1549/selene-bananas/db/result"
+	"github.com/jacobpatterson1549/selene-bananas/db/state"
 	"github.com/jacobpatterson1549/selene-bananas/game"
 	"github.com/jacobpatterson1549/selene-bananas/game/board"
 	"github.com/jacobpatterson1549/selene-bananas/game/message"
 	"github.com/jacobpatterson1549/selene-bananas/game/player"
+	"github.com/jacobpatterson1549/selene-bananas/game/replay"
 	"github.com/jacobpatterson1549/selene-bananas/game/tile"
 	playerController "github.com/jacobpatterson1549/selene-bananas/server/game/player"
 	"github.com/jacobpatterson1549/selene-bananas/server/log"
@@ -41,7 +44,7 @@ func TestNewGame(t *testing.T) {
 			Config: Config{
 				TimeFunc:               timeFunc,
 				MaxPlayers:             4,
-				NumNewTiles:            16,
+				NumNewTiles:            4,
 				TileLetters:            "INVALID WORDS :(",
 				IdlePeriod:             1 * time.Hour,
 				ShuffleUnusedTilesFunc: shuffleUnusedTilesFunc,
@@ -52,7 +55,7 @@ func TestNewGame(t *testing.T) {
 			Config: Config{
 				TimeFunc:               timeFunc,
 				MaxPlayers:             4,
-				NumNewTiles:            16,
+				NumNewTiles:            4,
 				TileLetters:            "VALIDWORDSAREHAPPY",
 				IdlePeriod:             1 * time.Hour,
 				ShuffleUnusedTilesFunc: shuffleUnusedTilesFunc,
@@ -66,7 +69,9 @@ func TestNewGame(t *testing.T) {
 		id := game.ID(7)
 		var WordValidator mockWordValidator
 		var userDao mockUserDao
-		got, err := test.Config.NewGame(log, id, WordValidator, userDao)
+		var stateStore mockStateStore
+		var resultStore mockResultStore
+		got, err := test.Config.NewGame(log, id, WordValidator, userDao, stateStore, resultStore)
 		switch {
 		case !test.wantOk:
 			if err == nil {
@@ -81,6 +86,8 @@ func TestNewGame(t *testing.T) {
 			len(got.players) != 0,
 			!reflect.DeepEqual(WordValidator, got.WordValidator),
 			!reflect.DeepEqual(userDao, got.userDao),
+			!reflect.DeepEqual(stateStore, got.stateStore),
+			!reflect.DeepEqual(resultStore, got.resultStore),
 			!reflect.DeepEqual(test.Config.TileLetters, got.Config.TileLetters):
 			t.Errorf("Test %v: fields not set", i)
 		}
@@ -101,12 +108,16 @@ func TestValidateConfig(t *testing.T) {
 		}
 		var wordValidator mockWordValidator
 		var userDao mockUserDao
+		var stateStore mockStateStore
+		var resultStore mockResultStore
 		errCheckTests := []struct {
 			Config
 			log.Logger
 			game.ID
 			WordValidator
 			UserDao
+			StateStore
+			ResultStore
 			wantOk bool
 		}{
 			{}, // no log
@@ -122,11 +133,26 @@ func TestValidateConfig(t *testing.T) {
 				ID:            1,
 				WordValidator: wordValidator,
 			},
+			{ // no state store
+				Logger:        testLog,
+				ID:            1,
+				WordValidator: wordValidator,
+				UserDao:       userDao,
+			},
+			{ // no result store
+				Logger:        testLog,
+				ID:            1,
+				WordValidator: wordValidator,
+				UserDao:       userDao,
+				StateStore:    stateStore,
+			},
 			{ // no time func
 				Logger:        testLog,
 				ID:            1,
 				WordValidator: wordValidator,
 				UserDao:       userDao,
+				StateStore:    stateStore,
+				ResultStore:   resultStore,
 			},
 			{ // low maxPlayers
 				Config: Config{
@@ -136,6 +162,8 @@ func TestValidateConfig(t *testing.T) {
 				ID:            1,
 				WordValidator: wordValidator,
 				UserDao:       userDao,
+				StateStore:    stateStore,
+				ResultStore:   resultStore,
 			},
 			{ // low num newTiles
 				Config: Config{
@@ -146,6 +174,8 @@ func TestValidateConfig(t *testing.T) {
 				ID:            1,
 				WordValidator: wordValidator,
 				UserDao:       userDao,
+				StateStore:    stateStore,
+				ResultStore:   resultStore,
 			},
 			{ // low idle period
 				Config: Config{
@@ -157,6 +187,8 @@ func TestValidateConfig(t *testing.T) {
 				ID:            1,
 				WordValidator: wordValidator,
 				UserDao:       userDao,
+				StateStore:    stateStore,
+				ResultStore:   resultStore,
 			},
 			{ // missing shuffle tiles func
 				Config: Config{
@@ -169,6 +201,8 @@ func TestValidateConfig(t *testing.T) {
 				ID:            1,
 				WordValidator: wordValidator,
 				UserDao:       userDao,
+				StateStore:    stateStore,
+				ResultStore:   resultStore,
 			},
 			{ // missing shuffle players func
 				Config: Config{
@@ -182,6 +216,8 @@ func TestValidateConfig(t *testing.T) {
 				ID:            1,
 				WordValidator: wordValidator,
 				UserDao:       userDao,
+				StateStore:    stateStore,
+				ResultStore:   resultStore,
 			},
 			{ // too few tiles for one player to start
 				Config: Config{
@@ -197,12 +233,14 @@ func TestValidateConfig(t *testing.T) {
 				ID:            1,
 				WordValidator: wordValidator,
 				UserDao:       userDao,
+				StateStore:    stateStore,
+				ResultStore:   resultStore,
 			},
 			{
 				Config: Config{
 					TimeFunc:               timeFunc,
 					MaxPlayers:             4,
-					NumNewTiles:            16,
+					NumNewTiles:            9,
 					TileLetters:            "HOWMANYWORDSCANYOUMAKEWITHTHESELETTERS",
 					IdlePeriod:             1 * time.Hour,
 					ShuffleUnusedTilesFunc: shuffleUnusedTilesFunc,
@@ -212,874 +250,3006 @@ func TestValidateConfig(t *testing.T) {
 				ID:            1,
 				WordValidator: wordValidator,
 				UserDao:       userDao,
+				StateStore:    stateStore,
+				ResultStore:   resultStore,
 				wantOk:        true,
 			},
-		}
-		for i, test := range errCheckTests {
-			err := test.Config.validate(test.Logger, test.ID, test.WordValidator, test.UserDao)
-			switch {
-			case !test.wantOk:
-				if err == nil {
-					t.Errorf("Test %v: wanted error validating config", i)
-				}
-			case err != nil:
-				t.Errorf("Test %v: unwanted error validating config: %v", i, err)
-			}
-		}
-	})
-	t.Run("TestSetTileLetters", func(t *testing.T) {
-		setTileLettersTests := []struct {
-			Config
-			wantTileLetters string
-		}{
-			{
-				wantTileLetters: defaultTileLetters,
-			},
-			{
+			{ // unknown language
 				Config: Config{
-					TileLetters: "ABC",
+					TimeFunc:               timeFunc,
+					MaxPlayers:             4,
+					NumNewTiles:            9,
+					TileLetters:            "HOWMANYWORDSCANYOUMAKEWITHTHESELETTERS",
+					IdlePeriod:             1 * time.Hour,
+					ShuffleUnusedTilesFunc: shuffleUnusedTilesFunc,
+					ShufflePlayersFunc:     shufflePlayersFunc,
+					Config: game.Config{
+						Language: "xx",
+					},
 				},
-				wantTileLetters: "ABC",
-			},
-		}
-		for i, test := range setTileLettersTests {
-			log := logtest.DiscardLogger
-			wordValidator := mockWordValidator(func(word string) bool { return false })
-			userDao := new(mockUserDao)
-			test.Config.validate(log, 1, wordValidator, userDao) // Ignore the error.  This test doesn't care about it.
-			got := test.Config
-			if test.wantTileLetters != got.TileLetters {
-				t.Errorf("Test %v: not equal:\nwanted: %v\ngot:    %v", i, test.wantTileLetters, got.TileLetters)
-			}
-		}
-	})
-}
-
-func TestRun(t *testing.T) {
-	ctx := context.Background()
-	ctx, cancelFunc := context.WithCancel(ctx)
-	var wg sync.WaitGroup
-	in := make(chan message.Message)
-	out := make(chan message.Message, 1)
-	g := Game{
-		players: map[player.Name]*playerController.Player{
-			"selene": nil,
-		},
-		Config: Config{
-			IdlePeriod: 1 * time.Hour,
-		},
-	}
-	g.Run(ctx, &wg, in, out)
-	m := message.Message{
-		Type:       message.GameChat,
-		PlayerName: "selene",
-	}
-	in <- m
-	m2 := <-out
-	cancelFunc()
-	wg.Wait()
-	if m2.Type != message.GameChat {
-		t.Errorf("wanted game to relay simple chat message back to player")
-	}
-}
-
-func TestRunSync(t *testing.T) {
-	t.Run("TestValidMessageHandler", func(t *testing.T) {
-		validMessageHandlerTests := []struct {
-			message.Type
-			wantSocketError bool
-		}{
-			{
-				Type: message.JoinGame,
+				Logger:        testLog,
+				ID:            1,
+				WordValidator: wordValidator,
+				UserDao:       userDao,
+				StateStore:    stateStore,
+				ResultStore:   resultStore,
 			},
-			{
-				Type: message.DeleteGame,
+			{ // not enough multi-byte tiles for a player
+				Config: Config{
+					TimeFunc:               timeFunc,
+					MaxPlayers:             4,
+					NumNewTiles:            4,
+					TileLetters:            "ÑÑÑ",
+					IdlePeriod:             1 * time.Hour,
+					ShuffleUnusedTilesFunc: shuffleUnusedTilesFunc,
+					ShufflePlayersFunc:     shufflePlayersFunc,
+				},
+				Logger:        testLog,
+				ID:            1,
+				WordValidator: wordValidator,
+				UserDao:       userDao,
+				StateStore:    stateStore,
+				ResultStore:   resultStore,
 			},
-			{
-				Type: message.ChangeGameStatus,
+			{ // negative time limit
+				Config: Config{
+					TimeFunc:               timeFunc,
+					MaxPlayers:             4,
+					NumNewTiles:            9,
+					TileLetters:            "HOWMANYWORDSCANYOUMAKEWITHTHESELETTERS",
+					IdlePeriod:             1 * time.Hour,
+					ShuffleUnusedTilesFunc: shuffleUnusedTilesFunc,
+					ShufflePlayersFunc:     shufflePlayersFunc,
+					Config: game.Config{
+						TimeLimitSec: -1,
+					},
+				},
+				Logger:        testLog,
+				ID:            1,
+				WordValidator: wordValidator,
+				UserDao:       userDao,
+				StateStore:    stateStore,
+				ResultStore:   resultStore,
 			},
-			{
-				Type: message.SnagGameTile,
+			{ // timed game without clock period
+				Config: Config{
+					TimeFunc:               timeFunc,
+					MaxPlayers:             4,
+					NumNewTiles:            9,
+					TileLetters:            "HOWMANYWORDSCANYOUMAKEWITHTHESELETTERS",
+					IdlePeriod:             1 * time.Hour,
+					ShuffleUnusedTilesFunc: shuffleUnusedTilesFunc,
+					ShufflePlayersFunc:     shufflePlayersFunc,
+					Config: game.Config{
+						TimeLimitSec: 60,
+					},
+				},
+				Logger:        testLog,
+				ID:            1,
+				WordValidator: wordValidator,
+				UserDao:       userDao,
+				StateStore:    stateStore,
+				ResultStore:   resultStore,
 			},
-			{
-				Type: message.SwapGameTile,
+			{ // long invite code
+				Config: Config{
+					TimeFunc:               timeFunc,
+					MaxPlayers:             4,
+					NumNewTiles:            9,
+					TileLetters:            "HOWMANYWORDSCANYOUMAKEWITHTHESELETTERS",
+					IdlePeriod:             1 * time.Hour,
+					ShuffleUnusedTilesFunc: shuffleUnusedTilesFunc,
+					ShufflePlayersFunc:     shufflePlayersFunc,
+					Config: game.Config{
+						InviteCode: "bananas-bananas-bananas-bananas-bananas",
+					},
+				},
+				Logger:        testLog,
+				ID:            1,
+				WordValidator: wordValidator,
+				UserDao:       userDao,
+				StateStore:    stateStore,
+				ResultStore:   resultStore,
 			},
-			{
-				Type: message.MoveGameTile,
+			{ // timed game
+				Config: Config{
+					TimeFunc:               timeFunc,
+					MaxPlayers:             4,
+					NumNewTiles:            9,
+					TileLetters:            "HOWMANYWORDSCANYOUMAKEWITHTHESELETTERS",
+					IdlePeriod:             1 * time.Hour,
+					ShuffleUnusedTilesFunc: shuffleUnusedTilesFunc,
+					ShufflePlayersFunc:     shufflePlayersFunc,
+					ClockPeriod:            1 * time.Second,
+					Config: game.Config{
+						TimeLimitSec: 60,
+					},
+				},
+				Logger:        testLog,
+				ID:            1,
+				WordValidator: wordValidator,
+				UserDao:       userDao,
+				StateStore:    stateStore,
+				ResultStore:   resultStore,
+				wantOk:        true,
 			},
-			{
-				Type: message.GameChat,
+			{ // unknown scoring
+				Config: Config{
+					TimeFunc:               timeFunc,
+					MaxPlayers:             4,
+					NumNewTiles:            9,
+					TileLetters:            "HOWMANYWORDSCANYOUMAKEWITHTHESELETTERS",
+					IdlePeriod:             1 * time.Hour,
+					ShuffleUnusedTilesFunc: shuffleUnusedTilesFunc,
+					ShufflePlayersFunc:     shufflePlayersFunc,
+					Config: game.Config{
+						Scoring: -1,
+					},
+				},
+				Logger:        testLog,
+				ID:            1,
+				WordValidator: wordValidator,
+				UserDao:       userDao,
+				StateStore:    stateStore,
+				ResultStore:   resultStore,
 			},
-			{
-				Type: message.RefreshGameBoard,
+			{ // negative team size
+				Config: Config{
+					TimeFunc:               timeFunc,
+					MaxPlayers:             4,
+					NumNewTiles:            9,
+					TileLetters:            "HOWMANYWORDSCANYOUMAKEWITHTHESELETTERS",
+					IdlePeriod:             1 * time.Hour,
+					ShuffleUnusedTilesFunc: shuffleUnusedTilesFunc,
+					ShufflePlayersFunc:     shufflePlayersFunc,
+					Config: game.Config{
+						TeamSize: -1,
+					},
+				},
+				Logger:        testLog,
+				ID:            1,
+				WordValidator: wordValidator,
+				UserDao:       userDao,
+				StateStore:    stateStore,
+				ResultStore:   resultStore,
 			},
-			{
-				Type:            message.SocketHTTPPing,
-				wantSocketError: true,
+			{ // team size larger than max players
+				Config: Config{
+					TimeFunc:               timeFunc,
+					MaxPlayers:             4,
+					NumNewTiles:            9,
+					TileLetters:            "HOWMANYWORDSCANYOUMAKEWITHTHESELETTERS",
+					IdlePeriod:             1 * time.Hour,
+					ShuffleUnusedTilesFunc: shuffleUnusedTilesFunc,
+					ShufflePlayersFunc:     shufflePlayersFunc,
+					Config: game.Config{
+						TeamSize: 5,
+					},
+				},
+				Logger:        testLog,
+				ID:            1,
+				WordValidator: wordValidator,
+				UserDao:       userDao,
+				StateStore:    stateStore,
+				ResultStore:   resultStore,
 			},
-		}
-		for i, test := range validMessageHandlerTests {
-			m := message.Message{
-				Type:       test.Type,
-				PlayerName: "selene",
-				Game: &game.Info{
-					Status: game.Finished,
-					Board:  new(board.Board),
+			{ // teams of two
+				Config: Config{
+					TimeFunc:               timeFunc,
+					MaxPlayers:             4,
+					NumNewTiles:            9,
+					TileLetters:            "HOWMANYWORDSCANYOUMAKEWITHTHESELETTERS",
+					IdlePeriod:             1 * time.Hour,
+					ShuffleUnusedTilesFunc: shuffleUnusedTilesFunc,
+					ShufflePlayersFunc:     shufflePlayersFunc,
+					Config: game.Config{
+						TeamSize: 2,
+					},
 				},
-			}
-			g := Game{
-				status: 0, // this should cause a gameWarning error
-				players: map[player.Name]*playerController.Player{
-					"selene": {
-						Board: new(board.Board),
+				Logger:        testLog,
+				ID:            1,
+				WordValidator: wordValidator,
+				UserDao:       userDao,
+				StateStore:    stateStore,
+				ResultStore:   resultStore,
+				wantOk:        true,
+			},
+			{ // unknown tile bag
+				Config: Config{
+					TimeFunc:               timeFunc,
+					MaxPlayers:             4,
+					NumNewTiles:            9,
+					TileLetters:            "HOWMANYWORDSCANYOUMAKEWITHTHESELETTERS",
+					IdlePeriod:             1 * time.Hour,
+					ShuffleUnusedTilesFunc: shuffleUnusedTilesFunc,
+					ShufflePlayersFunc:     shufflePlayersFunc,
+					Config: game.Config{
+						TileBag: -1,
 					},
 				},
-			}
-			ctx := context.Background()
-			ctx, cancelFunc := context.WithCancel(ctx)
-			var wg sync.WaitGroup
-			in := make(chan message.Message, 1)  // deleteGame will cause the run to stop before the second message is passed
-			out := make(chan message.Message, 2) // the second message might be handled, for an unknown message type
+				Logger:        testLog,
+				ID:            1,
+				WordValidator: wordValidator,
+				UserDao:       userDao,
+				StateStore:    stateStore,
+				ResultStore:   resultStore,
+			},
+			{ // not enough tiles in banana split tile bag for max players to start with the number of new tiles of the game
+				Config: Config{
+					TimeFunc:               timeFunc,
+					MaxPlayers:             4,
+					NumNewTiles:            9,
+					TileLetters:            "HOWMANYWORDSCANYOUMAKEWITHTHESELETTERS",
+					IdlePeriod:             1 * time.Hour,
+					ShuffleUnusedTilesFunc: shuffleUnusedTilesFunc,
+					ShufflePlayersFunc:     shufflePlayersFunc,
+					Config: game.Config{
+						TileBag:     game.BananaSplitTileBag,
+						NumNewTiles: 9,
+					},
+				},
+				Logger:        testLog,
+				ID:            1,
+				WordValidator: wordValidator,
+				UserDao:       userDao,
+				StateStore:    stateStore,
+				ResultStore:   resultStore,
+			},
+			{ // banana split tile bag with lowered default number of new tiles
+				Config: Config{
+					TimeFunc:               timeFunc,
+					MaxPlayers:             4,
+					NumNewTiles:            9,
+					TileLetters:            "HOWMANYWORDSCANYOUMAKEWITHTHESELETTERS",
+					IdlePeriod:             1 * time.Hour,
+					ShuffleUnusedTilesFunc: shuffleUnusedTilesFunc,
+					ShufflePlayersFunc:     shufflePlayersFunc,
+					Config: game.Config{
+						TileBag: game.BananaSplitTileBag,
+					},
+				},
+				Logger:        testLog,
+				ID:            1,
+				WordValidator: wordValidator,
+				UserDao:       userDao,
+				StateStore:    stateStore,
+				ResultStore:   resultStore,
+				wantOk:        true,
+			},
+			{ // enough tiles for teams that share boards
+				Config: Config{
+					TimeFunc:               timeFunc,
+					MaxPlayers:             6,
+					NumNewTiles:            9,
+					TileLetters:            "HOWMANYWORDSCANYOUMAKEWITHTHESELETTERS",
+					IdlePeriod:             1 * time.Hour,
+					ShuffleUnusedTilesFunc: shuffleUnusedTilesFunc,
+					ShufflePlayersFunc:     shufflePlayersFunc,
+					Config: game.Config{
+						NumNewTiles: 9,
+						TeamSize:    3,
+					},
+				},
+				Logger:        testLog,
+				ID:            1,
+				WordValidator: wordValidator,
+				UserDao:       userDao,
+				StateStore:    stateStore,
+				ResultStore:   resultStore,
+				wantOk:        true,
+			},
+			{ // not enough tiles for players without teams
+				Config: Config{
+					TimeFunc:               timeFunc,
+					MaxPlayers:             6,
+					NumNewTiles:            9,
+					TileLetters:            "HOWMANYWORDSCANYOUMAKEWITHTHESELETTERS",
+					IdlePeriod:             1 * time.Hour,
+					ShuffleUnusedTilesFunc: shuffleUnusedTilesFunc,
+					ShufflePlayersFunc:     shufflePlayersFunc,
+					Config: game.Config{
+						NumNewTiles: 9,
+					},
+				},
+				Logger:        testLog,
+				ID:            1,
+				WordValidator: wordValidator,
+				UserDao:       userDao,
+				StateStore:    stateStore,
+				ResultStore:   resultStore,
+			},
+			{ // custom tile bag
+				Config: Config{
+					TimeFunc:               timeFunc,
+					MaxPlayers:             4,
+					NumNewTiles:            9,
+					TileLetters:            "HOWMANYWORDSCANYOUMAKEWITHTHESELETTERS",
+					IdlePeriod:             1 * time.Hour,
+					ShuffleUnusedTilesFunc: shuffleUnusedTilesFunc,
+					ShufflePlayersFunc:     shufflePlayersFunc,
+					Config: game.Config{
+						TileBag:      game.CustomTileBag,
+						LetterCounts: map[string]int{"A": 20, "B": 20},
+					},
+				},
+				Logger:        testLog,
+				ID:            1,
+				WordValidator: wordValidator,
+				UserDao:       userDao,
+				StateStore:    stateStore,
+				ResultStore:   resultStore,
+				wantOk:        true,
+			},
+			{ // invalid custom tile bag
+				Config: Config{
+					TimeFunc:               timeFunc,
+					MaxPlayers:             4,
+					NumNewTiles:            9,
+					TileLetters:            "HOWMANYWORDSCANYOUMAKEWITHTHESELETTERS",
+					IdlePeriod:             1 * time.Hour,
+					ShuffleUnusedTilesFunc: shuffleUnusedTilesFunc,
+					ShufflePlayersFunc:     shufflePlayersFunc,
+					Config: game.Config{
+						TileBag:      game.CustomTileBag,
+						LetterCounts: map[string]int{"a": 40},
+					},
+				},
+				Logger:        testLog,
+				ID:            1,
+				WordValidator: wordValidator,
+				UserDao:       userDao,
+				StateStore:    stateStore,
+				ResultStore:   resultStore,
+			},
+			{ // starting hands too large for max players
+				Config: Config{
+					TimeFunc:               timeFunc,
+					MaxPlayers:             4,
+					NumNewTiles:            9,
+					TileLetters:            "HOWMANYWORDSCANYOUMAKEWITHTHESELETTERS",
+					IdlePeriod:             1 * time.Hour,
+					ShuffleUnusedTilesFunc: shuffleUnusedTilesFunc,
+					ShufflePlayersFunc:     shufflePlayersFunc,
+					Config: game.Config{
+						NumNewTiles: 10,
+					},
+				},
+				Logger:        testLog,
+				ID:            1,
+				WordValidator: wordValidator,
+				UserDao:       userDao,
+				StateStore:    stateStore,
+				ResultStore:   resultStore,
+			},
+			{ // smaller starting hands for double tile bag
+				Config: Config{
+					TimeFunc:               timeFunc,
+					MaxPlayers:             4,
+					NumNewTiles:            21,
+					TileLetters:            "HOWMANYWORDSCANYOUMAKEWITHTHESELETTERS",
+					IdlePeriod:             1 * time.Hour,
+					ShuffleUnusedTilesFunc: shuffleUnusedTilesFunc,
+					ShufflePlayersFunc:     shufflePlayersFunc,
+					Config: game.Config{
+						TileBag:     game.DoubleTileBag,
+						NumNewTiles: 19,
+					},
+				},
+				Logger:        testLog,
+				ID:            1,
+				WordValidator: wordValidator,
+				UserDao:       userDao,
+				StateStore:    stateStore,
+				ResultStore:   resultStore,
+				wantOk:        true,
+			},
+			{ // swap ratio too large
+				Config: Config{
+					TimeFunc:               timeFunc,
+					MaxPlayers:             4,
+					NumNewTiles:            9,
+					TileLetters:            "HOWMANYWORDSCANYOUMAKEWITHTHESELETTERS",
+					IdlePeriod:             1 * time.Hour,
+					ShuffleUnusedTilesFunc: shuffleUnusedTilesFunc,
+					ShufflePlayersFunc:     shufflePlayersFunc,
+					Config: game.Config{
+						SwapRatio: game.MaxSwapRatio + 1,
+					},
+				},
+				Logger:        testLog,
+				ID:            1,
+				WordValidator: wordValidator,
+				UserDao:       userDao,
+				StateStore:    stateStore,
+				ResultStore:   resultStore,
+			},
+			{ // negative swap limit
+				Config: Config{
+					TimeFunc:               timeFunc,
+					MaxPlayers:             4,
+					NumNewTiles:            9,
+					TileLetters:            "HOWMANYWORDSCANYOUMAKEWITHTHESELETTERS",
+					IdlePeriod:             1 * time.Hour,
+					ShuffleUnusedTilesFunc: shuffleUnusedTilesFunc,
+					ShufflePlayersFunc:     shufflePlayersFunc,
+					Config: game.Config{
+						SwapLimit: -1,
+					},
+				},
+				Logger:        testLog,
+				ID:            1,
+				WordValidator: wordValidator,
+				UserDao:       userDao,
+				StateStore:    stateStore,
+				ResultStore:   resultStore,
+			},
+			{ // negative swap cooldown
+				Config: Config{
+					TimeFunc:               timeFunc,
+					MaxPlayers:             4,
+					NumNewTiles:            9,
+					TileLetters:            "HOWMANYWORDSCANYOUMAKEWITHTHESELETTERS",
+					IdlePeriod:             1 * time.Hour,
+					ShuffleUnusedTilesFunc: shuffleUnusedTilesFunc,
+					ShufflePlayersFunc:     shufflePlayersFunc,
+					Config: game.Config{
+						SwapCooldownSec: -1,
+					},
+				},
+				Logger:        testLog,
+				ID:            1,
+				WordValidator: wordValidator,
+				UserDao:       userDao,
+				StateStore:    stateStore,
+				ResultStore:   resultStore,
+			},
+			{ // swap rules
+				Config: Config{
+					TimeFunc:               timeFunc,
+					MaxPlayers:             4,
+					NumNewTiles:            9,
+					TileLetters:            "HOWMANYWORDSCANYOUMAKEWITHTHESELETTERS",
+					IdlePeriod:             1 * time.Hour,
+					ShuffleUnusedTilesFunc: shuffleUnusedTilesFunc,
+					ShufflePlayersFunc:     shufflePlayersFunc,
+					Config: game.Config{
+						SwapRatio:       1,
+						SwapLimit:       3,
+						SwapCooldownSec: 30,
+						SwapUsedTiles:   true,
+					},
+				},
+				Logger:        testLog,
+				ID:            1,
+				WordValidator: wordValidator,
+				UserDao:       userDao,
+				StateStore:    stateStore,
+				ResultStore:   resultStore,
+				wantOk:        true,
+			},
+			{ // negative challenge time
+				Config: Config{
+					TimeFunc:               timeFunc,
+					MaxPlayers:             4,
+					NumNewTiles:            9,
+					TileLetters:            "HOWMANYWORDSCANYOUMAKEWITHTHESELETTERS",
+					IdlePeriod:             1 * time.Hour,
+					ShuffleUnusedTilesFunc: shuffleUnusedTilesFunc,
+					ShufflePlayersFunc:     shufflePlayersFunc,
+					Config: game.Config{
+						ChallengeSec: -1,
+					},
+				},
+				Logger:        testLog,
+				ID:            1,
+				WordValidator: wordValidator,
+				UserDao:       userDao,
+				StateStore:    stateStore,
+				ResultStore:   resultStore,
+			},
+			{ // challenges without clock period
+				Config: Config{
+					TimeFunc:               timeFunc,
+					MaxPlayers:             4,
+					NumNewTiles:            9,
+					TileLetters:            "HOWMANYWORDSCANYOUMAKEWITHTHESELETTERS",
+					IdlePeriod:             1 * time.Hour,
+					ShuffleUnusedTilesFunc: shuffleUnusedTilesFunc,
+					ShufflePlayersFunc:     shufflePlayersFunc,
+					Config: game.Config{
+						ChallengeSec: 30,
+					},
+				},
+				Logger:        testLog,
+				ID:            1,
+				WordValidator: wordValidator,
+				UserDao:       userDao,
+				StateStore:    stateStore,
+				ResultStore:   resultStore,
+			},
+			{ // challenges
+				Config: Config{
+					TimeFunc:               timeFunc,
+					MaxPlayers:             4,
+					NumNewTiles:            9,
+					TileLetters:            "HOWMANYWORDSCANYOUMAKEWITHTHESELETTERS",
+					IdlePeriod:             1 * time.Hour,
+					ClockPeriod:            1 * time.Second,
+					ShuffleUnusedTilesFunc: shuffleUnusedTilesFunc,
+					ShufflePlayersFunc:     shufflePlayersFunc,
+					Config: game.Config{
+						ChallengeSec: 30,
+					},
+				},
+				Logger:        testLog,
+				ID:            1,
+				WordValidator: wordValidator,
+				UserDao:       userDao,
+				StateStore:    stateStore,
+				ResultStore:   resultStore,
+				wantOk:        true,
+			},
+		}
+		for i, test := range errCheckTests {
+			err := test.Config.validate(test.Logger, test.ID, test.WordValidator, test.UserDao, test.StateStore, test.ResultStore)
+			switch {
+			case !test.wantOk:
+				if err == nil {
+					t.Errorf("Test %v: wanted error validating config", i)
+				}
+			case err != nil:
+				t.Errorf("Test %v: unwanted error validating config: %v", i, err)
+			}
+		}
+	})
+	t.Run("TestSetNumNewTiles", func(t *testing.T) {
+		setNumNewTilesTests := []struct {
+			serverNumNewTiles int
+			gameNumNewTiles   int
+			maxPlayers        int
+			teamSize          int
+			tileBag           game.TileBag
+			want              int
+		}{
+			{21, 0, 0, 0, game.ClassicTileBag, 21},
+			{21, -3, 0, 0, game.ClassicTileBag, 21},
+			{21, 11, 0, 0, game.ClassicTileBag, 11},
+			{21, 0, 6, 0, game.ClassicTileBag, 21},
+			{21, 0, 6, 0, game.BananaSplitTileBag, 12}, // lowered so each of the 6 boards can start with tiles from the 72 tiles
+			{21, 0, 6, 2, game.BananaSplitTileBag, 21}, // teammates share a board
+			{21, 11, 6, 0, game.BananaSplitTileBag, 11},
+		}
+		for i, test := range setNumNewTilesTests {
+			cfg := Config{
+				NumNewTiles: test.serverNumNewTiles,
+				MaxPlayers:  test.maxPlayers,
+				Config: game.Config{
+					NumNewTiles: test.gameNumNewTiles,
+					TeamSize:    test.teamSize,
+					TileBag:     test.tileBag,
+				},
+			}
+			log := logtest.DiscardLogger
+			wordValidator := mockWordValidator(func(word string) bool { return false })
+			cfg.validate(log, 1, wordValidator, new(mockUserDao), new(mockStateStore), new(mockResultStore)) // Ignore the error.  This test doesn't care about it.
+			if test.want != cfg.NumNewTiles {
+				t.Errorf("Test %v: number of new tiles not equal: wanted %v, got %v", i, test.want, cfg.NumNewTiles)
+			}
+		}
+	})
+	t.Run("TestSetTileLetters", func(t *testing.T) {
+		englishTileLetters, err := game.English.TileLetters()
+		if err != nil {
+			t.Fatalf("unwanted error getting English tile letters: %v", err)
+		}
+		spanishTileLetters, err := game.Spanish.TileLetters()
+		if err != nil {
+			t.Fatalf("unwanted error getting Spanish tile letters: %v", err)
+		}
+		setTileLettersTests := []struct {
+			Config
+			wantTileLetters string
+		}{
+			{
+				wantTileLetters: englishTileLetters,
+			},
+			{
+				Config: Config{
+					TileLetters: "ABC",
+				},
+				wantTileLetters: "ABC",
+			},
+			{
+				Config: Config{
+					Config: game.Config{
+						Language: game.Spanish,
+					},
+				},
+				wantTileLetters: spanishTileLetters,
+			},
+			{
+				Config: Config{
+					TileLetters: "ÄBC",
+					Config: game.Config{
+						Language: game.German,
+					},
+				},
+				wantTileLetters: "ÄBC",
+			},
+			{
+				Config: Config{
+					TileLetters: "AABBC",
+					Config: game.Config{
+						TileBag: game.BananaSplitTileBag,
+					},
+				},
+				wantTileLetters: "ABC",
+			},
+			{
+				Config: Config{
+					TileLetters: "ABC",
+					Config: game.Config{
+						TileBag:      game.CustomTileBag,
+						LetterCounts: map[string]int{"Z": 2, "Q": 1},
+					},
+				},
+				wantTileLetters: "QZZ",
+			},
+		}
+		for i, test := range setTileLettersTests {
+			log := logtest.DiscardLogger
+			wordValidator := mockWordValidator(func(word string) bool { return false })
+			userDao := new(mockUserDao)
+			stateStore := new(mockStateStore)
+			resultStore := new(mockResultStore)
+			test.Config.validate(log, 1, wordValidator, userDao, stateStore, resultStore) // Ignore the error.  This test doesn't care about it.
+			got := test.Config
+			if test.wantTileLetters != got.TileLetters {
+				t.Errorf("Test %v: not equal:\nwanted: %v\ngot:    %v", i, test.wantTileLetters, got.TileLetters)
+			}
+		}
+	})
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	ctx, cancelFunc := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	in := make(chan message.Message)
+	out := make(chan message.Message, 1)
+	g := Game{
+		players: map[player.Name]*playerController.Player{
+			"selene": nil,
+		},
+		Config: Config{
+			IdlePeriod: 1 * time.Hour,
+		},
+	}
+	done := g.Run(ctx, &wg, in, out)
+	m := message.Message{
+		Type:       message.GameChat,
+		PlayerName: "selene",
+	}
+	in <- m
+	m2 := <-out
+	cancelFunc()
+	wg.Wait()
+	if m2.Type != message.GameChat {
+		t.Errorf("wanted game to relay simple chat message back to player")
+	}
+	if _, ok := <-done; ok {
+		t.Errorf("wanted done channel to be closed after game stopped running")
+	}
+}
+
+func TestRunSync(t *testing.T) {
+	t.Run("TestValidMessageHandler", func(t *testing.T) {
+		validMessageHandlerTests := []struct {
+			message.Type
+			wantSocketError  bool
+			wantStateSaved   bool
+			wantStateDeleted bool
+		}{
+			{
+				Type:           message.JoinGame,
+				wantStateSaved: true,
+			},
+			{
+				Type:             message.DeleteGame,
+				wantStateDeleted: true,
+			},
+			{
+				Type:            message.LeaveGame,
+				wantSocketError: true, // the host is the only player, so nothing is sent before the error for the second message
+				wantStateSaved:  true,
+			},
+			{
+				Type:           message.KickPlayer,
+				wantStateSaved: true,
+			},
+			{
+				Type:           message.ChangeGameStatus,
+				wantStateSaved: true,
+			},
+			{
+				Type:           message.SnagGameTile,
+				wantStateSaved: true,
+			},
+			{
+				Type:           message.SwapGameTile,
+				wantStateSaved: true,
+			},
+			{
+				Type:           message.MoveGameTile,
+				wantStateSaved: true, // no save delay
+			},
+			{
+				Type: message.GameChat,
+			},
+			{
+				Type:           message.RefreshGameBoard,
+				wantStateSaved: true,
+			},
+			{
+				Type: message.GameReplay,
+			},
+			{
+				Type:            message.SocketHTTPPing,
+				wantSocketError: true,
+			},
+		}
+		for i, test := range validMessageHandlerTests {
+			m := message.Message{
+				Type:       test.Type,
+				PlayerName: "selene",
+				Game: &game.Info{
+					Status: game.Finished,
+					Board:  new(board.Board),
+				},
+			}
+			stateSaved, stateDeleted := false, false
+			stateStore := mockStateStore{
+				SaveFunc: func(ctx context.Context, g state.Game) error {
+					stateSaved = true
+					return nil
+				},
+				DeleteFunc: func(ctx context.Context, id game.ID) error {
+					stateDeleted = true
+					return nil
+				},
+			}
+			g := Game{
+				status: 0, // this should cause a gameWarning error
+				players: map[player.Name]*playerController.Player{
+					"selene": {
+						Board: new(board.Board),
+					},
+				},
+				host: "selene",
+				userPoints: map[player.Name]int{
+					"selene": 8,
+				},
+				stateStore: stateStore,
+				Config: Config{
+					TimeFunc: func() int64 { return 0 },
+				},
+			}
+			ctx := context.Background()
+			ctx, cancelFunc := context.WithCancel(ctx)
+			var wg sync.WaitGroup
+			in := make(chan message.Message, 1)  // deleteGame will cause the run to stop before the second message is passed
+			out := make(chan message.Message, 2) // the second message might be handled, for an unknown message type
 			idleTicker := new(time.Ticker)
 			wg.Add(1)
-			go g.runSync(ctx, &wg, in, out, idleTicker)
+			go g.runSync(ctx, &wg, in, out, idleTicker, nil, nil)
 			in <- m
 			var secondMessage message.Message
 			in <- secondMessage // force the game to handle the first Message, this will cause a socketError message to be sent
 			cancelFunc()
 			wg.Wait()
 			got := <-out
-			if test.wantSocketError != (got.Type == message.SocketError) {
+			switch {
+			case test.wantSocketError != (got.Type == message.SocketError):
 				t.Errorf("Test %v: when test is %v, got %v", i, test, got.Type)
+			case test.wantStateSaved != stateSaved:
+				t.Errorf("Test %v: wanted state to be saved: %v", i, test.wantStateSaved)
+			case test.wantStateDeleted != stateDeleted:
+				t.Errorf("Test %v: wanted state to be deleted: %v", i, test.wantStateDeleted)
+			}
+		}
+	})
+	t.Run("TestRunSyncSaveDelay", func(t *testing.T) {
+		saves := make(chan state.Game, 2)
+		stateStore := mockStateStore{
+			SaveFunc: func(ctx context.Context, g state.Game) error {
+				saves <- g
+				return nil
+			},
+		}
+		g := Game{
+			log:    logtest.DiscardLogger,
+			status: game.InProgress,
+			players: map[player.Name]*playerController.Player{
+				"selene": {
+					Board: board.New(nil, nil),
+				},
+			},
+			stateStore: stateStore,
+			Config: Config{
+				TimeFunc:  func() int64 { return 0 },
+				SaveDelay: 50 * time.Millisecond,
+			},
+		}
+		ctx := context.Background()
+		ctx, cancelFunc := context.WithCancel(ctx)
+		var wg sync.WaitGroup
+		in := make(chan message.Message)
+		out := make(chan message.Message, 4)
+		idleTicker := new(time.Ticker)
+		wg.Add(1)
+		go g.runSync(ctx, &wg, in, out, idleTicker, nil, nil)
+		m := message.Message{
+			Type:       message.MoveGameTile,
+			PlayerName: "selene",
+			Game:       new(game.Info),
+		}
+		in <- m
+		in <- m
+		if len(saves) != 0 {
+			t.Errorf("wanted moved tiles to not be saved before the save delay")
+		}
+		select {
+		case <-saves:
+		case <-time.After(time.Second):
+			t.Errorf("wanted moved tiles to be saved after the save delay")
+		}
+		in <- m
+		cancelFunc()
+		wg.Wait()
+		switch len(saves) {
+		case 0:
+			t.Errorf("wanted moved tiles to be saved when the game stops running")
+		case 1:
+		default:
+			t.Errorf("wanted moves to be saved together, got %v saves", len(saves)+1)
+		}
+	})
+	t.Run("TestRunSyncSpectateMove", func(t *testing.T) {
+		b := board.New(nil, []tile.Position{{Tile: tile.Tile{ID: 8, Ch: 'A'}, X: 7, Y: 3}})
+		b.Config = board.Config{NumRows: 10, NumCols: 10}
+		stateStore := mockStateStore{
+			SaveFunc: func(ctx context.Context, g state.Game) error {
+				return nil
+			},
+		}
+		g := Game{
+			log:    logtest.DiscardLogger,
+			status: game.InProgress,
+			players: map[player.Name]*playerController.Player{
+				"fred": {Board: b},
+			},
+			hasSpectators: true,
+			stateStore:    stateStore,
+			Config: Config{
+				TimeFunc: func() int64 { return 0 },
+			},
+		}
+		ctx := context.Background()
+		ctx, cancelFunc := context.WithCancel(ctx)
+		var wg sync.WaitGroup
+		in := make(chan message.Message)
+		out := make(chan message.Message, 4)
+		idleTicker := new(time.Ticker)
+		wg.Add(1)
+		go g.runSync(ctx, &wg, in, out, idleTicker, nil, nil)
+		d := b.NewDiff(board.OpMove, tile.Position{Tile: tile.Tile{ID: 8, Ch: 'A'}, X: 7, Y: 4})
+		in <- message.Message{
+			Type:       message.MoveGameTile,
+			PlayerName: "fred",
+			Game: &game.Info{
+				Diff: &d,
+			},
+		}
+		cancelFunc()
+		wg.Wait()
+		close(out)
+		var got *board.Board
+		for m := range out {
+			if m.Type == message.SpectateGame && len(m.PlayerName) == 0 {
+				b2 := m.Game.PlayerBoards["fred"]
+				got = &b2
+			}
+		}
+		switch {
+		case got == nil:
+			t.Errorf("wanted spectators to be sent the boards after tiles are moved")
+		case got.UsedTiles[8].Y != 4:
+			t.Errorf("wanted spectators to see the moved tile, got %v", got)
+		}
+	})
+	t.Run("TestRunSyncStop", func(t *testing.T) {
+		testRunSyncTickerTests := []struct {
+			ctxCancelled      bool
+			inClosed          bool
+			idleTick          bool
+			gameDeleteMessage bool
+		}{
+			{ctxCancelled: true},
+			{inClosed: true},
+			{idleTick: true},
+			{gameDeleteMessage: true},
+		}
+		for i, test := range testRunSyncTickerTests {
+			ctx := context.Background()
+			ctx, cancelFunc := context.WithCancel(ctx)
+			var wg sync.WaitGroup
+			in := make(chan message.Message, 1)
+			out := make(chan message.Message, 2)
+			idleC := make(chan time.Time, 1)
+			idleTicker := &time.Ticker{
+				C: idleC,
+			}
+			pn := player.Name("selene")
+			stateDeleted := false
+			stateStore := mockStateStore{
+				DeleteFunc: func(ctx context.Context, id game.ID) error {
+					stateDeleted = true
+					return fmt.Errorf("mock delete error should be logged")
+				},
+			}
+			g := Game{
+				log: logtest.DiscardLogger,
+				players: map[player.Name]*playerController.Player{
+					pn: {},
+				},
+				host:       pn,
+				stateStore: stateStore,
+			}
+			switch {
+			case test.ctxCancelled:
+				cancelFunc()
+			case test.inClosed:
+				close(in)
+			case test.idleTick:
+				var zeroTime time.Time
+				idleC <- zeroTime
+			case test.gameDeleteMessage:
+				m := message.Message{
+					Type:       message.DeleteGame,
+					PlayerName: pn,
+				}
+				in <- m
+			}
+			wg.Add(1)
+			g.runSync(ctx, &wg, in, out, idleTicker, nil, nil)
+			cancelFunc()
+			wg.Wait()
+			numWaiting := len(out)
+			wantGameDelete := test.idleTick || test.gameDeleteMessage
+			switch {
+			case wantGameDelete != stateDeleted:
+				t.Errorf("Test %v: wanted state to be deleted: %v", i, wantGameDelete)
+			case !wantGameDelete:
+				if numWaiting != 0 {
+					t.Errorf("Test %v: wanted no messages left on out channel, got %v", i, numWaiting)
+				}
+			case numWaiting != 2:
+				t.Errorf("Test %v: wanted two messages left on out channel, got %v", i, numWaiting)
+			default:
+				gotM1 := <-out
+				gotM2 := <-out
+				switch {
+				case gotM1.Type != message.LeaveGame, gotM1.PlayerName != pn:
+					t.Errorf("Test %v: wanted leave message sent to %v, got %v", i, pn, gotM1)
+				case gotM2.Type != message.GameInfos:
+					t.Errorf("Test %v: wanted final message sent by inactive game to be GameInfos, got %v", i, gotM2)
+				}
+			}
+		}
+	})
+	t.Run("TestRunSyncIdleTickAfterMessage", func(t *testing.T) {
+		ctx := context.Background()
+		ctx, cancelFunc := context.WithCancel(ctx)
+		var wg sync.WaitGroup
+		in := make(chan message.Message)
+		out := make(chan message.Message, 2)
+		idleC := make(chan time.Time)
+		idleTicker := &time.Ticker{
+			C: idleC,
+		}
+		pn := player.Name("selene")
+		g := Game{
+			log: logtest.DiscardLogger,
+			players: map[player.Name]*playerController.Player{
+				pn: nil,
+			},
+		}
+		wg.Add(1)
+		go g.runSync(ctx, &wg, in, out, idleTicker, nil, nil)
+		in <- message.Message{
+			Type:       message.GameChat,
+			PlayerName: pn,
+		}
+		<-out // error for unknown message type
+		var zeroTime time.Time
+		idleC <- zeroTime
+		cancelFunc()
+		wg.Wait()
+		numWaiting := len(out)
+		if numWaiting != 0 {
+			t.Errorf("wanted no messages left on out channel, got %v", numWaiting)
+		}
+	})
+}
+
+func TestRunSyncClockTick(t *testing.T) {
+	runSyncClockTickTests := []struct {
+		now          int64
+		wantStatus   game.Status
+		wantSecsLeft int
+	}{
+		{
+			now:          130,
+			wantStatus:   game.InProgress,
+			wantSecsLeft: 70,
+		},
+		{
+			now:        200,
+			wantStatus: game.Finished,
+		},
+	}
+	for i, test := range runSyncClockTickTests {
+		ctx := context.Background()
+		ctx, cancelFunc := context.WithCancel(ctx)
+		var wg sync.WaitGroup
+		in := make(chan message.Message)
+		out := make(chan message.Message, 10)
+		idleTicker := new(time.Ticker)
+		clockTicks := make(chan time.Time)
+		stateSaved := false
+		g := Game{
+			log:    logtest.DiscardLogger,
+			status: game.InProgress,
+			players: map[player.Name]*playerController.Player{
+				"selene": {
+					Board: board.New(nil, nil),
+				},
+			},
+			events: []replay.Event{
+				{Type: replay.Start, Time: 100},
+			},
+			stateStore: mockStateStore{
+				SaveFunc: func(ctx context.Context, g state.Game) error {
+					stateSaved = true
+					return nil
+				},
+			},
+			Config: Config{
+				TimeFunc: func() int64 { return test.now },
+				Config: game.Config{
+					TimeLimitSec: 100,
+				},
+			},
+		}
+		wg.Add(1)
+		go g.runSync(ctx, &wg, in, out, idleTicker, nil, clockTicks)
+		clockTicks <- time.Time{}
+		cancelFunc()
+		wg.Wait()
+		switch {
+		case len(out) == 0:
+			t.Errorf("Test %v: wanted message sent on clock tick", i)
+		case test.wantStatus != g.status:
+			t.Errorf("Test %v: game statuses not equal after clock tick: wanted %v, got %v", i, test.wantStatus, g.status)
+		case test.wantStatus == game.Finished && !stateSaved:
+			t.Errorf("Test %v: wanted state saved when time runs out", i)
+		default:
+			m := <-out
+			if want, got := test.wantSecsLeft, m.Game.SecondsLeft; want != got {
+				t.Errorf("Test %v: seconds left not equal: wanted %v, got %v", i, want, got)
+			}
+		}
+	}
+}
+
+func TestSecondsLeft(t *testing.T) {
+	secondsLeftTests := []struct {
+		status       game.Status
+		timeLimitSec int
+		now          int64
+		events       []replay.Event
+		want         int
+	}{
+		{ // not timed
+			status: game.InProgress,
+			now:    10,
+		},
+		{ // not started
+			status:       game.NotStarted,
+			timeLimitSec: 60,
+			now:          10,
+		},
+		{ // time since created
+			status:       game.InProgress,
+			timeLimitSec: 60,
+			now:          10,
+			want:         55,
+		},
+		{ // time since started
+			status:       game.InProgress,
+			timeLimitSec: 60,
+			now:          30,
+			events: []replay.Event{
+				{Type: replay.Join, Time: 6},
+				{Type: replay.Start, Time: 20},
+			},
+			want: 50,
+		},
+		{ // time has run out
+			status:       game.InProgress,
+			timeLimitSec: 60,
+			now:          99,
+		},
+	}
+	for i, test := range secondsLeftTests {
+		g := Game{
+			createdAt: 5,
+			status:    test.status,
+			events:    test.events,
+			Config: Config{
+				TimeFunc: func() int64 { return test.now },
+				Config: game.Config{
+					TimeLimitSec: test.timeLimitSec,
+				},
+			},
+		}
+		if want, got := test.want, g.secondsLeft(); want != got {
+			t.Errorf("Test %v: wanted %v seconds left, got %v", i, want, got)
+		}
+	}
+}
+
+func TestHandleTimeUp(t *testing.T) {
+	validBoard := func(letters string) *board.Board {
+		tps := make([]tile.Position, len(letters))
+		for i, ch := range letters {
+			tps[i] = tile.Position{Tile: tile.Tile{ID: tile.ID(i + 1), Ch: tile.Letter(ch)}, X: tile.X(i), Y: 1}
+		}
+		b := board.New([]tile.Tile{{ID: 99, Ch: 'Q'}}, tps) // unused tiles are ignored
+		return b
+	}
+	handleTimeUpTests := []struct {
+		players        map[player.Name]*playerController.Player
+		wantWinner     player.Name
+		wantWinnerInfo string
+	}{
+		{
+			players: map[player.Name]*playerController.Player{
+				"barney": {WinPoints: 5, Board: validBoard("BAD")},  // invalid word
+				"fred":   {WinPoints: 5, Board: validBoard("GOOD")}, // valid
+				"selene": {WinPoints: 5, Board: validBoard("NICE")}, // valid, ties go to the first player alphabetically
+				"wilma":  {WinPoints: 5, Board: validBoard("OK")},   // valid, but fewer tiles
+			},
+			wantWinner:     "fred",
+			wantWinnerInfo: "fred won, using 4 tiles to create 1 words",
+		},
+		{
+			players: map[player.Name]*playerController.Player{
+				"barney": {WinPoints: 5, Board: validBoard("BAD")},
+			},
+			wantWinnerInfo: "no player",
+		},
+		{ // players who forfeited cannot win
+			players: map[player.Name]*playerController.Player{
+				"fred":  {WinPoints: 5, Board: validBoard("GOOD"), Forfeited: true},
+				"wilma": {WinPoints: 5, Board: validBoard("OK")},
+			},
+			wantWinner:     "wilma",
+			wantWinnerInfo: "wilma won, using 2 tiles to create 1 words",
+		},
+	}
+	for i, test := range handleTimeUpTests {
+		ctx := context.Background()
+		var gotUserPoints map[string]int
+		resultCreated := false
+		g := Game{
+			status:  game.InProgress,
+			players: test.players,
+			userDao: mockUserDao{
+				UpdatePointsIncrementFunc: func(ctx context.Context, userPoints map[string]int) error {
+					gotUserPoints = userPoints
+					return nil
+				},
+			},
+			resultStore: mockResultStore{
+				CreateFunc: func(ctx context.Context, r result.Result) error {
+					resultCreated = true
+					if want, got := string(test.wantWinner), r.Winner; want != got {
+						t.Errorf("Test %v: result winners not equal: wanted %v, got %v", i, want, got)
+					}
+					return nil
+				},
+			},
+			WordValidator: mockWordValidator(func(word string) bool {
+				return word != "BAD"
+			}),
+			Config: Config{
+				TimeFunc: func() int64 { return 0 },
+			},
+		}
+		var sentMessages []message.Message
+		send := func(m message.Message) {
+			sentMessages = append(sentMessages, m)
+		}
+		g.handleTimeUp(ctx, send)
+		hasWinner := len(test.wantWinner) != 0
+		switch {
+		case g.status != game.Finished:
+			t.Errorf("Test %v: wanted game to be finished, got %v", i, g.status)
+		case len(test.players) != len(sentMessages):
+			t.Errorf("Test %v: wanted a message sent to each player, got %v", i, sentMessages)
+		case !strings.Contains(sentMessages[0].Info, test.wantWinnerInfo):
+			t.Errorf("Test %v: wanted message info to contain %q, got %q", i, test.wantWinnerInfo, sentMessages[0].Info)
+		case sentMessages[0].Game.Status != game.Finished || len(sentMessages[0].Game.FinalBoards) != len(test.players):
+			t.Errorf("Test %v: wanted finished status and final boards in message, got %v", i, sentMessages[0].Game)
+		case hasWinner != resultCreated:
+			t.Errorf("Test %v: wanted result created: %v, got %v", i, hasWinner, resultCreated)
+		case hasWinner && gotUserPoints[string(test.wantWinner)] != 5:
+			t.Errorf("Test %v: wanted winner to get win points, got %v", i, gotUserPoints)
+		case !hasWinner && gotUserPoints != nil:
+			t.Errorf("Test %v: wanted no points given when there is no winner, got %v", i, gotUserPoints)
+		}
+	}
+}
+
+func TestSendMessage(t *testing.T) {
+	sendMessageTests := []struct {
+		Game
+		message.Message
+		wantGameID game.ID
+	}{
+		{ // no game on message
+			Game: Game{
+				id: 1,
+			},
+			wantGameID: 1,
+		},
+		{
+			Game: Game{
+				id: 2,
+			},
+			Message: message.Message{
+				Game: &game.Info{
+					ID: 2,
+				},
+			},
+			wantGameID: 2,
+		},
+		{ // should be overwritten because it is coming from game 2
+			Game: Game{
+				id: 3,
+			},
+			Message: message.Message{
+				Game: &game.Info{
+					ID: 4,
+				},
+			},
+			wantGameID: 3,
+		},
+	}
+	for i, test := range sendMessageTests {
+		out := make(chan message.Message, 1)
+		send := test.Game.sendMessage(out)
+		send(test.Message)
+		got := <-out
+		gotGameID := got.Game.ID
+		if test.wantGameID != gotGameID {
+			t.Errorf("Test %v: game ids not equal: wanted %v, got %v", i, test.wantGameID, got.Game.ID)
+		}
+	}
+}
+
+func TestInitializeUnusedTiles(t *testing.T) {
+	initializeUnusedTilesTests := []struct {
+		tileLetters string
+		wantOk      bool
+		want        []tile.Tile
+	}{
+		{
+			tileLetters: ":(",
+		},
+		{
+			tileLetters: "AAABAC",
+			wantOk:      true,
+			want: []tile.Tile{
+				{ID: 6, Ch: 'C'},
+				{ID: 4, Ch: 'B'},
+				{ID: 1, Ch: 'A'},
+				{ID: 2, Ch: 'A'},
+				{ID: 3, Ch: 'A'},
+				{ID: 5, Ch: 'A'},
+			},
+		},
+		{
+			tileLetters: "ÑANÜ",
+			wantOk:      true,
+			want: []tile.Tile{
+				{ID: 4, Ch: 'Ü'},
+				{ID: 1, Ch: 'Ñ'},
+				{ID: 3, Ch: 'N'},
+				{ID: 2, Ch: 'A'},
+			},
+		},
+	}
+	for i, test := range initializeUnusedTilesTests {
+		shuffleFunc := func(tiles []tile.Tile) {
+			// sort tiles by letter descending, id ascending
+			sort.Slice(tiles, func(i, j int) bool {
+				if tiles[i].Ch == tiles[j].Ch {
+					return tiles[i].ID < tiles[j].ID
+				}
+				return tiles[i].Ch > tiles[j].Ch
+			})
+		}
+		g := Game{
+			Config: Config{
+				TileLetters:            test.tileLetters,
+				ShuffleUnusedTilesFunc: shuffleFunc,
+			},
+		}
+		err := g.initializeUnusedTiles()
+		switch {
+		case !test.wantOk:
+			if err == nil {
+				t.Errorf("Test %v: wanted error initializing unused tiles", i)
+			}
+		case err != nil:
+			t.Errorf("Test %v: unwanted error initializing unused tiles: %v", i, err)
+		case !reflect.DeepEqual(test.want, g.unusedTiles):
+			t.Errorf("Test %v: unusedTiles not equal:\nwanted: %v\ngot:    %v", i, test.want, g.unusedTiles)
+		}
+	}
+}
+
+func TestUpdateUserPoints(t *testing.T) {
+	want := fmt.Errorf("calling UpdatePointsIncrement")
+	ctx := context.Background()
+	wantUserPoints := map[string]int{
+		"alice":  1,
+		"bob":    1,
+		"selene": 5,
+	}
+	userDao := mockUserDao{
+		UpdatePointsIncrementFunc: func(ctx context.Context, gotUserPoints map[string]int) error {
+			switch {
+			case ctx == nil:
+				return fmt.Errorf("context missing")
+			case !reflect.DeepEqual(wantUserPoints, gotUserPoints):
+				return fmt.Errorf("user points not equal\nwanted: %v\ngot:    %v", wantUserPoints, gotUserPoints)
+			}
+			return want
+		},
+	}
+	alicePlayer, err := playerController.Config{WinPoints: 4}.New(new(board.Board))
+	if err != nil {
+		t.Errorf("unwanted error adding alice player: %v", err)
+	}
+	selenePlayer, err := playerController.Config{WinPoints: 5}.New(new(board.Board))
+	if err != nil {
+		t.Errorf("unwanted error adding selene player: %v", err)
+	}
+	g := Game{
+		players: map[player.Name]*playerController.Player{
+			"alice":  alicePlayer,
+			"selene": selenePlayer,
+			"bob":    {},
+		},
+		userDao: userDao,
+	}
+	gotUserPoints, got := g.updateUserPoints(ctx, "selene")
+	switch {
+	case want != got:
+		t.Errorf("wanted error %v, got %v", want, got)
+	case !reflect.DeepEqual(wantUserPoints, gotUserPoints):
+		t.Errorf("returned user points not equal\nwanted: %v\ngot:    %v", wantUserPoints, gotUserPoints)
+	}
+}
+
+func TestUpdateUserPointsScoring(t *testing.T) {
+	updateUserPointsScoringTests := []struct {
+		game.Scoring
+		wantOk         bool
+		wantUserPoints map[string]int
+	}{
+		{
+			Scoring: -1,
+		},
+		{
+			Scoring: game.LetterValueScoring,
+			wantOk:  true,
+			wantUserPoints: map[string]int{
+				"fred":   6,      // 2+1+2+1
+				"selene": 3 + 10, // 1+1+1 + win points
+			},
+		},
+		{
+			Scoring: game.WordLengthScoring,
+			wantOk:  true,
+			wantUserPoints: map[string]int{
+				"fred":   1, // DOGS is too short for a bonus
+				"selene": 10,
+			},
+		},
+	}
+	for i, test := range updateUserPointsScoringTests {
+		ctx := context.Background()
+		userDao := mockUserDao{
+			UpdatePointsIncrementFunc: func(ctx context.Context, userPoints map[string]int) error {
+				return nil
+			},
+		}
+		g := Game{
+			players: map[player.Name]*playerController.Player{
+				"fred": {
+					WinPoints: 10,
+					Board: board.New(nil, []tile.Position{
+						{Tile: tile.Tile{ID: 1, Ch: 'D'}, X: 1, Y: 1},
+						{Tile: tile.Tile{ID: 2, Ch: 'O'}, X: 2, Y: 1},
+						{Tile: tile.Tile{ID: 3, Ch: 'G'}, X: 3, Y: 1},
+						{Tile: tile.Tile{ID: 4, Ch: 'S'}, X: 4, Y: 1},
+					}),
+				},
+				"selene": {
+					WinPoints: 10,
+					Board: board.New(nil, []tile.Position{
+						{Tile: tile.Tile{ID: 5, Ch: 'E'}, X: 1, Y: 1},
+						{Tile: tile.Tile{ID: 6, Ch: 'A'}, X: 2, Y: 1},
+						{Tile: tile.Tile{ID: 7, Ch: 'T'}, X: 3, Y: 1},
+					}),
+				},
+			},
+			userDao: userDao,
+			Config: Config{
+				Config: game.Config{
+					Scoring: test.Scoring,
+				},
+			},
+			WordValidator: mockWordValidator(func(word string) bool {
+				return true
+			}),
+		}
+		gotUserPoints, err := g.updateUserPoints(ctx, "selene")
+		switch {
+		case !test.wantOk:
+			if err == nil {
+				t.Errorf("Test %v: wanted error", i)
 			}
+		case err != nil:
+			t.Errorf("Test %v: unwanted error: %v", i, err)
+		case !reflect.DeepEqual(test.wantUserPoints, gotUserPoints):
+			t.Errorf("Test %v: user points not equal\nwanted: %v\ngot:    %v", i, test.wantUserPoints, gotUserPoints)
 		}
-	})
-	t.Run("TestRunSyncStop", func(t *testing.T) {
-		testRunSyncTickerTests := []struct {
-			ctxCancelled      bool
-			inClosed          bool
-			idleTick          bool
-			gameDeleteMessage bool
-		}{
-			{ctxCancelled: true},
-			{inClosed: true},
-			{idleTick: true},
-			{gameDeleteMessage: true},
+	}
+}
+
+func TestUpdateUserPointsTeams(t *testing.T) {
+	ctx := context.Background()
+	var gotUserPoints map[string]int
+	userDao := mockUserDao{
+		UpdatePointsIncrementFunc: func(ctx context.Context, userPoints map[string]int) error {
+			gotUserPoints = userPoints
+			return nil
+		},
+	}
+	g := Game{
+		players: map[player.Name]*playerController.Player{
+			"barney":  {WinPoints: 11, Team: 1},
+			"fred":    {WinPoints: 8, Team: 2},
+			"selene":  {WinPoints: 7, Team: 1},
+			"wilma":   {WinPoints: 9, Team: 2},
+			"pebbles": {WinPoints: 4, Team: 3},
+		},
+		userDao: userDao,
+		Config: Config{
+			Config: game.Config{
+				TeamSize: 2,
+			},
+		},
+	}
+	want := map[string]int{
+		"barney":  4, // the team gets the 7 win points of selene, the first player gets the extra point
+		"selene":  3,
+		"fred":    1, // the consolation point of the team goes to the first player
+		"wilma":   0,
+		"pebbles": 1,
+	}
+	if _, err := g.updateUserPoints(ctx, "selene"); err != nil {
+		t.Fatalf("unwanted error: %v", err)
+	}
+	if !reflect.DeepEqual(want, gotUserPoints) {
+		t.Errorf("user points not equal\nwanted: %v\ngot:    %v", want, gotUserPoints)
+	}
+}
+
+func TestUpdateUserPointsIncrementsKnownPoints(t *testing.T) {
+	for i, incrementErr := range []error{nil, fmt.Errorf("increment error")} {
+		ctx := context.Background()
+		userDao := mockUserDao{
+			UpdatePointsIncrementFunc: func(ctx context.Context, userPoints map[string]int) error {
+				return incrementErr
+			},
 		}
-		for i, test := range testRunSyncTickerTests {
-			ctx := context.Background()
-			ctx, cancelFunc := context.WithCancel(ctx)
-			var wg sync.WaitGroup
-			in := make(chan message.Message, 1)
-			out := make(chan message.Message, 2)
-			idleC := make(chan time.Time, 1)
-			idleTicker := &time.Ticker{
-				C: idleC,
+		g := Game{
+			players: map[player.Name]*playerController.Player{
+				"alice":  {WinPoints: 4},
+				"selene": {WinPoints: 5},
+			},
+			userPoints: map[player.Name]int{
+				"selene": 30,
+			},
+			userDao: userDao,
+		}
+		want := map[player.Name]int{
+			"selene": 35,
+		}
+		if incrementErr != nil {
+			want["selene"] = 30
+		}
+		g.updateUserPoints(ctx, "selene")
+		if !reflect.DeepEqual(want, g.userPoints) {
+			t.Errorf("Test %v: user points not equal after updating points:\nwanted: %v\ngot:    %v", i, want, g.userPoints)
+		}
+	}
+}
+
+func TestUpdateUserPointsSkipsBots(t *testing.T) {
+	ctx := context.Background()
+	wantUserPoints := map[string]int{
+		"selene": 1,
+	}
+	userDao := mockUserDao{
+		UpdatePointsIncrementFunc: func(ctx context.Context, gotUserPoints map[string]int) error {
+			if !reflect.DeepEqual(wantUserPoints, gotUserPoints) {
+				return fmt.Errorf("user points not equal\nwanted: %v\ngot:    %v", wantUserPoints, gotUserPoints)
 			}
-			pn := player.Name("selene")
-			g := Game{
-				log: logtest.DiscardLogger,
+			return nil
+		},
+		ReadPointsFunc: func(ctx context.Context, username string) (int, error) {
+			return 0, fmt.Errorf("points should not be read for %v", username)
+		},
+	}
+	g := Game{
+		players: map[player.Name]*playerController.Player{
+			"selene":     {},
+			"bot-hard-1": {WinPoints: 7},
+		},
+		userPoints: map[player.Name]int{},
+		userDao:    userDao,
+	}
+	g.readUserPoints(ctx, "bot-hard-1")
+	gotUserPoints, err := g.updateUserPoints(ctx, "bot-hard-1")
+	switch {
+	case err != nil:
+		t.Errorf("unwanted error: %v", err)
+	case gotUserPoints["bot-hard-1"] != 7:
+		t.Errorf("wanted bot to be shown its win points, got %v", gotUserPoints)
+	case len(g.userPoints) != 0:
+		t.Errorf("wanted no points for the bot to be known, got %v", g.userPoints)
+	}
+}
+
+func TestResult(t *testing.T) {
+	g := Game{
+		id:        8,
+		createdAt: 100,
+		players: map[player.Name]*playerController.Player{
+			"selene": {
+				WinPoints: 6,
+			},
+			"alice": {},
+		},
+		events: []replay.Event{
+			{Time: 110, Type: replay.Join, PlayerName: "selene"},
+			{Time: 120, Type: replay.Start, PlayerName: "alice"},
+			{Time: 190, Type: replay.Finish, PlayerName: "selene"},
+		},
+		finishOrder: []player.Name{"alice", "selene"},
+		Config: Config{
+			TimeFunc: func() int64 {
+				return 200
+			},
+			Config: game.Config{
+				MinLength: 3,
+			},
+		},
+	}
+	want := result.Result{
+		GameID: 8,
+		Config: game.Config{
+			MinLength: 3,
+		},
+		Players:     []string{"alice", "selene"},
+		Winner:      "selene",
+		WinPoints:   6,
+		WordCount:   3,
+		LongestWord: "APPLE",
+		FinishedAt:  200,
+		DurationSec: 80,
+		FinishOrder: []string{"alice", "selene"},
+	}
+	got := g.result("selene", []string{"CAT", "APPLE", "DOG"})
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("results not equal:\nwanted: %v\ngot:    %v", want, got)
+	}
+}
+
+func TestPlayerNames(t *testing.T) {
+	g := Game{
+		players: map[player.Name]*playerController.Player{
+			"b": {},
+			"c": {},
+			"a": {},
+		},
+	}
+	want := []string{"a", "b", "c"}
+	got := g.playerNames()
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("player names not equal/sorted:\nwanted: %v\ngot:    %v", want, got)
+	}
+}
+
+func TestHandleMessage(t *testing.T) {
+	handleMessageTests := []struct {
+		message.Message
+		Game
+		messageHandlers map[message.Type]messageHandler
+		wantSendType    message.Type
+		wantCode        message.Code
+		wantActive      bool
+		wantLog         bool
+	}{
+		{ // unknown message type #1
+			wantSendType: message.SocketError,
+			wantCode:     message.CodeUnknownMessage,
+		},
+		{ // unknown message type #2
+			Message: message.Message{
+				Type: message.CreateGame,
+			},
+			messageHandlers: map[message.Type]messageHandler{
+				message.JoinGame: func(ctx context.Context, m message.Message, send messageSender) error {
+					return nil
+				},
+			},
+			wantSendType: message.SocketError,
+			wantCode:     message.CodeUnknownMessage,
+		},
+		{ // unknown message type #3 with debug
+			Game: Game{
+				Config: Config{
+					Debug: true,
+				},
+			},
+			wantSendType: message.SocketError,
+			wantCode:     message.CodeUnknownMessage,
+			wantLog:      true,
+		},
+		{ // unknown player
+			Message: message.Message{
+				Type:       message.SnagGameTile,
+				PlayerName: "ghost",
+			},
+			Game: Game{
+				players: map[player.Name]*playerController.Player{
+					"selene": nil,
+				},
+			},
+			messageHandlers: map[message.Type]messageHandler{
+				message.SnagGameTile: func(ctx context.Context, m message.Message, send messageSender) error {
+					return nil
+				},
+			},
+			wantSendType: message.SocketError,
+			wantCode:     message.CodeNotInGame,
+		},
+		{ // message handler error
+			Message: message.Message{
+				Type:       message.SnagGameTile,
+				PlayerName: "selene",
+			},
+			Game: Game{
+				players: map[player.Name]*playerController.Player{
+					"selene": nil,
+				},
+			},
+			messageHandlers: map[message.Type]messageHandler{
+				message.SnagGameTile: func(ctx context.Context, m message.Message, send messageSender) error {
+					return fmt.Errorf("snag tile error")
+				},
+			},
+			wantSendType: message.SocketError,
+			wantCode:     message.CodeServerError,
+			wantActive:   true,
+		},
+		{ // message handler error game warning
+			Message: message.Message{
+				Type:       message.SnagGameTile,
+				PlayerName: "selene",
+			},
+			Game: Game{
+				players: map[player.Name]*playerController.Player{
+					"selene": nil,
+				},
+			},
+			messageHandlers: map[message.Type]messageHandler{
+				message.SnagGameTile: func(ctx context.Context, m message.Message, send messageSender) error {
+					return gameWarning{code: message.CodeNoTilesLeft, text: "snag tile warning"}
+				},
+			},
+			wantSendType: message.SocketWarning,
+			wantCode:     message.CodeNoTilesLeft,
+			wantActive:   true,
+		},
+		{ // message ok
+			Message: message.Message{
+				Type:       message.SwapGameTile,
+				PlayerName: "selene",
+			},
+			Game: Game{
+				players: map[player.Name]*playerController.Player{
+					"selene": nil,
+				},
+			},
+			messageHandlers: map[message.Type]messageHandler{
+				message.SnagGameTile: func(ctx context.Context, m message.Message, send messageSender) error {
+					return fmt.Errorf("wrong handler")
+				},
+				message.SwapGameTile: func(ctx context.Context, m message.Message, send messageSender) error {
+					m2 := message.Message{
+						Type: message.ChangeGameTiles,
+					}
+					send(m2)
+					return nil
+				},
+			},
+			wantSendType: message.ChangeGameTiles,
+			wantActive:   true,
+		},
+		{ // message ok with no want send type (normal snag sends no message)
+			Message: message.Message{
+				Type:       message.SnagGameTile,
+				PlayerName: "selene",
+			},
+			Game: Game{
 				players: map[player.Name]*playerController.Player{
-					pn: nil,
+					"selene": nil,
 				},
-			}
-			switch {
-			case test.ctxCancelled:
-				cancelFunc()
-			case test.inClosed:
-				close(in)
-			case test.idleTick:
-				var zeroTime time.Time
-				idleC <- zeroTime
-			case test.gameDeleteMessage:
-				m := message.Message{
-					Type:       message.DeleteGame,
-					PlayerName: pn,
-				}
-				in <- m
-			}
-			wg.Add(1)
-			g.runSync(ctx, &wg, in, out, idleTicker)
-			cancelFunc()
-			wg.Wait()
-			numWaiting := len(out)
-			wantGameDelete := test.idleTick || test.gameDeleteMessage
+			},
+			messageHandlers: map[message.Type]messageHandler{
+				message.SnagGameTile: func(ctx context.Context, m message.Message, send messageSender) error {
+					return nil
+				},
+			},
+			wantActive: true,
+		},
+		{ // message ok with debug
+			Message: message.Message{
+				Type:       message.SnagGameTile,
+				PlayerName: "selene",
+			},
+			Game: Game{
+				players: map[player.Name]*playerController.Player{
+					"selene": nil,
+				},
+				Config: Config{
+					Debug: true,
+				},
+			},
+			messageHandlers: map[message.Type]messageHandler{
+				message.SnagGameTile: func(ctx context.Context, m message.Message, send messageSender) error {
+					return nil
+				},
+			},
+			wantActive: true,
+			wantLog:    true,
+		},
+	}
+	for i, test := range handleMessageTests {
+		ctx := context.Background()
+		log := new(logtest.Logger)
+		test.Game.log = log
+		gotSend := false
+		send := func(m message.Message) {
+			gotSend = true
 			switch {
-			case !wantGameDelete:
-				if numWaiting != 0 {
-					t.Errorf("Test %v: wanted no messages left on out channel, got %v", i, numWaiting)
+			case m.Type != test.wantSendType:
+				t.Errorf("Test %v: wanted message sent with type %v, got %v", i, m.Type, test.wantSendType)
+			case m.Type == message.SocketError, m.Type == message.SocketWarning:
+				if m.PlayerName != test.Message.PlayerName || !reflect.DeepEqual(test.Message.Game, m.Game) || len(m.Info) == 0 {
+					t.Errorf("Test: %v wanted message for %v with game and error info, got %v", i, test.Message.PlayerName, m)
 				}
-			case numWaiting != 2:
-				t.Errorf("Test %v: wanted two messages left on out channel, got %v", i, numWaiting)
-			default:
-				gotM1 := <-out
-				gotM2 := <-out
-				switch {
-				case gotM1.Type != message.LeaveGame, gotM1.PlayerName != pn:
-					t.Errorf("Test %v: wanted leave message sent to %v, got %v", i, pn, gotM1)
-				case gotM2.Type != message.GameInfos:
-					t.Errorf("Test %v: wanted final message sent by inactive game to be GameInfos, got %v", i, gotM2)
+				if want, got := test.wantCode, m.Code; want != got {
+					t.Errorf("Test %v: codes not equal: wanted %v, got %v", i, want, got)
 				}
 			}
 		}
-	})
-	t.Run("TestRunSyncIdleTickAfterMessage", func(t *testing.T) {
+		active := false
+		test.Game.handleMessage(ctx, test.Message, send, &active, test.messageHandlers)
+		switch {
+		case active != test.wantActive:
+			t.Errorf("Test %v: wanted active flag to be %v after handler was run, got %v", i, active, test.wantActive)
+		case test.wantLog != !log.Empty():
+			t.Errorf("Test %v: wanted message logged (%v), got %v", i, test.wantLog, !log.Empty())
+		case test.wantSendType != 0 && !gotSend:
+			t.Errorf("Test %v: wanted message to be sent", i)
+		}
+	}
+}
+
+func TestHandleGameJoin(t *testing.T) {
+	handleGameJoinTests := []struct {
+		message.Message
+		Game
+		wantOk         bool
+		wantNumPlayers int
+	}{
+		{ // rejoin
+			Message: message.Message{
+				PlayerName: "selene",
+				Game: &game.Info{
+					Board: new(board.Board),
+				},
+			},
+			Game: Game{
+				players: map[player.Name]*playerController.Player{
+					"selene": {
+						Board: new(board.Board),
+					},
+				},
+			},
+			wantOk:         true,
+			wantNumPlayers: 1,
+		},
+		{ // resume on new socket, keeping the config of the board
+			Message: message.Message{
+				PlayerName: "selene",
+				Game:       &game.Info{},
+			},
+			Game: Game{
+				players: map[player.Name]*playerController.Player{
+					"selene": {
+						Board: &board.Board{
+							Config: board.Config{
+								NumRows: 10,
+								NumCols: 12,
+							},
+						},
+					},
+				},
+			},
+			wantOk:         true,
+			wantNumPlayers: 1,
+		},
+		{ // rejoin private game without invite code
+			Message: message.Message{
+				PlayerName: "selene",
+				Game: &game.Info{
+					Board: new(board.Board),
+				},
+			},
+			Game: Game{
+				status: game.InProgress,
+				players: map[player.Name]*playerController.Player{
+					"selene": {
+						Board: new(board.Board),
+					},
+				},
+				Config: Config{
+					Config: game.Config{
+						InviteCode: "b4n4n4",
+					},
+				},
+			},
+			wantOk:         true,
+			wantNumPlayers: 1,
+		},
+		{}, // game not started
+		{ // wrong invite code for private game
+			Message: message.Message{
+				PlayerName: "young",
+				Game: &game.Info{
+					InviteCode: "apple",
+				},
+			},
+			Game: Game{
+				status: game.NotStarted,
+				Config: Config{
+					MaxPlayers: 4,
+					Config: game.Config{
+						InviteCode: "b4n4n4",
+					},
+				},
+			},
+		},
+		{ // no room for new player
+			Game: Game{
+				status: game.NotStarted,
+			},
+		},
+		{ // not enough tiles for new player
+			Game: Game{
+				status: game.NotStarted,
+				Config: Config{
+					MaxPlayers:  4,
+					NumNewTiles: 12,
+				},
+			},
+		},
+		{ // add player, error
+			Message: message.Message{
+				PlayerName: "young",
+				Game: &game.Info{
+					Board: &board.Board{
+						Config: board.Config{
+							NumRows: 79,
+							NumCols: 28,
+						},
+					},
+				},
+			},
+			Game: Game{
+				status: game.NotStarted,
+				Config: Config{
+					MaxPlayers: 3,
+					PlayerCfg: playerController.Config{
+						WinPoints: 7,
+					},
+				},
+				players: map[player.Name]*playerController.Player{
+					"crosby": {},
+					"stills": {},
+					"nash":   {},
+				},
+			},
+		},
+		{ // add player
+			Message: message.Message{
+				PlayerName: "young",
+				Game: &game.Info{
+					Board: &board.Board{
+						Config: board.Config{
+							NumRows: 79,
+							NumCols: 28,
+						},
+					},
+				},
+			},
+			Game: Game{
+				status: game.NotStarted,
+				Config: Config{
+					MaxPlayers: 4,
+					PlayerCfg: playerController.Config{
+						WinPoints: 7,
+					},
+				},
+				players: map[player.Name]*playerController.Player{
+					"crosby": {},
+					"stills": {},
+					"nash":   {},
+				},
+			},
+			wantOk:         true,
+			wantNumPlayers: 4,
+		},
+		{ // add player to private game
+			Message: message.Message{
+				PlayerName: "young",
+				Game: &game.Info{
+					Board: &board.Board{
+						Config: board.Config{
+							NumRows: 79,
+							NumCols: 28,
+						},
+					},
+					InviteCode: "b4n4n4",
+				},
+			},
+			Game: Game{
+				status: game.NotStarted,
+				Config: Config{
+					MaxPlayers: 4,
+					PlayerCfg: playerController.Config{
+						WinPoints: 7,
+					},
+					Config: game.Config{
+						InviteCode: "b4n4n4",
+					},
+				},
+				players: map[player.Name]*playerController.Player{
+					"crosby": {},
+				},
+			},
+			wantOk:         true,
+			wantNumPlayers: 2,
+		},
+	}
+	for i, test := range handleGameJoinTests {
 		ctx := context.Background()
-		ctx, cancelFunc := context.WithCancel(ctx)
-		var wg sync.WaitGroup
-		in := make(chan message.Message)
-		out := make(chan message.Message, 2)
-		idleC := make(chan time.Time)
-		idleTicker := &time.Ticker{
-			C: idleC,
+		messageSent := false
+		send := func(m message.Message) {
+			messageSent = true
+			if !test.wantOk && (m.Type != message.LeaveGame || m.PlayerName != test.Message.PlayerName) {
+				t.Errorf("Test %v: wanted leavegame message sent to %v if an error occurred, got %v", i, test.Message.PlayerName, m)
+			}
 		}
-		pn := player.Name("selene")
-		g := Game{
-			log: logtest.DiscardLogger,
-			players: map[player.Name]*playerController.Player{
-				pn: nil,
+		test.Game.TimeFunc = func() int64 { return 0 }
+		test.Game.userPoints = make(map[player.Name]int)
+		test.Game.userDao = mockUserDao{
+			ReadPointsFunc: func(ctx context.Context, username string) (int, error) {
+				return 0, nil
 			},
 		}
-		wg.Add(1)
-		go g.runSync(ctx, &wg, in, out, idleTicker)
-		in <- message.Message{
-			Type:       message.GameChat,
-			PlayerName: pn,
-		}
-		<-out // error for unknown message type
-		var zeroTime time.Time
-		idleC <- zeroTime
-		cancelFunc()
-		wg.Wait()
-		numWaiting := len(out)
-		if numWaiting != 0 {
-			t.Errorf("wanted no messages left on out channel, got %v", numWaiting)
+		err := test.Game.handleGameJoin(ctx, test.Message, send)
+		switch {
+		case !messageSent:
+			t.Errorf("Test %v: wanted at least one message sent after a join-game attempt", i)
+		case !test.wantOk:
+			if err == nil {
+				t.Errorf("Test %v: wanted error joining game", i)
+			}
+		case err != nil:
+			t.Errorf("Test %v: unwanted error joining game: %v", i, err)
+		case test.wantNumPlayers != len(test.Game.players):
+			t.Errorf("Test %v: wanted %v players in game after join/rejoin, got %v", i, test.wantNumPlayers, len(test.Game.players))
 		}
-	})
+	}
 }
 
-func TestSendMessage(t *testing.T) {
-	sendMessageTests := []struct {
-		Game
+func TestHandleAddPlayer(t *testing.T) {
+	withConfig := func(b *board.Board, cfg board.Config) *board.Board {
+		b.Config = cfg
+		b.Revision = 1 // the board is resized when the player is added
+		return b
+	}
+	hasPlayer := func(players []string, player string) bool {
+		return slices.Contains(players, player)
+	}
+	handleAddPlayerTests := []struct {
 		message.Message
-		wantGameID game.ID
+		Game
+		wantOk          bool
+		wantUnusedTiles []tile.Tile
+		wantPlayer      *playerController.Player
+		wantHost        player.Name
 	}{
-		{ // no game on message
-			Game: Game{
-				id: 1,
+		{ // board config error
+			Message: message.Message{
+				Game: &game.Info{
+					Board: new(board.Board),
+				},
 			},
-			wantGameID: 1,
 		},
-		{
+		{ // player config error
+			Message: message.Message{
+				Game: &game.Info{
+					Board: &board.Board{
+						Config: board.Config{
+							NumRows: 79,
+							NumCols: 28,
+						},
+					},
+				},
+			},
 			Game: Game{
-				id: 2,
+				Config: Config{
+					PlayerCfg: playerController.Config{
+						WinPoints: -1,
+					},
+				},
 			},
+		},
+		{ // happy path
 			Message: message.Message{
+				PlayerName: "young",
 				Game: &game.Info{
-					ID: 2,
+					Board: &board.Board{
+						Config: board.Config{
+							NumRows: 79,
+							NumCols: 28,
+						},
+					},
 				},
 			},
-			wantGameID: 2,
-		},
-		{ // should be overwritten because it is coming from game 2
 			Game: Game{
-				id: 3,
+				Config: Config{
+					NumNewTiles: 2,
+					PlayerCfg: playerController.Config{
+						WinPoints: 7,
+					},
+				},
+				players: map[player.Name]*playerController.Player{
+					"crosby": {},
+					"stills": {},
+					"nash":   {},
+				},
+				host:        "crosby",
+				unusedTiles: []tile.Tile{{ID: 11}, {ID: 22}, {ID: 3}, {ID: 6}, {ID: 2}},
+			},
+			wantOk:          true,
+			wantUnusedTiles: []tile.Tile{{ID: 3}, {ID: 6}, {ID: 2}},
+			wantPlayer: &playerController.Player{
+				WinPoints: 7,
+				Board: withConfig(
+					board.New([]tile.Tile{{ID: 11}, {ID: 22}}, nil),
+					board.Config{
+						NumRows: 79,
+						NumCols: 28,
+					}),
 			},
+			wantHost: "crosby",
+		},
+		{ // first player is host
 			Message: message.Message{
+				PlayerName: "young",
 				Game: &game.Info{
-					ID: 4,
+					Board: &board.Board{
+						Config: board.Config{
+							NumRows: 79,
+							NumCols: 28,
+						},
+					},
 				},
 			},
-			wantGameID: 3,
-		},
-	}
-	for i, test := range sendMessageTests {
-		out := make(chan message.Message, 1)
-		send := test.Game.sendMessage(out)
-		send(test.Message)
-		got := <-out
-		gotGameID := got.Game.ID
-		if test.wantGameID != gotGameID {
-			t.Errorf("Test %v: game ids not equal: wanted %v, got %v", i, test.wantGameID, got.Game.ID)
-		}
-	}
-}
-
-func TestInitializeUnusedTiles(t *testing.T) {
-	initializeUnusedTilesTests := []struct {
-		tileLetters string
-		wantOk      bool
-		want        []tile.Tile
-	}{
-		{
-			tileLetters: ":(",
-		},
-		{
-			tileLetters: "AAABAC",
-			wantOk:      true,
-			want: []tile.Tile{
-				{ID: 6, Ch: 'C'},
-				{ID: 4, Ch: 'B'},
-				{ID: 1, Ch: 'A'},
-				{ID: 2, Ch: 'A'},
-				{ID: 3, Ch: 'A'},
-				{ID: 5, Ch: 'A'},
+			Game: Game{
+				Config: Config{
+					NumNewTiles: 1,
+					PlayerCfg: playerController.Config{
+						WinPoints: 7,
+					},
+				},
+				players:     map[player.Name]*playerController.Player{},
+				unusedTiles: []tile.Tile{{ID: 11}},
+			},
+			wantOk:          true,
+			wantUnusedTiles: []tile.Tile{},
+			wantPlayer: &playerController.Player{
+				WinPoints: 7,
+				Board: withConfig(
+					board.New([]tile.Tile{{ID: 11}}, nil),
+					board.Config{
+						NumRows: 79,
+						NumCols: 28,
+					}),
 			},
+			wantHost: "young",
 		},
 	}
-	for i, test := range initializeUnusedTilesTests {
-		shuffleFunc := func(tiles []tile.Tile) {
-			// sort tiles by letter descending, id ascending
-			sort.Slice(tiles, func(i, j int) bool {
-				if tiles[i].Ch == tiles[j].Ch {
-					return tiles[i].ID < tiles[j].ID
-				}
-				return tiles[i].Ch > tiles[j].Ch
-			})
+	for i, test := range handleAddPlayerTests {
+		ctx := context.Background()
+		gotInfoChanged := false
+		gotMessages := make(map[player.Name]struct{}, len(test.Game.players))
+		wantTilesLeft := len(test.wantUnusedTiles)
+		send := func(m message.Message) {
+			if m.Type == message.GameInfos {
+				gotInfoChanged = true
+				return
+			}
+			pn := m.PlayerName
+			if _, ok := test.Game.players[pn]; !ok {
+				t.Errorf("message sent to unknown player when adding player: %v", m)
+			}
+			if _, ok := gotMessages[pn]; ok {
+				t.Errorf("extra message sent to when adding player %v: %v", pn, m)
+			}
+			gotMessages[pn] = struct{}{}
+			switch {
+			case wantTilesLeft != m.Game.TilesLeft:
+				t.Errorf("Test %v: tilesLeft in message sent to %v not equal: wanted %v, got: %v", i, pn, wantTilesLeft, m.Game.TilesLeft)
+			case !hasPlayer(m.Game.Players, string(test.Message.PlayerName)):
+				t.Errorf("Test %v: wanted new player %v to be in players slice, got %v", i, test.Message.PlayerName, m.Game.Players)
+			case pn == test.Message.PlayerName && m.Game.Board == nil:
+				t.Errorf("Test %v: wanted board resize info sent to new player (%v), got %v", i, test.Message.PlayerName, m)
+			case m.Game.PlayerPoints[string(test.Message.PlayerName)] != 37:
+				t.Errorf("Test %v: wanted points of new player (%v) in message sent to %v, got %v", i, test.Message.PlayerName, pn, m.Game.PlayerPoints)
+			}
 		}
-		g := Game{
-			Config: Config{
-				TileLetters:            test.tileLetters,
-				ShuffleUnusedTilesFunc: shuffleFunc,
+		test.Game.TimeFunc = func() int64 { return 0 }
+		test.Game.userPoints = make(map[player.Name]int)
+		test.Game.userDao = mockUserDao{
+			ReadPointsFunc: func(ctx context.Context, username string) (int, error) {
+				return 37, nil
 			},
 		}
-		err := g.initializeUnusedTiles()
+		err := test.Game.handleAddPlayer(ctx, test.Message, send)
 		switch {
 		case !test.wantOk:
 			if err == nil {
-				t.Errorf("Test %v: wanted error initializing unused tiles", i)
+				t.Errorf("Test %v: wanted error adding player", i)
 			}
 		case err != nil:
-			t.Errorf("Test %v: unwanted error initializing unused tiles: %v", i, err)
-		case !reflect.DeepEqual(test.want, g.unusedTiles):
-			t.Errorf("Test %v: unusedTiles not equal:\nwanted: %v\ngot:    %v", i, test.want, g.unusedTiles)
+			t.Errorf("Test %v: unwanted error adding player: %v", i, err)
+		case len(test.Game.players) != len(gotMessages):
+			t.Errorf("Test %v: wanted messages sent to all players but the new one (%v total), got %v", i, len(test.Game.players), len(gotMessages))
+		case !gotInfoChanged:
+			t.Errorf("Test %v: wanted to get message to change game info", i)
+		case !reflect.DeepEqual(test.wantUnusedTiles, test.Game.unusedTiles):
+			t.Errorf("Test %v: game unused tiles not equal after adding new player:\nwanted: %v\ngot:    %v", i, test.wantUnusedTiles, test.Game.unusedTiles)
+		case !reflect.DeepEqual(test.wantPlayer, test.Game.players[test.Message.PlayerName]):
+			t.Errorf("Test %v: new player not equal:\nwanted: %v\ngot:    %v", i, test.wantPlayer, test.Game.players[test.Message.PlayerName])
+		case test.wantHost != test.Game.host:
+			t.Errorf("Test %v: hosts not equal: wanted %v, got %v", i, test.wantHost, test.Game.host)
 		}
 	}
 }
 
-func TestUpdateUserPoints(t *testing.T) {
-	want := fmt.Errorf("calling UpdatePointsIncrement")
+func TestHandleAddPlayerBotReady(t *testing.T) {
+	g := Game{
+		status:     game.NotStarted,
+		players:    map[player.Name]*playerController.Player{},
+		userPoints: map[player.Name]int{},
+		Config: Config{
+			TimeFunc:    func() int64 { return 0 },
+			NumNewTiles: 1,
+			PlayerCfg: playerController.Config{
+				WinPoints: 7,
+			},
+		},
+		unusedTiles: []tile.Tile{{ID: 1}},
+	}
+	ctx := context.Background()
+	m := message.Message{
+		PlayerName: "bot-hard-1",
+		Game: &game.Info{
+			Board: &board.Board{
+				Config: board.Config{
+					NumRows: 10,
+					NumCols: 10,
+				},
+			},
+		},
+	}
+	send := func(m message.Message) {}
+	if err := g.handleAddPlayer(ctx, m, send); err != nil {
+		t.Fatalf("unwanted error: %v", err)
+	}
+	if !g.players["bot-hard-1"].Ready {
+		t.Errorf("wanted bot to be ready when it joins the game")
+	}
+}
+
+func TestHandleAddPlayerTeams(t *testing.T) {
+	g := Game{
+		status:     game.NotStarted,
+		players:    map[player.Name]*playerController.Player{},
+		userPoints: map[player.Name]int{"fred": 1, "barney": 2, "wilma": 3},
+		Config: Config{
+			TimeFunc:    func() int64 { return 0 },
+			NumNewTiles: 2,
+			PlayerCfg: playerController.Config{
+				WinPoints: 7,
+			},
+			Config: game.Config{
+				TeamSize: 2,
+			},
+		},
+		unusedTiles: []tile.Tile{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}, {ID: 5}, {ID: 6}},
+	}
 	ctx := context.Background()
-	wantUserPoints := map[string]int{
-		"alice":  1,
-		"bob":    1,
-		"selene": 5,
+	joinMessage := func(pn player.Name, numRows, numCols int) message.Message {
+		return message.Message{
+			PlayerName: pn,
+			Game: &game.Info{
+				Board: &board.Board{
+					Config: board.Config{
+						NumRows: numRows,
+						NumCols: numCols,
+					},
+				},
+			},
+		}
 	}
-	userDao := mockUserDao{
-		UpdatePointsIncrementFunc: func(ctx context.Context, gotUserPoints map[string]int) error {
-			switch {
-			case ctx == nil:
-				return fmt.Errorf("context missing")
-			case !reflect.DeepEqual(wantUserPoints, gotUserPoints):
-				return fmt.Errorf("user points not equal\nwanted: %v\ngot:    %v", wantUserPoints, gotUserPoints)
-			}
-			return want
-		},
+	var gotTeamBoards []message.Message
+	send := func(m message.Message) {
+		if m.PlayerName == "fred" && m.Game != nil && m.Game.Board != nil {
+			gotTeamBoards = append(gotTeamBoards, m)
+		}
 	}
-	alicePlayer, err := playerController.Config{WinPoints: 4}.New(new(board.Board))
-	if err != nil {
-		t.Errorf("unwanted error adding alice player: %v", err)
+	for _, m := range []message.Message{
+		joinMessage("fred", 10, 20),
+		joinMessage("barney", 15, 12),
+		joinMessage("wilma", 10, 10),
+	} {
+		if err := g.handleAddPlayer(ctx, m, send); err != nil {
+			t.Fatalf("unwanted error adding %v: %v", m.PlayerName, err)
+		}
 	}
-	selenePlayer, err := playerController.Config{WinPoints: 5}.New(new(board.Board))
-	if err != nil {
-		t.Errorf("unwanted error adding selene player: %v", err)
+	fred, barney, wilma := g.players["fred"], g.players["barney"], g.players["wilma"]
+	wantBoardConfig := board.Config{NumRows: 10, NumCols: 12}
+	switch {
+	case fred.Team != 1, barney.Team != 1, wilma.Team != 2:
+		t.Errorf("wanted fred and barney on team 1 and wilma on team 2, got %v, %v, %v", fred.Team, barney.Team, wilma.Team)
+	case fred.Board != barney.Board:
+		t.Errorf("wanted teammates to share a board")
+	case fred.Board == wilma.Board:
+		t.Errorf("wanted players on different teams to have different boards")
+	case len(g.unusedTiles) != 2:
+		t.Errorf("wanted new tiles only for each team, got %v unused tiles", len(g.unusedTiles))
+	case fred.Board.Config != wantBoardConfig:
+		t.Errorf("wanted shared board to fit the screens of both teammates (%v), got %v", wantBoardConfig, fred.Board.Config)
+	case len(gotTeamBoards) != 2, gotTeamBoards[1].Type != message.ChangeGameTiles:
+		t.Errorf("wanted the shared board to be sent to fred when barney joined, got %v", gotTeamBoards)
+	case len(g.events) != 3, g.events[1].Teammate != "fred", len(g.events[1].Tiles) != 0:
+		t.Errorf("wanted barney to join the board of fred in the replay, got %v", g.events)
 	}
+	want := map[string]int{"fred": 1, "barney": 1, "wilma": 2}
+	if got := g.teams(); !reflect.DeepEqual(want, got) {
+		t.Errorf("teams not equal:\nwanted: %v\ngot:    %v", want, got)
+	}
+}
+
+func TestHandleGameDelete(t *testing.T) {
 	g := Game{
 		players: map[player.Name]*playerController.Player{
-			"alice":  alicePlayer,
-			"selene": selenePlayer,
-			"bob":    {},
+			"larry": {},
+			"curly": {},
+			"moe":   {},
 		},
-		userDao: userDao,
+		host: "moe",
 	}
-	got := g.updateUserPoints(ctx, "selene")
-	if want != got {
-		t.Errorf("wanted error %v, got %v", want, got)
+	ctx := context.Background()
+	m := message.Message{
+		PlayerName: "moe",
+	}
+	gotMessages := make(map[player.Name]struct{}, len(g.players))
+	gotInfoChanged := false
+	send := func(m message.Message) {
+		switch m.Type {
+		case message.GameInfos:
+			gotInfoChanged = true
+			return
+		case message.LeaveGame:
+			// NOOP
+		default:
+			t.Errorf("wanted leave game message, got %v", m.Type)
+		}
+		pn := m.PlayerName
+		if _, ok := g.players[pn]; !ok {
+			t.Errorf("message sent to unknown player when deleting game: %v", m)
+		}
+		if _, ok := gotMessages[pn]; ok {
+			t.Errorf("extra message sent to %v when deleting game: %v", pn, m)
+		}
+		gotMessages[pn] = struct{}{}
+	}
+	err := g.handleGameDelete(ctx, m, send)
+	switch {
+	case err != nil:
+		t.Errorf("unwanted error: %v", err)
+	case len(gotMessages) != 3:
+		t.Errorf("wanted messages sent to all players (%v) when deleting game, got %v", len(g.players), len(gotMessages))
+	case g.status != game.Deleted:
+		t.Errorf("wanted game status to be deleted, got %v", g.status)
+	case !gotInfoChanged:
+		t.Errorf("wanted to get message to change game info")
 	}
 }
 
-func TestPlayerNames(t *testing.T) {
+func TestHandleGameDeleteNotHost(t *testing.T) {
 	g := Game{
+		status: game.InProgress,
 		players: map[player.Name]*playerController.Player{
-			"b": {},
-			"c": {},
-			"a": {},
+			"larry": {},
+			"moe":   {},
 		},
+		host: "moe",
 	}
-	want := []string{"a", "b", "c"}
-	got := g.playerNames()
-	if !reflect.DeepEqual(want, got) {
-		t.Errorf("player names not equal/sorted:\nwanted: %v\ngot:    %v", want, got)
+	ctx := context.Background()
+	m := message.Message{
+		PlayerName: "larry",
+	}
+	send := func(m message.Message) {
+		t.Errorf("unwanted message sent: %v", m)
+	}
+	err := g.handleGameDelete(ctx, m, send)
+	w, ok := err.(gameWarning)
+	switch {
+	case !ok, w.code != message.CodeNotHost:
+		t.Errorf("wanted not host warning, got %v", err)
+	case g.status != game.InProgress:
+		t.Errorf("wanted game status to not change, got %v", g.status)
 	}
 }
 
-func TestHandleMessage(t *testing.T) {
-	handleMessageTests := []struct {
-		message.Message
-		Game
-		messageHandlers map[message.Type]messageHandler
-		wantSendType    message.Type
-		wantActive      bool
-		wantLog         bool
+func TestHandleGameLeave(t *testing.T) {
+	newPlayer := func(id tile.ID) *playerController.Player {
+		return &playerController.Player{
+			Board: board.New([]tile.Tile{{ID: id}}, nil),
+		}
+	}
+	handleGameLeaveTests := []struct {
+		status          game.Status
+		autoStart       bool
+		players         map[player.Name]*playerController.Player
+		playerName      player.Name
+		wantHost        player.Name
+		wantPlayers     int
+		wantUnusedTiles int
+		wantMessages    int
 	}{
-		{ // unknown message type #1
-			wantSendType: message.SocketError,
-		},
-		{ // unknown message type #2
-			Message: message.Message{
-				Type: message.CreateGame,
-			},
-			messageHandlers: map[message.Type]messageHandler{
-				message.JoinGame: func(ctx context.Context, m message.Message, send messageSender) error {
-					return nil
-				},
-			},
-			wantSendType: message.SocketError,
-		},
-		{ // unknown message type #3 with debug
-			Game: Game{
-				Config: Config{
-					Debug: true,
-				},
-			},
-			wantSendType: message.SocketError,
-			wantLog:      true,
-		},
-		{ // unknown player
-			Message: message.Message{
-				Type:       message.SnagGameTile,
-				PlayerName: "ghost",
-			},
-			Game: Game{
-				players: map[player.Name]*playerController.Player{
-					"selene": nil,
-				},
-			},
-			messageHandlers: map[message.Type]messageHandler{
-				message.SnagGameTile: func(ctx context.Context, m message.Message, send messageSender) error {
-					return nil
-				},
+		{ // not host
+			status: game.InProgress,
+			players: map[player.Name]*playerController.Player{
+				"larry": {},
+				"moe":   {},
 			},
-			wantSendType: message.SocketError,
+			playerName:  "larry",
+			wantHost:    "moe",
+			wantPlayers: 2,
 		},
-		{ // message handler error
-			Message: message.Message{
-				Type:       message.SnagGameTile,
-				PlayerName: "selene",
-			},
-			Game: Game{
-				players: map[player.Name]*playerController.Player{
-					"selene": nil,
-				},
-			},
-			messageHandlers: map[message.Type]messageHandler{
-				message.SnagGameTile: func(ctx context.Context, m message.Message, send messageSender) error {
-					return fmt.Errorf("snag tile error")
-				},
+		{ // only player
+			status: game.InProgress,
+			players: map[player.Name]*playerController.Player{
+				"moe": {},
 			},
-			wantSendType: message.SocketError,
-			wantActive:   true,
+			playerName:  "moe",
+			wantHost:    "moe",
+			wantPlayers: 1,
 		},
-		{ // message handler error game warning
-			Message: message.Message{
-				Type:       message.SnagGameTile,
-				PlayerName: "selene",
-			},
-			Game: Game{
-				players: map[player.Name]*playerController.Player{
-					"selene": nil,
-				},
-			},
-			messageHandlers: map[message.Type]messageHandler{
-				message.SnagGameTile: func(ctx context.Context, m message.Message, send messageSender) error {
-					return gameWarning("snag tile warning")
-				},
-			},
-			wantSendType: message.SocketWarning,
-			wantActive:   true,
+		{
+			status: game.InProgress,
+			players: map[player.Name]*playerController.Player{
+				"larry": {},
+				"curly": {},
+				"moe":   {},
+			},
+			playerName:   "moe",
+			wantHost:     "curly",
+			wantPlayers:  3,
+			wantMessages: 4, // the players and the game infos
 		},
-		{ // message ok
-			Message: message.Message{
-				Type:       message.SwapGameTile,
-				PlayerName: "selene",
-			},
-			Game: Game{
-				players: map[player.Name]*playerController.Player{
-					"selene": nil,
-				},
-			},
-			messageHandlers: map[message.Type]messageHandler{
-				message.SnagGameTile: func(ctx context.Context, m message.Message, send messageSender) error {
-					return fmt.Errorf("wrong handler")
-				},
-				message.SwapGameTile: func(ctx context.Context, m message.Message, send messageSender) error {
-					m2 := message.Message{
-						Type: message.ChangeGameTiles,
-					}
-					send(m2)
-					return nil
-				},
-			},
-			wantSendType: message.ChangeGameTiles,
-			wantActive:   true,
+		{ // not host, game not started
+			status: game.NotStarted,
+			players: map[player.Name]*playerController.Player{
+				"larry": newPlayer(1),
+				"moe":   newPlayer(2),
+			},
+			playerName:      "larry",
+			wantHost:        "moe",
+			wantPlayers:     1,
+			wantUnusedTiles: 1,
+			wantMessages:    2,
 		},
-		{ // message ok with no want send type (normal snag sends no message)
-			Message: message.Message{
-				Type:       message.SnagGameTile,
-				PlayerName: "selene",
-			},
-			Game: Game{
-				players: map[player.Name]*playerController.Player{
-					"selene": nil,
-				},
-			},
-			messageHandlers: map[message.Type]messageHandler{
-				message.SnagGameTile: func(ctx context.Context, m message.Message, send messageSender) error {
-					return nil
-				},
+		{ // only player, game not started
+			status: game.NotStarted,
+			players: map[player.Name]*playerController.Player{
+				"moe": newPlayer(1),
 			},
-			wantActive: true,
+			playerName:      "moe",
+			wantUnusedTiles: 1,
+			wantMessages:    1, // the game infos
 		},
-		{ // message ok with debug
-			Message: message.Message{
-				Type:       message.SnagGameTile,
-				PlayerName: "selene",
-			},
-			Game: Game{
-				players: map[player.Name]*playerController.Player{
-					"selene": nil,
-				},
-				Config: Config{
-					Debug: true,
-				},
-			},
-			messageHandlers: map[message.Type]messageHandler{
-				message.SnagGameTile: func(ctx context.Context, m message.Message, send messageSender) error {
-					return nil
-				},
+		{ // only player, auto started game not started
+			status:    game.NotStarted,
+			autoStart: true,
+			players: map[player.Name]*playerController.Player{
+				"moe": newPlayer(1),
 			},
-			wantActive: true,
-			wantLog:    true,
+			playerName:      "moe",
+			wantUnusedTiles: 1,
+			wantMessages:    1, // the empty game is not started
+		},
+		{ // game not started
+			status: game.NotStarted,
+			players: map[player.Name]*playerController.Player{
+				"larry": newPlayer(1),
+				"curly": newPlayer(2),
+				"moe":   newPlayer(3),
+			},
+			playerName:      "moe",
+			wantHost:        "curly",
+			wantPlayers:     2,
+			wantUnusedTiles: 1,
+			wantMessages:    3,
 		},
 	}
-	for i, test := range handleMessageTests {
+	for i, test := range handleGameLeaveTests {
+		shuffled := false
+		g := Game{
+			status:     test.status,
+			players:    test.players,
+			host:       "moe",
+			userPoints: map[player.Name]int{},
+			Config: Config{
+				TimeFunc: func() int64 { return 0 },
+				ShuffleUnusedTilesFunc: func(tiles []tile.Tile) {
+					shuffled = true
+				},
+				Config: game.Config{
+					AutoStart: test.autoStart,
+				},
+			},
+		}
 		ctx := context.Background()
-		log := new(logtest.Logger)
-		test.Game.log = log
-		gotSend := false
+		m := message.Message{
+			Type:       message.LeaveGame,
+			PlayerName: test.playerName,
+		}
+		var gotMessages []message.Message
 		send := func(m message.Message) {
-			gotSend = true
-			switch {
-			case m.Type != test.wantSendType:
-				t.Errorf("Test %v: wanted message sent with type %v, got %v", i, m.Type, test.wantSendType)
-			case m.Type == message.SocketError, m.Type == message.SocketWarning:
-				if m.PlayerName != test.Message.PlayerName || !reflect.DeepEqual(test.Message.Game, m.Game) || len(m.Info) == 0 {
-					t.Errorf("Test: %v wanted message for %v with game and error info, got %v", i, test.Message.PlayerName, m)
-				}
-			}
+			gotMessages = append(gotMessages, m)
 		}
-		active := false
-		test.Game.handleMessage(ctx, test.Message, send, &active, test.messageHandlers)
+		err := g.handleGameLeave(ctx, m, send)
 		switch {
-		case active != test.wantActive:
-			t.Errorf("Test %v: wanted active flag to be %v after handler was run, got %v", i, active, test.wantActive)
-		case test.wantLog != !log.Empty():
-			t.Errorf("Test %v: wanted message logged (%v), got %v", i, test.wantLog, !log.Empty())
-		case test.wantSendType != 0 && !gotSend:
-			t.Errorf("Test %v: wanted message to be sent", i)
+		case err != nil:
+			t.Errorf("Test %v: unwanted error: %v", i, err)
+		case test.wantHost != g.host:
+			t.Errorf("Test %v: hosts not equal: wanted %v, got %v", i, test.wantHost, g.host)
+		case test.status != g.status:
+			t.Errorf("Test %v: wanted status to stay %v when player leaves, got %v", i, test.status, g.status)
+		case test.wantPlayers != len(g.players):
+			t.Errorf("Test %v: wanted %v players to be left in the game, got %v", i, test.wantPlayers, len(g.players))
+		case test.wantUnusedTiles != len(g.unusedTiles):
+			t.Errorf("Test %v: wanted %v tiles to be returned to the unused tiles, got %v", i, test.wantUnusedTiles, g.unusedTiles)
+		case test.wantUnusedTiles != 0 && !shuffled:
+			t.Errorf("Test %v: wanted returned tiles to be shuffled", i)
+		case test.wantMessages != len(gotMessages):
+			t.Errorf("Test %v: wanted %v messages, got %v", i, test.wantMessages, len(gotMessages))
+		default:
+			for _, m2 := range gotMessages {
+				if m2.Game.Host != string(test.wantHost) {
+					t.Errorf("Test %v: wanted new host in message, got %v", i, m2)
+				}
+			}
 		}
 	}
 }
 
-func TestHandleGameJoin(t *testing.T) {
-	handleGameJoinTests := []struct {
-		message.Message
-		Game
-		wantOk         bool
-		wantNumPlayers int
+func TestHandleGameForfeit(t *testing.T) {
+	handleGameForfeitTests := []struct {
+		status               game.Status
+		forfeited            bool
+		otherForfeited       bool
+		wantOk               bool
+		wantStatus           game.Status
+		wantForfeitedPlayers []string
+		wantMessageType      message.Type
 	}{
-		{ // rejoin
-			Message: message.Message{
-				PlayerName: "selene",
-				Game: &game.Info{
-					Board: new(board.Board),
-				},
-			},
-			Game: Game{
-				players: map[player.Name]*playerController.Player{
-					"selene": {
-						Board: new(board.Board),
-					},
-				},
-			},
-			wantOk:         true,
-			wantNumPlayers: 1,
+		{ // game not started
+			status:     game.NotStarted,
+			wantStatus: game.NotStarted,
 		},
-		{}, // game not started
-		{ // no room for new player
-			Game: Game{
-				status: game.NotStarted,
-			},
+		{ // already forfeited
+			status:     game.InProgress,
+			forfeited:  true,
+			wantStatus: game.InProgress,
 		},
-		{ // not enough tiles for new player
-			Game: Game{
-				status: game.NotStarted,
-				Config: Config{
-					MaxPlayers:  4,
-					NumNewTiles: 12,
-				},
-			},
+		{
+			status:               game.InProgress,
+			wantOk:               true,
+			wantStatus:           game.InProgress,
+			wantForfeitedPlayers: []string{"fred"},
+			wantMessageType:      message.ChangeGameTiles,
 		},
-		{ // add player, error
-			Message: message.Message{
-				PlayerName: "young",
-				Game: &game.Info{
-					Board: &board.Board{
-						Config: board.Config{
-							NumRows: 79,
-							NumCols: 28,
-						},
-					},
-				},
-			},
-			Game: Game{
-				status: game.NotStarted,
-				Config: Config{
-					MaxPlayers: 3,
-					PlayerCfg: playerController.Config{
-						WinPoints: 7,
-					},
-				},
-				players: map[player.Name]*playerController.Player{
-					"crosby": {},
-					"stills": {},
-					"nash":   {},
-				},
-			},
+		{ // every player forfeited
+			status:          game.InProgress,
+			otherForfeited:  true,
+			wantOk:          true,
+			wantStatus:      game.Finished,
+			wantMessageType: message.ChangeGameStatus,
 		},
-		{ // add player
-			Message: message.Message{
-				PlayerName: "young",
-				Game: &game.Info{
-					Board: &board.Board{
-						Config: board.Config{
-							NumRows: 79,
-							NumCols: 28,
-						},
-					},
-				},
+	}
+	for i, test := range handleGameForfeitTests {
+		g := Game{
+			status: test.status,
+			players: map[player.Name]*playerController.Player{
+				"barney": {Forfeited: test.otherForfeited, Board: new(board.Board)},
+				"fred":   {Forfeited: test.forfeited, Board: new(board.Board)},
 			},
-			Game: Game{
-				status: game.NotStarted,
-				Config: Config{
-					MaxPlayers: 4,
-					PlayerCfg: playerController.Config{
-						WinPoints: 7,
-					},
-				},
-				players: map[player.Name]*playerController.Player{
-					"crosby": {},
-					"stills": {},
-					"nash":   {},
-				},
+			Config: Config{
+				TimeFunc: func() int64 { return 0 },
 			},
-			wantOk:         true,
-			wantNumPlayers: 4,
-		},
-	}
-	for i, test := range handleGameJoinTests {
+		}
 		ctx := context.Background()
-		messageSent := false
+		m := message.Message{
+			Type:       message.Forfeit,
+			PlayerName: "fred",
+		}
+		playerMessages := make(map[player.Name]message.Message)
 		send := func(m message.Message) {
-			messageSent = true
-			if !test.wantOk && (m.Type != message.LeaveGame || m.PlayerName != test.Message.PlayerName) {
-				t.Errorf("Test %v: wanted leavegame message sent to %v if an error occurred, got %v", i, test.Message.PlayerName, m)
+			if len(m.PlayerName) != 0 {
+				playerMessages[m.PlayerName] = m
 			}
 		}
-		err := test.Game.handleGameJoin(ctx, test.Message, send)
+		err := g.handleGameForfeit(ctx, m, send)
 		switch {
-		case !messageSent:
-			t.Errorf("Test %v: wanted at least one message sent after a join-game attempt", i)
 		case !test.wantOk:
-			if err == nil {
-				t.Errorf("Test %v: wanted error joining game", i)
+			if _, ok := err.(gameWarning); !ok {
+				t.Errorf("Test %v: wanted warning, got %v", i, err)
 			}
 		case err != nil:
-			t.Errorf("Test %v: unwanted error joining game: %v", i, err)
-		case test.wantNumPlayers != len(test.Game.players):
-			t.Errorf("Test %v: wanted %v players in game after join/rejoin, got %v", i, test.wantNumPlayers, len(test.Game.players))
+			t.Errorf("Test %v: unwanted error: %v", i, err)
+		case !g.players["fred"].Forfeited:
+			t.Errorf("Test %v: wanted player to forfeit", i)
+		case len(playerMessages) != 2:
+			t.Errorf("Test %v: wanted both players to be notified, got %v", i, playerMessages)
+		default:
+			for pn, m2 := range playerMessages {
+				switch {
+				case test.wantMessageType != m2.Type:
+					t.Errorf("Test %v: message types sent to %v not equal: wanted %v, got %v", i, pn, test.wantMessageType, m2.Type)
+				case !reflect.DeepEqual(test.wantForfeitedPlayers, m2.Game.ForfeitedPlayers):
+					t.Errorf("Test %v: forfeited players sent to %v not equal:\nwanted: %v\ngot:    %v", i, pn, test.wantForfeitedPlayers, m2.Game.ForfeitedPlayers)
+				}
+			}
+		}
+		if want, got := test.wantStatus, g.status; want != got {
+			t.Errorf("Test %v: statuses not equal: wanted %v, got %v", i, want, got)
 		}
 	}
 }
 
-func TestHandleAddPlayer(t *testing.T) {
-	withConfig := func(b *board.Board, cfg board.Config) *board.Board {
-		b.Config = cfg
-		return b
+func TestForfeitedPlayerBoardFrozen(t *testing.T) {
+	g := Game{
+		status: game.InProgress,
+		players: map[player.Name]*playerController.Player{
+			"fred": {
+				Forfeited: true,
+				Board:     board.New([]tile.Tile{{ID: 1, Ch: 'A'}}, nil),
+			},
+		},
+		unusedTiles: []tile.Tile{{ID: 2, Ch: 'B'}},
+		Config: Config{
+			Config: game.Config{
+				Hints: true,
+			},
+		},
 	}
-	hasPlayer := func(players []string, player string) bool {
-		return slices.Contains(players, player)
+	ctx := context.Background()
+	removeDiff := g.players["fred"].Board.NewDiff(board.OpRemove, tile.Position{Tile: tile.Tile{ID: 1, Ch: 'A'}})
+	moveDiff := g.players["fred"].Board.NewDiff(board.OpMove, tile.Position{Tile: tile.Tile{ID: 1, Ch: 'A'}, X: 1, Y: 1})
+	handlers := []struct {
+		name string
+		messageHandler
+		*board.Diff
+	}{
+		{"snag", g.handleGameSnag, nil},
+		{"swap", g.handleGameSwap, &removeDiff},
+		{"move", g.handleGameTilesMoved, &moveDiff},
+		{"hint", g.handleGameHint, nil},
 	}
-	handleAddPlayerTests := []struct {
+	for _, test := range handlers {
+		m := message.Message{
+			PlayerName: "fred",
+			Game: &game.Info{
+				Diff: test.Diff,
+			},
+		}
+		send := func(m message.Message) {
+			t.Errorf("%v: unwanted message sent to player who forfeited: %v", test.name, m)
+		}
+		err := test.messageHandler(ctx, m, send)
+		if w, ok := err.(gameWarning); !ok || w.code != message.CodeForfeited {
+			t.Errorf("%v: wanted forfeited warning, got %v", test.name, err)
+		}
+	}
+	g.unusedTiles = nil
+	m := message.Message{
+		PlayerName: "fred",
+	}
+	send := func(m message.Message) {
+		t.Errorf("finish: unwanted message sent to player who forfeited: %v", m)
+	}
+	if err := g.handleGameFinish(ctx, m, send); err != gameWarningForfeited {
+		t.Errorf("finish: wanted forfeited warning, got %v", err)
+	}
+	if want, got := 1, len(g.players["fred"].Board.UnusedTiles); want != got {
+		t.Errorf("wanted board of player who forfeited to not change, got %v", g.players["fred"].Board)
+	}
+}
+
+func TestHandleGameKick(t *testing.T) {
+	handleGameKickTests := []struct {
 		message.Message
-		Game
+		status          game.Status
 		wantOk          bool
+		wantCode        message.Code
 		wantUnusedTiles []tile.Tile
-		wantPlayer      *playerController.Player
 	}{
-		{ // board config error
+		{ // not host
 			Message: message.Message{
+				PlayerName: "larry",
 				Game: &game.Info{
-					Board: new(board.Board),
+					Players: []string{"curly"},
 				},
 			},
+			wantCode: message.CodeNotHost,
 		},
-		{ // player config error
+		{ // no player to kick
+			Message: message.Message{
+				PlayerName: "moe",
+				Game:       &game.Info{},
+			},
+			wantCode: message.CodeNotInGame,
+		},
+		{ // unknown player
 			Message: message.Message{
+				PlayerName: "moe",
 				Game: &game.Info{
-					Board: &board.Board{
-						Config: board.Config{
-							NumRows: 79,
-							NumCols: 28,
-						},
-					},
+					Players: []string{"curly", "shemp"},
 				},
 			},
-			Game: Game{
-				Config: Config{
-					PlayerCfg: playerController.Config{
-						WinPoints: -1,
-					},
+			wantCode: message.CodeNotInGame,
+		},
+		{ // kick host
+			Message: message.Message{
+				PlayerName: "moe",
+				Game: &game.Info{
+					Players: []string{"moe"},
 				},
 			},
+			wantCode: message.CodeNotInGame,
 		},
-		{ // happy path
+		{ // not started: tiles returned
 			Message: message.Message{
-				PlayerName: "young",
+				PlayerName: "moe",
 				Game: &game.Info{
-					Board: &board.Board{
-						Config: board.Config{
-							NumRows: 79,
-							NumCols: 28,
-						},
-					},
+					Players: []string{"curly"},
 				},
 			},
-			Game: Game{
-				Config: Config{
-					NumNewTiles: 2,
-					PlayerCfg: playerController.Config{
-						WinPoints: 7,
-					},
-				},
-				players: map[player.Name]*playerController.Player{
-					"crosby": {},
-					"stills": {},
-					"nash":   {},
+			status:          game.NotStarted,
+			wantOk:          true,
+			wantUnusedTiles: []tile.Tile{{ID: 1, Ch: 'A'}, {ID: 3, Ch: 'C'}, {ID: 4, Ch: 'D'}},
+		},
+		{ // in progress: tiles not returned
+			Message: message.Message{
+				PlayerName: "moe",
+				Game: &game.Info{
+					Players: []string{"curly"},
 				},
-				unusedTiles: []tile.Tile{{ID: 11}, {ID: 22}, {ID: 3}, {ID: 6}, {ID: 2}},
 			},
+			status:          game.InProgress,
 			wantOk:          true,
-			wantUnusedTiles: []tile.Tile{{ID: 3}, {ID: 6}, {ID: 2}},
-			wantPlayer: &playerController.Player{
-				WinPoints: 7,
-				Board: withConfig(
-					board.New([]tile.Tile{{ID: 11}, {ID: 22}}, nil),
-					board.Config{
-						NumRows: 79,
-						NumCols: 28,
-					}),
+			wantUnusedTiles: []tile.Tile{{ID: 1, Ch: 'A'}},
+		},
+		{ // same player twice: kicked once
+			Message: message.Message{
+				PlayerName: "moe",
+				Game: &game.Info{
+					Players: []string{"curly", "curly"},
+				},
 			},
+			status:          game.NotStarted,
+			wantOk:          true,
+			wantUnusedTiles: []tile.Tile{{ID: 1, Ch: 'A'}, {ID: 3, Ch: 'C'}, {ID: 4, Ch: 'D'}},
 		},
 	}
-	for i, test := range handleAddPlayerTests {
+	for i, test := range handleGameKickTests {
+		g := Game{
+			status: test.status,
+			players: map[player.Name]*playerController.Player{
+				"larry": {Board: board.New(nil, nil)},
+				"curly": {Board: board.New([]tile.Tile{{ID: 3, Ch: 'C'}}, []tile.Position{{Tile: tile.Tile{ID: 4, Ch: 'D'}, X: 1, Y: 2}})},
+				"moe":   {Board: board.New(nil, nil)},
+			},
+			host: "moe",
+			userPoints: map[player.Name]int{
+				"curly": 5,
+			},
+			unusedTiles: []tile.Tile{{ID: 1, Ch: 'A'}},
+			Config: Config{
+				TimeFunc:               func() int64 { return 0 },
+				ShuffleUnusedTilesFunc: func(tiles []tile.Tile) {},
+			},
+		}
 		ctx := context.Background()
+		gotMessages := make(map[player.Name]message.Message)
 		gotInfoChanged := false
-		gotMessages := make(map[player.Name]struct{}, len(test.Game.players))
-		wantTilesLeft := len(test.wantUnusedTiles)
 		send := func(m message.Message) {
 			if m.Type == message.GameInfos {
 				gotInfoChanged = true
 				return
 			}
-			pn := m.PlayerName
-			if _, ok := test.Game.players[pn]; !ok {
-				t.Errorf("message sent to unknown player when adding player: %v", m)
-			}
-			if _, ok := gotMessages[pn]; ok {
-				t.Errorf("extra message sent to when adding player %v: %v", pn, m)
-			}
-			gotMessages[pn] = struct{}{}
+			gotMessages[m.PlayerName] = m
+		}
+		err := g.handleGameKick(ctx, test.Message, send)
+		switch {
+		case !test.wantOk:
+			w, ok := err.(gameWarning)
 			switch {
-			case wantTilesLeft != m.Game.TilesLeft:
-				t.Errorf("Test %v: tilesLeft in message sent to %v not equal: wanted %v, got: %v", i, pn, wantTilesLeft, m.Game.TilesLeft)
-			case !hasPlayer(m.Game.Players, string(test.Message.PlayerName)):
-				t.Errorf("Test %v: wanted new player %v to be in players slice, got %v", i, test.Message.PlayerName, m.Game.Players)
-			case pn == test.Message.PlayerName && m.Game.Board == nil:
-				t.Errorf("Test %v: wanted board resize info sent to new player (%v), got %v", i, test.Message.PlayerName, m)
+			case !ok, w.code != test.wantCode:
+				t.Errorf("Test %v: wanted warning with code %v, got %v", i, test.wantCode, err)
+			case len(g.players) != 3, len(g.unusedTiles) != 1:
+				t.Errorf("Test %v: wanted game to not change when kick fails", i)
 			}
+		case err != nil:
+			t.Errorf("Test %v: unwanted error: %v", i, err)
+		case g.players["curly"] != nil, len(g.players) != 2:
+			t.Errorf("Test %v: wanted kicked player to be removed, got %v", i, g.players)
+		case g.userPoints["curly"] != 0:
+			t.Errorf("Test %v: wanted points of kicked player to be removed", i)
+		case !reflect.DeepEqual(test.wantUnusedTiles, g.unusedTiles):
+			t.Errorf("Test %v: unused tiles not equal:\nwanted: %v\ngot:    %v", i, test.wantUnusedTiles, g.unusedTiles)
+		case gotMessages["curly"].Type != message.LeaveGame:
+			t.Errorf("Test %v: wanted kicked player to be sent leave message, got %v", i, gotMessages["curly"])
+		case gotMessages["larry"].Type != message.ChangeGameTiles, gotMessages["larry"].Game.TilesLeft != len(test.wantUnusedTiles):
+			t.Errorf("Test %v: wanted other player to be sent tiles left, got %v", i, gotMessages["larry"])
+		case !gotInfoChanged:
+			t.Errorf("Test %v: wanted to get message to change game info", i)
+		case len(g.events) != 1, g.events[0].Type != replay.Kick:
+			t.Errorf("Test %v: wanted kick event to be recorded, got %v", i, g.events)
 		}
-		err := test.Game.handleAddPlayer(ctx, test.Message, send)
+	}
+}
+
+func TestHandleToggleReady(t *testing.T) {
+	handleToggleReadyTests := []struct {
+		status           game.Status
+		autoStart        bool
+		ready            bool
+		wantOk           bool
+		wantReady        bool
+		wantReadyPlayers []string
+		wantStatus       game.Status
+	}{
+		{ // game started
+			status:     game.InProgress,
+			wantStatus: game.InProgress,
+		},
+		{
+			status:           game.NotStarted,
+			wantOk:           true,
+			wantReady:        true,
+			wantReadyPlayers: []string{"barney", "fred"},
+			wantStatus:       game.NotStarted,
+		},
+		{
+			status:           game.NotStarted,
+			ready:            true,
+			wantOk:           true,
+			wantReadyPlayers: []string{"barney"},
+			wantStatus:       game.NotStarted,
+		},
+		{ // every player ready
+			status:           game.NotStarted,
+			autoStart:        true,
+			wantOk:           true,
+			wantReady:        true,
+			wantReadyPlayers: []string{"barney", "fred"},
+			wantStatus:       game.InProgress,
+		},
+		{ // not every player ready
+			status:           game.NotStarted,
+			autoStart:        true,
+			ready:            true,
+			wantOk:           true,
+			wantReadyPlayers: []string{"barney"},
+			wantStatus:       game.NotStarted,
+		},
+	}
+	for i, test := range handleToggleReadyTests {
+		g := Game{
+			status: test.status,
+			players: map[player.Name]*playerController.Player{
+				"barney": {Ready: true},
+				"fred":   {Ready: test.ready},
+			},
+			userPoints: map[player.Name]int{},
+			Config: Config{
+				TimeFunc:   func() int64 { return 0 },
+				MaxPlayers: 4,
+				Config: game.Config{
+					AutoStart: test.autoStart,
+				},
+			},
+		}
+		ctx := context.Background()
+		m := message.Message{
+			Type:       message.ToggleReady,
+			PlayerName: "fred",
+		}
+		var gotReadyPlayers []string
+		send := func(m message.Message) {
+			if m.Type == message.ChangeGameTiles {
+				gotReadyPlayers = m.Game.ReadyPlayers
+			}
+		}
+		err := g.handleToggleReady(ctx, m, send)
 		switch {
 		case !test.wantOk:
 			if err == nil {
-				t.Errorf("Test %v: wanted error adding player", i)
+				t.Errorf("Test %v: wanted error", i)
 			}
 		case err != nil:
-			t.Errorf("Test %v: unwanted error adding player: %v", i, err)
-		case len(test.Game.players) != len(gotMessages):
-			t.Errorf("Test %v: wanted messages sent to all players but the new one (%v total), got %v", i, len(test.Game.players), len(gotMessages))
-		case !gotInfoChanged:
-			t.Errorf("Test %v: wanted to get message to change game info", i)
-		case !reflect.DeepEqual(test.wantUnusedTiles, test.Game.unusedTiles):
-			t.Errorf("Test %v: game unused tiles not equal after adding new player:\nwanted: %v\ngot:    %v", i, test.wantUnusedTiles, test.Game.unusedTiles)
-		case !reflect.DeepEqual(test.wantPlayer, test.Game.players[test.Message.PlayerName]):
-			t.Errorf("Test %v: new player not equal:\nwanted: %v\ngot:    %v", i, test.wantPlayer, test.Game.players[test.Message.PlayerName])
+			t.Errorf("Test %v: unwanted error: %v", i, err)
+		case test.wantReady != g.players["fred"].Ready:
+			t.Errorf("Test %v: wanted player to be ready: %v", i, test.wantReady)
+		case !reflect.DeepEqual(test.wantReadyPlayers, gotReadyPlayers):
+			t.Errorf("Test %v: ready players not equal:\nwanted: %v\ngot:    %v", i, test.wantReadyPlayers, gotReadyPlayers)
+		}
+		if test.wantStatus != g.status {
+			t.Errorf("Test %v: statuses not equal: wanted %v, got %v", i, test.wantStatus, g.status)
 		}
 	}
 }
 
-func TestHandleGameDelete(t *testing.T) {
-	g := Game{
-		players: map[player.Name]*playerController.Player{
-			"larry": {},
-			"curly": {},
-			"moe":   {},
+func TestAutoStart(t *testing.T) {
+	autoStartTests := []struct {
+		autoStart   bool
+		status      game.Status
+		maxPlayers  int
+		wantStarted bool
+	}{
+		{ // game full, but not auto started
+			status:     game.NotStarted,
+			maxPlayers: 2,
+		},
+		{ // not full
+			autoStart:  true,
+			status:     game.NotStarted,
+			maxPlayers: 3,
+		},
+		{ // already finished
+			autoStart:  true,
+			status:     game.Finished,
+			maxPlayers: 2,
+		},
+		{
+			autoStart:   true,
+			status:      game.NotStarted,
+			maxPlayers:  2,
+			wantStarted: true,
 		},
 	}
-	ctx := context.Background()
-	var m message.Message
-	gotMessages := make(map[player.Name]struct{}, len(g.players))
-	gotInfoChanged := false
-	send := func(m message.Message) {
-		switch m.Type {
-		case message.GameInfos:
-			gotInfoChanged = true
-			return
-		case message.LeaveGame:
-			// NOOP
-		default:
-			t.Errorf("wanted leave game message, got %v", m.Type)
+	for i, test := range autoStartTests {
+		g := Game{
+			status: test.status,
+			players: map[player.Name]*playerController.Player{
+				"barney": {Ready: true},
+				"fred":   {},
+			},
+			Config: Config{
+				TimeFunc:   func() int64 { return 0 },
+				MaxPlayers: test.maxPlayers,
+				Config: game.Config{
+					AutoStart: test.autoStart,
+				},
+			},
 		}
-		pn := m.PlayerName
-		if _, ok := g.players[pn]; !ok {
-			t.Errorf("message sent to unknown player when deleting game: %v", m)
+		gotMessages := make(map[message.Type]int)
+		send := func(m message.Message) {
+			gotMessages[m.Type]++
 		}
-		if _, ok := gotMessages[pn]; ok {
-			t.Errorf("extra message sent to %v when deleting game: %v", pn, m)
+		g.autoStart(send)
+		switch {
+		case !test.wantStarted:
+			if test.status != g.status || len(gotMessages) != 0 {
+				t.Errorf("Test %v: wanted game to not be started, got status %v and messages %v", i, g.status, gotMessages)
+			}
+		case g.status != game.InProgress:
+			t.Errorf("Test %v: wanted game to be started, got %v", i, g.status)
+		case gotMessages[message.ChangeGameStatus] != 2, gotMessages[message.GameInfos] != 1:
+			t.Errorf("Test %v: wanted players and lobby to be told the game started, got %v", i, gotMessages)
+		case len(g.events) != 1, g.events[0].Type != replay.Start, len(g.events[0].PlayerName) != 0:
+			t.Errorf("Test %v: wanted start event without player, got %v", i, g.events)
 		}
-		gotMessages[pn] = struct{}{}
 	}
-	err := g.handleGameDelete(ctx, m, send)
-	switch {
-	case err != nil:
-		t.Errorf("unwanted error: %v", err)
-	case len(gotMessages) != 3:
-		t.Errorf("wanted messages sent to all players (%v) when deleting game, got %v", len(g.players), len(gotMessages))
-	case g.status != game.Deleted:
-		t.Errorf("wanted game status to be deleted, got %v", g.status)
-	case !gotInfoChanged:
-		t.Errorf("wanted to get message to change game info")
+}
+
+func TestReadyPlayers(t *testing.T) {
+	g := Game{
+		status: game.NotStarted,
+		players: map[player.Name]*playerController.Player{
+			"fred":   {Ready: true},
+			"wilma":  {},
+			"barney": {Ready: true},
+		},
+	}
+	if want, got := []string{"barney", "fred"}, g.readyPlayers(); !reflect.DeepEqual(want, got) {
+		t.Errorf("not equal:\nwanted: %v\ngot:    %v", want, got)
+	}
+	g.status = game.InProgress
+	if got := g.readyPlayers(); got != nil {
+		t.Errorf("wanted no ready players after game started, got %v", got)
 	}
 }
 
@@ -1158,6 +3328,11 @@ func TestHandleGameStatusChange(t *testing.T) {
 						return nil
 					},
 				},
+				resultStore: mockResultStore{
+					CreateFunc: func(ctx context.Context, r result.Result) error {
+						return nil
+					},
+				},
 			},
 			wantOk:     true,
 			wantStatus: game.Finished,
@@ -1171,6 +3346,7 @@ func TestHandleGameStatusChange(t *testing.T) {
 				gotInfoChanged = true
 			}
 		}
+		test.Game.TimeFunc = func() int64 { return 0 }
 		err := test.Game.handleGameStatusChange(ctx, test.Message, send)
 		switch {
 		case !test.wantOk:
@@ -1198,6 +3374,19 @@ func TestHandleGameStart(t *testing.T) {
 		wantTilesLeft int
 	}{
 		{}, // game not started
+		{ // not host
+			Message: message.Message{
+				PlayerName: "moe",
+			},
+			Game: Game{
+				status: game.NotStarted,
+				players: map[player.Name]*playerController.Player{
+					"moe":   nil,
+					"curly": nil,
+				},
+				host: "curly",
+			},
+		},
 		{
 			Message: message.Message{
 				PlayerName: "curly",
@@ -1209,6 +3398,7 @@ func TestHandleGameStart(t *testing.T) {
 					"larry": nil,
 					"curly": nil,
 				},
+				host:        "curly",
 				unusedTiles: []tile.Tile{{}, {}, {}, {}},
 			},
 			wantOk:        true,
@@ -1234,6 +3424,7 @@ func TestHandleGameStart(t *testing.T) {
 				t.Errorf("Test %v: wanted change game status message from %v, got %v", i, test.Message.PlayerName, m)
 			}
 		}
+		test.Game.TimeFunc = func() int64 { return 0 }
 		err := test.Game.handleGameStart(ctx, test.Message, send)
 		switch {
 		case !test.wantOk:
@@ -1258,6 +3449,8 @@ func TestCheckPlayerBoard(t *testing.T) {
 		penalize      bool
 		wantOk        bool
 		wantUsedWords []string
+		wantCode      message.Code
+		wantDetails   *message.Details
 	}{
 		{ // not all tiles used
 			Player: playerController.Player{
@@ -1265,6 +3458,10 @@ func TestCheckPlayerBoard(t *testing.T) {
 				Board:     board.New([]tile.Tile{{}}, nil),
 			},
 			wantWinPoints: 6,
+			wantCode:      message.CodeUnusedTiles,
+			wantDetails: &message.Details{
+				TileIDs: []tile.ID{0},
+			},
 		},
 		{ // multiple letter groups
 			Player: playerController.Player{
@@ -1273,6 +3470,10 @@ func TestCheckPlayerBoard(t *testing.T) {
 					{Tile: tile.Tile{ID: 3}, X: 8, Y: 4},
 				}),
 			},
+			wantCode: message.CodeMultipleGroups,
+			wantDetails: &message.Details{
+				TileIDs: []tile.ID{3},
+			},
 		},
 		{ // check words error
 			Player: playerController.Player{
@@ -1285,6 +3486,10 @@ func TestCheckPlayerBoard(t *testing.T) {
 				return false
 			}),
 			checkWords: true,
+			wantCode:   message.CodeInvalidWords,
+			wantDetails: &message.Details{
+				InvalidWords: []string{"\x00\x00"},
+			},
 		},
 		{ // win points decremented from 10,
 			Player: playerController.Player{
@@ -1298,6 +3503,11 @@ func TestCheckPlayerBoard(t *testing.T) {
 			Config: game.Config{
 				Penalize: true,
 			},
+			wantCode: message.CodeUnusedTiles,
+			wantDetails: &message.Details{
+				TileIDs: []tile.ID{0},
+				Penalty: 1,
+			},
 		},
 		{ // ok, check used words
 			Player: playerController.Player{
@@ -1350,8 +3560,14 @@ func TestCheckPlayerBoard(t *testing.T) {
 		case test.wantWinPoints != g.players[pn].WinPoints:
 			t.Errorf("Test %v: wanted player win points to be %v after check, got %v", i, test.wantWinPoints, g.players[pn].WinPoints)
 		case !test.wantOk:
-			if err == nil {
-				t.Errorf("Test %v: wanted error checking player board", i)
+			w, ok := err.(gameWarning)
+			switch {
+			case !ok:
+				t.Errorf("Test %v: wanted game warning checking player board, got %v", i, err)
+			case test.wantCode != w.code:
+				t.Errorf("Test %v: warning codes not equal: wanted %v, got %v", i, test.wantCode, w.code)
+			case !reflect.DeepEqual(test.wantDetails, w.details):
+				t.Errorf("Test %v: warning details not equal:\nwanted: %v\ngot:    %v", i, test.wantDetails, w.details)
 			}
 		case err != nil:
 			t.Errorf("Test %v: unwanted error checking player board: %v", i, err)
@@ -1570,6 +3786,35 @@ func TestHandleGameFinish(t *testing.T) {
 			wantOk:     true,
 			userDaoErr: fmt.Errorf("user dao error"),
 		},
+		{ // bananas race: unused tiles left, but not enough for every player
+			Message: message.Message{
+				PlayerName: "fred",
+			},
+			Game: Game{
+				status:      game.InProgress,
+				unusedTiles: []tile.Tile{{ID: 4, Ch: 'E'}},
+				players: map[player.Name]*playerController.Player{
+					"fred": {
+						Board: board.New(nil, []tile.Position{
+							{Tile: tile.Tile{ID: 2}, X: 3, Y: 4},
+							{Tile: tile.Tile{ID: 3}, X: 4, Y: 4},
+						}),
+					},
+					"barney": {
+						Board: new(board.Board),
+					},
+				},
+				WordValidator: mockWordValidator(func(word string) bool {
+					return true
+				}),
+				Config: Config{
+					Config: game.Config{
+						StrictPeel: true,
+					},
+				},
+			},
+			wantOk: true,
+		},
 	}
 	for i, test := range handleGameFinishTests {
 		ctx := context.Background()
@@ -1597,10 +3842,20 @@ func TestHandleGameFinish(t *testing.T) {
 				return test.userDaoErr
 			},
 		}
+		resultStoreCalled := false
+		test.Game.resultStore = mockResultStore{
+			CreateFunc: func(ctx context.Context, r result.Result) error {
+				resultStoreCalled = true
+				return nil
+			},
+		}
+		test.Game.TimeFunc = func() int64 { return 0 }
 		err := test.Game.handleGameFinish(ctx, test.Message, send)
 		switch {
 		case test.wantOk != userDaoCalled:
 			t.Errorf("Test %v: wanted user dao to be called to increment points of users", i)
+		case test.wantOk != resultStoreCalled:
+			t.Errorf("Test %v: wanted result store to be called to record the result of the game", i)
 		case !log.Empty() != (test.userDaoErr != nil):
 			t.Errorf("Test %v: wanted log message (%v) if and only if user dao fails (%v)", i, !log.Empty(), test.userDaoErr != nil)
 		case !test.wantOk:
@@ -1613,17 +3868,256 @@ func TestHandleGameFinish(t *testing.T) {
 			t.Errorf("Test %v: wanted messages sent to all players (%v) when finishing game, got %v", i, len(test.Game.players), len(gotMessages))
 		case test.Game.status != game.Finished:
 			t.Errorf("Test %v: wanted game to be finished, got %v", i, test.Game.status)
+		case !reflect.DeepEqual([]player.Name{test.Message.PlayerName}, test.Game.finishOrder):
+			t.Errorf("Test %v: wanted finish of %v to be recorded, got %v", i, test.Message.PlayerName, test.Game.finishOrder)
+		}
+	}
+}
+
+func TestHandleGameFinishOrder(t *testing.T) {
+	g := Game{
+		status: game.InProgress,
+		players: map[player.Name]*playerController.Player{
+			"fred": {
+				Board: board.New([]tile.Tile{{ID: 1, Ch: 'A'}}, nil),
+			},
+			"barney": {
+				Board: board.New(nil, []tile.Position{
+					{Tile: tile.Tile{ID: 2, Ch: 'A'}, X: 3, Y: 4},
+					{Tile: tile.Tile{ID: 3, Ch: 'T'}, X: 4, Y: 4},
+				}),
+			},
+		},
+		WordValidator: mockWordValidator(func(word string) bool {
+			return true
+		}),
+		userDao: mockUserDao{
+			UpdatePointsIncrementFunc: func(ctx context.Context, userPoints map[string]int) error {
+				return nil
+			},
+		},
+		Config: Config{
+			TimeFunc: func() int64 { return 0 },
+		},
+	}
+	var gotResult result.Result
+	g.resultStore = mockResultStore{
+		CreateFunc: func(ctx context.Context, r result.Result) error {
+			gotResult = r
+			return nil
+		},
+	}
+	ctx := context.Background()
+	send := func(m message.Message) {
+		// NOOP
+	}
+	if err := g.handleGameFinish(ctx, message.Message{PlayerName: "fred"}, send); err == nil {
+		t.Fatalf("wanted error finishing with unused tiles")
+	}
+	if err := g.handleGameFinish(ctx, message.Message{PlayerName: "barney"}, send); err != nil {
+		t.Fatalf("unwanted error: %v", err)
+	}
+	want := []string{"fred", "barney"}
+	if got := gotResult.FinishOrder; !reflect.DeepEqual(want, got) {
+		t.Errorf("finish orders not equal:\nwanted: %v\ngot:    %v", want, got)
+	}
+}
+
+// challengeGame creates a game that fred has claimed to win by creating the word AT.
+// Selene and barney can challenge the word, but the bot cannot.
+func challengeGame(t *testing.T) *Game {
+	t.Helper()
+	g := Game{
+		log:    logtest.DiscardLogger,
+		status: game.InProgress,
+		players: map[player.Name]*playerController.Player{
+			"fred": {
+				Board: board.New(nil, []tile.Position{
+					{Tile: tile.Tile{ID: 1, Ch: 'A'}, X: 3, Y: 4},
+					{Tile: tile.Tile{ID: 2, Ch: 'T'}, X: 4, Y: 4},
+				}),
+			},
+			"selene": {
+				Board: board.New([]tile.Tile{{ID: 3, Ch: 'Q'}}, nil),
+			},
+			"barney": {
+				Board: board.New([]tile.Tile{{ID: 4, Ch: 'X'}}, nil),
+			},
+			"bot-easy-1": {
+				Board: new(board.Board),
+			},
+		},
+		WordValidator: mockWordValidator(func(word string) bool {
+			return true
+		}),
+		userDao: mockUserDao{
+			UpdatePointsIncrementFunc: func(ctx context.Context, userPoints map[string]int) error {
+				return nil
+			},
+		},
+		resultStore: mockResultStore{
+			CreateFunc: func(ctx context.Context, r result.Result) error {
+				return nil
+			},
+		},
+		Config: Config{
+			TimeFunc: func() int64 { return 100 },
+			ShuffleUnusedTilesFunc: func(tiles []tile.Tile) {
+				// NOOP
+			},
+			Config: game.Config{
+				ChallengeSec: 30,
+			},
+		},
+	}
+	send := func(m message.Message) {
+		// NOOP
+	}
+	if err := g.handleGameFinish(context.Background(), message.Message{PlayerName: "fred"}, send); err != nil {
+		t.Fatalf("unwanted error claiming win: %v", err)
+	}
+	return &g
+}
+
+func TestHandleGameFinishChallenge(t *testing.T) {
+	g := challengeGame(t)
+	switch {
+	case g.status != game.Challenging:
+		t.Errorf("wanted game to be challenging, got %v", g.status)
+	case g.challenge == nil, g.challenge.claimant != "fred", !reflect.DeepEqual([]string{"AT"}, g.challenge.words), g.challenge.endsAt != 130:
+		t.Errorf("wanted win of fred to be challenged until 130, got %#v", g.challenge)
+	case !reflect.DeepEqual([]player.Name{"barney", "selene"}, g.challengers("fred")):
+		t.Errorf("wanted players other than fred and the bot to be challengers, got %v", g.challengers("fred"))
+	case len(g.events) != 0:
+		t.Errorf("wanted game to not be finished before the challenge, got events %v", g.events)
+	}
+}
+
+func TestHandleChallengeWords(t *testing.T) {
+	handleChallengeWordsTests := []struct {
+		votes           map[player.Name][]string
+		m               message.Message
+		wantCode        message.Code
+		wantStatus      game.Status
+		wantUnusedTiles int
+	}{
+		{ // claimant cannot challenge
+			m:        message.Message{PlayerName: "fred"},
+			wantCode: message.CodeCannotChallenge,
+		},
+		{ // bot cannot challenge
+			m:        message.Message{PlayerName: "bot-easy-1"},
+			wantCode: message.CodeCannotChallenge,
+		},
+		{
+			m:        message.Message{PlayerName: "selene", Game: &game.Info{ChallengedWords: []string{"CAT"}}},
+			wantCode: message.CodeCannotChallenge,
+		},
+		{ // first vote
+			m:          message.Message{PlayerName: "selene", Game: &game.Info{ChallengedWords: []string{"AT"}}},
+			wantStatus: game.Challenging,
+		},
+		{ // accepted
+			votes:      map[player.Name][]string{"selene": {"AT"}},
+			m:          message.Message{PlayerName: "barney"},
+			wantStatus: game.Finished,
+		},
+		{ // rotten banana
+			votes:           map[player.Name][]string{"selene": {"AT"}},
+			m:               message.Message{PlayerName: "barney", Game: &game.Info{ChallengedWords: []string{"AT", "AT"}}},
+			wantStatus:      game.InProgress,
+			wantUnusedTiles: 2,
+		},
+	}
+	for i, test := range handleChallengeWordsTests {
+		g := challengeGame(t)
+		for pn, words := range test.votes {
+			g.challenge.votes[pn] = words
+		}
+		ctx := context.Background()
+		gotMessages := make(map[player.Name]message.Message, len(g.players))
+		send := func(m message.Message) {
+			if len(m.PlayerName) != 0 {
+				gotMessages[m.PlayerName] = m
+			}
+		}
+		err := g.handleChallengeWords(ctx, test.m, send)
+		switch {
+		case test.wantCode != message.CodeNone:
+			w, ok := err.(gameWarning)
+			switch {
+			case !ok, w.code != test.wantCode:
+				t.Errorf("Test %v: wanted warning with code %v, got %v", i, test.wantCode, err)
+			case g.status != game.Challenging, len(g.challenge.votes) != 0:
+				t.Errorf("Test %v: wanted challenge to be unchanged, got %v", i, g.challenge)
+			}
+		case err != nil:
+			t.Errorf("Test %v: unwanted error: %v", i, err)
+		case test.wantStatus != g.status:
+			t.Errorf("Test %v: statuses not equal: wanted %v, got %v", i, test.wantStatus, g.status)
+		case len(gotMessages) != len(g.players):
+			t.Errorf("Test %v: wanted messages sent to all players, got %v", i, gotMessages)
+		case test.wantUnusedTiles != len(g.unusedTiles):
+			t.Errorf("Test %v: wanted %v unused tiles, got %v", i, test.wantUnusedTiles, len(g.unusedTiles))
+		case test.wantStatus == game.InProgress:
+			fred := g.players["fred"]
+			switch {
+			case !fred.Forfeited, len(fred.Board.Tiles()) != 0:
+				t.Errorf("Test %v: wanted fred to be out of the game with no tiles, got %v", i, fred)
+			case g.challenge != nil:
+				t.Errorf("Test %v: wanted challenge to be resolved", i)
+			case len(g.events) != 1, g.events[0].Type != replay.RottenBanana:
+				t.Errorf("Test %v: wanted rotten banana to be recorded, got %v", i, g.events)
+			case gotMessages["fred"].Game.Board == nil, gotMessages["selene"].Game.Board != nil:
+				t.Errorf("Test %v: wanted only fred to get his empty board", i)
+			case !reflect.DeepEqual([]string{"AT"}, gotMessages["selene"].Game.ChallengedWords):
+				t.Errorf("Test %v: wanted challenged words sent to players, got %v", i, gotMessages["selene"])
+			}
 		}
 	}
 }
 
+func TestHandleChallengeTick(t *testing.T) {
+	g := challengeGame(t)
+	g.stateStore = mockStateStore{
+		SaveFunc: func(ctx context.Context, g state.Game) error {
+			return nil
+		},
+	}
+	ctx := context.Background()
+	var gotMessages []message.Message
+	send := func(m message.Message) {
+		gotMessages = append(gotMessages, m)
+	}
+	g.TimeFunc = func() int64 { return 120 }
+	g.handleChallengeTick(ctx, send)
+	switch {
+	case g.status != game.Challenging:
+		t.Errorf("wanted game to still be challenging, got %v", g.status)
+	case len(gotMessages) != len(g.players), gotMessages[0].Game.SecondsLeft != 10:
+		t.Errorf("wanted 10 seconds left sent to every player, got %v", gotMessages)
+	}
+	g.TimeFunc = func() int64 { return 130 }
+	g.handleChallengeTick(ctx, send)
+	if g.status != game.Finished {
+		t.Errorf("wanted game to be finished when time is up to challenge it, got %v", g.status)
+	}
+}
+
 func TestHandleGameSnag(t *testing.T) {
+	addDiff := func(id tile.ID) *board.Diff {
+		d := board.Board{}.NewDiff(board.OpAdd, tile.Position{Tile: tile.Tile{ID: id}})
+		return &d
+	}
+	teamBoard := board.New(nil, []tile.Position{
+		{Tile: tile.Tile{ID: 1}, X: 3, Y: 4},
+	})
 	handleGameSnagTests := []struct {
 		message.Message
 		Game
 		wantOk        bool
 		wantTilesLeft int
-		wantBoards    map[player.Name]*board.Board
+		wantDiffs     map[player.Name]*board.Diff
 	}{
 		{}, // game not in progress
 		{ // no game unused tiles
@@ -1768,10 +4262,10 @@ func TestHandleGameSnag(t *testing.T) {
 			},
 			wantOk:        true,
 			wantTilesLeft: 2,
-			wantBoards: map[player.Name]*board.Board{
-				"larry": board.New([]tile.Tile{{ID: 4}}, nil),
-				"curly": board.New([]tile.Tile{{ID: 6}}, nil),
-				"moe":   board.New([]tile.Tile{{ID: 5}}, nil),
+			wantDiffs: map[player.Name]*board.Diff{
+				"larry": addDiff(4),
+				"curly": addDiff(6),
+				"moe":   addDiff(5),
 			},
 		},
 		{ // 3 players, only 2 get tiles
@@ -1808,9 +4302,91 @@ func TestHandleGameSnag(t *testing.T) {
 			},
 			wantOk:        true,
 			wantTilesLeft: 0,
-			wantBoards: map[player.Name]*board.Board{
-				"larry": board.New([]tile.Tile{{ID: 4}}, nil),
-				"moe":   board.New([]tile.Tile{{ID: 5}}, nil),
+			wantDiffs: map[player.Name]*board.Diff{
+				"larry": addDiff(4),
+				"moe":   addDiff(5),
+			},
+		},
+		{ // players who forfeited do not get tiles
+			Message: message.Message{
+				PlayerName: "larry",
+			},
+			Game: Game{
+				status:      game.InProgress,
+				unusedTiles: []tile.Tile{{ID: 4}, {ID: 5}, {ID: 6}},
+				players: map[player.Name]*playerController.Player{
+					"larry": {
+						Board: board.New(nil, []tile.Position{
+							{Tile: tile.Tile{ID: 1}, X: 3, Y: 4},
+						}),
+					},
+					"curly": {
+						Board: board.New(nil, []tile.Position{
+							{Tile: tile.Tile{ID: 2}, X: 3, Y: 4},
+						}),
+					},
+					"moe": {
+						Board: board.New(nil, []tile.Position{
+							{Tile: tile.Tile{ID: 3}, X: 3, Y: 4},
+						}),
+						Forfeited: true,
+					},
+				},
+				Config: Config{
+					ShufflePlayersFunc: func(playerNames []player.Name) {
+						sort.Slice(playerNames, func(i, j int) bool {
+							return playerNames[i] > playerNames[j] // order moe before curly
+						})
+					},
+				},
+			},
+			wantOk:        true,
+			wantTilesLeft: 1,
+			wantDiffs: map[player.Name]*board.Diff{
+				"larry": addDiff(4),
+				"curly": addDiff(5),
+			},
+		},
+		{ // teammates share a tile
+			Message: message.Message{
+				PlayerName: "larry",
+			},
+			Game: Game{
+				status:      game.InProgress,
+				unusedTiles: []tile.Tile{{ID: 4}, {ID: 5}, {ID: 6}},
+				players: map[player.Name]*playerController.Player{
+					"larry": {
+						Board: teamBoard,
+						Team:  1,
+					},
+					"curly": {
+						Board: teamBoard,
+						Team:  1,
+					},
+					"moe": {
+						Board: board.New(nil, []tile.Position{
+							{Tile: tile.Tile{ID: 3}, X: 3, Y: 4},
+						}),
+						Team: 2,
+					},
+				},
+				Config: Config{
+					ShufflePlayersFunc: func(playerNames []player.Name) {
+						sort.Slice(playerNames, func(i, j int) bool {
+							return playerNames[i] < playerNames[j] // order curly before moe
+						})
+					},
+					Config: game.Config{
+						TeamSize: 2,
+					},
+				},
+			},
+			wantOk:        true,
+			wantTilesLeft: 1,
+			wantDiffs: map[player.Name]*board.Diff{
+				"larry": addDiff(4),
+				"curly": addDiff(4),
+				"moe":   addDiff(5),
 			},
 		},
 	}
@@ -1837,22 +4413,27 @@ func TestHandleGameSnag(t *testing.T) {
 			switch {
 			case test.wantTilesLeft != m.Game.TilesLeft:
 				t.Errorf("Test %v: message sent to %v did not have correct tilesLeft: wanted %v, got %v", i, pn, test.wantTilesLeft, m.Game.TilesLeft)
-			case test.wantBoards[pn] != nil:
-				if !reflect.DeepEqual(test.wantBoards[pn], m.Game.Board) {
-					t.Errorf("Test %v: snag board sent to %v not equal:\nwanted: %v\ngot:    %v", i, pn, test.wantBoards[pn], m.Game.Board)
+			case test.wantDiffs[pn] != nil:
+				if !reflect.DeepEqual(test.wantDiffs[pn], m.Game.Diff) {
+					t.Errorf("Test %v: snag diff sent to %v not equal:\nwanted: %v\ngot:    %v", i, pn, test.wantDiffs[pn], m.Game.Diff)
+					return
 				}
-				for tID, tile := range m.Game.Board.UnusedTiles {
-					if _, ok := test.Game.players[pn].Board.UnusedTiles[tID]; !ok {
-						t.Errorf("Test %v: wanted tile %v added to player's board in game", i, tile)
+				for _, c := range m.Game.Diff.Changes {
+					if _, ok := test.Game.players[pn].Board.UnusedTiles[c.Tile.ID]; !ok {
+						t.Errorf("Test %v: wanted tile %v added to player's board in game", i, c.Tile)
 					}
-					if hasTile(test.Game.unusedTiles, tID) {
-						t.Errorf("Test %v: player received tileId=%v, but game still has it: %v", i, tID, test.Game.unusedTiles)
+					if hasTile(test.Game.unusedTiles, c.Tile.ID) {
+						t.Errorf("Test %v: player received tileId=%v, but game still has it: %v", i, c.Tile.ID, test.Game.unusedTiles)
 					}
 				}
-			case m.Game.Board != nil && test.wantBoards[pn] != nil:
+				if want, got := 1, test.Game.players[pn].Board.Revision; want != got {
+					t.Errorf("Test %v: wanted revision of board of %v to be %v after snag, got %v", i, pn, want, got)
+				}
+			case m.Game.Diff != nil:
 				t.Errorf("Test %v: wanted no board/tile information sent to player who did not make snag and should not get a tile because none are left: got %v", i, m)
 			}
 		}
+		test.Game.TimeFunc = func() int64 { return 0 }
 		err := test.Game.handleGameSnag(ctx, test.Message, send)
 		switch {
 		case !test.wantOk:
@@ -1867,19 +4448,126 @@ func TestHandleGameSnag(t *testing.T) {
 	}
 }
 
+func TestHandleGameSnagStrictPeel(t *testing.T) {
+	handleGameSnagStrictPeelTests := []struct {
+		numUnusedTiles  int
+		invalidBoard    bool
+		forfeited       bool
+		wantCode        message.Code
+		wantBananasRace bool
+	}{
+		{
+			numUnusedTiles: 4,
+		},
+		{
+			numUnusedTiles: 4,
+			invalidBoard:   true,
+			wantCode:       message.CodeInvalidWords,
+		},
+		{ // last peel
+			numUnusedTiles:  3,
+			wantBananasRace: true,
+		},
+		{
+			numUnusedTiles: 1,
+			wantCode:       message.CodeBananasRace,
+		},
+		{ // forfeited players do not get tiles
+			numUnusedTiles:  1,
+			forfeited:       true,
+			wantBananasRace: true,
+		},
+	}
+	for i, test := range handleGameSnagStrictPeelTests {
+		unusedTiles := make([]tile.Tile, test.numUnusedTiles)
+		for j := range unusedTiles {
+			unusedTiles[j] = tile.Tile{ID: tile.ID(10 + j), Ch: 'E'}
+		}
+		g := Game{
+			status:      game.InProgress,
+			unusedTiles: unusedTiles,
+			players: map[player.Name]*playerController.Player{
+				"fred": {
+					Board: board.New(nil, []tile.Position{
+						{Tile: tile.Tile{ID: 1, Ch: 'A'}, X: 3, Y: 4},
+						{Tile: tile.Tile{ID: 2, Ch: 'T'}, X: 4, Y: 4},
+					}),
+				},
+				"barney": {
+					Board:     new(board.Board),
+					Forfeited: test.forfeited,
+				},
+			},
+			WordValidator: mockWordValidator(func(word string) bool {
+				return !test.invalidBoard
+			}),
+			Config: Config{
+				TimeFunc: func() int64 { return 0 },
+				ShufflePlayersFunc: func(playerNames []player.Name) {
+					// NOOP
+				},
+				Config: game.Config{
+					StrictPeel: true,
+				},
+			},
+		}
+		ctx := context.Background()
+		m := message.Message{
+			Type:       message.SnagGameTile,
+			PlayerName: "fred",
+		}
+		gotMessages := make(map[player.Name]message.Message, len(g.players))
+		send := func(m message.Message) {
+			gotMessages[m.PlayerName] = m
+		}
+		err := g.handleGameSnag(ctx, m, send)
+		switch {
+		case test.wantCode != message.CodeNone:
+			w, ok := err.(gameWarning)
+			switch {
+			case !ok, w.code != test.wantCode:
+				t.Errorf("Test %v: wanted warning with code %v, got %v", i, test.wantCode, err)
+			case len(g.unusedTiles) != test.numUnusedTiles:
+				t.Errorf("Test %v: wanted no tiles to be snagged", i)
+			}
+		case err != nil:
+			t.Errorf("Test %v: unwanted error: %v", i, err)
+		case len(gotMessages) != len(g.players):
+			t.Errorf("Test %v: wanted messages sent to all players, got %v", i, gotMessages)
+		default:
+			for pn, m2 := range gotMessages {
+				if want, got := test.wantBananasRace, m2.Game.BananasRace; want != got {
+					t.Errorf("Test %v: wanted Bananas race flag to be %v for %v, got %v: %v", i, want, pn, got, m2)
+				}
+			}
+		}
+	}
+}
+
 func TestHandleGameSwap(t *testing.T) {
+	removeDiff := func(t tile.Tile) *board.Diff {
+		d := board.Board{}.NewDiff(board.OpRemove, tile.Position{Tile: t})
+		return &d
+	}
+	addDiff := func(tiles ...tile.Tile) *board.Diff {
+		d := board.Diff{Revision: 1}
+		for _, t := range tiles {
+			d.Changes = append(d.Changes, board.Change{Op: board.OpAdd, Position: tile.Position{Tile: t}})
+		}
+		return &d
+	}
 	handleGameSwapTests := []struct {
 		message.Message
 		Game
 		wantOk        bool
 		wantTilesLeft int
-		wantBoard     *board.Board
+		wantDiff      *board.Diff
 	}{
 		{}, // game not in progress
 		{ // no swap tile specified
 			Message: message.Message{
 				Game: &game.Info{
-					Board: new(board.Board),
+					Diff: new(board.Diff),
 				},
 			},
 			Game: Game{
@@ -1889,7 +4577,7 @@ func TestHandleGameSwap(t *testing.T) {
 		{ // no game unused tiles
 			Message: message.Message{
 				Game: &game.Info{
-					Board: board.New([]tile.Tile{{}}, nil),
+					Diff: removeDiff(tile.Tile{}),
 				},
 			},
 			Game: Game{
@@ -1900,7 +4588,7 @@ func TestHandleGameSwap(t *testing.T) {
 			Message: message.Message{
 				PlayerName: "alice",
 				Game: &game.Info{
-					Board: board.New([]tile.Tile{{ID: 6}}, nil),
+					Diff: removeDiff(tile.Tile{ID: 6}),
 				},
 			},
 			Game: Game{
@@ -1917,7 +4605,7 @@ func TestHandleGameSwap(t *testing.T) {
 			Message: message.Message{
 				PlayerName: "alice",
 				Game: &game.Info{
-					Board: board.New([]tile.Tile{{ID: 6}}, nil),
+					Diff: removeDiff(tile.Tile{ID: 6}),
 				},
 			},
 			Game: Game{
@@ -1939,7 +4627,7 @@ func TestHandleGameSwap(t *testing.T) {
 			Message: message.Message{
 				PlayerName: "shaggy",
 				Game: &game.Info{
-					Board: board.New([]tile.Tile{{ID: 13, Ch: 'D'}}, nil),
+					Diff: removeDiff(tile.Tile{ID: 13, Ch: 'D'}),
 				},
 			},
 			Game: Game{
@@ -1963,13 +4651,13 @@ func TestHandleGameSwap(t *testing.T) {
 			},
 			wantOk:        true,
 			wantTilesLeft: 2,
-			wantBoard:     board.New([]tile.Tile{{ID: 4, Ch: 'F'}, {ID: 6, Ch: 'E'}, {ID: 8, Ch: 'A'}}, nil),
+			wantDiff:      addDiff(tile.Tile{ID: 4, Ch: 'F'}, tile.Tile{ID: 6, Ch: 'E'}, tile.Tile{ID: 8, Ch: 'A'}),
 		},
 		{ // 2 tiles left, 1 player, shuffle alphabetically
 			Message: message.Message{
 				PlayerName: "selene",
 				Game: &game.Info{
-					Board: board.New([]tile.Tile{{ID: 3, Ch: 'D'}}, nil),
+					Diff: removeDiff(tile.Tile{ID: 3, Ch: 'D'}),
 				},
 			},
 			Game: Game{
@@ -1988,14 +4676,14 @@ func TestHandleGameSwap(t *testing.T) {
 					},
 				},
 			},
-			wantOk:    true,
-			wantBoard: board.New([]tile.Tile{{ID: 8, Ch: 'A'}, {ID: 3, Ch: 'D'}, {ID: 6, Ch: 'E'}}, nil),
+			wantOk:   true,
+			wantDiff: addDiff(tile.Tile{ID: 8, Ch: 'A'}, tile.Tile{ID: 3, Ch: 'D'}, tile.Tile{ID: 6, Ch: 'E'}),
 		},
 		{ // 1 tile left, 1 player.  Because there is only one tile left, the player will get back the tile they swapped because they always get up to three tiles
 			Message: message.Message{
 				PlayerName: "selene",
 				Game: &game.Info{
-					Board: board.New([]tile.Tile{{ID: 1, Ch: 'X'}}, nil),
+					Diff: removeDiff(tile.Tile{ID: 1, Ch: 'X'}),
 				},
 			},
 			Game: Game{
@@ -2014,8 +4702,8 @@ func TestHandleGameSwap(t *testing.T) {
 					},
 				},
 			},
-			wantOk:    true,
-			wantBoard: board.New([]tile.Tile{{ID: 2}, {ID: 1, Ch: 'X'}}, nil),
+			wantOk:   true,
+			wantDiff: addDiff(tile.Tile{ID: 2}, tile.Tile{ID: 1, Ch: 'X'}),
 		},
 	}
 	hasTile := func(tiles []tile.Tile, tID tile.ID) bool {
@@ -2042,27 +4730,30 @@ func TestHandleGameSwap(t *testing.T) {
 			case test.wantTilesLeft != m.Game.TilesLeft:
 				t.Errorf("Test %v: message sent to %v did not have correct tilesLeft: wanted %v, got %v", i, pn, test.wantTilesLeft, m.Game.TilesLeft)
 			case pn == test.Message.PlayerName: // the player making the swap
-				if !reflect.DeepEqual(test.wantBoard, m.Game.Board) {
-					t.Errorf("Test %v: newly swapped tile not equal:\nwanted: %v\ngot:    %v", i, test.wantBoard, m.Game.Board)
+				if !reflect.DeepEqual(test.wantDiff, m.Game.Diff) {
+					t.Errorf("Test %v: newly swapped tiles not equal:\nwanted: %v\ngot:    %v", i, test.wantDiff, m.Game.Diff)
+					return
 				}
-				for tID, tile := range m.Game.Board.UnusedTiles {
-					if _, ok := test.Game.players[pn].Board.UnusedTiles[tID]; !ok {
-						t.Errorf("Test %v: wanted tile %v added to player's board in game", i, tile)
+				for _, c := range m.Game.Diff.Changes {
+					if _, ok := test.Game.players[pn].Board.UnusedTiles[c.Tile.ID]; !ok {
+						t.Errorf("Test %v: wanted tile %v added to player's board in game", i, c.Tile)
 					}
-					if hasTile(test.Game.unusedTiles, tID) {
-						t.Errorf("Test %v: player received tileId=%v, but game still has it: %v", i, tID, test.Game.unusedTiles)
+					if hasTile(test.Game.unusedTiles, c.Tile.ID) {
+						t.Errorf("Test %v: player received tileId=%v, but game still has it: %v", i, c.Tile.ID, test.Game.unusedTiles)
 					}
 				}
-				wantTileID := test.Message.Game.Board.UnusedTileIDs[0]
-				wantTile := test.Message.Game.Board.UnusedTiles[wantTileID]
-				wantLetter := rune(wantTile.Ch)
+				if want, got := 2, test.Game.players[pn].Board.Revision; want != got {
+					t.Errorf("Test %v: wanted revision of board to be %v after swap, got %v", i, want, got)
+				}
+				wantLetter := rune(test.Message.Game.Diff.Changes[0].Tile.Ch)
 				if !strings.ContainsRune(m.Info, wantLetter) {
 					t.Errorf("Test %v: wanted message sent to player to inform them that tile with the letter %v was swapped, got: '%v'", i, string(wantLetter), m.Info)
 				}
-			case m.Game.Board != nil:
+			case m.Game.Diff != nil:
 				t.Errorf("Test %v: wanted no board/tile information sent to player who did not make swap: got %v", i, m)
 			}
 		}
+		test.Game.TimeFunc = func() int64 { return 0 }
 		err := test.Game.handleGameSwap(ctx, test.Message, send)
 		switch {
 		case !test.wantOk:
@@ -2081,23 +4772,30 @@ func TestHandleInfoChanged(t *testing.T) {
 	want := message.Message{
 		Type: message.GameInfos,
 		Game: &game.Info{
-			ID:        7,
-			Status:    game.InProgress,
-			Players:   []string{"barney", "fred"},
-			CreatedAt: 555,
-			Capacity:  7,
+			ID:               7,
+			Status:           game.InProgress,
+			Players:          []string{"barney", "fred"},
+			CreatedAt:        555,
+			Capacity:         7,
+			Private:          true,
+			Host:             "fred",
+			ForfeitedPlayers: []string{"barney"},
 		},
 	}
 	g := Game{
 		id:     7,
 		status: game.InProgress,
 		players: map[player.Name]*playerController.Player{
-			"fred":   nil,
-			"barney": nil,
+			"fred":   {},
+			"barney": {Forfeited: true},
 		},
+		host:      "fred",
 		createdAt: 555,
 		Config: Config{
 			MaxPlayers: 7,
+			Config: game.Config{
+				InviteCode: "b4n4n4",
+			},
 		},
 	}
 	var got message.Message
@@ -2110,62 +4808,339 @@ func TestHandleInfoChanged(t *testing.T) {
 	}
 }
 
+func TestHandleGameSwapRules(t *testing.T) {
+	swapRulesTests := []struct {
+		game.Config
+		swaps        int
+		lastSwapTime int64
+		usedTile     bool
+		wantCode     message.Code
+		wantNumTiles int
+	}{
+		{ // default rules
+			wantNumTiles: 3,
+		},
+		{
+			Config: game.Config{
+				SwapRatio: 1,
+			},
+			wantNumTiles: 1,
+		},
+		{
+			Config: game.Config{
+				SwapLimit: 2,
+			},
+			swaps:        1,
+			wantNumTiles: 3,
+		},
+		{
+			Config: game.Config{
+				SwapLimit: 2,
+			},
+			swaps:    2,
+			wantCode: message.CodeSwapLimit,
+		},
+		{
+			Config: game.Config{
+				SwapCooldownSec: 30,
+			},
+			swaps:        1,
+			lastSwapTime: 80,
+			wantCode:     message.CodeSwapCooldown,
+		},
+		{
+			Config: game.Config{
+				SwapCooldownSec: 30,
+			},
+			swaps:        1,
+			lastSwapTime: 70,
+			wantNumTiles: 3,
+		},
+		{
+			usedTile: true,
+			wantCode: message.CodeUsedTileSwap,
+		},
+		{
+			Config: game.Config{
+				SwapUsedTiles: true,
+			},
+			usedTile:     true,
+			wantNumTiles: 3,
+		},
+	}
+	for i, test := range swapRulesTests {
+		swapTile := tile.Tile{ID: 1, Ch: 'Q'}
+		b := board.New([]tile.Tile{swapTile}, nil)
+		if test.usedTile {
+			b = board.New(nil, []tile.Position{{Tile: swapTile, X: 2, Y: 3}})
+		}
+		b.Config = board.Config{NumRows: 10, NumCols: 10}
+		g := Game{
+			status:      game.InProgress,
+			unusedTiles: []tile.Tile{{ID: 2, Ch: 'A'}, {ID: 3, Ch: 'B'}, {ID: 4, Ch: 'C'}, {ID: 5, Ch: 'D'}},
+			players: map[player.Name]*playerController.Player{
+				"selene": {
+					Board:        b,
+					Swaps:        test.swaps,
+					LastSwapTime: test.lastSwapTime,
+				},
+			},
+			Config: Config{
+				TimeFunc: func() int64 { return 100 },
+				ShuffleUnusedTilesFunc: func(tiles []tile.Tile) {
+					// NOOP
+				},
+				Config: test.Config,
+			},
+		}
+		ctx := context.Background()
+		d := b.NewDiff(board.OpRemove, tile.Position{Tile: swapTile})
+		m := message.Message{
+			PlayerName: "selene",
+			Game: &game.Info{
+				Diff: &d,
+			},
+		}
+		var gotMessage *message.Message
+		send := func(m message.Message) {
+			gotMessage = &m
+		}
+		err := g.handleGameSwap(ctx, m, send)
+		p := g.players["selene"]
+		_, unused := p.Board.UnusedTiles[swapTile.ID]
+		_, used := p.Board.UsedTiles[swapTile.ID]
+		hasSwapTile := unused || used
+		switch {
+		case test.wantCode != message.CodeNone:
+			w, ok := err.(gameWarning)
+			switch {
+			case !ok, w.code != test.wantCode:
+				t.Errorf("Test %v: wanted warning with code %v, got %v", i, test.wantCode, err)
+			case !hasSwapTile, p.Swaps != test.swaps:
+				t.Errorf("Test %v: wanted tile to not be swapped", i)
+			case gotMessage == nil, gotMessage.Type != message.RefreshGameBoard, gotMessage.Game.Board == nil:
+				t.Errorf("Test %v: wanted whole board sent to player to undo the swap, got %v", i, gotMessage)
+			}
+		case err != nil:
+			t.Errorf("Test %v: unwanted error: %v", i, err)
+		case hasSwapTile:
+			t.Errorf("Test %v: wanted tile to be swapped", i)
+		case len(g.events) != 1, len(g.events[0].Tiles) != test.wantNumTiles:
+			t.Errorf("Test %v: wanted %v tiles for the swapped tile, got events %v", i, test.wantNumTiles, g.events)
+		case p.Swaps != test.swaps+1, p.LastSwapTime != 100:
+			t.Errorf("Test %v: wanted swap to be counted at time 100, got %v swaps at %v", i, p.Swaps, p.LastSwapTime)
+		}
+	}
+}
+
 func TestHandleGameTilesMoved(t *testing.T) {
+	moveDiff := func(revision int, tp tile.Position) *board.Diff {
+		d := board.Board{Revision: revision}.NewDiff(board.OpMove, tp)
+		return &d
+	}
+	newBoard := func(revision int, tp tile.Position) *board.Board {
+		b := board.New(nil, []tile.Position{tp})
+		b.Config = board.Config{NumRows: 10, NumCols: 10}
+		b.Revision = revision
+		return b
+	}
 	handleGameTilesMovedTests := []struct {
 		game.Status
 		message.Message
-		*board.Board
-		wantOk bool
-		want   *board.Board
+		wantOk           bool
+		wantCode         message.Code
+		wantRefreshed    bool
+		wantTilePosition tile.Position
 	}{
 		{}, // game not in progress
+		{ // no diff
+			Status: game.InProgress,
+			Message: message.Message{
+				PlayerName: "selene",
+				Game:       &game.Info{},
+			},
+		},
+		{ // not a move
+			Status: game.InProgress,
+			Message: message.Message{
+				PlayerName: "selene",
+				Game: &game.Info{
+					Diff: &board.Diff{
+						Revision: 3,
+						Changes: []board.Change{
+							{Op: board.OpAdd, Position: tile.Position{Tile: tile.Tile{ID: 9}}},
+						},
+					},
+				},
+			},
+		},
+		{ // stale revision
+			Status: game.InProgress,
+			Message: message.Message{
+				PlayerName: "selene",
+				Game: &game.Info{
+					Diff: moveDiff(2, tile.Position{Tile: tile.Tile{ID: 8}, X: 7, Y: 4}),
+				},
+			},
+			wantCode:         message.CodeStaleBoard,
+			wantRefreshed:    true,
+			wantTilePosition: tile.Position{Tile: tile.Tile{ID: 8}, X: 7, Y: 3},
+		},
 		{
 			Status: game.InProgress,
 			Message: message.Message{
 				PlayerName: "selene",
 				Game: &game.Info{
-					Board: board.New(nil, []tile.Position{
-						{Tile: tile.Tile{ID: 8}, X: 7, Y: 4},
-					}),
+					Diff: moveDiff(3, tile.Position{Tile: tile.Tile{ID: 8}, X: 7, Y: 4}),
 				},
 			},
-			Board: &board.Board{
-				Config:       board.Config{NumRows: 10, NumCols: 10},
-				UsedTiles:    map[tile.ID]tile.Position{8: {Tile: tile.Tile{ID: 8}, X: 17, Y: 3}},
-				UsedTileLocs: map[tile.X]map[tile.Y]tile.Tile{17: {3: {ID: 8}}},
+			wantOk:           true,
+			wantTilePosition: tile.Position{Tile: tile.Tile{ID: 8}, X: 7, Y: 4},
+		},
+	}
+	for i, test := range handleGameTilesMovedTests {
+		g := Game{
+			status: test.Status,
+			players: map[player.Name]*playerController.Player{
+				test.Message.PlayerName: {
+					Board: newBoard(3, tile.Position{Tile: tile.Tile{ID: 8}, X: 7, Y: 3}),
+				},
+			},
+			Config: Config{
+				TimeFunc: func() int64 { return 13 },
+			},
+		}
+		ctx := context.Background()
+		var gotReport, gotRefresh *message.Message
+		send := func(m message.Message) {
+			switch m.Type {
+			case message.RefreshGameBoard:
+				gotRefresh = &m
+			default:
+				gotReport = &m
+			}
+		}
+		err := g.handleGameTilesMoved(ctx, test.Message, send)
+		got := g.players[test.Message.PlayerName].Board
+		switch {
+		case !test.wantOk:
+			gw, isWarning := err.(gameWarning)
+			switch {
+			case err == nil:
+				t.Errorf("Test %v: wanted error moving tiles", i)
+			case test.wantCode != message.CodeNone && (!isWarning || gw.code != test.wantCode):
+				t.Errorf("Test %v: wanted warning with code %v, got %v", i, test.wantCode, err)
+			case test.wantRefreshed && (gotRefresh == nil || gotRefresh.Game.Board.Revision != got.Revision || len(gotRefresh.Game.Board.UsedTiles) != 1):
+				t.Errorf("Test %v: wanted whole board sent to player after stale diff, got %v", i, gotRefresh)
+			case test.wantTilePosition.X != 0 && test.wantTilePosition != got.UsedTiles[8]:
+				t.Errorf("Test %v: wanted tile to not be moved, got %v", i, got.UsedTiles[8])
+			}
+		case err != nil:
+			t.Errorf("Test %v: unwanted error moving tiles: %v", i, err)
+		case !reflect.DeepEqual(newBoard(4, test.wantTilePosition), got):
+			t.Errorf("Test %v: boards not equal:\nwanted: %v\ngot:    %v", i, newBoard(4, test.wantTilePosition), got)
+		case len(g.events) != 1, g.events[0].Type != replay.Move, g.events[0].Time != 13, len(g.events[0].TilePositions) != 1:
+			t.Errorf("Test %v: wanted move event to be recorded, got %v", i, g.events)
+		case gotReport == nil, gotReport.Type != message.BoardReport, gotReport.PlayerName != test.Message.PlayerName, gotReport.Game == nil, gotReport.Game.Report == nil:
+			t.Errorf("Test %v: wanted board report to be sent to player, got %v", i, gotReport)
+		}
+	}
+}
+
+func TestHandleGameTilesMovedTeam(t *testing.T) {
+	b := board.New(nil, []tile.Position{{Tile: tile.Tile{ID: 8}, X: 7, Y: 3}})
+	b.Config = board.Config{NumRows: 10, NumCols: 10}
+	b.Revision = 3
+	g := Game{
+		status: game.InProgress,
+		players: map[player.Name]*playerController.Player{
+			"fred":   {Board: b, Team: 1},
+			"barney": {Board: b, Team: 1},
+			"wilma":  {Board: board.New(nil, nil), Team: 2},
+		},
+		Config: Config{
+			TimeFunc: func() int64 { return 13 },
+			Config: game.Config{
+				TeamSize: 2,
+			},
+		},
+	}
+	d := b.NewDiff(board.OpMove, tile.Position{Tile: tile.Tile{ID: 8}, X: 7, Y: 4})
+	ctx := context.Background()
+	m := message.Message{
+		PlayerName: "fred",
+		Game: &game.Info{
+			Diff: &d,
+		},
+	}
+	var gotTeammateDiff *board.Diff
+	send := func(m message.Message) {
+		switch m.PlayerName {
+		case "barney":
+			if m.Type != message.ChangeGameTiles || m.Game == nil {
+				t.Errorf("wanted tiles changed message sent to teammate, got %v", m)
+				return
+			}
+			gotTeammateDiff = m.Game.Diff
+		case "wilma":
+			t.Errorf("unwanted message sent to player on other team: %v", m)
+		}
+	}
+	if err := g.handleGameTilesMoved(ctx, m, send); err != nil {
+		t.Fatalf("unwanted error: %v", err)
+	}
+	switch {
+	case g.players["barney"].Board.UsedTiles[8].Y != 4:
+		t.Errorf("wanted move to change the shared board, got %v", g.players["barney"].Board)
+	case gotTeammateDiff == nil, !reflect.DeepEqual(d, *gotTeammateDiff):
+		t.Errorf("wanted the moves sent to the teammate:\nwanted: %v\ngot:    %v", d, gotTeammateDiff)
+	}
+}
+
+func TestBoardReport(t *testing.T) {
+	b := board.New(nil, []tile.Position{
+		{Tile: tile.Tile{ID: 1, Ch: 'C'}, X: 1, Y: 1},
+		{Tile: tile.Tile{ID: 2, Ch: 'A'}, X: 2, Y: 1},
+		{Tile: tile.Tile{ID: 3, Ch: 'T'}, X: 3, Y: 1},
+		{Tile: tile.Tile{ID: 4, Ch: 'A'}, X: 1, Y: 2},
+		{Tile: tile.Tile{ID: 5, Ch: 'T'}, X: 1, Y: 3},
+		{Tile: tile.Tile{ID: 6, Ch: 'A'}, X: 3, Y: 2},
+		{Tile: tile.Tile{ID: 7, Ch: 'X'}, X: 3, Y: 3},
+	})
+	boardReportTests := []struct {
+		game.Config
+		want []bool
+	}{
+		{
+			want: []bool{true, true, false},
+		},
+		{
+			Config: game.Config{
+				ProhibitDuplicates: true,
 			},
-			wantOk: true,
-			want: &board.Board{
-				Config:       board.Config{NumRows: 10, NumCols: 10},
-				UsedTiles:    map[tile.ID]tile.Position{8: {Tile: tile.Tile{ID: 8}, X: 7, Y: 4}},
-				UsedTileLocs: map[tile.X]map[tile.Y]tile.Tile{7: {4: {ID: 8}}},
+			want: []bool{true, false, false},
+		},
+		{
+			Config: game.Config{
+				MinLength: 4,
 			},
+			want: []bool{false, false, false},
 		},
 	}
-	for i, test := range handleGameTilesMovedTests {
+	for i, test := range boardReportTests {
 		g := Game{
-			status: test.Status,
-			players: map[player.Name]*playerController.Player{
-				test.Message.PlayerName: {
-					Board: test.Board,
-				},
+			WordValidator: mockDictionary{"cat"},
+			Config: Config{
+				Config: test.Config,
 			},
 		}
-		ctx := context.Background()
-		send := func(m message.Message) {
-			t.Errorf("Test %v: unwanted message sent: %v", i, m)
+		r := g.boardReport(*b)
+		got := make([]bool, len(r.Words))
+		for j, w := range r.Words {
+			got[j] = w.Valid
 		}
-		err := g.handleGameTilesMoved(ctx, test.Message, send)
-		got := g.players[test.Message.PlayerName].Board
-		switch {
-		case !test.wantOk:
-			if err == nil {
-				t.Errorf("Test %v: wanted error moving tiles", i)
-			}
-		case err != nil:
-			t.Errorf("Test %v: unwanted error moving tiles: %v", i, err)
-		case !reflect.DeepEqual(test.want, got):
-			t.Errorf("Test %v: boards not equal:\nwanted: %v\ngot:    %v", i, test.want, got)
+		if !reflect.DeepEqual(test.want, got) {
+			t.Errorf("Test %v: valid words not equal: wanted %v, got %v (%v)", i, test.want, got, r.Words)
 		}
 	}
 }
@@ -2191,6 +5166,7 @@ func TestHandleBoardRefresh(t *testing.T) {
 	send := func(m message.Message) {
 		got = m
 	}
+	g.TimeFunc = func() int64 { return 0 }
 	err := g.handleBoardRefresh(ctx, m, send)
 	switch {
 	case err != nil:
@@ -2239,10 +5215,346 @@ func TestHandleGameChat(t *testing.T) {
 	}
 }
 
+func TestHandleGameReplay(t *testing.T) {
+	events := []replay.Event{
+		{Type: replay.Start, PlayerName: "selene"},
+		{Type: replay.Finish, PlayerName: "selene"},
+	}
+	handleGameReplayTests := []struct {
+		game.Status
+		wantOk bool
+	}{
+		{
+			Status: game.InProgress,
+		},
+		{
+			Status: game.Finished,
+			wantOk: true,
+		},
+	}
+	for i, test := range handleGameReplayTests {
+		g := Game{
+			id:     5,
+			status: test.Status,
+			events: events,
+		}
+		m := message.Message{
+			Type:       message.GameReplay,
+			PlayerName: "fred",
+		}
+		want := message.Message{
+			Type:       message.GameReplay,
+			PlayerName: "fred",
+			Replay: &replay.Replay{
+				GameID: 5,
+				Events: events,
+			},
+		}
+		var got *message.Message
+		send := func(m message.Message) {
+			got = &m
+		}
+		ctx := context.Background()
+		err := g.handleGameReplay(ctx, m, send)
+		switch {
+		case !test.wantOk:
+			if _, ok := err.(gameWarning); !ok {
+				t.Errorf("Test %v: wanted game warning, got %v", i, err)
+			}
+		case err != nil:
+			t.Errorf("Test %v: unwanted error: %v", i, err)
+		case got == nil:
+			t.Errorf("Test %v: wanted replay message to be sent", i)
+		case !reflect.DeepEqual(want, *got):
+			t.Errorf("Test %v: messages not equal:\nwanted: %v\ngot:    %v", i, want, *got)
+		}
+	}
+}
+
+func TestHandleGameHint(t *testing.T) {
+	handleGameHintTests := []struct {
+		game.Status
+		game.Config
+		unusedTiles   []tile.Tile
+		winPoints     int
+		wantOk        bool
+		wantWinPoints int
+	}{
+		{ // not in progress
+			Status: game.Finished,
+			Config: game.Config{
+				Hints: true,
+			},
+		},
+		{ // hints not allowed
+			Status: game.InProgress,
+		},
+		{ // no word can be formed
+			Status: game.InProgress,
+			Config: game.Config{
+				Hints: true,
+			},
+			unusedTiles: []tile.Tile{
+				{ID: 1, Ch: 'X'},
+				{ID: 2, Ch: 'Y'},
+			},
+		},
+		{
+			Status: game.InProgress,
+			Config: game.Config{
+				Hints: true,
+			},
+			unusedTiles: []tile.Tile{
+				{ID: 1, Ch: 'T'},
+				{ID: 2, Ch: 'A'},
+				{ID: 3, Ch: 'C'},
+			},
+			winPoints:     5,
+			wantOk:        true,
+			wantWinPoints: 5,
+		},
+		{
+			Status: game.InProgress,
+			Config: game.Config{
+				Hints:         true,
+				PenalizeHints: true,
+			},
+			unusedTiles: []tile.Tile{
+				{ID: 1, Ch: 'T'},
+				{ID: 2, Ch: 'A'},
+				{ID: 3, Ch: 'C'},
+			},
+			winPoints:     5,
+			wantOk:        true,
+			wantWinPoints: 4,
+		},
+		{ // win points are not decremented past 2
+			Status: game.InProgress,
+			Config: game.Config{
+				Hints:         true,
+				PenalizeHints: true,
+			},
+			unusedTiles: []tile.Tile{
+				{ID: 1, Ch: 'T'},
+				{ID: 2, Ch: 'A'},
+				{ID: 3, Ch: 'C'},
+			},
+			winPoints:     2,
+			wantOk:        true,
+			wantWinPoints: 2,
+		},
+	}
+	for i, test := range handleGameHintTests {
+		b := board.New(test.unusedTiles, nil)
+		b.Config = board.Config{
+			NumRows: 5,
+			NumCols: 5,
+		}
+		p := playerController.Player{
+			WinPoints: test.winPoints,
+			Board:     b,
+		}
+		g := Game{
+			status: test.Status,
+			players: map[player.Name]*playerController.Player{
+				"selene": &p,
+			},
+			WordValidator: mockDictionary{"cat", "dog"},
+			Config: Config{
+				Config: test.Config,
+			},
+		}
+		m := message.Message{
+			Type:       message.Hint,
+			PlayerName: "selene",
+		}
+		var got *message.Message
+		send := func(m message.Message) {
+			got = &m
+		}
+		ctx := context.Background()
+		err := g.handleGameHint(ctx, m, send)
+		switch {
+		case !test.wantOk:
+			if _, ok := err.(gameWarning); !ok {
+				t.Errorf("Test %v: wanted game warning, got %v", i, err)
+			}
+		case err != nil:
+			t.Errorf("Test %v: unwanted error: %v", i, err)
+		case got == nil:
+			t.Errorf("Test %v: wanted hint message to be sent", i)
+		case got.Type != message.Hint, got.PlayerName != "selene", got.Game == nil, got.Game.Board == nil:
+			t.Errorf("Test %v: wanted hint message for player with board, got %v", i, *got)
+		case len(got.Game.Board.UsedTiles) != 3:
+			t.Errorf("Test %v: wanted hint to place all three tiles, got %v", i, got.Game.Board.UsedTiles)
+		case len(p.Board.UsedTiles) != 0:
+			t.Errorf("Test %v: wanted hint to not move tiles on player's board", i)
+		case test.wantWinPoints != p.WinPoints:
+			t.Errorf("Test %v: wanted player to have %v win points, got %v", i, test.wantWinPoints, p.WinPoints)
+		case (test.winPoints != test.wantWinPoints) != (got.Details != nil && got.Details.Penalty == 1):
+			t.Errorf("Test %v: wanted penalty in details only if win points were decremented, got %v", i, got.Details)
+		}
+	}
+}
+
+func TestHandleGameSpectate(t *testing.T) {
+	handleGameSpectateTests := []struct {
+		game.Status
+		message.Message
+		inviteCode string
+		wantOk     bool
+	}{
+		{ // not started
+			Status: game.NotStarted,
+			Message: message.Message{
+				PlayerName: "fred",
+			},
+		},
+		{ // player in game
+			Status: game.InProgress,
+			Message: message.Message{
+				PlayerName: "selene",
+			},
+		},
+		{ // private game without invite code
+			Status: game.InProgress,
+			Message: message.Message{
+				PlayerName: "fred",
+				Addr:       "fred.pc",
+			},
+			inviteCode: "b4n4n4",
+		},
+		{
+			Status: game.InProgress,
+			Message: message.Message{
+				PlayerName: "fred",
+				Addr:       "fred.pc",
+			},
+			wantOk: true,
+		},
+		{ // private game
+			Status: game.InProgress,
+			Message: message.Message{
+				PlayerName: "fred",
+				Addr:       "fred.pc",
+				Game: &game.Info{
+					InviteCode: "b4n4n4",
+				},
+			},
+			inviteCode: "b4n4n4",
+			wantOk:     true,
+		},
+	}
+	for i, test := range handleGameSpectateTests {
+		seleneBoard := board.New([]tile.Tile{{ID: 1, Ch: 'A'}}, nil)
+		g := Game{
+			status: test.Status,
+			players: map[player.Name]*playerController.Player{
+				"selene": {
+					Board: seleneBoard,
+				},
+			},
+			unusedTiles: []tile.Tile{{ID: 2, Ch: 'B'}},
+			Config: Config{
+				Config: game.Config{
+					InviteCode: test.inviteCode,
+				},
+			},
+		}
+		var got *message.Message
+		send := func(m message.Message) {
+			got = &m
+		}
+		ctx := context.Background()
+		err := g.handleGameSpectate(ctx, test.Message, send)
+		switch {
+		case !test.wantOk:
+			if _, ok := err.(gameWarning); !ok {
+				t.Errorf("Test %v: wanted game warning, got %v", i, err)
+			}
+			if got == nil || got.Type != message.LeaveGame {
+				t.Errorf("Test %v: wanted spectator to be told to leave game, got %v", i, got)
+			}
+		case err != nil:
+			t.Errorf("Test %v: unwanted error: %v", i, err)
+		case !g.hasSpectators:
+			t.Errorf("Test %v: wanted game to know it has spectators", i)
+		default:
+			want := message.Message{
+				Type:       message.SpectateGame,
+				PlayerName: "fred",
+				Info:       "spectating game",
+				Addr:       "fred.pc",
+				Game: &game.Info{
+					Status:    game.InProgress,
+					TilesLeft: 1,
+					Players:   []string{"selene"},
+					PlayerBoards: map[string]board.Board{
+						"selene": *seleneBoard,
+					},
+				},
+			}
+			if !reflect.DeepEqual(want, *got) {
+				t.Errorf("Test %v: messages not equal:\nwanted: %v\ngot:    %v", i, want, *got)
+			}
+		}
+	}
+}
+
+func TestSpectatorBoards(t *testing.T) {
+	a := tile.Tile{ID: 1, Ch: 'A'}
+	b := tile.Tile{ID: 2, Ch: 'B'}
+	boardConfig := board.Config{NumCols: 10, NumRows: 10}
+	g := Game{
+		status: game.InProgress,
+		players: map[player.Name]*playerController.Player{
+			"selene": {
+				Board: &board.Board{
+					UnusedTiles: map[tile.ID]tile.Tile{
+						1: a,
+						2: b,
+					},
+					UnusedTileIDs: []tile.ID{1, 2},
+				},
+			},
+		},
+		events: []replay.Event{
+			{Time: 10, Type: replay.Join, PlayerName: "selene", Tiles: []tile.Tile{a}, BoardConfig: &boardConfig},
+			{Time: 20, Type: replay.Start, PlayerName: "selene"},
+			{Time: 40, Type: replay.Snag, PlayerName: "selene", Tiles: []tile.Tile{b}},
+		},
+		Config: Config{
+			TimeFunc: func() int64 {
+				return 50
+			},
+			SpectateDelay: 15 * time.Second,
+		},
+	}
+	got, err := g.spectatorBoards()
+	switch {
+	case err != nil:
+		t.Errorf("unwanted error: %v", err)
+	case len(got["selene"].UnusedTileIDs) != 1:
+		t.Errorf("wanted delayed board to not have tile snagged within delay, got %v", got["selene"])
+	}
+	g.SpectateDelay = 0
+	got, err = g.spectatorBoards()
+	switch {
+	case err != nil:
+		t.Errorf("unwanted error: %v", err)
+	case len(got["selene"].UnusedTileIDs) != 2:
+		t.Errorf("wanted current board when there is no delay, got %v", got["selene"])
+	}
+}
+
 func TestResizeBoard(t *testing.T) {
 	barneyBoard := &board.Board{
 		UnusedTileIDs: []tile.ID{2},
 	}
+	resized := func(b *board.Board) *board.Board {
+		b.Revision = 1
+		return b
+	}
 	resizeBoardTests := []struct {
 		message.Message
 		playerBoard     board.Board
@@ -2273,7 +5585,7 @@ func TestResizeBoard(t *testing.T) {
 				Info:       "",
 				PlayerName: "fred",
 				Game: &game.Info{
-					Board:     board.New(nil, nil),
+					Board:     resized(board.New(nil, nil)),
 					TilesLeft: 3,
 					Status:    game.InProgress,
 					Players:   []string{"barney", "fred"},
@@ -2306,7 +5618,7 @@ func TestResizeBoard(t *testing.T) {
 			want: message.Message{
 				PlayerName: "fred",
 				Game: &game.Info{
-					Board:   board.New([]tile.Tile{{ID: 1}}, nil),
+					Board:   resized(board.New([]tile.Tile{{ID: 1}}, nil)),
 					Players: []string{"barney", "fred"},
 					Config:  new(game.Config),
 				},
@@ -2324,12 +5636,12 @@ func TestResizeBoard(t *testing.T) {
 			want: message.Message{
 				PlayerName: "fred",
 				Game: &game.Info{
-					Board:   board.New(nil, nil),
+					Board:   resized(board.New(nil, nil)),
 					Status:  game.Finished,
 					Players: []string{"barney", "fred"},
 					Config:  new(game.Config),
 					FinalBoards: map[string]board.Board{
-						"fred":   {},
+						"fred":   {Revision: 1},
 						"barney": *barneyBoard,
 					},
 				},
@@ -2390,3 +5702,143 @@ func TestPlayerFinalBoards(t *testing.T) {
 		t.Errorf("final boards not equal:\nwanted: %v\ngot:    %v", want, got)
 	}
 }
+
+func TestShareTeamBoards(t *testing.T) {
+	players := map[player.Name]*playerController.Player{
+		"fred":   {Board: board.New(nil, nil), Team: 1},
+		"barney": {Board: board.New(nil, nil), Team: 1},
+		"wilma":  {Board: board.New(nil, nil), Team: 2},
+		"selene": {Board: board.New(nil, nil)},
+		"moe":    {Board: board.New(nil, nil)},
+	}
+	shareTeamBoards(players)
+	switch {
+	case players["fred"].Board != players["barney"].Board:
+		t.Errorf("wanted teammates to share a board")
+	case players["fred"].Board == players["wilma"].Board:
+		t.Errorf("wanted players on different teams to have different boards")
+	case players["selene"].Board == players["moe"].Board:
+		t.Errorf("wanted players who are not on teams to have their own boards")
+	}
+}
+
+func TestRestoreGame(t *testing.T) {
+	cfg := Config{
+		TimeFunc:               func() int64 { return 99 },
+		MaxPlayers:             3,
+		NumNewTiles:            1,
+		TileLetters:            "ABC",
+		IdlePeriod:             1 * time.Hour,
+		ShuffleUnusedTilesFunc: func(tiles []tile.Tile) {},
+		ShufflePlayersFunc:     func(playerNames []player.Name) {},
+	}
+	restoreGameTests := []struct {
+		state.Game
+		wantOk   bool
+		wantHost player.Name
+	}{
+		{ // bad id
+		},
+		{ // missing player board
+			Game: state.Game{
+				ID: 3,
+				Players: map[player.Name]state.Player{
+					"selene": {},
+				},
+			},
+		},
+		{
+			Game: state.Game{
+				ID:        3,
+				CreatedAt: 47,
+				Status:    game.InProgress,
+				Players: map[player.Name]state.Player{
+					"selene": {
+						WinPoints: 8,
+						Board:     board.New([]tile.Tile{{ID: 1, Ch: 'A'}}, nil),
+						Ready:     true,
+						Forfeited: true,
+					},
+				},
+				Host:        "selene",
+				UnusedTiles: []tile.Tile{{ID: 2, Ch: 'B'}},
+				Config: game.Config{
+					Penalize: true,
+				},
+				FinishOrder: []player.Name{"selene"},
+			},
+			wantOk:   true,
+			wantHost: "selene",
+		},
+		{ // missing challenge
+			Game: state.Game{
+				ID:     3,
+				Status: game.Challenging,
+			},
+		},
+		{ // saved while a win is challenged
+			Game: state.Game{
+				ID:        3,
+				CreatedAt: 47,
+				Status:    game.Challenging,
+				Players: map[player.Name]state.Player{
+					"selene": {
+						WinPoints: 8,
+						Board:     board.New(nil, nil),
+					},
+					"barney": {
+						Board: board.New(nil, nil),
+					},
+				},
+				Host: "selene",
+				Challenge: &state.Challenge{
+					Claimant: "selene",
+					Words:    []string{"AT"},
+					EndsAt:   120,
+					Votes:    map[player.Name][]string{"barney": {"AT"}},
+				},
+			},
+			wantOk:   true,
+			wantHost: "selene",
+		},
+		{ // saved without a host
+			Game: state.Game{
+				ID:        3,
+				CreatedAt: 47,
+				Players: map[player.Name]state.Player{
+					"selene": {
+						WinPoints: 8,
+						Board:     board.New(nil, nil),
+					},
+					"barney": {
+						Board: board.New(nil, nil),
+					},
+				},
+			},
+			wantOk:   true,
+			wantHost: "barney",
+		},
+	}
+	for i, test := range restoreGameTests {
+		log := logtest.DiscardLogger
+		var wordValidator mockWordValidator
+		var userDao mockUserDao
+		var stateStore mockStateStore
+		var resultStore mockResultStore
+		g, err := cfg.restoreGame(log, test.Game, wordValidator, userDao, stateStore, resultStore)
+		switch {
+		case !test.wantOk:
+			if err == nil {
+				t.Errorf("Test %v: wanted error restoring game", i)
+			}
+		case err != nil:
+			t.Errorf("Test %v: unwanted error restoring game: %v", i, err)
+		case g.createdAt != 47, g.players["selene"].WinPoints != 8, g.TileLetters != "ABC":
+			t.Errorf("Test %v: fields not set: %v", i, g)
+		case test.wantHost != g.host:
+			t.Errorf("Test %v: hosts not equal: wanted %v, got %v", i, test.wantHost, g.host)
+		case len(test.Game.Host) != 0 && !reflect.DeepEqual(test.Game, g.state()):
+			t.Errorf("Test %v: state of restored game not equal:\nwanted: %v\ngot:    %v", i, test.Game, g.state())
+		}
+	}
+}
@@ -24,6 +24,7 @@ type (
 		// socketMessages is used for sending messages to the socket runner that stem from HTTP requests to add and remove sockets.
 		socketMessages chan message.Socket
 		// games is a cache of game infos.  This is useful so all can be easily sent out if the info for one game changes.
+		// Private games are only sent to their players.
 		games map[game.ID]game.Info
 		Config
 	}
@@ -138,7 +139,7 @@ func (l *Lobby) RemoveUser(username string) {
 func (l *Lobby) handleSocketMessage(m message.Message, gameRunnerIn, socketRunnerIn chan<- message.Message) {
 	switch m.Type {
 	case message.GameInfos:
-		m.Games = l.gameInfos()
+		m.Games = l.gameInfos(m.PlayerName)
 		message.Send(m, socketRunnerIn, l.Debug, l.log)
 	default:
 		message.Send(m, gameRunnerIn, l.Debug, l.log)
@@ -156,11 +157,13 @@ func (l *Lobby) handleGameMessage(m message.Message, socketRunnerIn chan<- messa
 }
 
 // handleGameInfo updates the game info for the game.
+// The public game infos are sent to all sockets and then the players of private games are sent the infos they can see.
 func (l *Lobby) handleGameInfoChanged(m message.Message, socketRunnerIn chan<- message.Message) {
 	if m.Game == nil {
 		m2 := message.Message{
 			Type:       message.SocketError,
 			Info:       "cannot update game info when no game is provided",
+			Code:       message.CodeServerError,
 			PlayerName: m.PlayerName,
 		}
 		message.Send(m2, socketRunnerIn, l.Debug, l.log)
@@ -173,22 +176,52 @@ func (l *Lobby) handleGameInfoChanged(m message.Message, socketRunnerIn chan<- m
 	default:
 		l.games[m.Game.ID] = *m.Game
 	}
-	infos := l.gameInfos()
+	infos := l.gameInfos("")
 	m2 := message.Message{
 		Type:  message.GameInfos,
 		Games: infos,
 	}
 	message.Send(m2, socketRunnerIn, l.Debug, l.log)
+	for _, pn := range l.privateGamePlayers() {
+		m3 := message.Message{
+			Type:       message.GameInfos,
+			PlayerName: pn,
+			Games:      l.gameInfos(pn),
+		}
+		message.Send(m3, socketRunnerIn, l.Debug, l.log)
+	}
 }
 
-// game infos gets the sorted game infos for the Lobby.
-func (l *Lobby) gameInfos() []game.Info {
+// game infos gets the sorted game infos for the Lobby that the player can see.
+func (l *Lobby) gameInfos(pn player.Name) []game.Info {
 	infos := make([]game.Info, 0, len(l.games))
 	for _, info := range l.games {
-		infos = append(infos, info)
+		if info.IsVisibleTo(string(pn)) {
+			infos = append(infos, info)
+		}
 	}
 	sort.Slice(infos, func(i, j int) bool {
 		return infos[i].ID < infos[j].ID
 	})
 	return infos
 }
+
+// privateGamePlayers gets the sorted names of the players in private games.
+func (l *Lobby) privateGamePlayers() []player.Name {
+	playerNamesM := make(map[player.Name]struct{})
+	for _, info := range l.games {
+		if info.Private {
+			for _, pn := range info.Players {
+				playerNamesM[player.Name(pn)] = struct{}{}
+			}
+		}
+	}
+	playerNames := make([]player.Name, 0, len(playerNamesM))
+	for pn := range playerNamesM {
+		playerNames = append(playerNames, pn)
+	}
+	sort.Slice(playerNames, func(i, j int) bool {
+		return playerNames[i] < playerNames[j]
+	})
+	return playerNames
+}
@@ -262,7 +262,20 @@ func TestHandleSocketMessage(t *testing.T) {
 			wantSocketM: message.Message{
 				Type:  message.GameInfos,
 				Addr:  "test.two",
-				Games: []game.Info{{}, {}},
+				Games: []game.Info{{ID: 1}, {ID: 2}},
+			},
+		},
+		{ // private game of player
+			Message: message.Message{
+				Type:       message.GameInfos,
+				PlayerName: "selene",
+				Addr:       "test.three",
+			},
+			wantSocketM: message.Message{
+				Type:       message.GameInfos,
+				PlayerName: "selene",
+				Addr:       "test.three",
+				Games:      []game.Info{{ID: 1}, {ID: 2}, {ID: 3, Private: true, Players: []string{"selene"}}},
 			},
 		},
 	}
@@ -291,8 +304,9 @@ func TestHandleSocketMessage(t *testing.T) {
 				return nil
 			}),
 			games: map[game.ID]game.Info{
-				1: {},
-				2: {},
+				1: {ID: 1},
+				2: {ID: 2},
+				3: {ID: 3, Private: true, Players: []string{"selene"}},
 			},
 		}
 		ctx := context.Background()
@@ -427,3 +441,50 @@ func TestHandleGameMessage(t *testing.T) {
 		}
 	}
 }
+
+func TestHandleGameInfoChangedPrivate(t *testing.T) {
+	l := Lobby{
+		log: logtest.DiscardLogger,
+		games: map[game.ID]game.Info{
+			1: {ID: 1, Players: []string{"fred"}},
+			2: {ID: 2, Private: true, Players: []string{"selene"}},
+		},
+	}
+	m := message.Message{
+		Type: message.GameInfos,
+		Game: &game.Info{ID: 3, Private: true, Players: []string{"selene", "barney"}},
+	}
+	socketRunnerIn := make(chan message.Message, 3)
+	l.handleGameInfoChanged(m, socketRunnerIn)
+	close(socketRunnerIn)
+	want := []message.Message{
+		{
+			Type:  message.GameInfos,
+			Games: []game.Info{{ID: 1, Players: []string{"fred"}}},
+		},
+		{
+			Type:       message.GameInfos,
+			PlayerName: "barney",
+			Games: []game.Info{
+				{ID: 1, Players: []string{"fred"}},
+				{ID: 3, Private: true, Players: []string{"selene", "barney"}},
+			},
+		},
+		{
+			Type:       message.GameInfos,
+			PlayerName: "selene",
+			Games: []game.Info{
+				{ID: 1, Players: []string{"fred"}},
+				{ID: 2, Private: true, Players: []string{"selene"}},
+				{ID: 3, Private: true, Players: []string{"selene", "barney"}},
+			},
+		},
+	}
+	var got []message.Message
+	for m := range socketRunnerIn {
+		got = append(got, m)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("messages not equal:\nwanted: %v\ngot:    %v", want, got)
+	}
+}
@@ -1,6 +1,13 @@
 package game
 
-import "context"
+import (
+	"context"
+	"strings"
+
+	"github.com/jacobpatterson1549/selene-bananas/db/result"
+	"github.com/jacobpatterson1549/selene-bananas/db/state"
+	"github.com/jacobpatterson1549/selene-bananas/game"
+)
 
 type mockWordValidator func(word string) bool
 
@@ -8,10 +15,60 @@ func (m mockWordValidator) Validate(word string) bool {
 	return m(word)
 }
 
+func (m mockWordValidator) Words() []string {
+	return nil
+}
+
+type mockDictionary []string
+
+func (m mockDictionary) Validate(word string) bool {
+	for _, w := range m {
+		if strings.EqualFold(w, word) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m mockDictionary) Words() []string {
+	return m
+}
+
 type mockUserDao struct {
 	UpdatePointsIncrementFunc func(ctx context.Context, userPoints map[string]int) error
+	ReadPointsFunc            func(ctx context.Context, username string) (int, error)
 }
 
 func (m mockUserDao) UpdatePointsIncrement(ctx context.Context, userPoints map[string]int) error {
 	return m.UpdatePointsIncrementFunc(ctx, userPoints)
 }
+
+func (m mockUserDao) ReadPoints(ctx context.Context, username string) (int, error) {
+	return m.ReadPointsFunc(ctx, username)
+}
+
+type mockStateStore struct {
+	SaveFunc    func(ctx context.Context, g state.Game) error
+	ReadAllFunc func(ctx context.Context) ([]state.Game, error)
+	DeleteFunc  func(ctx context.Context, id game.ID) error
+}
+
+func (m mockStateStore) Save(ctx context.Context, g state.Game) error {
+	return m.SaveFunc(ctx, g)
+}
+
+func (m mockStateStore) ReadAll(ctx context.Context) ([]state.Game, error) {
+	return m.ReadAllFunc(ctx)
+}
+
+func (m mockStateStore) Delete(ctx context.Context, id game.ID) error {
+	return m.DeleteFunc(ctx, id)
+}
+
+type mockResultStore struct {
+	CreateFunc func(ctx context.Context, r result.Result) error
+}
+
+func (m mockResultStore) Create(ctx context.Context, r result.Result) error {
+	return m.CreateFunc(ctx, r)
+}
@@ -12,6 +12,16 @@ type (
 	Player struct {
 		WinPoints int
 		Board     *board.Board
+		// Ready is a flag for players who are ready for the game to start.
+		Ready bool
+		// Forfeited is a flag for players who gave up the game.  The boards of players who forfeited are frozen.
+		Forfeited bool
+		// Team is the number of the team of the player.  Teammates share a board.  Players are not on a team if this is zero.
+		Team int
+		// Swaps is the number of tiles the player has swapped.
+		Swaps int
+		// LastSwapTime is when the player last swapped a tile.
+		LastSwapTime int64
 	}
 
 	// Config can be used to create new players.
@@ -2,9 +2,12 @@ package game
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 
+	"github.com/jacobpatterson1549/selene-bananas/db/result"
+	"github.com/jacobpatterson1549/selene-bananas/db/state"
 	"github.com/jacobpatterson1549/selene-bananas/game"
 	"github.com/jacobpatterson1549/selene-bananas/game/message"
 	"github.com/jacobpatterson1549/selene-bananas/game/player"
@@ -19,12 +22,19 @@ type (
 		// games maps game ids to the channel each games listens to for incoming messages
 		// OutChannels are stored here because the Runner writes to the game, which in turn reads from the Runner's channel as an InChannel
 		games map[game.ID]chan<- message.Message
+		// gamesDone maps game ids to channels that are closed when the games stop running.
+		// Games stop running when they are deleted by their hosts or become idle.
+		gamesDone map[game.ID]<-chan struct{}
 		// lastID is the ID of themost recently created game.  The next new game should get a larger ID.
 		lastID game.ID
-		// WordValidator is used to validate players' words when they try to finish the game.
-		WordValidator WordValidator
+		// wordValidators are used to validate players' words when they try to finish games, keyed by the language of the words.
+		wordValidators map[game.Language]WordValidator
 		// UserDao increments user points when a game is finished.
 		userDao UserDao
+		// stateStore saves the states of games so they can be restored when the runner starts.
+		stateStore StateStore
+		// resultStore records the results of finished games.
+		resultStore ResultStore
 		// RunnerConfig contains configuration properties of the Runner.
 		RunnerConfig
 	}
@@ -42,26 +52,49 @@ type (
 	// WordValidator checks if words are valid.
 	WordValidator interface {
 		Validate(word string) bool
+		Words() []string
 	}
 
 	// UserDao makes changes to the stored state of users in the game
 	UserDao interface {
 		// UpdatePointsIncrement increments points for the specified usernames based on the userPointsIncrementFunc
 		UpdatePointsIncrement(ctx context.Context, userPoints map[string]int) error
+		// ReadPoints gets the total points the user has earned.
+		ReadPoints(ctx context.Context, username string) (int, error)
+	}
+
+	// StateStore persists the states of games so they can be resumed after the server restarts.
+	StateStore interface {
+		// Save creates or replaces the state of the game.
+		Save(ctx context.Context, g state.Game) error
+		// ReadAll gets the states of all saved games.
+		ReadAll(ctx context.Context) ([]state.Game, error)
+		// Delete removes the state of the game.
+		Delete(ctx context.Context, id game.ID) error
+	}
+
+	// ResultStore records the outcomes of finished games so players can view their statistics.
+	ResultStore interface {
+		// Create adds the result of a finished game.
+		Create(ctx context.Context, r result.Result) error
 	}
 )
 
 // NewRunner creates a new game runner from the config.
-func (cfg RunnerConfig) NewRunner(log log.Logger, WordValidator WordValidator, userDao UserDao) (*Runner, error) {
-	if err := cfg.validate(log, WordValidator, userDao); err != nil {
+// A word validator is required for each language games can be created in.
+func (cfg RunnerConfig) NewRunner(log log.Logger, wordValidators map[game.Language]WordValidator, userDao UserDao, stateStore StateStore, resultStore ResultStore) (*Runner, error) {
+	if err := cfg.validate(log, wordValidators, userDao, stateStore, resultStore); err != nil {
 		return nil, fmt.Errorf("creating game runner: validation: %w", err)
 	}
 	m := Runner{
-		log:           log,
-		games:         make(map[game.ID]chan<- message.Message, cfg.MaxGames),
-		RunnerConfig:  cfg,
-		WordValidator: WordValidator,
-		userDao:       userDao,
+		log:            log,
+		games:          make(map[game.ID]chan<- message.Message, cfg.MaxGames),
+		gamesDone:      make(map[game.ID]<-chan struct{}, cfg.MaxGames),
+		RunnerConfig:   cfg,
+		wordValidators: wordValidators,
+		userDao:        userDao,
+		stateStore:     stateStore,
+		resultStore:    resultStore,
 	}
 	return &m, nil
 }
@@ -77,6 +110,7 @@ func (r *Runner) Run(ctx context.Context, wg *sync.WaitGroup, in <-chan message.
 		defer r.log.Printf("game runner stopped")
 		defer close(out)
 		defer cancelFunc()
+		r.restoreGames(ctx, wg, out)
 		for { // BLOCKING
 			select {
 			case <-ctx.Done():
@@ -94,27 +128,61 @@ func (r *Runner) Run(ctx context.Context, wg *sync.WaitGroup, in <-chan message.
 }
 
 // validate ensures the configuration has no errors.
-func (cfg RunnerConfig) validate(log log.Logger, WordValidator WordValidator, userDao UserDao) error {
+func (cfg RunnerConfig) validate(log log.Logger, wordValidators map[game.Language]WordValidator, userDao UserDao, stateStore StateStore, resultStore ResultStore) error {
 	switch {
 	case log == nil:
 		return fmt.Errorf("log required")
-	case WordValidator == nil:
-		return fmt.Errorf("word validator required")
+	case len(wordValidators) == 0:
+		return fmt.Errorf("word validators required")
 	case userDao == nil:
 		return fmt.Errorf("user dao required")
+	case stateStore == nil:
+		return fmt.Errorf("state store required")
+	case resultStore == nil:
+		return fmt.Errorf("result store required")
 	case cfg.MaxGames < 1:
 		return fmt.Errorf("must be able to create at least one game")
 	}
 	return nil
 }
 
+// restoreGames runs the games that were saved before the runner started.
+// Players can rejoin restored games to continue playing them.
+func (r *Runner) restoreGames(ctx context.Context, wg *sync.WaitGroup, out chan<- message.Message) {
+	states, err := r.stateStore.ReadAll(ctx)
+	if err != nil {
+		r.log.Printf("reading saved games: %v", err)
+		return
+	}
+	for _, s := range states {
+		if s.ID > r.lastID {
+			r.lastID = s.ID
+		}
+		if len(r.games) >= r.MaxGames {
+			r.log.Printf("not restoring game %v: the maximum number of games have already been created (%v)", s.ID, r.MaxGames)
+			continue
+		}
+		wordValidator, err := r.wordValidator(s.Config.Language)
+		if err != nil {
+			r.log.Printf("restoring game %v: %v", s.ID, err)
+			continue
+		}
+		gameCfg := r.GameConfig
+		g, err := gameCfg.restoreGame(r.log, s, wordValidator, r.userDao, r.stateStore, r.resultStore)
+		if err != nil {
+			r.log.Printf("restoring game %v: %v", s.ID, err)
+			continue
+		}
+		g.handleInfoChanged(g.sendMessage(out)) // notify the lobby of the game before it is run
+		r.runGame(ctx, wg, s.ID, g, out)
+	}
+}
+
 // handleMessage takes appropriate actions for different message types.
 func (r *Runner) handleMessage(ctx context.Context, wg *sync.WaitGroup, m message.Message, out chan<- message.Message) {
 	switch m.Type {
 	case message.CreateGame:
 		r.createGame(ctx, wg, m, out)
-	case message.DeleteGame:
-		r.deleteGame(ctx, m, out)
 	default:
 		r.handleGameMessage(ctx, m, out)
 	}
@@ -122,79 +190,126 @@ func (r *Runner) handleMessage(ctx context.Context, wg *sync.WaitGroup, m messag
 
 // createGame allocates a new game, adding it to the open games.
 func (r *Runner) createGame(ctx context.Context, wg *sync.WaitGroup, m message.Message, out chan<- message.Message) {
+	r.removeDoneGames()
 	if err := r.validateCreateGame(m); err != nil {
 		r.sendError(err, m.PlayerName, out)
 		return
 	}
+	wordValidator, err := r.wordValidator(m.Game.Config.Language)
+	if err != nil {
+		r.sendError(err, m.PlayerName, out)
+		return
+	}
 	id := r.lastID + 1
 	gameCfg := r.GameConfig
 	gameCfg.Config = *m.Game.Config
-	g, err := gameCfg.NewGame(r.log, id, r.WordValidator, r.userDao)
+	g, err := gameCfg.NewGame(r.log, id, wordValidator, r.userDao, r.stateStore, r.resultStore)
 	if err != nil {
-		r.sendError(err, m.PlayerName, out)
+		r.sendError(gameError{code: message.CodeInvalidConfig, err: err}, m.PlayerName, out)
 		return
 	}
 	r.lastID = id
-	gIn := make(chan message.Message)
-	g.Run(ctx, wg, gIn, out) // all games publish to the same "out" channel
-	r.games[id] = gIn
+	gIn := r.runGame(ctx, wg, id, g, out)
 	m.Type = message.JoinGame
 	message.Send(m, gIn, r.Debug, r.log)
 }
 
+// runGame runs the game, adding it to the games of the runner.
+func (r *Runner) runGame(ctx context.Context, wg *sync.WaitGroup, id game.ID, g *Game, out chan<- message.Message) chan<- message.Message {
+	gIn := make(chan message.Message)
+	done := g.Run(ctx, wg, gIn, out) // all games publish to the same "out" channel
+	r.games[id] = gIn
+	r.gamesDone[id] = done
+	return gIn
+}
+
+// removeDoneGames removes games that have stopped running from the runner.
+func (r *Runner) removeDoneGames() {
+	for id, done := range r.gamesDone {
+		select {
+		case <-done:
+			r.removeGame(id)
+		default:
+		}
+	}
+}
+
+// removeGame removes the game from the runner.
+func (r *Runner) removeGame(id game.ID) {
+	delete(r.games, id)
+	delete(r.gamesDone, id)
+}
+
+// wordValidator gets the word validator for games in the language.
+func (r Runner) wordValidator(l game.Language) (WordValidator, error) {
+	wordValidator, ok := r.wordValidators[l.OrDefault()]
+	if !ok || wordValidator == nil {
+		return nil, gameError{code: message.CodeInvalidConfig, err: fmt.Errorf("games cannot be played in language %q", string(l))}
+	}
+	return wordValidator, nil
+}
+
 // validateCreateGame returns an err if the runner cannot create a new game or the message to create one is invalid.
 func (r *Runner) validateCreateGame(m message.Message) error {
 	switch {
 	case len(r.games) >= r.MaxGames:
-		return fmt.Errorf("the maximum number of games have already been created (%v)", r.MaxGames)
+		return gameError{code: message.CodeTooManyGames, err: fmt.Errorf("the maximum number of games have already been created (%v)", r.MaxGames)}
 	case m.Game == nil, m.Game.Board == nil:
-		return fmt.Errorf("board config required when creating game")
+		return gameError{code: message.CodeInvalidConfig, err: fmt.Errorf("board config required when creating game")}
 	case m.Game.Config == nil:
-		return fmt.Errorf("missing config for game properties")
+		return gameError{code: message.CodeInvalidConfig, err: fmt.Errorf("missing config for game properties")}
 	}
 	return nil
 }
 
-// deleteGame removes a game from the runner, notifying the game that it is being deleted so it can notify users.
-func (r *Runner) deleteGame(ctx context.Context, m message.Message, out chan<- message.Message) {
-	gIn, err := r.getGame(m)
-	if err != nil {
-		r.sendError(err, m.PlayerName, out)
-		return
-	}
-	delete(r.games, m.Game.ID)
-	message.Send(m, gIn, r.Debug, r.log)
-}
-
-// handleGameMessage passes an error to the game the message is for.
+// handleGameMessage passes the message to the game it is for.
+// Games that have stopped running, such as games that were deleted, are removed from the runner.
 func (r *Runner) handleGameMessage(ctx context.Context, m message.Message, out chan<- message.Message) {
 	gIn, err := r.getGame(m)
 	if err != nil {
-		r.sendError(err, m.PlayerName, out)
+		if m.Type != message.LeaveGame { // players can leave games that no longer exist
+			r.sendError(err, m.PlayerName, out)
+		}
 		return
 	}
-	message.Send(m, gIn, r.Debug, r.log)
+	if r.Debug {
+		r.log.Printf("sending message to game %v: %v", m.Game.ID, m)
+	}
+	select {
+	case gIn <- m:
+	case <-r.gamesDone[m.Game.ID]:
+		r.removeGame(m.Game.ID)
+		if m.Type != message.LeaveGame {
+			r.sendError(gameError{code: message.CodeGameNotFound, err: fmt.Errorf("game %v is not running", m.Game.ID)}, m.PlayerName, out)
+		}
+	}
 }
 
 // getGame retrieves the game from the runner for the message, if the runner has a game for the message's game ID.
 func (r *Runner) getGame(m message.Message) (chan<- message.Message, error) {
 	if m.Game == nil {
-		return nil, fmt.Errorf("no game for runner to handle in message: %v", m)
+		return nil, gameError{code: message.CodeGameNotFound, err: fmt.Errorf("no game for runner to handle in message: %v", m)}
 	}
 	gIn, ok := r.games[m.Game.ID]
 	if !ok {
-		return nil, fmt.Errorf("no game ID for runner to handle in message: %v", m)
+		return nil, gameError{code: message.CodeGameNotFound, err: fmt.Errorf("no game ID for runner to handle in message: %v", m)}
 	}
 	return gIn, nil
 }
 
 // sendError adds a message for the player on the channel
 func (r *Runner) sendError(err error, pn player.Name, out chan<- message.Message) {
+	code := message.CodeServerError
+	var ge gameError
+	if errors.As(err, &ge) {
+		code = ge.code
+	}
 	err = fmt.Errorf("player %v: %w", pn, err)
 	r.log.Printf("game runner error: %v", err)
 	m := message.Message{
 		Type:       message.SocketError,
 		Info:       err.Error(),
+		Code:       code,
 		PlayerName: pn,
 	}
 	message.Send(m, out, r.Debug, r.log)
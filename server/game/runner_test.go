@@ -2,11 +2,14 @@ package game
 
 import (
 	"context"
+	"fmt"
 	"reflect"
+	"sort"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/jacobpatterson1549/selene-bananas/db/state"
 	"github.com/jacobpatterson1549/selene-bananas/game"
 	"github.com/jacobpatterson1549/selene-bananas/game/board"
 	"github.com/jacobpatterson1549/selene-bananas/game/message"
@@ -19,13 +22,20 @@ import (
 
 func TestNewRunner(t *testing.T) {
 	var wc mockWordValidator
+	wordValidators := map[game.Language]WordValidator{
+		game.English: wc,
+	}
 	var userDao mockUserDao
+	var stateStore mockStateStore
+	var resultStore mockResultStore
 	testLog := logtest.DiscardLogger
 	newRunnerTests := []struct {
 		log log.Logger
 		RunnerConfig
-		WordValidator
+		wordValidators map[game.Language]WordValidator
 		UserDao
+		StateStore
+		ResultStore
 		wantOk bool
 		want   *Runner
 	}{
@@ -33,52 +43,74 @@ func TestNewRunner(t *testing.T) {
 		{ // no word validater
 			log: testLog,
 		},
+		{ // nil word validator
+			log:            testLog,
+			wordValidators: map[game.Language]WordValidator{},
+		},
 		{ // no user dao
-			log:           testLog,
-			WordValidator: wc,
+			log:            testLog,
+			wordValidators: wordValidators,
 		},
-		{ // low MaxGames
-			log:           testLog,
-			WordValidator: wc,
-			UserDao:       userDao,
+		{ // no state store
+			log:            testLog,
+			wordValidators: wordValidators,
+			UserDao:        userDao,
+		},
+		{ // no result store
+			log:            testLog,
+			wordValidators: wordValidators,
+			UserDao:        userDao,
+			StateStore:     stateStore,
 		},
 		{ // low MaxGames
-			log:           testLog,
-			WordValidator: wc,
-			UserDao:       userDao,
+			log:            testLog,
+			wordValidators: wordValidators,
+			UserDao:        userDao,
+			StateStore:     stateStore,
+			ResultStore:    resultStore,
 		},
 		{ // ok
-			log:           testLog,
-			WordValidator: wc,
-			UserDao:       userDao,
+			log:            testLog,
+			wordValidators: wordValidators,
+			UserDao:        userDao,
+			StateStore:     stateStore,
+			ResultStore:    resultStore,
 			RunnerConfig: RunnerConfig{
 				MaxGames: 10,
 			},
 			wantOk: true,
 			want: &Runner{
-				log:           testLog,
-				games:         make(map[game.ID]chan<- message.Message),
-				WordValidator: wc,
-				userDao:       userDao,
+				log:            testLog,
+				games:          make(map[game.ID]chan<- message.Message),
+				gamesDone:      make(map[game.ID]<-chan struct{}),
+				wordValidators: wordValidators,
+				userDao:        userDao,
+				stateStore:     stateStore,
+				resultStore:    resultStore,
 				RunnerConfig: RunnerConfig{
 					MaxGames: 10,
 				},
 			},
 		},
 		{ // ok debug
-			log:           testLog,
-			WordValidator: wc,
-			UserDao:       userDao,
+			log:            testLog,
+			wordValidators: wordValidators,
+			UserDao:        userDao,
+			StateStore:     stateStore,
+			ResultStore:    resultStore,
 			RunnerConfig: RunnerConfig{
 				Debug:    true,
 				MaxGames: 10,
 			},
 			wantOk: true,
 			want: &Runner{
-				log:           testLog,
-				games:         make(map[game.ID]chan<- message.Message),
-				WordValidator: wc,
-				userDao:       userDao,
+				log:            testLog,
+				games:          make(map[game.ID]chan<- message.Message),
+				gamesDone:      make(map[game.ID]<-chan struct{}),
+				wordValidators: wordValidators,
+				userDao:        userDao,
+				stateStore:     stateStore,
+				resultStore:    resultStore,
 				RunnerConfig: RunnerConfig{
 					Debug:    true,
 					MaxGames: 10,
@@ -87,7 +119,7 @@ func TestNewRunner(t *testing.T) {
 		},
 	}
 	for i, test := range newRunnerTests {
-		got, err := test.RunnerConfig.NewRunner(test.log, test.WordValidator, test.UserDao)
+		got, err := test.RunnerConfig.NewRunner(test.log, test.wordValidators, test.UserDao, test.StateStore, test.ResultStore)
 		switch {
 		case !test.wantOk:
 			if err == nil {
@@ -117,8 +149,14 @@ func TestRunRunner(t *testing.T) {
 		},
 	}
 	for i, test := range runRunnerTests {
+		stateStore := mockStateStore{
+			ReadAllFunc: func(ctx context.Context) ([]state.Game, error) {
+				return nil, nil
+			},
+		}
 		r := Runner{
-			log: logtest.DiscardLogger,
+			log:        logtest.DiscardLogger,
+			stateStore: stateStore,
 		}
 		ctx := context.Background()
 		ctx, cancelFunc := context.WithCancel(ctx)
@@ -140,8 +178,9 @@ func TestGameCreate(t *testing.T) {
 		CheckOnSnag: true,
 	}
 	gameCreateTests := []struct {
-		m      message.Message
-		wantOk bool
+		m        message.Message
+		wantOk   bool
+		wantCode message.Code
 		RunnerConfig
 	}{
 		{ // happy path
@@ -173,6 +212,37 @@ func TestGameCreate(t *testing.T) {
 			},
 			wantOk: true,
 		},
+		{ // unsupported language
+			m: message.Message{
+				Type:       message.CreateGame,
+				PlayerName: "selene",
+				Game: &game.Info{
+					Board: &board.Board{
+						Config: board.Config{NumRows: 18, NumCols: 22},
+					},
+					Config: &game.Config{
+						Language: game.German,
+					},
+				},
+			},
+			wantCode: message.CodeInvalidConfig,
+			RunnerConfig: RunnerConfig{
+				MaxGames: 1,
+				GameConfig: Config{
+					PlayerCfg:   playerController.Config{WinPoints: 10},
+					TimeFunc:    func() int64 { return 0 },
+					MaxPlayers:  1,
+					NumNewTiles: 1,
+					IdlePeriod:  1 * time.Hour,
+					ShuffleUnusedTilesFunc: func(tiles []tile.Tile) {
+						// Not called
+					},
+					ShufflePlayersFunc: func(playerNames []player.Name) {
+						// Not called
+					},
+				},
+			},
+		},
 		{ // no room for game
 			m: message.Message{
 				Type:       message.CreateGame,
@@ -181,6 +251,7 @@ func TestGameCreate(t *testing.T) {
 			RunnerConfig: RunnerConfig{
 				MaxGames: 0,
 			},
+			wantCode: message.CodeTooManyGames,
 		},
 		{ // bad message: no game
 			m: message.Message{
@@ -190,6 +261,7 @@ func TestGameCreate(t *testing.T) {
 			RunnerConfig: RunnerConfig{
 				MaxGames: 1,
 			},
+			wantCode: message.CodeInvalidConfig,
 		}, { // bad message: no board
 			m: message.Message{
 				Type:       message.CreateGame,
@@ -199,6 +271,7 @@ func TestGameCreate(t *testing.T) {
 			RunnerConfig: RunnerConfig{
 				MaxGames: 1,
 			},
+			wantCode: message.CodeInvalidConfig,
 		},
 		{ // no config in game of message
 			m: message.Message{
@@ -213,6 +286,7 @@ func TestGameCreate(t *testing.T) {
 			RunnerConfig: RunnerConfig{
 				MaxGames: 1,
 			},
+			wantCode: message.CodeInvalidConfig,
 		},
 		{ // bad gameConfig
 			m: message.Message{
@@ -231,18 +305,35 @@ func TestGameCreate(t *testing.T) {
 					MaxPlayers: -1,
 				},
 			},
+			wantCode: message.CodeInvalidConfig,
 		},
 	}
 	for i, test := range gameCreateTests {
 		var wordValidator mockWordValidator
-		var userDao mockUserDao
+		userDao := mockUserDao{
+			ReadPointsFunc: func(ctx context.Context, username string) (int, error) {
+				return 0, nil
+			},
+		}
+		stateStore := mockStateStore{
+			SaveFunc: func(ctx context.Context, g state.Game) error {
+				return nil
+			},
+			ReadAllFunc: func(ctx context.Context) ([]state.Game, error) {
+				return nil, nil
+			},
+		}
+		var resultStore mockResultStore
 		r := Runner{
-			log:           logtest.DiscardLogger,
-			games:         make(map[game.ID]chan<- message.Message),
-			lastID:        3,
-			WordValidator: wordValidator,
-			userDao:       userDao,
-			RunnerConfig:  test.RunnerConfig,
+			log:            logtest.DiscardLogger,
+			games:          make(map[game.ID]chan<- message.Message),
+			gamesDone:      make(map[game.ID]<-chan struct{}),
+			lastID:         3,
+			wordValidators: map[game.Language]WordValidator{game.English: wordValidator},
+			userDao:        userDao,
+			stateStore:     stateStore,
+			resultStore:    resultStore,
+			RunnerConfig:   test.RunnerConfig,
 		}
 		ctx := context.Background()
 		ctx, cancelFunc := context.WithCancel(ctx)
@@ -261,13 +352,16 @@ func TestGameCreate(t *testing.T) {
 			if gotM.Type != message.SocketError {
 				t.Errorf("Test %v: wanted returned message to be a warning that to game could be created, but got %v", i, gotM)
 			}
+			if test.wantCode != gotM.Code {
+				t.Errorf("Test %v: error codes not equal: wanted %v, got %v", i, test.wantCode, gotM.Code)
+			}
 		case gotNumGames != 1:
 			t.Errorf("Test %v: wanted 1 game to be created, got %v", i, gotNumGames)
 		case r.games[4] == nil:
 			t.Errorf("Test %v: wanted game of id 4 to be created", i)
 		case gotM.Type != message.JoinGame, gotM.Game.ID != 4, gotM.PlayerName != "selene":
 			t.Errorf("Test %v: wanted join message for game 4 for player, got %v", i, gotM)
-		case r.RunnerConfig.GameConfig.Config != zeroGameConfig:
+		case !reflect.DeepEqual(zeroGameConfig, r.RunnerConfig.GameConfig.Config):
 			t.Errorf("Test %v: did not want the game's config to be stored in the runner", i)
 		case !reflect.DeepEqual(basicGameCfg, gotM.Game.Config):
 			t.Errorf("Test %v: game config not set to basic config:\nwanted: %#v\ngot:    %#v", i, basicGameCfg, gotM.Game.Config)
@@ -313,11 +407,17 @@ func TestGameDelete(t *testing.T) {
 	for i, test := range gameDeleteTests {
 		in := make(chan message.Message)
 		gIn := make(chan message.Message)
+		stateStore := mockStateStore{
+			ReadAllFunc: func(ctx context.Context) ([]state.Game, error) {
+				return nil, nil
+			},
+		}
 		r := Runner{
 			log: logtest.DiscardLogger,
 			games: map[game.ID]chan<- message.Message{
 				5: gIn,
 			},
+			stateStore: stateStore,
 		}
 		ctx := context.Background()
 		ctx, cancelFunc := context.WithCancel(ctx)
@@ -337,12 +437,10 @@ func TestGameDelete(t *testing.T) {
 		m2 := <-out
 		gotNumGames := len(r.games)
 		switch {
-		case !test.wantOk && gotNumGames != 1:
-			t.Errorf("Test %v: wanted 1 game to be not be deleted, got %v", i, gotNumGames)
+		case gotNumGames != 1:
+			t.Errorf("Test %v: wanted game to be removed when it stops running, not when it is sent a delete message, got %v games", i, gotNumGames)
 		case !test.wantOk && m2.Type != message.SocketError:
 			t.Errorf("Test %v: wanted socket error message, got %v", i, m2.Type)
-		case test.wantOk && gotNumGames != 0:
-			t.Errorf("Test %v: wanted game to be deleted, yet %v still existed", i, gotNumGames)
 		case test.wantOk && !messageHandled:
 			t.Errorf("Test %v: message not handled", i)
 		}
@@ -382,11 +480,17 @@ func TestHandleGameMessage(t *testing.T) {
 	for i, test := range handleGameMessageTests {
 		in := make(chan message.Message)
 		gIn := make(chan message.Message)
+		stateStore := mockStateStore{
+			ReadAllFunc: func(ctx context.Context) ([]state.Game, error) {
+				return nil, nil
+			},
+		}
 		r := Runner{
 			log: logtest.DiscardLogger,
 			games: map[game.ID]chan<- message.Message{
 				3: gIn,
 			},
+			stateStore: stateStore,
 		}
 		ctx := context.Background()
 		ctx, cancelFunc := context.WithCancel(ctx)
@@ -409,6 +513,9 @@ func TestHandleGameMessage(t *testing.T) {
 			if m2.Type != message.SocketError {
 				t.Errorf("Test %v: wanted socket error message, got %v", i, m2.Type)
 			}
+			if m2.Code != message.CodeGameNotFound {
+				t.Errorf("Test %v: wanted game not found code, got %v", i, m2.Code)
+			}
 		case !messageHandled:
 			t.Errorf("Test %v: message not handled", i)
 		}
@@ -416,3 +523,176 @@ func TestHandleGameMessage(t *testing.T) {
 		wg.Wait()
 	}
 }
+
+func TestHandleGameMessageStoppedGame(t *testing.T) {
+	handleGameMessageTests := []struct {
+		m         message.Message
+		wantError bool
+	}{
+		{
+			m: message.Message{
+				Type: message.GameChat,
+				Game: &game.Info{
+					ID: 5,
+				},
+			},
+			wantError: true,
+		},
+		{
+			m: message.Message{
+				Type: message.LeaveGame,
+				Game: &game.Info{
+					ID: 5,
+				},
+			},
+		},
+	}
+	for i, test := range handleGameMessageTests {
+		done := make(chan struct{})
+		close(done)
+		r := Runner{
+			log: logtest.DiscardLogger,
+			games: map[game.ID]chan<- message.Message{
+				5: make(chan message.Message), // not read by a game
+			},
+			gamesDone: map[game.ID]<-chan struct{}{
+				5: done,
+			},
+		}
+		ctx := context.Background()
+		out := make(chan message.Message, 1)
+		r.handleGameMessage(ctx, test.m, out)
+		switch {
+		case len(r.games) != 0, len(r.gamesDone) != 0:
+			t.Errorf("Test %v: wanted stopped game to be removed from runner", i)
+		case !test.wantError:
+			if len(out) != 0 {
+				t.Errorf("Test %v: wanted no message to be sent, got %v", i, <-out)
+			}
+		case len(out) != 1:
+			t.Errorf("Test %v: wanted error message to be sent", i)
+		default:
+			if m2 := <-out; m2.Type != message.SocketError || m2.Code != message.CodeGameNotFound {
+				t.Errorf("Test %v: wanted game not found error, got %v", i, m2)
+			}
+		}
+	}
+}
+
+func TestRemoveDoneGames(t *testing.T) {
+	done := make(chan struct{})
+	close(done)
+	r := Runner{
+		games: map[game.ID]chan<- message.Message{
+			1: make(chan message.Message),
+			2: make(chan message.Message),
+		},
+		gamesDone: map[game.ID]<-chan struct{}{
+			1: done,
+			2: make(chan struct{}),
+		},
+	}
+	r.removeDoneGames()
+	if _, ok := r.games[1]; ok || len(r.games) != 1 || len(r.gamesDone) != 1 {
+		t.Errorf("wanted only game 1 to be removed, got %v", r.games)
+	}
+}
+
+func TestRunnerRestoreGames(t *testing.T) {
+	gameCfg := Config{
+		TimeFunc:               func() int64 { return 0 },
+		MaxPlayers:             4,
+		NumNewTiles:            1,
+		IdlePeriod:             1 * time.Hour,
+		ShuffleUnusedTilesFunc: func(tiles []tile.Tile) {},
+		ShufflePlayersFunc:     func(playerNames []player.Name) {},
+	}
+	restoreGamesTests := []struct {
+		readAllErr  error
+		states      []state.Game
+		maxGames    int
+		wantGameIDs []game.ID
+		wantLastID  game.ID
+	}{
+		{
+			readAllErr: fmt.Errorf("mock read all error"),
+			maxGames:   1,
+		},
+		{
+			maxGames: 1,
+		},
+		{
+			states: []state.Game{
+				{ID: 2},
+				{ID: -1}, // invalid
+				{ID: 8},
+			},
+			maxGames:    5,
+			wantGameIDs: []game.ID{2, 8},
+			wantLastID:  8,
+		},
+		{
+			states: []state.Game{
+				{ID: 2},
+				{ID: 8},
+			},
+			maxGames:    1,
+			wantGameIDs: []game.ID{2},
+			wantLastID:  8,
+		},
+		{
+			states: []state.Game{
+				{ID: 2},
+				{ID: 5, Config: game.Config{Language: game.German}}, // no word validator
+			},
+			maxGames:    5,
+			wantGameIDs: []game.ID{2},
+			wantLastID:  5,
+		},
+	}
+	for i, test := range restoreGamesTests {
+		stateStore := mockStateStore{
+			ReadAllFunc: func(ctx context.Context) ([]state.Game, error) {
+				return test.states, test.readAllErr
+			},
+		}
+		var wordValidator mockWordValidator
+		var userDao mockUserDao
+		var resultStore mockResultStore
+		r := Runner{
+			log:            logtest.DiscardLogger,
+			games:          make(map[game.ID]chan<- message.Message),
+			gamesDone:      make(map[game.ID]<-chan struct{}),
+			wordValidators: map[game.Language]WordValidator{game.English: wordValidator},
+			userDao:        userDao,
+			stateStore:     stateStore,
+			resultStore:    resultStore,
+			RunnerConfig: RunnerConfig{
+				MaxGames:   test.maxGames,
+				GameConfig: gameCfg,
+			},
+		}
+		ctx := context.Background()
+		ctx, cancelFunc := context.WithCancel(ctx)
+		var wg sync.WaitGroup
+		out := make(chan message.Message, len(test.states))
+		r.restoreGames(ctx, &wg, out)
+		cancelFunc()
+		wg.Wait()
+		gotGameIDs := make([]game.ID, 0, len(r.games))
+		for id := range r.games {
+			gotGameIDs = append(gotGameIDs, id)
+		}
+		sort.Slice(gotGameIDs, func(i, j int) bool {
+			return gotGameIDs[i] < gotGameIDs[j]
+		})
+		switch {
+		case len(test.wantGameIDs) != len(gotGameIDs), len(test.wantGameIDs) != 0 && !reflect.DeepEqual(test.wantGameIDs, gotGameIDs):
+			t.Errorf("Test %v: restored game ids not equal:\nwanted: %v\ngot:    %v", i, test.wantGameIDs, gotGameIDs)
+		case test.wantLastID != r.lastID:
+			t.Errorf("Test %v: wanted last id to be %v, got %v", i, test.wantLastID, r.lastID)
+		case len(out) != len(test.wantGameIDs):
+			t.Errorf("Test %v: wanted a game infos message for each restored game, got %v", i, len(out))
+		}
+	}
+}
@@ -2,6 +2,7 @@
 package gorilla
 
 import (
+	"encoding/json"
 	"net/http"
 
 	"github.com/gorilla/websocket"
@@ -21,9 +22,12 @@ type (
 )
 
 // NewUpgrader returns a upgrader tha creates gorilla websocket connections.
+// Connections use the binary subprotocol for messages if the client requests it.
 func NewUpgrader() *Upgrader {
-	u := new(websocket.Upgrader)
-	return &Upgrader{u}
+	u := websocket.Upgrader{
+		Subprotocols: []string{message.BinarySubprotocol},
+	}
+	return &Upgrader{&u}
 }
 
 // Upgrade creates a Conn from the http request.
@@ -36,13 +40,29 @@ func (u *Upgrader) Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error
 }
 
 // ReadMessage reads the next message from the GorillaConnection.
+// Binary websocket messages are decoded with the binary encoding of messages.  Text websocket messages are decoded as json.
 func (c *Conn) ReadMessage(m *message.Message) error {
-	return c.Conn.ReadJSON(m)
+	messageType, data, err := c.Conn.ReadMessage()
+	if err != nil {
+		return err
+	}
+	if messageType == websocket.BinaryMessage {
+		return m.UnmarshalBinary(data)
+	}
+	return json.Unmarshal(data, m)
 }
 
-// WriteMessage writes the message as json to the GorillaConnection.
+// WriteMessage writes the message to the GorillaConnection.
+// The message is written as json unless the binary subprotocol was negotiated.
 func (c *Conn) WriteMessage(m message.Message) error {
-	return c.Conn.WriteJSON(m)
+	if c.Subprotocol() != message.BinarySubprotocol {
+		return c.Conn.WriteJSON(m)
+	}
+	data, err := m.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return c.Conn.WriteMessage(websocket.BinaryMessage, data)
 }
 
 // WritePing writes a ping message on the GorillaConnection.
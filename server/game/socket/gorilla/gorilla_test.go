@@ -1,12 +1,17 @@
 package gorilla
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/gorilla/websocket"
+	"github.com/jacobpatterson1549/selene-bananas/game"
+	"github.com/jacobpatterson1549/selene-bananas/game/message"
 )
 
 func TestUpgraderUpgrade(t *testing.T) {
@@ -84,3 +89,93 @@ func TestConnRemoteAddr(t *testing.T) {
 		t.Error("wanted non-nil remote address")
 	}
 }
+
+func TestConnReadWriteMessage(t *testing.T) {
+	readWriteMessageTests := []struct {
+		subprotocols    []string
+		sendBinary      bool
+		wantMessageType int
+	}{
+		{ // json
+			wantMessageType: websocket.TextMessage,
+		},
+		{ // unknown subprotocol
+			subprotocols:    []string{"selene-bananas.xml"},
+			wantMessageType: websocket.TextMessage,
+		},
+		{ // binary
+			subprotocols:    []string{message.BinarySubprotocol},
+			sendBinary:      true,
+			wantMessageType: websocket.BinaryMessage,
+		},
+		{ // binary subprotocol, but json sent
+			subprotocols:    []string{message.BinarySubprotocol},
+			wantMessageType: websocket.BinaryMessage,
+		},
+	}
+	want := message.Message{
+		Type: message.JoinGame,
+		Info: "echo",
+		Game: &game.Info{
+			ID: 3,
+		},
+	}
+	for i, test := range readWriteMessageTests {
+		u := NewUpgrader()
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			conn, err := u.Upgrade(w, r)
+			if err != nil {
+				t.Errorf("Test %v: upgrading: %v", i, err)
+				return
+			}
+			defer conn.Close()
+			var m message.Message
+			if err := conn.ReadMessage(&m); err != nil {
+				t.Errorf("Test %v: reading message: %v", i, err)
+				return
+			}
+			if err := conn.WriteMessage(m); err != nil {
+				t.Errorf("Test %v: writing message: %v", i, err)
+			}
+		}))
+		d := websocket.Dialer{
+			Subprotocols: test.subprotocols,
+		}
+		url := "ws" + strings.TrimPrefix(s.URL, "http")
+		conn, _, err := d.Dial(url, nil)
+		if err != nil {
+			t.Fatalf("Test %v: dialing: %v", i, err)
+		}
+		messageType, data := websocket.TextMessage, []byte(nil)
+		switch {
+		case test.sendBinary:
+			messageType = websocket.BinaryMessage
+			data, err = want.MarshalBinary()
+		default:
+			data, err = json.Marshal(want)
+		}
+		if err != nil {
+			t.Fatalf("Test %v: encoding message: %v", i, err)
+		}
+		if err := conn.WriteMessage(messageType, data); err != nil {
+			t.Fatalf("Test %v: writing message: %v", i, err)
+		}
+		gotMessageType, gotData, err := conn.ReadMessage()
+		var got message.Message
+		switch {
+		case err != nil:
+			t.Errorf("Test %v: reading message: %v", i, err)
+		case test.wantMessageType != gotMessageType:
+			t.Errorf("Test %v: message types not equal: wanted %v, got %v", i, test.wantMessageType, gotMessageType)
+		case gotMessageType == websocket.BinaryMessage:
+			err = got.UnmarshalBinary(gotData)
+		default:
+			err = json.Unmarshal(gotData, &got)
+		}
+		if err == nil && !reflect.DeepEqual(want, got) {
+			t.Errorf("Test %v: messages not equal:\nwanted: %v\ngot:    %v", i, want, got)
+		}
+		conn.Close()
+		s.Close()
+	}
+}
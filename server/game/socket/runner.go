@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/jacobpatterson1549/selene-bananas/game"
 	"github.com/jacobpatterson1549/selene-bananas/game/message"
 	"github.com/jacobpatterson1549/selene-bananas/game/player"
+	"github.com/jacobpatterson1549/selene-bananas/server/game/bot"
 	"github.com/jacobpatterson1549/selene-bananas/server/game/socket/gorilla"
 	"github.com/jacobpatterson1549/selene-bananas/server/log"
 )
@@ -16,11 +18,18 @@ import (
 type (
 	// Runner handles sending messages to different sockets.
 	// The runner allows for players to open multiple sockets, but multiple sockets cannot play in the same game before first leaving.
+	// Sockets can also spectate games that they are not playing in.
+	// When the connection of a socket that is playing a game is lost, the player can resume the game on a new socket before the reconnect period ends.
 	Runner struct {
-		log           log.Logger
-		upgradeFunc   upgradeFunc
-		playerSockets map[player.Name]map[message.Addr]chan<- message.Message
-		playerGames   map[player.Name]map[game.ID]message.Addr
+		log            log.Logger
+		upgradeFunc    upgradeFunc
+		playerSockets  map[player.Name]map[message.Addr]chan<- message.Message
+		playerGames    map[player.Name]map[game.ID]message.Addr
+		gameSpectators map[game.ID]map[message.Addr]player.Name
+		// reconnectDeadlines are the times that the games of disconnected sockets can no longer be resumed, in seconds since the epoch.
+		reconnectDeadlines map[message.Addr]int64
+		// numBots is the number of bots that have been added, used to give each bot a different name.
+		numBots int
 		RunnerConfig
 	}
 
@@ -32,8 +41,13 @@ type (
 		MaxSockets int
 		// The maximum number of sockets each player can open.  Must be no more than maxSockets.
 		MaxPlayerSockets int
+		// ReconnectPeriod is how long a player has to open a new socket to resume the game of a socket that was disconnected.
+		// Games are left as soon as sockets are disconnected if the period is zero.
+		ReconnectPeriod time.Duration
 		// The config for creating new sockets
 		SocketConfig Config
+		// The config for creating bots that players can add to their games.
+		BotConfig bot.Config
 	}
 
 	// upgradeFunc turns a http request into a websocket.
@@ -50,11 +64,13 @@ func (cfg RunnerConfig) NewRunner(log log.Logger) (*Runner, error) {
 		return u.Upgrade(w, r)
 	}
 	r := Runner{
-		log:           log,
-		upgradeFunc:   uf,
-		playerSockets: make(map[player.Name]map[message.Addr]chan<- message.Message, cfg.MaxSockets),
-		playerGames:   make(map[player.Name]map[game.ID]message.Addr),
-		RunnerConfig:  cfg,
+		log:                log,
+		upgradeFunc:        uf,
+		playerSockets:      make(map[player.Name]map[message.Addr]chan<- message.Message, cfg.MaxSockets),
+		playerGames:        make(map[player.Name]map[game.ID]message.Addr),
+		gameSpectators:     make(map[game.ID]map[message.Addr]player.Name),
+		reconnectDeadlines: make(map[message.Addr]int64),
+		RunnerConfig:       cfg,
 	}
 	return &r, nil
 }
@@ -68,6 +84,8 @@ func (cfg RunnerConfig) validate(log log.Logger) error {
 		return fmt.Errorf("each player must be able to open at least one socket")
 	case cfg.MaxSockets < cfg.MaxPlayerSockets:
 		return fmt.Errorf("players cannot create more sockets than the runner allows")
+	case cfg.ReconnectPeriod < 0:
+		return fmt.Errorf("reconnect period cannot be negative")
 	}
 	return nil
 }
@@ -84,6 +102,12 @@ func (r *Runner) Run(ctx context.Context, wg *sync.WaitGroup, in <-chan message.
 		defer r.log.Printf("socket runner stopped")
 		defer close(out)
 		defer cancelFunc()
+		var expireTicks <-chan time.Time
+		if r.ReconnectPeriod > 0 {
+			expireTicker := time.NewTicker(time.Second) // reconnect deadlines are in seconds
+			defer expireTicker.Stop()
+			expireTicks = expireTicker.C
+		}
 		for { // BLOCKING
 			select {
 			case <-ctx.Done():
@@ -99,7 +123,9 @@ func (r *Runner) Run(ctx context.Context, wg *sync.WaitGroup, in <-chan message.
 				}
 				r.handleLobbyModifyRequest(ctx, wg, sm, socketOut, out)
 			case m := <-socketOut:
-				r.handleSocketMessage(ctx, m, out)
+				r.handleSocketMessage(ctx, wg, m, socketOut, out)
+			case <-expireTicks:
+				r.removeExpiredGames(out)
 			}
 		}
 	}
@@ -122,6 +148,55 @@ func (r *Runner) addSocket(ctx context.Context, wg *sync.WaitGroup, sm message.S
 		Addr:       s.Addr,
 	}
 	go message.Send(m, lobbyIn, r.Debug, r.log)
+	if r.ReconnectPeriod > 0 {
+		r.resumeGame(sm.PlayerName, s.Addr, lobbyIn)
+	}
+}
+
+// resumeGame joins the new socket to a game that the player was playing on a disconnected socket if the reconnect period of that socket has not ended.
+// The game is joined without a board so the game sends the current board of the player to the new socket.
+func (r *Runner) resumeGame(pn player.Name, addr message.Addr, lobbyIn chan<- message.Message) {
+	r.removeExpiredGames(lobbyIn)
+	games := r.playerGames[pn]
+	for id, addr2 := range games {
+		if _, ok := r.reconnectDeadlines[addr2]; !ok {
+			continue
+		}
+		delete(r.reconnectDeadlines, addr2)
+		delete(games, id) // the game is added back to the player when the game sends the join message to the new socket
+		if len(games) == 0 {
+			delete(r.playerGames, pn)
+		}
+		m := message.Message{
+			Type:       message.JoinGame,
+			PlayerName: pn,
+			Addr:       addr,
+			Game: &game.Info{
+				ID: id,
+			},
+		}
+		go message.Send(m, lobbyIn, r.Debug, r.log)
+		return // sockets can only play one game
+	}
+}
+
+// removeExpiredGames removes the games of disconnected sockets that were not resumed before the reconnect period ended.
+// The games are told that the players left them.  The messages are sent asynchronously because lobby might be processing other messages.
+func (r *Runner) removeExpiredGames(lobbyIn chan<- message.Message) {
+	now := r.SocketConfig.TimeFunc()
+	for pn, games := range r.playerGames {
+		for id, addr := range games {
+			if deadline, ok := r.reconnectDeadlines[addr]; ok && now >= deadline {
+				delete(r.reconnectDeadlines, addr)
+				delete(games, id)
+				m := leaveGameMessage(pn, addr, id)
+				go message.Send(m, lobbyIn, r.Debug, r.log)
+			}
+		}
+		if len(games) == 0 {
+			delete(r.playerGames, pn)
+		}
+	}
 }
 
 // handleAddSocket runs and adds a socket for the player to the runner.
@@ -197,7 +272,7 @@ func (r *Runner) handleLobbyMessage(ctx context.Context, wg *sync.WaitGroup, m m
 func (r *Runner) handleLobbyModifyRequest(ctx context.Context, wg *sync.WaitGroup, sm message.Socket, socketOut chan<- message.Message, lobbyIn chan<- message.Message) {
 	switch sm.Type {
 	case message.PlayerRemove:
-		r.removePlayer(ctx, sm)
+		r.removePlayer(ctx, sm, lobbyIn)
 	case message.SocketAdd:
 		r.addSocket(ctx, wg, sm, socketOut, lobbyIn)
 	default:
@@ -225,7 +300,7 @@ func (r *Runner) validateSocketMessage(m message.Message) error {
 		return fmt.Errorf("received message without game")
 	}
 	switch m.Type {
-	case message.CreateGame, message.JoinGame, message.SocketClose, message.LeaveGame:
+	case message.CreateGame, message.JoinGame, message.SocketClose, message.LeaveGame, message.SpectateGame:
 		// NOOP
 	default:
 		games, ok := r.playerGames[m.PlayerName]
@@ -246,23 +321,68 @@ func (r *Runner) validateSocketMessage(m message.Message) error {
 }
 
 // handleSocketMessage writes the socket message to to the out channel, possibly taking action.
-func (r *Runner) handleSocketMessage(ctx context.Context, m message.Message, out chan<- message.Message) {
+func (r *Runner) handleSocketMessage(ctx context.Context, wg *sync.WaitGroup, m message.Message, socketOut, out chan<- message.Message) {
 	if err := r.validateSocketMessage(m); err != nil {
 		r.log.Printf("invalid message from socket: %v: %v", err, m)
 		return
 	}
 	switch m.Type {
 	case message.SocketClose:
-		r.removeSocket(ctx, m)
+		r.disconnectSocket(ctx, m, out)
 	case message.LeaveGame:
+		addr, ok := r.playerGames[m.PlayerName][m.Game.ID]
 		r.leaveGame(ctx, m)
+		if ok && addr == m.Addr { // the game changes its host if the host leaves
+			message.Send(m, out, r.Debug, r.log)
+		}
+	case message.AddBot:
+		r.addBot(ctx, wg, m, socketOut)
 	default:
 		message.Send(m, out, r.Debug, r.log)
 	}
 }
 
-// sendGameInfos sends the game message with infos to the single socket or all.
+// addBot runs a bot that joins the game of the player who requested it.
+// The player is warned if the bot cannot be added.
+func (r *Runner) addBot(ctx context.Context, wg *sync.WaitGroup, m message.Message, socketOut chan<- message.Message) {
+	if err := r.handleAddBot(ctx, wg, m, socketOut); err != nil {
+		m2 := message.Message{
+			Type:       message.SocketWarning,
+			PlayerName: m.PlayerName,
+			Info:       fmt.Sprintf("could not add bot: %v", err),
+			Code:       message.CodeBotNotAdded,
+			Addr:       m.Addr,
+		}
+		socketIn := r.playerSockets[m.PlayerName][m.Addr]
+		message.Send(m2, socketIn, r.Debug, r.log)
+	}
+}
+
+// handleAddBot runs a bot of the requested difficulty and adds it to the runner like a socket.
+// The bot sends messages to the socketOut channel to join and play the game.
+func (r *Runner) handleAddBot(ctx context.Context, wg *sync.WaitGroup, m message.Message, socketOut chan<- message.Message) error {
+	if r.numSockets() >= r.MaxSockets {
+		return fmt.Errorf("no room for another socket")
+	}
+	d := m.Game.BotDifficulty
+	pn := player.BotName(d, r.numBots+1)
+	b, err := r.BotConfig.NewBot(r.log, pn, m.Game.ID, d)
+	if err != nil {
+		return err
+	}
+	b.InviteCode = m.Game.InviteCode
+	r.numBots++
+	socketIn := make(chan message.Message)
+	b.Run(ctx, wg, socketIn, socketOut)
+	r.playerSockets[pn] = map[message.Addr]chan<- message.Message{
+		b.Addr: socketIn,
+	}
+	return nil
+}
+
+// sendGameInfos sends the game message with infos to the single socket, the sockets of the player, or all.
 // When a socket is added, only it immediately needs game infos.  Otherwise, when any game info changes, all sockets must be notified.
+// Players of private games are also sent the infos that only they can see.
 func (r *Runner) sendGameInfos(ctx context.Context, m message.Message) {
 	switch {
 	case len(m.Addr) != 0:
@@ -277,6 +397,11 @@ func (r *Runner) sendGameInfos(ctx context.Context, m message.Message) {
 			return
 		}
 		message.Send(m, socketIn, r.Debug, r.log)
+	case len(m.PlayerName) != 0:
+		// the player is not sent the infos if they have no sockets
+		for _, socketIn := range r.playerSockets[m.PlayerName] {
+			message.Send(m, socketIn, r.Debug, r.log)
+		}
 	default:
 		// send to all sockets (likely game info change)
 		for _, addrs := range r.playerSockets {
@@ -307,14 +432,18 @@ func (r *Runner) sendMessageForGame(ctx context.Context, m message.Message) {
 		r.log.Printf("no 'game' to send game message for in %v", m)
 		return
 	}
+	if len(m.PlayerName) == 0 {
+		r.sendSpectatorMessage(ctx, m)
+		return
+	}
 	socketAddrs, ok := r.playerSockets[m.PlayerName]
-	if !ok {
+	if !ok && !r.isReconnecting(m.PlayerName) {
 		r.log.Printf("could not send game message to %v, socket addrs not found - message: (%v)", m.PlayerName, m)
 		return
 	}
 	var addr message.Addr
 	switch m.Type {
-	case message.JoinGame:
+	case message.JoinGame, message.SpectateGame:
 		addr = m.Addr
 	case message.LeaveGame:
 		defer r.leaveGame(ctx, m)
@@ -334,6 +463,9 @@ func (r *Runner) sendMessageForGame(ctx context.Context, m message.Message) {
 			return // don't worry if player not observing game
 		}
 	}
+	if _, ok := r.reconnectDeadlines[addr]; ok {
+		return // the game sends the whole board when it is resumed on a new socket
+	}
 	socketIn, ok := socketAddrs[addr]
 	if !ok {
 		r.log.Printf("could not send game message to %v at %v - message: (%v)", m.PlayerName, addr, m)
@@ -342,6 +474,9 @@ func (r *Runner) sendMessageForGame(ctx context.Context, m message.Message) {
 	switch m.Type {
 	case message.JoinGame:
 		r.joinGame(ctx, m, socketIn)
+	case message.SpectateGame:
+		r.spectateGame(ctx, m)
+		message.Send(m, socketIn, r.Debug, r.log)
 	default:
 		message.Send(m, socketIn, r.Debug, r.log)
 	}
@@ -351,6 +486,7 @@ func (r *Runner) sendMessageForGame(ctx context.Context, m message.Message) {
 // If the socket is in a different game, that game is left.
 // If a different socket is in the game for the player, that socket leaves the game.
 func (r *Runner) joinGame(ctx context.Context, m message.Message, out chan<- message.Message) {
+	r.removeSpectator(m.Addr)
 	games, ok := r.playerGames[m.PlayerName]
 	switch {
 	case !ok:
@@ -367,8 +503,10 @@ func (r *Runner) joinGame(ctx context.Context, m message.Message, out chan<- mes
 				Type: message.LeaveGame,
 				Info: "leaving game because it is being played on a different socket",
 			}
-			socketIn2 := r.playerSockets[m.PlayerName][addr2]
-			message.Send(m2, socketIn2, r.Debug, r.log)
+			if socketIn2, ok := r.playerSockets[m.PlayerName][addr2]; ok {
+				message.Send(m2, socketIn2, r.Debug, r.log)
+			}
+			delete(r.reconnectDeadlines, addr2)
 		}
 		// remove the addr from its previously joined game if it is different
 		for id, addr := range games {
@@ -382,8 +520,65 @@ func (r *Runner) joinGame(ctx context.Context, m message.Message, out chan<- mes
 	message.Send(m, out, r.Debug, r.log)
 }
 
+// disconnectSocket removes the socket from the runner after it is closed.
+// If the socket is playing a game, the game is kept for the player to resume on a new socket until the reconnect period ends.
+// Otherwise, the game is told that the player left it so the seat of the player is freed.
+func (r *Runner) disconnectSocket(ctx context.Context, m message.Message, out chan<- message.Message) {
+	id, playing := r.playedGame(m.PlayerName, m.Addr)
+	if playing && r.ReconnectPeriod > 0 {
+		r.closeSocket(m)
+		r.removeExpiredGames(out)
+		r.reconnectDeadlines[m.Addr] = r.SocketConfig.TimeFunc() + int64(r.ReconnectPeriod.Seconds())
+		return
+	}
+	r.removeSocket(ctx, m)
+	if playing {
+		m2 := leaveGameMessage(m.PlayerName, m.Addr, id)
+		message.Send(m2, out, r.Debug, r.log)
+	}
+}
+
+// playedGame returns the id of the game the socket is playing, if it is playing one.
+func (r *Runner) playedGame(pn player.Name, a message.Addr) (game.ID, bool) {
+	for id, addr := range r.playerGames[pn] {
+		if addr == a {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// leaveGameMessage creates a message for the game that the player on the socket has left it.
+func leaveGameMessage(pn player.Name, a message.Addr, id game.ID) message.Message {
+	m := message.Message{
+		Type:       message.LeaveGame,
+		PlayerName: pn,
+		Addr:       a,
+		Game: &game.Info{
+			ID: id,
+		},
+	}
+	return m
+}
+
+// isReconnecting determines if the player has games that can be resumed on a new socket.
+func (r *Runner) isReconnecting(pn player.Name) bool {
+	for _, addr := range r.playerGames[pn] {
+		if _, ok := r.reconnectDeadlines[addr]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 // removeSocket removes the socket from the runner.
 func (r *Runner) removeSocket(ctx context.Context, m message.Message) {
+	r.closeSocket(m)
+	r.leaveGame(ctx, m)
+}
+
+// closeSocket closes the in channel of the socket and removes it from the runner.
+func (r *Runner) closeSocket(m message.Message) {
 	if socketIn, ok := r.playerSockets[m.PlayerName][m.Addr]; ok {
 		close(socketIn)
 	}
@@ -391,21 +586,25 @@ func (r *Runner) removeSocket(ctx context.Context, m message.Message) {
 	if len(r.playerSockets[m.PlayerName]) == 0 {
 		delete(r.playerSockets, m.PlayerName)
 	}
-	r.leaveGame(ctx, m)
 }
 
-// leaveGame removes the socket from any game it is in.
+// leaveGame removes the socket from any game it is in or is spectating.
 func (r *Runner) leaveGame(ctx context.Context, m message.Message) {
+	if r.removeSpectator(m.Addr) {
+		return
+	}
 	playerGames, ok := r.playerGames[m.PlayerName]
 	if !ok {
 		return
 	}
 	switch {
 	case m.Game != nil:
+		delete(r.reconnectDeadlines, playerGames[m.Game.ID])
 		delete(playerGames, m.Game.ID)
 	case len(m.Addr) != 0:
 		for gID, addr := range playerGames {
 			if addr == m.Addr {
+				delete(r.reconnectDeadlines, addr)
 				delete(playerGames, gID)
 				break
 			}
@@ -416,8 +615,67 @@ func (r *Runner) leaveGame(ctx context.Context, m message.Message) {
 	}
 }
 
+// spectateGame adds the socket as a spectator of the game after the game allows it.
+// The socket stops playing or spectating any other game.
+func (r *Runner) spectateGame(ctx context.Context, m message.Message) {
+	if playerGames, ok := r.playerGames[m.PlayerName]; ok {
+		for id, addr := range playerGames {
+			if addr == m.Addr {
+				delete(playerGames, id)
+				break
+			}
+		}
+		if len(playerGames) == 0 {
+			delete(r.playerGames, m.PlayerName)
+		}
+	}
+	r.removeSpectator(m.Addr)
+	spectators, ok := r.gameSpectators[m.Game.ID]
+	if !ok {
+		spectators = make(map[message.Addr]player.Name, 1)
+		r.gameSpectators[m.Game.ID] = spectators
+	}
+	spectators[m.Addr] = m.PlayerName
+}
+
+// removeSpectator stops the socket from spectating a game, returning true if it was spectating one.
+func (r *Runner) removeSpectator(a message.Addr) bool {
+	for id, spectators := range r.gameSpectators {
+		if _, ok := spectators[a]; ok {
+			delete(spectators, a)
+			if len(spectators) == 0 {
+				delete(r.gameSpectators, id)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// sendSpectatorMessage sends the game message to all sockets spectating the game.
+// Spectators are removed from the game if the message is for them to leave it.
+func (r *Runner) sendSpectatorMessage(ctx context.Context, m message.Message) {
+	spectators := r.gameSpectators[m.Game.ID]
+	for addr, pn := range spectators {
+		socketIn, ok := r.playerSockets[pn][addr]
+		if !ok {
+			r.log.Printf("could not send game message to spectator %v at %v - message: (%v)", pn, addr, m)
+			continue
+		}
+		message.Send(m, socketIn, r.Debug, r.log)
+	}
+	if m.Type == message.LeaveGame {
+		delete(r.gameSpectators, m.Game.ID)
+	}
+}
+
 // removePlayer removes the player's sockets and games.
-func (r *Runner) removePlayer(ctx context.Context, sm message.Socket) {
+// The games are told that the player left them.  The messages are sent asynchronously because lobby might be processing other messages.
+func (r *Runner) removePlayer(ctx context.Context, sm message.Socket, lobbyIn chan<- message.Message) {
+	for id, addr := range r.playerGames[sm.PlayerName] {
+		m := leaveGameMessage(sm.PlayerName, addr, id)
+		go message.Send(m, lobbyIn, r.Debug, r.log)
+	}
 	if addrs, ok := r.playerSockets[sm.PlayerName]; ok {
 		for addr := range addrs {
 			m2 := message.Message{
@@ -427,4 +685,8 @@ func (r *Runner) removePlayer(ctx context.Context, sm message.Socket) {
 			r.removeSocket(ctx, m2)
 		}
 	}
+	for _, addr := range r.playerGames[sm.PlayerName] {
+		delete(r.reconnectDeadlines, addr) // the player cannot resume games on disconnected sockets
+	}
+	delete(r.playerGames, sm.PlayerName)
 }
@@ -7,13 +7,17 @@ import (
 	"net"
 	"net/http"
 	"reflect"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/jacobpatterson1549/selene-bananas/game"
+	"github.com/jacobpatterson1549/selene-bananas/game/board"
 	"github.com/jacobpatterson1549/selene-bananas/game/message"
 	"github.com/jacobpatterson1549/selene-bananas/game/player"
+	"github.com/jacobpatterson1549/selene-bananas/game/word"
+	"github.com/jacobpatterson1549/selene-bananas/server/game/bot"
 	"github.com/jacobpatterson1549/selene-bananas/server/log"
 	"github.com/jacobpatterson1549/selene-bananas/server/log/logtest"
 )
@@ -52,6 +56,14 @@ func TestNewRunner(t *testing.T) {
 				MaxPlayerSockets: 2,
 			},
 		},
+		{ // negative reconnect period
+			log: testLog,
+			RunnerConfig: RunnerConfig{
+				MaxSockets:       10,
+				MaxPlayerSockets: 3,
+				ReconnectPeriod:  -1 * time.Second,
+			},
+		},
 		{
 			log: testLog,
 			RunnerConfig: RunnerConfig{
@@ -60,9 +72,11 @@ func TestNewRunner(t *testing.T) {
 			},
 			wantOk: true,
 			want: &Runner{
-				log:           testLog,
-				playerSockets: make(map[player.Name]map[message.Addr]chan<- message.Message),
-				playerGames:   make(map[player.Name]map[game.ID]message.Addr),
+				log:                testLog,
+				playerSockets:      make(map[player.Name]map[message.Addr]chan<- message.Message),
+				playerGames:        make(map[player.Name]map[game.ID]message.Addr),
+				gameSpectators:     make(map[game.ID]map[message.Addr]player.Name),
+				reconnectDeadlines: make(map[message.Addr]int64),
 				RunnerConfig: RunnerConfig{
 					MaxSockets:       10,
 					MaxPlayerSockets: 3,
@@ -716,6 +730,34 @@ func TestRunnerHandleLobbyMessage(t *testing.T) {
 				},
 			},
 		},
+		{ // game infos, with player: send to all sockets for the player
+			playerSockets: map[player.Name]map[message.Addr]chan<- message.Message{
+				"fred": {
+					"addr1": make(chan<- message.Message, 1),
+					"addr2": make(chan<- message.Message, 1),
+				},
+				"barney": {
+					"addr3": nil,
+				},
+			},
+			m: message.Message{
+				Type:       message.GameInfos,
+				PlayerName: "fred",
+			},
+			wantOk: true,
+		},
+		{ // game infos, with player: no sockets for the player
+			playerSockets: map[player.Name]map[message.Addr]chan<- message.Message{
+				"barney": {
+					"addr3": nil,
+				},
+			},
+			m: message.Message{
+				Type:       message.GameInfos,
+				PlayerName: "fred",
+			},
+			wantOk: true,
+		},
 		{ // game infos, with addr: only send to player for the socket, but no player socket exists
 			m: message.Message{
 				Type:       message.GameInfos,
@@ -1162,8 +1204,39 @@ func TestRunnerHandleSocketMessage(t *testing.T) {
 				},
 			},
 			wantPlayerGames: make(map[player.Name]map[game.ID]message.Addr),
-			wantOk:          true,
-			skipOutSend:     true, // don't tell the game the socket is not listening
+			wantOk:          true, // the game is told the player left so it can change its host
+		},
+		{ // leave game that the socket is not playing
+			playerSockets: map[player.Name]map[message.Addr]chan<- message.Message{
+				"fred": {
+					"addr1": nil,
+				},
+			},
+			playerGames: map[player.Name]map[game.ID]message.Addr{
+				"fred": {
+					8: "addr1",
+				},
+			},
+			m: message.Message{
+				Type:       message.LeaveGame,
+				PlayerName: "fred",
+				Addr:       "addr1",
+				Game: &game.Info{
+					ID: 9,
+				},
+			},
+			wantPlayerSockets: map[player.Name]map[message.Addr]chan<- message.Message{
+				"fred": {
+					"addr1": nil,
+				},
+			},
+			wantPlayerGames: map[player.Name]map[game.ID]message.Addr{
+				"fred": {
+					8: "addr1",
+				},
+			},
+			wantOk:      true,
+			skipOutSend: true,
 		},
 		{ // leave game when player not in any game
 			playerSockets: map[player.Name]map[message.Addr]chan<- message.Message{
@@ -1206,8 +1279,15 @@ func TestRunnerHandleSocketMessage(t *testing.T) {
 			},
 			wantPlayerSockets: make(map[player.Name]map[message.Addr]chan<- message.Message),
 			wantPlayerGames:   make(map[player.Name]map[game.ID]message.Addr),
-			wantOk:            true,
-			skipOutSend:       true,
+			want: message.Message{ // the game is told the player left so it can free the seat of the player
+				Type:       message.LeaveGame,
+				PlayerName: "fred",
+				Addr:       "addr1",
+				Game: &game.Info{
+					ID: 9,
+				},
+			},
+			wantOk: true,
 		},
 		{ // socket close when not in any game
 			playerSockets: map[player.Name]map[message.Addr]chan<- message.Message{
@@ -1235,8 +1315,10 @@ func TestRunnerHandleSocketMessage(t *testing.T) {
 		ctx := context.Background()
 		ctx, cancelFunc := context.WithCancel(ctx)
 		defer cancelFunc()
+		var wg sync.WaitGroup
+		socketOut := make(chan message.Message)
 		gameOut := make(chan message.Message, 1)
-		r.handleSocketMessage(ctx, test.m, gameOut)
+		r.handleSocketMessage(ctx, &wg, test.m, socketOut, gameOut)
 		switch {
 		case !test.wantOk && log.Empty():
 			t.Errorf("Test %v: wanted error logged for bad message", i)
@@ -1245,7 +1327,11 @@ func TestRunnerHandleSocketMessage(t *testing.T) {
 		case test.wantOk && !reflect.DeepEqual(test.wantPlayerGames, r.playerGames):
 			t.Errorf("Test %v: player games not equal:\nwanted: %v\ngot:    %v", i, test.wantPlayerGames, r.playerGames)
 		case test.wantOk:
-			verifyMessagesSent(t, gameOut, i, test.skipOutSend, test.m)
+			want := test.m
+			if test.want.Type != 0 {
+				want = test.want
+			}
+			verifyMessagesSent(t, gameOut, i, test.skipOutSend, want)
 			verifyAllSocketsSentOneMessage(t, r, i)
 		}
 	}
@@ -1312,6 +1398,17 @@ func TestRunnerHandleLobbyMessagePlayerRemove(t *testing.T) {
 			t.Errorf("wanted c2 to be left open")
 		}
 	}
+	want := message.Message{
+		Type:       message.LeaveGame,
+		PlayerName: "fred",
+		Addr:       "addr1",
+		Game: &game.Info{
+			ID: 1,
+		},
+	}
+	if got := <-lobbyIn; !reflect.DeepEqual(want, got) {
+		t.Errorf("wanted game to be left when player is removed:\nwanted: %v\ngot:    %v", want, got)
+	}
 }
 
 // TestSendMessageForGameBadRunnerState adds coverage for some scenarios where playerGames do do not have matching playerSocket entries
@@ -1390,6 +1487,461 @@ func TestRemoveSocket(t *testing.T) {
 	}
 }
 
+func TestRunnerDisconnectSocket(t *testing.T) {
+	pn := player.Name("fred")
+	addr := message.Addr("fred.pc")
+	disconnectSocketTests := []struct {
+		reconnectPeriod  time.Duration
+		playerGames      map[game.ID]message.Addr
+		wantPlayerGames  map[player.Name]map[game.ID]message.Addr
+		wantReconnecting bool
+		wantLeave        bool
+	}{
+		{ // no reconnect period
+			playerGames: map[game.ID]message.Addr{
+				1: addr,
+			},
+			wantPlayerGames: map[player.Name]map[game.ID]message.Addr{},
+			wantLeave:       true,
+		},
+		{ // not playing a game
+			reconnectPeriod: 5 * time.Second,
+			playerGames: map[game.ID]message.Addr{
+				1: "fred.phone",
+			},
+			wantPlayerGames: map[player.Name]map[game.ID]message.Addr{
+				pn: {
+					1: "fred.phone",
+				},
+			},
+		},
+		{ // game kept to be resumed
+			reconnectPeriod: 5 * time.Second,
+			playerGames: map[game.ID]message.Addr{
+				1: addr,
+			},
+			wantPlayerGames: map[player.Name]map[game.ID]message.Addr{
+				pn: {
+					1: addr,
+				},
+			},
+			wantReconnecting: true,
+		},
+	}
+	for i, test := range disconnectSocketTests {
+		socketIn := make(chan message.Message)
+		r := Runner{
+			playerSockets: map[player.Name]map[message.Addr]chan<- message.Message{
+				pn: {
+					addr: socketIn,
+				},
+			},
+			playerGames: map[player.Name]map[game.ID]message.Addr{
+				pn: test.playerGames,
+			},
+			reconnectDeadlines: make(map[message.Addr]int64),
+			RunnerConfig: RunnerConfig{
+				ReconnectPeriod: test.reconnectPeriod,
+				SocketConfig: Config{
+					TimeFunc: func() int64 { return 100 },
+				},
+			},
+		}
+		ctx := context.Background()
+		m := message.Message{
+			Type:       message.SocketClose,
+			PlayerName: pn,
+			Addr:       addr,
+		}
+		out := make(chan message.Message, 1)
+		r.disconnectSocket(ctx, m, out)
+		<-socketIn // disconnecting a socket should close it's in channel
+		deadline, gotReconnecting := r.reconnectDeadlines[addr]
+		switch {
+		case test.wantLeave != (len(out) == 1):
+			t.Errorf("Test %v: wanted game to be left: %v", i, test.wantLeave)
+		case len(r.playerSockets) != 0:
+			t.Errorf("Test %v: wanted player socket to be removed", i)
+		case !reflect.DeepEqual(test.wantPlayerGames, r.playerGames):
+			t.Errorf("Test %v: player games not equal:\nwanted: %v\ngot:    %v", i, test.wantPlayerGames, r.playerGames)
+		case test.wantReconnecting != gotReconnecting:
+			t.Errorf("Test %v: wanted socket to be reconnecting: %v, got: %v", i, test.wantReconnecting, gotReconnecting)
+		case gotReconnecting && deadline != 105:
+			t.Errorf("Test %v: wanted reconnect deadline to be at the end of the reconnect period, got %v", i, deadline)
+		}
+	}
+}
+
+func TestRunnerResumeGame(t *testing.T) {
+	pn := player.Name("fred")
+	newAddr := message.Addr("fred.pc.2")
+	newSocketIn := make(chan message.Message, 1)
+	log := new(logtest.Logger)
+	r := Runner{
+		log: log,
+		playerSockets: map[player.Name]map[message.Addr]chan<- message.Message{
+			pn: {
+				newAddr: newSocketIn,
+			},
+		},
+		playerGames: map[player.Name]map[game.ID]message.Addr{
+			pn: {
+				1: "fred.pc",
+				2: "fred.phone",
+			},
+			"barney": {
+				3: "barney.pc",
+			},
+		},
+		reconnectDeadlines: map[message.Addr]int64{
+			"fred.pc":    100,
+			"fred.phone": 5,
+			"barney.pc":  10,
+		},
+		RunnerConfig: RunnerConfig{
+			ReconnectPeriod: time.Minute,
+			SocketConfig: Config{
+				TimeFunc: func() int64 { return 10 },
+			},
+		},
+	}
+	ctx := context.Background()
+	m := message.Message{
+		Type:       message.BoardReport,
+		PlayerName: pn,
+		Game: &game.Info{
+			ID: 1,
+		},
+	}
+	r.sendMessageForGame(ctx, m) // dropped because the player is reconnecting
+	if !log.Empty() {
+		t.Errorf("wanted no error logged sending a message to a player who is reconnecting: %v", log)
+	}
+	lobbyIn := make(chan message.Message, 3)
+	r.resumeGame(pn, newAddr, lobbyIn)
+	want := message.Message{
+		Type:       message.JoinGame,
+		PlayerName: pn,
+		Addr:       newAddr,
+		Game: &game.Info{
+			ID: 1,
+		},
+	}
+	wantMessages := map[game.ID]message.Message{
+		1: want,
+		2: leaveGameMessage(pn, "fred.phone", 2), // expired
+		3: leaveGameMessage("barney", "barney.pc", 3),
+	}
+	gotMessages := make(map[game.ID]message.Message, len(wantMessages))
+	for range wantMessages {
+		got := <-lobbyIn
+		gotMessages[got.Game.ID] = got
+	}
+	if !reflect.DeepEqual(wantMessages, gotMessages) {
+		t.Errorf("resume messages not equal:\nwanted: %v\ngot:    %v", wantMessages, gotMessages)
+	}
+	if len(r.playerGames) != 0 || len(r.reconnectDeadlines) != 0 {
+		t.Errorf("wanted expired games removed and resumed game to be removed until it is joined, got %v and deadlines %v", r.playerGames, r.reconnectDeadlines)
+	}
+	r.sendMessageForGame(ctx, want)
+	if got := <-newSocketIn; !reflect.DeepEqual(want, got) {
+		t.Errorf("join messages not equal:\nwanted: %v\ngot:    %v", want, got)
+	}
+	if want, got := newAddr, r.playerGames[pn][1]; want != got {
+		t.Errorf("wanted game to be resumed on %v, got %v", want, got)
+	}
+}
+
+func TestRunnerRemoveExpiredGames(t *testing.T) {
+	r := Runner{
+		playerGames: map[player.Name]map[game.ID]message.Addr{
+			"fred": {
+				1: "fred.pc",
+				2: "fred.phone",
+			},
+		},
+		reconnectDeadlines: map[message.Addr]int64{
+			"fred.pc": 10,
+		},
+		RunnerConfig: RunnerConfig{
+			SocketConfig: Config{
+				TimeFunc: func() int64 { return 10 },
+			},
+		},
+	}
+	lobbyIn := make(chan message.Message)
+	r.removeExpiredGames(lobbyIn)
+	wantPlayerGames := map[player.Name]map[game.ID]message.Addr{
+		"fred": {
+			2: "fred.phone",
+		},
+	}
+	want := leaveGameMessage("fred", "fred.pc", 1)
+	switch {
+	case !reflect.DeepEqual(wantPlayerGames, r.playerGames):
+		t.Errorf("player games not equal:\nwanted: %v\ngot:    %v", wantPlayerGames, r.playerGames)
+	case len(r.reconnectDeadlines) != 0:
+		t.Errorf("wanted reconnect deadline to be removed, got %v", r.reconnectDeadlines)
+	default:
+		if got := <-lobbyIn; !reflect.DeepEqual(want, got) {
+			t.Errorf("wanted expired game to be left:\nwanted: %v\ngot:    %v", want, got)
+		}
+	}
+}
+
+func TestRunnerJoinGameDisconnectedSocket(t *testing.T) {
+	pn := player.Name("fred")
+	addr := message.Addr("fred.pc.2")
+	socketIn := make(chan message.Message, 1)
+	r := Runner{
+		playerSockets: map[player.Name]map[message.Addr]chan<- message.Message{
+			pn: {
+				addr: socketIn,
+			},
+		},
+		playerGames: map[player.Name]map[game.ID]message.Addr{
+			pn: {
+				1: "fred.pc",
+			},
+		},
+		reconnectDeadlines: map[message.Addr]int64{
+			"fred.pc": 100,
+		},
+	}
+	ctx := context.Background()
+	m := message.Message{
+		Type:       message.JoinGame,
+		PlayerName: pn,
+		Addr:       addr,
+		Game: &game.Info{
+			ID: 1,
+		},
+	}
+	r.joinGame(ctx, m, socketIn) // should not block sending a message to the disconnected socket
+	switch {
+	case r.playerGames[pn][1] != addr:
+		t.Errorf("wanted game to be played on the new socket")
+	case len(r.reconnectDeadlines) != 0:
+		t.Errorf("wanted game on the disconnected socket to not be resumable")
+	}
+}
+
+func TestRunnerSpectateGame(t *testing.T) {
+	fredIn := make(chan message.Message, 1)
+	barneyIn := make(chan message.Message, 1)
+	r := Runner{
+		log: new(logtest.Logger),
+		playerSockets: map[player.Name]map[message.Addr]chan<- message.Message{
+			"fred": {
+				"addr1": fredIn,
+			},
+			"barney": {
+				"addr2": barneyIn,
+			},
+		},
+		playerGames: map[player.Name]map[game.ID]message.Addr{
+			"fred": {
+				1: "addr1",
+			},
+		},
+		gameSpectators: make(map[game.ID]map[message.Addr]player.Name),
+	}
+	ctx := context.Background()
+	gameOut := make(chan message.Message, 1)
+	m := message.Message{
+		Type:       message.SpectateGame,
+		PlayerName: "fred",
+		Addr:       "addr1",
+		Game: &game.Info{
+			ID: 2,
+		},
+	}
+	var wg sync.WaitGroup
+	socketOut := make(chan message.Message)
+	r.handleSocketMessage(ctx, &wg, m, socketOut, gameOut)
+	verifyMessagesSent(t, gameOut, 0, false, m)
+	if len(r.gameSpectators) != 0 {
+		t.Errorf("wanted socket to not spectate game before the game allows it, got %v", r.gameSpectators)
+	}
+	m1 := message.Message{
+		Type: message.SpectateGame,
+		Game: &game.Info{
+			ID: 2,
+		},
+	}
+	r.handleLobbyMessage(ctx, nil, m1)
+	if len(fredIn) != 0 {
+		t.Errorf("wanted message for spectators to not be sent to socket before the game allows it to spectate")
+	}
+	m.Info = "spectating game"
+	r.handleLobbyMessage(ctx, nil, m)
+	verifyMessagesSent(t, fredIn, 0, false, m)
+	wantPlayerGames := map[player.Name]map[game.ID]message.Addr{}
+	wantGameSpectators := map[game.ID]map[message.Addr]player.Name{
+		2: {
+			"addr1": "fred",
+		},
+	}
+	switch {
+	case !reflect.DeepEqual(wantPlayerGames, r.playerGames):
+		t.Errorf("wanted spectating socket to stop playing other games:\nwanted: %v\ngot:    %v", wantPlayerGames, r.playerGames)
+	case !reflect.DeepEqual(wantGameSpectators, r.gameSpectators):
+		t.Errorf("game spectators not equal:\nwanted: %v\ngot:    %v", wantGameSpectators, r.gameSpectators)
+	}
+	m2 := message.Message{
+		Type: message.SpectateGame,
+		Game: &game.Info{
+			ID: 2,
+		},
+	}
+	r.handleLobbyMessage(ctx, nil, m2)
+	switch {
+	case len(fredIn) != 1:
+		t.Errorf("wanted message without player name to be sent to spectator")
+	case len(barneyIn) != 0:
+		t.Errorf("wanted message without player name to only be sent to spectators")
+	}
+	<-fredIn
+	m3 := message.Message{
+		Type: message.LeaveGame,
+		Game: &game.Info{
+			ID: 2,
+		},
+	}
+	r.handleLobbyMessage(ctx, nil, m3)
+	switch {
+	case len(fredIn) != 1:
+		t.Errorf("wanted spectator to be told to leave game")
+	case len(r.gameSpectators) != 0:
+		t.Errorf("wanted spectators to be removed after game is left, got %v", r.gameSpectators)
+	}
+}
+
+func TestRunnerLeaveGameSpectator(t *testing.T) {
+	r := Runner{
+		playerGames: map[player.Name]map[game.ID]message.Addr{
+			"fred": {
+				1: "addr1",
+			},
+		},
+		gameSpectators: map[game.ID]map[message.Addr]player.Name{
+			2: {
+				"addr2": "fred",
+			},
+		},
+	}
+	ctx := context.Background()
+	m := message.Message{
+		Type:       message.LeaveGame,
+		PlayerName: "fred",
+		Addr:       "addr2",
+		Game: &game.Info{
+			ID: 2,
+		},
+	}
+	r.leaveGame(ctx, m)
+	switch {
+	case len(r.gameSpectators) != 0:
+		t.Errorf("wanted spectator to be removed, got %v", r.gameSpectators)
+	case len(r.playerGames["fred"]) != 1:
+		t.Errorf("wanted game played on other socket to not be left")
+	}
+}
+
+func TestRunnerAddBot(t *testing.T) {
+	words, err := word.NewValidator(strings.NewReader("cat"))
+	if err != nil {
+		t.Fatalf("unwanted error creating words: %v", err)
+	}
+	botConfig := bot.Config{
+		Dictionaries: map[game.Language]board.Dictionary{
+			game.English: words,
+		},
+		Skills: map[player.Difficulty]bot.Skill{
+			player.Medium: {
+				ThinkTime:      time.Hour,
+				VocabularySize: 10,
+			},
+		},
+		BoardConfig: board.Config{
+			NumRows: 10,
+			NumCols: 10,
+		},
+	}
+	addBotTests := []struct {
+		maxSockets int
+		bot.Config
+		wantOk bool
+	}{
+		{ // no room
+			maxSockets: 1,
+			Config:     botConfig,
+		},
+		{ // bots not configured
+			maxSockets: 2,
+		},
+		{
+			maxSockets: 2,
+			Config:     botConfig,
+			wantOk:     true,
+		},
+	}
+	for i, test := range addBotTests {
+		socketIn := make(chan message.Message, 1)
+		r := Runner{
+			log: logtest.DiscardLogger,
+			playerSockets: map[player.Name]map[message.Addr]chan<- message.Message{
+				"fred": {
+					"addr1": socketIn,
+				},
+			},
+			playerGames: map[player.Name]map[game.ID]message.Addr{
+				"fred": {
+					5: "addr1",
+				},
+			},
+			RunnerConfig: RunnerConfig{
+				MaxSockets: test.maxSockets,
+				BotConfig:  test.Config,
+			},
+		}
+		ctx := context.Background()
+		ctx, cancelFunc := context.WithCancel(ctx)
+		var wg sync.WaitGroup
+		socketOut := make(chan message.Message)
+		gameOut := make(chan message.Message, 1)
+		m := message.Message{
+			Type:       message.AddBot,
+			PlayerName: "fred",
+			Addr:       "addr1",
+			Game: &game.Info{
+				ID:            5,
+				BotDifficulty: player.Medium,
+			},
+		}
+		r.handleSocketMessage(ctx, &wg, m, socketOut, gameOut)
+		switch {
+		case !test.wantOk:
+			if m2 := <-socketIn; m2.Type != message.SocketWarning || m2.Code != message.CodeBotNotAdded {
+				t.Errorf("Test %v: wanted warning sent to player, got %v", i, m2)
+			}
+		default:
+			m2 := <-socketOut
+			switch {
+			case m2.Type != message.JoinGame, m2.PlayerName != "bot-medium-1", m2.Game.ID != 5:
+				t.Errorf("Test %v: wanted bot to join game, got %v", i, m2)
+			case len(r.playerSockets["bot-medium-1"]) != 1:
+				t.Errorf("Test %v: wanted bot to be added like a socket, got %v", i, r.playerSockets)
+			}
+		}
+		if len(gameOut) != 0 {
+			t.Errorf("Test %v: wanted add bot message to not be sent to the game", i)
+		}
+		cancelFunc()
+		wg.Wait()
+	}
+}
+
 func verifyMessagesSent(t *testing.T, gameOut <-chan message.Message, i int, skipOutSend bool, wantM message.Message) {
 	numMessagesSent := len(gameOut)
 	switch {
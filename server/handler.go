@@ -44,6 +44,8 @@ type (
 		ColorConfig ColorConfig
 		// NoTLSRedirect disables redirection to https from http when true.
 		NoTLSRedirect bool
+		// LeaderboardCacheSec is the number of seconds the leaderboard is cached before the top users are read again.
+		LeaderboardCacheSec int
 	}
 
 	// Parameters contains the interfaces needed to create a new server
@@ -51,6 +53,7 @@ type (
 		log.Logger
 		Tokenizer
 		UserDao
+		ResultDao
 		Lobby
 		StaticFS       fs.FS
 		TemplateFS     fs.FS
@@ -177,6 +180,8 @@ func (cfg Config) validate(p Parameters) error {
 		return fmt.Errorf("stop timeout duration required")
 	case cfg.CacheSec < 0:
 		return fmt.Errorf("nonnegative cache seconds required")
+	case cfg.LeaderboardCacheSec < 0:
+		return fmt.Errorf("nonnegative leaderboard cache seconds required")
 	case cfg.HTTPSPort <= 0:
 		return fmt.Errorf("positive https port required")
 	case len(cfg.Version) == 0:
@@ -221,6 +226,8 @@ func (p Parameters) validate() error {
 		return fmt.Errorf("tokenizer required")
 	case p.UserDao == nil:
 		return fmt.Errorf("user dao required")
+	case p.ResultDao == nil:
+		return fmt.Errorf("result dao required")
 	case p.Lobby == nil:
 		return fmt.Errorf("lobby required")
 	case p.StaticFS == nil:
@@ -294,6 +301,7 @@ func (p Parameters) getHandler(cfg Config, template *template.Template, monitor
 	}
 	getMux.Handle("/lobby", http.HandlerFunc(userLobbyConnectHandler(p.Lobby, p.Tokenizer, p.Logger)))
 	getMux.Handle("/monitor", monitor)
+	getMux.Handle("/leaderboard", http.HandlerFunc(leaderboardHandler(p.UserDao, cfg.LeaderboardCacheSec, p.Logger)))
 	if p.GoogleEndpoint != nil {
 		jwtHandler := oauth2JWTTemplateHandler(template, *data, p.Logger)
 		getMux.Handle(oauth2.GoogleLoginURL, p.GoogleEndpoint.HandleLogin())
@@ -309,6 +317,7 @@ func (p Parameters) postHandler() http.Handler {
 	postMux.Handle("/user_login", http.HandlerFunc(userLoginHandler(p.UserDao, p.Tokenizer, p.Logger)))
 	postMux.Handle("/user_update_password", http.HandlerFunc(userUpdatePasswordHandler(p.UserDao, p.Lobby, p.Logger)))
 	postMux.Handle("/user_delete", http.HandlerFunc(userDeleteHandler(p.UserDao, p.GoogleEndpoint, p.Lobby, p.Logger)))
+	postMux.Handle("/user_stats", http.HandlerFunc(userStatsHandler(p.ResultDao, p.Logger)))
 	postMux.Handle("/ping", http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
 		// NOOP
 	}))
@@ -15,6 +15,7 @@ import (
 	"testing/fstest"
 	"time"
 
+	"github.com/jacobpatterson1549/selene-bananas/db/result"
 	"github.com/jacobpatterson1549/selene-bananas/db/user"
 	"github.com/jacobpatterson1549/selene-bananas/server/log/logtest"
 )
@@ -29,6 +30,7 @@ func TestNewServer(t *testing.T) {
 			return nil
 		},
 	}
+	var resultDao mockResultDao
 	var lobby mockLobby
 	templateFS := fstest.MapFS{ // tests parseTemplate
 		"any-file": new(fstest.MapFile),
@@ -52,11 +54,19 @@ func TestNewServer(t *testing.T) {
 				Tokenizer: tokenizer,
 			},
 		},
+		{ // no resultDao
+			Parameters: Parameters{
+				Logger:    testLog,
+				Tokenizer: tokenizer,
+				UserDao:   userDao,
+			},
+		},
 		{ // no lobby
 			Parameters: Parameters{
 				Logger:    testLog,
 				Tokenizer: tokenizer,
 				UserDao:   userDao,
+				ResultDao: resultDao,
 			},
 		},
 		{ // no challenge
@@ -64,6 +74,7 @@ func TestNewServer(t *testing.T) {
 				Logger:    testLog,
 				Tokenizer: tokenizer,
 				UserDao:   userDao,
+				ResultDao: resultDao,
 				Lobby:     lobby,
 			},
 		},
@@ -72,6 +83,7 @@ func TestNewServer(t *testing.T) {
 				Logger:    testLog,
 				Tokenizer: tokenizer,
 				UserDao:   userDao,
+				ResultDao: resultDao,
 				Lobby:     lobby,
 			},
 		},
@@ -80,6 +92,7 @@ func TestNewServer(t *testing.T) {
 				Logger:    testLog,
 				Tokenizer: tokenizer,
 				UserDao:   userDao,
+				ResultDao: resultDao,
 				Lobby:     lobby,
 				StaticFS:  staticFS,
 			},
@@ -89,6 +102,7 @@ func TestNewServer(t *testing.T) {
 				Logger:     testLog,
 				Tokenizer:  tokenizer,
 				UserDao:    userDao,
+				ResultDao:  resultDao,
 				Lobby:      lobby,
 				StaticFS:   staticFS,
 				TemplateFS: templateFS,
@@ -99,6 +113,7 @@ func TestNewServer(t *testing.T) {
 				Logger:     testLog,
 				Tokenizer:  tokenizer,
 				UserDao:    userDao,
+				ResultDao:  resultDao,
 				Lobby:      lobby,
 				StaticFS:   staticFS,
 				TemplateFS: templateFS,
@@ -108,11 +123,29 @@ func TestNewServer(t *testing.T) {
 				CacheSec: -1,
 			},
 		},
+		{ // bad leaderboardCacheSec
+			Parameters: Parameters{
+				Logger:     testLog,
+				Tokenizer:  tokenizer,
+				UserDao:    userDao,
+				ResultDao:  resultDao,
+				Lobby:      lobby,
+				StaticFS:   staticFS,
+				TemplateFS: templateFS,
+			},
+			Config: Config{
+				StopDur:             1 * time.Hour,
+				HTTPSPort:           443,
+				Version:             "1",
+				LeaderboardCacheSec: -1,
+			},
+		},
 		{ // missing httpsPort
 			Parameters: Parameters{
 				Logger:     testLog,
 				Tokenizer:  tokenizer,
 				UserDao:    userDao,
+				ResultDao:  resultDao,
 				Lobby:      lobby,
 				StaticFS:   staticFS,
 				TemplateFS: templateFS,
@@ -126,6 +159,7 @@ func TestNewServer(t *testing.T) {
 				Logger:     testLog,
 				Tokenizer:  tokenizer,
 				UserDao:    userDao,
+				ResultDao:  resultDao,
 				Lobby:      lobby,
 				StaticFS:   staticFS,
 				TemplateFS: templateFS,
@@ -140,6 +174,7 @@ func TestNewServer(t *testing.T) {
 				Logger:     testLog,
 				Tokenizer:  tokenizer,
 				UserDao:    userDao,
+				ResultDao:  resultDao,
 				Lobby:      lobby,
 				StaticFS:   staticFS,
 				TemplateFS: templateFS,
@@ -155,6 +190,7 @@ func TestNewServer(t *testing.T) {
 				Logger:     testLog,
 				Tokenizer:  tokenizer,
 				UserDao:    userDao,
+				ResultDao:  resultDao,
 				Lobby:      lobby,
 				StaticFS:   staticFS,
 				TemplateFS: make(fstest.MapFS),
@@ -170,6 +206,7 @@ func TestNewServer(t *testing.T) {
 				Logger:     testLog,
 				Tokenizer:  tokenizer,
 				UserDao:    userDao,
+				ResultDao:  resultDao,
 				Lobby:      lobby,
 				StaticFS:   staticFS,
 				TemplateFS: templateFS,
@@ -819,6 +856,20 @@ func TestGetHandler(t *testing.T) {
 		// empty monitor used in checkCode
 		checkCode(t, "/monitor", p, cfg, nil, 200)
 	})
+	t.Run("leaderboard", func(t *testing.T) {
+		var cfg Config
+		p := Parameters{
+			UserDao: mockUserDao{
+				readTopUsersFunc: func(ctx context.Context, limit, offset int) ([]user.User, error) {
+					return nil, nil
+				},
+				backendFunc: func() user.Backend {
+					return nil
+				},
+			},
+		}
+		checkCode(t, "/leaderboard", p, cfg, nil, 200)
+	})
 	t.Run("rootHandler", func(t *testing.T) {
 		template := template.Must(template.New(indexHTML).Parse(""))
 		p := Parameters{
@@ -847,7 +898,7 @@ func TestPostHandler(t *testing.T) {
 			handlePostTest{path: path, wantCode: 200},
 		)
 	}
-	for _, path := range []string{"/user_update_password", "/user_delete", "/ping"} {
+	for _, path := range []string{"/user_update_password", "/user_delete", "/user_stats", "/ping"} {
 		handlePostTests = append(handlePostTests,
 			handlePostTest{path: path, wantCode: 403},
 			handlePostTest{path: path, wantCode: 200, authorization: "Bearer GOOD6"},
@@ -886,6 +937,11 @@ func TestPostHandler(t *testing.T) {
 			return nil
 		},
 	}
+	resultDao := mockResultDao{
+		readStatsFunc: func(ctx context.Context, username string, recentLimit int) (*result.Stats, error) {
+			return new(result.Stats), nil
+		},
+	}
 	for i, test := range handlePostTests {
 		r := httptest.NewRequest("", test.path, nil)
 		r.Form = formParams
@@ -896,6 +952,7 @@ func TestPostHandler(t *testing.T) {
 			Tokenizer: tokenizer,
 			Lobby:     lobby,
 			UserDao:   userDao,
+			ResultDao: resultDao,
 		}
 		h := p.postHandler()
 		h.ServeHTTP(w, r)
@@ -0,0 +1,162 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jacobpatterson1549/selene-bananas/server/log"
+)
+
+type (
+	// leaderboardEntry is the position of a user on the leaderboard.
+	leaderboardEntry struct {
+		Rank     int    `json:"rank"`
+		Username string `json:"username"`
+		Points   int    `json:"points"`
+	}
+
+	// leaderboardCache stores encoded leaderboard pages so the top users are not read for every request.
+	leaderboardCache struct {
+		mu      sync.Mutex
+		pages   map[leaderboardPage]cachedLeaderboard
+		maxAge  time.Duration
+		nowFunc func() time.Time
+	}
+
+	// leaderboardPage identifies a range of the leaderboard.
+	leaderboardPage struct {
+		limit  int
+		offset int
+	}
+
+	// cachedLeaderboard is an encoded leaderboard page and when it should be read again.
+	cachedLeaderboard struct {
+		data    []byte
+		expires time.Time
+	}
+)
+
+const (
+	// defaultLeaderboardLimit is the number of users on the leaderboard if the limit is not specified.
+	defaultLeaderboardLimit = 10
+	// maxLeaderboardLimit is the most users that can be requested on the leaderboard at once.
+	maxLeaderboardLimit = 100
+	// maxCachedLeaderboardPages is the most leaderboard pages that are cached at once.
+	maxCachedLeaderboardPages = 16
+)
+
+// leaderboardHandler writes the users with the most points as json.
+// The optional limit and offset query parameters page through the leaderboard.
+func leaderboardHandler(userDao UserDao, cacheSec int, log log.Logger) http.HandlerFunc {
+	c := leaderboardCache{
+		pages:   make(map[leaderboardPage]cachedLeaderboard),
+		maxAge:  time.Duration(cacheSec) * time.Second,
+		nowFunc: time.Now,
+	}
+	cacheMaxAge := fmt.Sprintf("max-age=%d", cacheSec)
+	return func(w http.ResponseWriter, r *http.Request) {
+		page, err := newLeaderboardPage(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		data, err := c.get(page, func() ([]byte, error) {
+			ctx := r.Context()
+			users, err := userDao.ReadTopUsers(ctx, page.limit, page.offset)
+			if err != nil {
+				return nil, err
+			}
+			entries := make([]leaderboardEntry, len(users))
+			for i, u := range users {
+				entries[i] = leaderboardEntry{
+					Rank:     page.offset + i + 1,
+					Username: u.Username,
+					Points:   u.Points,
+				}
+			}
+			return json.Marshal(entries)
+		})
+		if err != nil {
+			writeInternalError(err, log, w)
+			return
+		}
+		w.Header().Set(HeaderContentType, "application/json")
+		w.Header().Set(HeaderCacheControl, cacheMaxAge)
+		w.Write(data)
+	}
+}
+
+// newLeaderboardPage reads the limit and offset query parameters of the request.
+func newLeaderboardPage(r *http.Request) (*leaderboardPage, error) {
+	page := leaderboardPage{
+		limit: defaultLeaderboardLimit,
+	}
+	query := r.URL.Query()
+	if limit := query.Get("limit"); len(limit) != 0 {
+		i, err := strconv.Atoi(limit)
+		if err != nil || i <= 0 || i > maxLeaderboardLimit {
+			return nil, fmt.Errorf("limit must be between 1 and %v", maxLeaderboardLimit)
+		}
+		page.limit = i
+	}
+	if offset := query.Get("offset"); len(offset) != 0 {
+		i, err := strconv.Atoi(offset)
+		if err != nil || i < 0 {
+			return nil, fmt.Errorf("offset must not be negative")
+		}
+		page.offset = i
+	}
+	return &page, nil
+}
+
+// get returns the cached data for the page, or the data from the read function if the page is not cached or has expired.
+// The cache is not locked while reading so slow reads do not block other requests.
+func (c *leaderboardCache) get(page *leaderboardPage, read func() ([]byte, error)) ([]byte, error) {
+	if data, ok := c.cached(page); ok {
+		return data, nil
+	}
+	data, err := read()
+	if err != nil {
+		return nil, fmt.Errorf("reading leaderboard: %w", err)
+	}
+	c.store(page, data)
+	return data, nil
+}
+
+// cached returns the data for the page if it has not expired.
+func (c *leaderboardCache) cached(page *leaderboardPage) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cl, ok := c.pages[*page]
+	if !ok || !c.nowFunc().Before(cl.expires) {
+		return nil, false
+	}
+	return cl.data, true
+}
+
+// store caches the data for the page if the cache has a positive max age.
+// Expired pages are removed first.  The page is not stored if the cache is full.
+func (c *leaderboardCache) store(page *leaderboardPage, data []byte) {
+	if c.maxAge <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.nowFunc()
+	for p, cl := range c.pages {
+		if !now.Before(cl.expires) {
+			delete(c.pages, p)
+		}
+	}
+	if _, ok := c.pages[*page]; !ok && len(c.pages) >= maxCachedLeaderboardPages {
+		return
+	}
+	c.pages[*page] = cachedLeaderboard{
+		data:    data,
+		expires: now.Add(c.maxAge),
+	}
+}
@@ -0,0 +1,196 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jacobpatterson1549/selene-bananas/db/user"
+	"github.com/jacobpatterson1549/selene-bananas/server/log/logtest"
+)
+
+func TestLeaderboardHandler(t *testing.T) {
+	leaderboardHandlerTests := []struct {
+		query      string
+		wantLimit  int
+		wantOffset int
+		users      []user.User
+		daoErr     error
+		wantCode   int
+		wantBody   string
+	}{
+		{
+			query:    "?limit=0",
+			wantCode: 400,
+		},
+		{
+			query:    "?limit=101",
+			wantCode: 400,
+		},
+		{
+			query:    "?limit=ten",
+			wantCode: 400,
+		},
+		{
+			query:    "?offset=-1",
+			wantCode: 400,
+		},
+		{
+			wantLimit: 10,
+			daoErr:    fmt.Errorf("error reading top users"),
+			wantCode:  500,
+		},
+		{
+			wantLimit: 10,
+			wantCode:  200,
+			wantBody:  `[]`,
+		},
+		{
+			query:      "?limit=2&offset=4",
+			wantLimit:  2,
+			wantOffset: 4,
+			users: []user.User{
+				{Username: "selene", Points: 99},
+				{Username: "fred", Points: 7},
+			},
+			wantCode: 200,
+			wantBody: `[{"rank":5,"username":"selene","points":99},{"rank":6,"username":"fred","points":7}]`,
+		},
+	}
+	for i, test := range leaderboardHandlerTests {
+		userDao := mockUserDao{
+			readTopUsersFunc: func(ctx context.Context, limit, offset int) ([]user.User, error) {
+				if test.wantLimit != limit || test.wantOffset != offset {
+					t.Errorf("Test %v: wanted top users read with limit %v and offset %v, got %v and %v", i, test.wantLimit, test.wantOffset, limit, offset)
+				}
+				return test.users, test.daoErr
+			},
+		}
+		log := logtest.DiscardLogger
+		r := httptest.NewRequest("", "/leaderboard"+test.query, nil)
+		w := httptest.NewRecorder()
+		h := leaderboardHandler(userDao, 60, log)
+		h.ServeHTTP(w, r)
+		gotCode := w.Code
+		switch {
+		case test.wantCode != gotCode:
+			t.Errorf("Test %v: response codes not equal after reading leaderboard: wanted: %v, got: %v", i, test.wantCode, gotCode)
+		case gotCode != 200:
+		case test.wantBody != w.Body.String():
+			t.Errorf("Test %v: response bodies not equal:\nwanted: %q\ngot:    %q", i, test.wantBody, w.Body.String())
+		case w.Header().Get(HeaderCacheControl) != "max-age=60":
+			t.Errorf("Test %v: wanted leaderboard to be cached by browsers, got cache control header of %q", i, w.Header().Get(HeaderCacheControl))
+		}
+	}
+}
+
+func TestLeaderboardCacheGet(t *testing.T) {
+	now := time.Unix(1000, 0)
+	c := leaderboardCache{
+		pages:  make(map[leaderboardPage]cachedLeaderboard),
+		maxAge: 10 * time.Second,
+		nowFunc: func() time.Time {
+			return now
+		},
+	}
+	readCount := 0
+	read := func() ([]byte, error) {
+		readCount++
+		return []byte{byte(readCount)}, nil
+	}
+	page1 := leaderboardPage{limit: 10}
+	page2 := leaderboardPage{limit: 10, offset: 10}
+	getTests := []struct {
+		page         *leaderboardPage
+		elapsed      time.Duration
+		want         byte
+		wantNumPages int
+	}{
+		{&page1, 0, 1, 1},
+		{&page1, 5 * time.Second, 1, 1},  // cached
+		{&page2, 0, 2, 2},                // different page
+		{&page1, 5 * time.Second, 3, 2},  // expired
+		{&page2, 20 * time.Second, 4, 1}, // expired, page1 removed
+	}
+	for i, test := range getTests {
+		now = now.Add(test.elapsed)
+		got, err := c.get(test.page, read)
+		switch {
+		case err != nil:
+			t.Errorf("Test %v: unwanted error: %v", i, err)
+		case len(got) != 1 || test.want != got[0]:
+			t.Errorf("Test %v: wanted data from read %v, got %v", i, test.want, got)
+		case test.wantNumPages != len(c.pages):
+			t.Errorf("Test %v: wanted %v cached pages, got %v", i, test.wantNumPages, len(c.pages))
+		}
+	}
+}
+
+func TestLeaderboardCacheGetNoCache(t *testing.T) {
+	c := leaderboardCache{
+		pages:   make(map[leaderboardPage]cachedLeaderboard),
+		nowFunc: time.Now,
+	}
+	readCount := 0
+	read := func() ([]byte, error) {
+		readCount++
+		return nil, nil
+	}
+	page := leaderboardPage{limit: 1}
+	c.get(&page, read)
+	c.get(&page, read)
+	if readCount != 2 || len(c.pages) != 0 {
+		t.Errorf("wanted leaderboard to be read each time and not cached when max age is not positive, got %v reads and %v cached pages", readCount, len(c.pages))
+	}
+}
+
+func TestLeaderboardCacheGetFull(t *testing.T) {
+	c := leaderboardCache{
+		pages:   make(map[leaderboardPage]cachedLeaderboard),
+		maxAge:  time.Minute,
+		nowFunc: time.Now,
+	}
+	readCount := 0
+	read := func() ([]byte, error) {
+		readCount++
+		return nil, nil
+	}
+	for i := 0; i <= maxCachedLeaderboardPages; i++ {
+		page := leaderboardPage{limit: 1, offset: i}
+		c.get(&page, read)
+	}
+	if want, got := maxCachedLeaderboardPages, len(c.pages); want != got {
+		t.Errorf("wanted %v cached pages, got %v", want, got)
+	}
+	lastPage := leaderboardPage{limit: 1, offset: maxCachedLeaderboardPages}
+	c.get(&lastPage, read)
+	if want, got := maxCachedLeaderboardPages+2, readCount; want != got {
+		t.Errorf("wanted page to be read again when the cache is full: wanted %v reads, got %v", want, got)
+	}
+}
+
+func TestLeaderboardCacheGetReadUnlocked(t *testing.T) {
+	c := leaderboardCache{
+		pages:   make(map[leaderboardPage]cachedLeaderboard),
+		maxAge:  time.Minute,
+		nowFunc: time.Now,
+	}
+	page1 := leaderboardPage{limit: 1}
+	page2 := leaderboardPage{limit: 1, offset: 1}
+	read1 := func() ([]byte, error) {
+		done := make(chan struct{})
+		go func() {
+			c.get(&page2, func() ([]byte, error) { return nil, nil })
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Error("wanted other page to be read while the first page is being read")
+		}
+		return nil, nil
+	}
+	c.get(&page1, read1)
+}
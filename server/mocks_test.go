@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"sync"
 
+	"github.com/jacobpatterson1549/selene-bananas/db/result"
 	"github.com/jacobpatterson1549/selene-bananas/db/user"
 )
 
@@ -27,6 +28,7 @@ type mockUserDao struct {
 	loginFunc          func(ctx context.Context, u user.User) (*user.User, error)
 	updatePasswordFunc func(ctx context.Context, u user.User, newP string) error
 	deleteFunc         func(ctx context.Context, u user.User) error
+	readTopUsersFunc   func(ctx context.Context, limit, offset int) ([]user.User, error)
 	backendFunc        func() user.Backend
 }
 
@@ -46,10 +48,22 @@ func (m mockUserDao) Delete(ctx context.Context, u user.User) error {
 	return m.deleteFunc(ctx, u)
 }
 
+func (m mockUserDao) ReadTopUsers(ctx context.Context, limit, offset int) ([]user.User, error) {
+	return m.readTopUsersFunc(ctx, limit, offset)
+}
+
 func (m mockUserDao) Backend() user.Backend {
 	return m.backendFunc()
 }
 
+type mockResultDao struct {
+	readStatsFunc func(ctx context.Context, username string, recentLimit int) (*result.Stats, error)
+}
+
+func (m mockResultDao) ReadStats(ctx context.Context, username string, recentLimit int) (*result.Stats, error) {
+	return m.readStatsFunc(ctx, username, recentLimit)
+}
+
 type mockOauth2Endpoint struct {
 	revokeAccessFunc func(accessToken string) error
 }
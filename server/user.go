@@ -2,9 +2,11 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 
+	"github.com/jacobpatterson1549/selene-bananas/db/result"
 	"github.com/jacobpatterson1549/selene-bananas/db/user"
 	"github.com/jacobpatterson1549/selene-bananas/server/log"
 )
@@ -15,9 +17,18 @@ type UserDao interface {
 	Login(ctx context.Context, u user.User) (*user.User, error)
 	UpdatePassword(ctx context.Context, u user.User, newP string) error
 	Delete(ctx context.Context, u user.User) error
+	ReadTopUsers(ctx context.Context, limit, offset int) ([]user.User, error)
 	Backend() user.Backend
 }
 
+// ResultDao reads the results of finished games.
+type ResultDao interface {
+	ReadStats(ctx context.Context, username string, recentLimit int) (*result.Stats, error)
+}
+
+// recentGamesLimit is the maximum number of recent games shown with a user's statistics.
+const recentGamesLimit = 10
+
 // userCreateHandler creates a user, adding it to the database.
 func userCreateHandler(userDao UserDao, log log.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -124,6 +135,28 @@ func userDeleteHandler(userDao UserDao, e Oauth2Endpoint, lobby Lobby, log log.L
 	}
 }
 
+// userStatsHandler writes the statistics of the games the user has played as json.
+func userStatsHandler(resultDao ResultDao, log log.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u, err := getUser(r)
+		if err != nil {
+			writeInternalError(err, log, w)
+			return
+		}
+		ctx := r.Context()
+		s, err := resultDao.ReadStats(ctx, u.Username, recentGamesLimit)
+		if err != nil {
+			writeInternalError(err, log, w)
+			return
+		}
+		w.Header().Set(HeaderContentType, "application/json")
+		if err := json.NewEncoder(w).Encode(s); err != nil {
+			writeInternalError(err, log, w)
+			return
+		}
+	}
+}
+
 // handleUserDaoError writes a 401 error for users that signed in incorrectly, otherwise writing and logging an internal server error.
 func handleUserDaoError(w http.ResponseWriter, err error, action string, log log.Logger) {
 	switch err {
@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"testing"
 
+	"github.com/jacobpatterson1549/selene-bananas/db/result"
 	"github.com/jacobpatterson1549/selene-bananas/db/user"
 	"github.com/jacobpatterson1549/selene-bananas/server/log/logtest"
 )
@@ -356,3 +357,54 @@ func TestUserDeleteHandler(t *testing.T) {
 		}
 	}
 }
+
+func TestUserStatsHandler(t *testing.T) {
+	userStatsHandlerTests := []struct {
+		stats    *result.Stats
+		daoErr   error
+		wantCode int
+		wantBody string
+	}{
+		{
+			daoErr:   fmt.Errorf("error reading game results"),
+			wantCode: 500,
+		},
+		{
+			stats: &result.Stats{
+				GamesPlayed:        4,
+				Wins:               1,
+				WinRate:            0.25,
+				AverageWordsPerWin: 7,
+			},
+			wantCode: 200,
+			wantBody: `{"gamesPlayed":4,"wins":1,"winRate":0.25,"averageWordsPerWin":7}` + "\n",
+		},
+	}
+	for i, test := range userStatsHandlerTests {
+		resultDao := mockResultDao{
+			readStatsFunc: func(ctx context.Context, username string, recentLimit int) (*result.Stats, error) {
+				switch {
+				case username != "selene":
+					t.Errorf("Test %v: wanted stats for selene to be read, got %v", i, username)
+				case recentLimit <= 0:
+					t.Errorf("Test %v: wanted positive recent games limit, got %v", i, recentLimit)
+				}
+				return test.stats, test.daoErr
+			},
+		}
+		log := logtest.DiscardLogger
+		r := httptest.NewRequest("", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), usernameContextKey, "selene"))
+		r = r.WithContext(context.WithValue(r.Context(), isOauth2ContextKey, false))
+		w := httptest.NewRecorder()
+		h := userStatsHandler(resultDao, log)
+		h.ServeHTTP(w, r)
+		gotCode := w.Code
+		switch {
+		case test.wantCode != gotCode:
+			t.Errorf("Test %v: response codes not equal after reading user stats: wanted: %v, got: %v", i, test.wantCode, gotCode)
+		case gotCode == 200 && test.wantBody != w.Body.String():
+			t.Errorf("Test %v: response bodies not equal:\nwanted: %q\ngot:    %q", i, test.wantBody, w.Body.String())
+		}
+	}
+}
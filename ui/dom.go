@@ -105,9 +105,32 @@ func (dom DOM) Color(element js.Value) string {
 }
 
 // NewWebSocket creates a new WebSocket with the specified url.
-func (dom *DOM) NewWebSocket(url string) js.Value {
+// The server picks one of the subprotocols, if any are specified.
+func (dom *DOM) NewWebSocket(url string, protocols ...string) js.Value {
 	webSocket := dom.global.Get("WebSocket")
-	return webSocket.New(url)
+	if len(protocols) == 0 {
+		return webSocket.New(url)
+	}
+	jsProtocols := make([]any, len(protocols))
+	for i, p := range protocols {
+		jsProtocols[i] = p
+	}
+	return webSocket.New(url, jsProtocols)
+}
+
+// NewUint8Array creates a javascript Uint8Array with a copy of the bytes.
+func (dom DOM) NewUint8Array(data []byte) js.Value {
+	uint8Array := dom.global.Get("Uint8Array").New(len(data))
+	js.CopyBytesToJS(uint8Array, data)
+	return uint8Array
+}
+
+// ArrayBufferBytes copies the bytes of a javascript ArrayBuffer.
+func (dom DOM) ArrayBufferBytes(arrayBuffer js.Value) []byte {
+	uint8Array := dom.global.Get("Uint8Array").New(arrayBuffer)
+	data := make([]byte, uint8Array.Length())
+	js.CopyBytesToGo(data, uint8Array)
+	return data
 }
 
 // NewXHR creates a new XML HTTP Request.
@@ -140,3 +163,20 @@ func (dom DOM) EncodeURIComponent(str string) string {
 	encodedURIValue := fn.Invoke(str)
 	return encodedURIValue.String()
 }
+
+// PageURL gets the url of the page without its query or fragment.
+func (dom DOM) PageURL() string {
+	location := dom.global.Get("location")
+	return location.Get("origin").String() + location.Get("pathname").String()
+}
+
+// QueryParam gets the decoded value of the parameter in the query of the url of the page, or an empty string if the parameter is not present.
+func (dom DOM) QueryParam(key string) string {
+	search := dom.global.Get("location").Get("search")
+	params := dom.global.Get("URLSearchParams").New(search)
+	value := params.Call("get", key)
+	if value.IsNull() {
+		return ""
+	}
+	return value.String()
+}
@@ -261,6 +261,35 @@ func TestNewWebSocket(t *testing.T) {
 	}
 }
 
+func TestNewWebSocketWithProtocols(t *testing.T) {
+	wantProtocols := []string{"binary", "json"}
+	websocketFn := js.FuncOf(func(this js.Value, args []js.Value) any {
+		gotProtocols := args[1]
+		if len(wantProtocols) != gotProtocols.Length() {
+			t.Fatalf("wanted %v protocols, got %v", len(wantProtocols), gotProtocols.Length())
+		}
+		for i, want := range wantProtocols {
+			if got := gotProtocols.Index(i).String(); want != got {
+				t.Errorf("protocol %v not equal: wanted %v, got %v", i, want, got)
+			}
+		}
+		return js.ValueOf(map[string]any{})
+	})
+	dom := DOM{js.ValueOf(map[string]any{"WebSocket": websocketFn})}
+	dom.NewWebSocket("special_url", wantProtocols...)
+	websocketFn.Release()
+}
+
+func TestUint8Array(t *testing.T) {
+	dom := DOM{js.Global()}
+	want := []byte{1, 2, 0, 255}
+	uint8Array := dom.NewUint8Array(want)
+	got := dom.ArrayBufferBytes(uint8Array.Get("buffer"))
+	if string(want) != string(got) {
+		t.Errorf("bytes not equal:\nwanted: %v\ngot:    %v", want, got)
+	}
+}
+
 func TestNewXHR(t *testing.T) {
 	want := js.ValueOf(map[string]any{
 		"key": "the new xhr",
@@ -374,3 +403,36 @@ func TestEncodeURIComponent(t *testing.T) {
 		}
 	}
 }
+
+func TestPageURL(t *testing.T) {
+	dom := DOM{js.ValueOf(map[string]any{
+		"location": map[string]any{
+			"origin":   "https://example.com",
+			"pathname": "/lobby",
+			"search":   "?game=4",
+		},
+	})}
+	want := "https://example.com/lobby"
+	if got := dom.PageURL(); want != got {
+		t.Errorf("not equal:\nwanted: %v\ngot:    %v", want, got)
+	}
+}
+
+func TestQueryParam(t *testing.T) {
+	dom := DOM{js.ValueOf(map[string]any{
+		"location": map[string]any{
+			"search": "?game=4&inviteCode=b4n4n4%20split",
+		},
+		"URLSearchParams": js.Global().Get("URLSearchParams"),
+	})}
+	queryParamTests := map[string]string{
+		"game":       "4",
+		"inviteCode": "b4n4n4 split",
+		"other":      "",
+	}
+	for key, want := range queryParamTests {
+		if got := dom.QueryParam(key); want != got {
+			t.Errorf("query param %v not equal:\nwanted: %v\ngot:    %v", key, want, got)
+		}
+	}
+}
@@ -25,6 +25,8 @@ type (
 		draw       drawMetrics
 		selection  selection
 		gameStatus game.Status
+		hint       []tile.Position
+		report     *board.Report
 		Socket     Socket
 		parentDiv  js.Value
 		element    js.Value
@@ -252,6 +254,8 @@ func (c *Canvas) Redraw() {
 		c.draw.numCols*c.draw.tileLength, c.draw.numRows*c.draw.tileLength)
 	c.drawUnusedTiles(false)
 	c.drawUsedTiles(false)
+	c.drawHint()
+	c.drawReport()
 	switch {
 	case c.gameStatus == game.NotStarted:
 		c.drawErrorMessage("Not Started")
@@ -273,6 +277,95 @@ func (c *Canvas) SetGameStatus(s game.Status) {
 	c.gameStatus = s
 	c.selection.setMoveState(none)
 	c.selection.tiles = make(map[tile.ID]tileSelection)
+	c.hint = nil
+	c.report = nil
+}
+
+// SetHint sets the positions to suggest moving unused tiles to.  The canvas should be redrawn afterwards to show them.
+func (c *Canvas) SetHint(positions []tile.Position) {
+	c.hint = positions
+}
+
+// drawHint outlines the positions of the hint for tiles that are still unused.
+func (c *Canvas) drawHint() {
+	if len(c.hint) == 0 {
+		return
+	}
+	c.ctx.SetStrokeColor(c.DragColor)
+	c.ctx.SetFillColor(c.DragColor)
+	for _, tp := range c.hint {
+		if _, ok := c.board.UnusedTiles[tp.Tile.ID]; !ok {
+			continue
+		}
+		x := c.draw.usedMin.x + int(tp.X)*c.draw.tileLength
+		y := c.draw.usedMin.y + int(tp.Y)*c.draw.tileLength
+		c.ctx.StrokeRect(x, y, c.draw.tileLength, c.draw.tileLength)
+		c.ctx.FillText(string(tp.Tile.Ch), x+c.draw.textOffset, y+c.draw.tileLength-c.draw.textOffset)
+	}
+	c.ctx.SetStrokeColor(c.MainColor)
+	c.ctx.SetFillColor(c.MainColor)
+}
+
+// SetReport sets the report of the words on the board.  The canvas should be redrawn afterwards to show it.
+func (c *Canvas) SetReport(r *board.Report) {
+	c.report = r
+}
+
+// drawReport outlines the invalid words and the tiles that are not in the largest group in the error color.
+// Words that have been moved since the report was created are not outlined.
+func (c *Canvas) drawReport() {
+	if c.report == nil {
+		return
+	}
+	c.ctx.SetStrokeColor(c.ErrorColor)
+	for _, w := range c.report.Words {
+		if w.Valid || !c.hasWord(w) {
+			continue
+		}
+		width, height := c.draw.tileLength, c.draw.tileLength
+		switch n := len([]rune(w.Word)); {
+		case w.Down:
+			height *= n
+		default:
+			width *= n
+		}
+		x := c.draw.usedMin.x + int(w.X)*c.draw.tileLength
+		y := c.draw.usedMin.y + int(w.Y)*c.draw.tileLength
+		c.ctx.StrokeRect(x, y, width, height)
+	}
+	for i, group := range c.report.Groups {
+		if i == 0 {
+			continue // the largest group is not stray
+		}
+		for _, id := range group {
+			tp, ok := c.board.UsedTiles[id]
+			if !ok {
+				continue
+			}
+			x := c.draw.usedMin.x + int(tp.X)*c.draw.tileLength
+			y := c.draw.usedMin.y + int(tp.Y)*c.draw.tileLength
+			c.ctx.StrokeRect(x, y, c.draw.tileLength, c.draw.tileLength)
+		}
+	}
+	c.ctx.SetStrokeColor(c.MainColor)
+}
+
+// hasWord determines if the letters of the word are still on the board at the position of the word.
+func (c Canvas) hasWord(w board.WordReport) bool {
+	x, y := w.X, w.Y
+	for _, r := range w.Word {
+		t, ok := c.board.UsedTileLocs[x][y]
+		if !ok || rune(t.Ch) != r {
+			return false
+		}
+		switch {
+		case w.Down:
+			y++
+		default:
+			x++
+		}
+	}
+	return true
 }
 
 // drawErrorMEssage draws the specified message at the top of the canvas
@@ -436,7 +529,7 @@ func (c Canvas) canMove() bool {
 
 // StartSwap start a swap move.
 func (c *Canvas) StartSwap() {
-	c.log.Info("click a tile to swap for three others from the pile")
+	c.log.Info("click a tile to swap it for others from the pile")
 	c.selection.setMoveState(swap)
 	c.selection.tiles = make(map[tile.ID]tileSelection)
 	c.Redraw()
@@ -449,16 +542,14 @@ func (c *Canvas) swap() {
 		c.log.Info("swap cancelled")
 		return
 	}
-	if err := c.board.RemoveTile(endTS.tile); err != nil {
+	d := c.board.NewDiff(board.OpRemove, tile.Position{Tile: endTS.tile})
+	if err := c.board.Apply(d); err != nil {
 		c.log.Error("removing tile while swapping: " + err.Error())
 	}
-	tiles := []tile.Tile{
-		endTS.tile,
-	}
 	m := message.Message{
 		Type: message.SwapGameTile,
 		Game: &game.Info{
-			Board: board.New(tiles, nil),
+			Diff: &d,
 		},
 	}
 	c.Socket.Send(m)
@@ -582,7 +673,8 @@ func (c *Canvas) moveSelectedTiles() {
 	if !c.board.CanMoveTiles(tilePositionsM) {
 		return
 	}
-	if err := c.board.MoveTiles(tilePositionsM); err != nil {
+	d := c.board.NewDiff(board.OpMove, tilePositions...)
+	if err := c.board.Apply(d); err != nil {
 		c.log.Error("moving tiles to presumably valid locations: " + err.Error())
 		return
 	}
@@ -592,7 +684,7 @@ func (c *Canvas) moveSelectedTiles() {
 	m := message.Message{
 		Type: message.MoveGameTile,
 		Game: &game.Info{
-			Board: board.New(nil, tilePositions),
+			Diff: &d,
 		},
 	}
 	c.Socket.Send(m)
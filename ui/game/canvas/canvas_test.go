@@ -433,6 +433,8 @@ func TestSetGameStatus(t *testing.T) {
 			},
 			tiles: map[tile.ID]tileSelection{1: {}},
 		},
+		hint:   []tile.Position{{}},
+		report: &board.Report{},
 	}
 	want := game.InProgress
 	c.SetGameStatus(want)
@@ -443,6 +445,98 @@ func TestSetGameStatus(t *testing.T) {
 		t.Errorf("wanted moveState to be none (%v), got %v", none, c.selection.moveState)
 	case len(c.selection.tiles) != 0:
 		t.Errorf("wanted selection tiles to be cleared")
+	case len(c.hint) != 0:
+		t.Errorf("wanted hint to be cleared")
+	case c.report != nil:
+		t.Errorf("wanted report to be cleared")
+	}
+}
+
+func TestDrawReport(t *testing.T) {
+	b := board.New(nil, []tile.Position{
+		{Tile: tile.Tile{ID: 1, Ch: 'X'}, X: 0, Y: 0},
+		{Tile: tile.Tile{ID: 2, Ch: 'Y'}, X: 1, Y: 0},
+		{Tile: tile.Tile{ID: 3, Ch: 'Z'}, X: 1, Y: 1},
+		{Tile: tile.Tile{ID: 4, Ch: 'Q'}, X: 5, Y: 5},
+	})
+	r := board.Report{
+		Words: []board.WordReport{
+			{Word: "XY", X: 0, Y: 0, Valid: true},
+			{Word: "YZ", X: 1, Y: 0, Down: true},
+			{Word: "AB", X: 3, Y: 3}, // moved since the report was created
+		},
+		Groups: [][]tile.ID{
+			{1, 2, 3},
+			{4},
+		},
+	}
+	var gotRects [][4]int
+	c := Canvas{
+		board: b,
+		draw: drawMetrics{
+			tileLength: 10,
+			usedMin:    pixelPosition{x: 5, y: 50},
+		},
+		report: &r,
+		ctx: &mockContext{
+			SetStrokeColorFunc: func(name string) {
+				// NOOP
+			},
+			StrokeRectFunc: func(x, y, width, height int) {
+				gotRects = append(gotRects, [4]int{x, y, width, height})
+			},
+		},
+	}
+	c.drawReport()
+	wantRects := [][4]int{
+		{15, 50, 10, 20},  // YZ
+		{55, 100, 10, 10}, // Q
+	}
+	if !reflect.DeepEqual(wantRects, gotRects) {
+		t.Errorf("report outlines not equal:\nwanted: %v\ngot:    %v", wantRects, gotRects)
+	}
+}
+
+func TestDrawHint(t *testing.T) {
+	hint := []tile.Position{
+		{Tile: tile.Tile{ID: 1, Ch: 'A'}, X: 2, Y: 3},
+		{Tile: tile.Tile{ID: 2, Ch: 'B'}, X: 3, Y: 3},
+	}
+	var gotTexts []string
+	var gotRects [][2]int
+	c := Canvas{
+		board: &board.Board{
+			UnusedTileIDs: []tile.ID{1},
+			UnusedTiles:   map[tile.ID]tile.Tile{1: {ID: 1, Ch: 'A'}},
+		},
+		draw: drawMetrics{
+			tileLength: 10,
+			usedMin:    pixelPosition{x: 5, y: 50},
+		},
+		hint: hint,
+		ctx: &mockContext{
+			SetStrokeColorFunc: func(name string) {
+				// NOOP
+			},
+			SetFillColorFunc: func(name string) {
+				// NOOP
+			},
+			StrokeRectFunc: func(x, y, width, height int) {
+				gotRects = append(gotRects, [2]int{x, y})
+			},
+			FillTextFunc: func(text string, x, y int) {
+				gotTexts = append(gotTexts, text)
+			},
+		},
+	}
+	c.drawHint()
+	wantRects := [][2]int{{25, 80}} // the tile with id 2 is not drawn because it has been used
+	wantTexts := []string{"A"}
+	switch {
+	case !reflect.DeepEqual(wantRects, gotRects):
+		t.Errorf("hint outlines not equal:\nwanted: %v\ngot:    %v", wantRects, gotRects)
+	case !reflect.DeepEqual(wantTexts, gotTexts):
+		t.Errorf("hint letters not equal:\nwanted: %v\ngot:    %v", wantTexts, gotTexts)
 	}
 }
 
@@ -955,7 +1049,7 @@ func TestSwap(t *testing.T) {
 		Socket: mockSocket{
 			SendFunc: func(m message.Message) {
 				switch {
-				case m.Type != message.SwapGameTile, m.Game.Board.UnusedTileIDs[0] != st.ID:
+				case m.Type != message.SwapGameTile, m.Game.Diff == nil, len(m.Game.Diff.Changes) != 1, m.Game.Diff.Changes[0].Op != board.OpRemove, m.Game.Diff.Changes[0].Tile.ID != st.ID:
 					t.Errorf("wanted message to swap tile 8, got: %v", m)
 				}
 				messageSent = true
@@ -966,6 +1060,8 @@ func TestSwap(t *testing.T) {
 	switch {
 	case len(c.board.UnusedTiles) != 0:
 		t.Error("wanted tile to be swapped")
+	case c.board.Revision != 1:
+		t.Errorf("wanted revision of board to be incremented after swap, got %v", c.board.Revision)
 	case !messageSent:
 		t.Error("wanted message to be sent")
 	case len(c.selection.tiles) != 0:
@@ -1016,7 +1112,11 @@ func TestSwapCannotRemove(t *testing.T) {
 				want := message.Message{
 					Type: message.SwapGameTile,
 					Game: &game.Info{
-						Board: board.New([]tile.Tile{{ID: 66}}, nil),
+						Diff: &board.Diff{
+							Changes: []board.Change{
+								{Op: board.OpRemove, Position: tile.Position{Tile: tile.Tile{ID: 66}}},
+							},
+						},
 					},
 				}
 				if got := m; !reflect.DeepEqual(want, got) {
@@ -5,6 +5,10 @@ package game
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -13,6 +17,8 @@ import (
 	"github.com/jacobpatterson1549/selene-bananas/game"
 	"github.com/jacobpatterson1549/selene-bananas/game/board"
 	"github.com/jacobpatterson1549/selene-bananas/game/message"
+	"github.com/jacobpatterson1549/selene-bananas/game/player"
+	"github.com/jacobpatterson1549/selene-bananas/game/replay"
 	"github.com/jacobpatterson1549/selene-bananas/game/tile"
 	"github.com/jacobpatterson1549/selene-bananas/ui"
 )
@@ -22,12 +28,17 @@ type (
 	Game struct {
 		dom           DOM
 		id            game.ID
+		inviteCode    string
+		status        game.Status
+		isHost        bool
+		forfeited     bool
 		log           Log
 		board         *board.Board
 		canvas        Canvas
 		canvasCreator CanvasCreator
 		Socket        Socket
 		finalBoards   map[string]board.Board
+		replay        *replay.Replay
 	}
 
 	// Socket sends messages to the server.
@@ -47,6 +58,8 @@ type (
 		CloneElement(query string) js.Value
 		Confirm(message string) bool
 		Color(element js.Value) string
+		EncodeURIComponent(str string) string
+		PageURL() string
 		RegisterFuncs(ctx context.Context, wg *sync.WaitGroup, parentName string, jsFuncs map[string]js.Func)
 		NewJsFunc(fn func()) js.Func
 		NewJsEventFunc(fn func(event js.Value)) js.Func
@@ -71,6 +84,8 @@ type (
 		UpdateSize(width int)
 		NumRows() int
 		NumCols() int
+		SetHint(positions []tile.Position)
+		SetReport(r *board.Report)
 	}
 
 	// CanvasCreator creates canvases to draw other player's final boards.
@@ -94,19 +109,30 @@ func New(dom DOM, log Log, board *board.Board, canvas Canvas, createCanvas Canva
 // InitDom registers game dom functions.
 func (g *Game) InitDom(ctx context.Context, wg *sync.WaitGroup) {
 	jsFuncs := map[string]js.Func{
-		"create":            g.dom.NewJsFunc(g.startCreate),
-		"createWithConfig":  g.dom.NewJsEventFunc(g.createWithConfig),
-		"join":              g.dom.NewJsEventFunc(g.join),
-		"leave":             g.dom.NewJsFunc(g.sendLeave),
-		"delete":            g.dom.NewJsFunc(g.delete),
-		"start":             g.dom.NewJsFunc(g.Start),
-		"finish":            g.dom.NewJsFunc(g.finish),
-		"snagTile":          g.dom.NewJsFunc(g.snagTile),
-		"swapTile":          g.dom.NewJsFunc(g.startTileSwap),
-		"sendChat":          g.dom.NewJsEventFunc(g.sendChat),
-		"resizeTiles":       g.dom.NewJsFunc(g.resizeTiles),
-		"refreshTileLength": g.dom.NewJsFunc(g.refreshTileLength),
-		"viewFinalBoard":    g.dom.NewJsFunc(g.viewFinalBoard),
+		"create":             g.dom.NewJsFunc(g.startCreate),
+		"createWithConfig":   g.dom.NewJsEventFunc(g.createWithConfig),
+		"join":               g.dom.NewJsEventFunc(g.join),
+		"joinWithInviteCode": g.dom.NewJsEventFunc(g.joinWithInviteCode),
+		"spectate":           g.dom.NewJsEventFunc(g.spectate),
+		"leave":              g.dom.NewJsFunc(g.sendLeave),
+		"forfeit":            g.dom.NewJsFunc(g.forfeit),
+		"delete":             g.dom.NewJsFunc(g.delete),
+		"start":              g.dom.NewJsFunc(g.Start),
+		"toggleReady":        g.dom.NewJsFunc(g.toggleReady),
+		"finish":             g.dom.NewJsFunc(g.finish),
+		"snagTile":           g.dom.NewJsFunc(g.snagTile),
+		"swapTile":           g.dom.NewJsFunc(g.startTileSwap),
+		"addBot":             g.dom.NewJsFunc(g.addBot),
+		"kick":               g.dom.NewJsFunc(g.kick),
+		"requestHint":        g.dom.NewJsFunc(g.requestHint),
+		"challenge":          g.dom.NewJsFunc(g.challenge),
+		"acceptWin":          g.dom.NewJsFunc(g.acceptWin),
+		"sendChat":           g.dom.NewJsEventFunc(g.sendChat),
+		"resizeTiles":        g.dom.NewJsFunc(g.resizeTiles),
+		"refreshTileLength":  g.dom.NewJsFunc(g.refreshTileLength),
+		"viewFinalBoard":     g.dom.NewJsFunc(g.viewFinalBoard),
+		"requestReplay":      g.dom.NewJsFunc(g.requestReplay),
+		"viewReplayStep":     g.dom.NewJsFunc(g.viewReplayStep),
 	}
 	g.dom.RegisterFuncs(ctx, wg, "game", jsFuncs)
 }
@@ -121,6 +147,7 @@ func (g *Game) startCreate() {
 // createWithConfig clears the tiles and asks the server for a new game to join with the create config.
 func (g *Game) createWithConfig(event js.Value) {
 	checkOnSnag := g.dom.Checked(".checkOnSnag")
+	strictPeel := g.dom.Checked(".strictPeel")
 	penalize := g.dom.Checked(".penalize")
 	minLengthStr := g.dom.Value(".minLength")
 	minLength, err := strconv.Atoi(minLengthStr)
@@ -129,20 +156,128 @@ func (g *Game) createWithConfig(event js.Value) {
 		return
 	}
 	prohibitDuplicates := g.dom.Checked(".prohibitDuplicates")
+	hints := g.dom.Checked(".hints")
+	penalizeHints := g.dom.Checked(".penalizeHints")
+	scoringStr := g.dom.Value(".scoring")
+	scoring, err := strconv.Atoi(scoringStr)
+	if err != nil {
+		g.log.Error("retrieving scoring: " + err.Error())
+		return
+	}
+	timeLimitStr := g.dom.Value(".timeLimit")
+	timeLimit, err := strconv.Atoi(timeLimitStr)
+	if err != nil {
+		g.log.Error("retrieving time limit: " + err.Error())
+		return
+	}
+	challengeSecStr := g.dom.Value(".challengeSec")
+	challengeSec, err := strconv.Atoi(challengeSecStr)
+	if err != nil {
+		g.log.Error("retrieving challenge time: " + err.Error())
+		return
+	}
+	language := g.dom.Value(".language")
+	tileBagStr := g.dom.Value(".tileBag")
+	tileBag, err := strconv.Atoi(tileBagStr)
+	if err != nil {
+		g.log.Error("retrieving tile bag: " + err.Error())
+		return
+	}
+	var letterCounts map[string]int
+	if game.TileBag(tileBag) == game.CustomTileBag {
+		letterCounts, err = parseLetterCounts(g.dom.Value(".letterCounts"))
+		if err != nil {
+			g.log.Error("retrieving letter counts: " + err.Error())
+			return
+		}
+	}
+	numNewTilesStr := g.dom.Value(".numNewTiles")
+	numNewTiles, err := strconv.Atoi(numNewTilesStr)
+	if err != nil {
+		g.log.Error("retrieving starting tile count: " + err.Error())
+		return
+	}
+	swapRatioStr := g.dom.Value(".swapRatio")
+	swapRatio, err := strconv.Atoi(swapRatioStr)
+	if err != nil {
+		g.log.Error("retrieving swap ratio: " + err.Error())
+		return
+	}
+	swapLimitStr := g.dom.Value(".swapLimit")
+	swapLimit, err := strconv.Atoi(swapLimitStr)
+	if err != nil {
+		g.log.Error("retrieving swap limit: " + err.Error())
+		return
+	}
+	swapCooldownStr := g.dom.Value(".swapCooldown")
+	swapCooldown, err := strconv.Atoi(swapCooldownStr)
+	if err != nil {
+		g.log.Error("retrieving swap cooldown: " + err.Error())
+		return
+	}
+	swapUsedTiles := g.dom.Checked(".swapUsedTiles")
+	teamSizeStr := g.dom.Value(".teamSize")
+	teamSize, err := strconv.Atoi(teamSizeStr)
+	if err != nil {
+		g.log.Error("retrieving team size: " + err.Error())
+		return
+	}
+	autoStart := g.dom.Checked(".autoStart")
+	inviteCode := g.dom.Value(".inviteCode")
 	m := message.Message{
 		Type: message.CreateGame,
 		Game: &game.Info{
 			Config: &game.Config{
 				CheckOnSnag:        checkOnSnag,
+				StrictPeel:         strictPeel,
 				Penalize:           penalize,
 				MinLength:          minLength,
 				ProhibitDuplicates: prohibitDuplicates,
+				Hints:              hints,
+				PenalizeHints:      penalizeHints,
+				Scoring:            game.Scoring(scoring),
+				TimeLimitSec:       timeLimit * 60,
+				ChallengeSec:       challengeSec,
+				Language:           game.Language(language),
+				TeamSize:           teamSize,
+				TileBag:            game.TileBag(tileBag),
+				LetterCounts:       letterCounts,
+				NumNewTiles:        numNewTiles,
+				SwapRatio:          swapRatio,
+				SwapLimit:          swapLimit,
+				SwapCooldownSec:    swapCooldown,
+				SwapUsedTiles:      swapUsedTiles,
+				AutoStart:          autoStart,
+				InviteCode:         inviteCode,
 			},
 		},
 	}
 	g.setTabActive(m)
 }
 
+// parseLetterCounts reads the counts of tiles of each letter in a custom tile bag, such as "A:13 B:3 C:3".
+func parseLetterCounts(text string) (map[string]int, error) {
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\n' || r == '\t'
+	})
+	if len(fields) == 0 {
+		return nil, errors.New("no letter counts")
+	}
+	letterCounts := make(map[string]int, len(fields))
+	for _, f := range fields {
+		letter, countStr, ok := strings.Cut(f, ":")
+		if !ok {
+			return nil, errors.New("letter count must be a letter and a number separated by a colon: " + f)
+		}
+		count, err := strconv.Atoi(countStr)
+		if err != nil {
+			return nil, errors.New("count of " + letter + " tiles: " + err.Error())
+		}
+		letterCounts[strings.ToUpper(letter)] += count
+	}
+	return letterCounts, nil
+}
+
 // join asks the server to join an existing game.
 func (g *Game) join(event js.Value) {
 	joinGameButton := event.Get("srcElement")
@@ -160,6 +295,49 @@ func (g *Game) join(event js.Value) {
 	g.setTabActive(m)
 }
 
+// joinWithInviteCode asks the server to join a private game with the id and invite code from the form of the event.
+func (g *Game) joinWithInviteCode(event js.Value) {
+	f, err := ui.NewForm(g.dom.QuerySelectorAll, event)
+	if err != nil {
+		g.log.Error(err.Error())
+		return
+	}
+	idText := f.Params.Get("game-id")
+	id, err := strconv.Atoi(idText)
+	if err != nil {
+		g.log.Error("could not get Id of game: " + err.Error())
+		return
+	}
+	g.id = game.ID(id)
+	m := message.Message{
+		Type: message.JoinGame,
+		Game: &game.Info{
+			InviteCode: f.Params.Get("invite-code"),
+		},
+	}
+	g.setTabActive(m)
+}
+
+// spectate asks the server to watch an existing game without playing in it.
+func (g *Game) spectate(event js.Value) {
+	spectateGameButton := event.Get("srcElement")
+	gameIDInput := spectateGameButton.Get("parentElement").Get("children").Index(0)
+	idText := gameIDInput.Get("value").String()
+	id, err := strconv.Atoi(idText)
+	if err != nil {
+		g.log.Error("could not get Id of game to spectate: " + err.Error())
+		return
+	}
+	g.id = game.ID(id)
+	g.hide(true)
+	g.dom.SetChecked("#hide-spectate", false)
+	g.dom.SetChecked("#tab-spectate", true)
+	m := message.Message{
+		Type: message.SpectateGame,
+	}
+	g.Socket.Send(m)
+}
+
 // hide sets the #hide-game input.
 func (g *Game) hide(hideGame bool) {
 	g.dom.SetChecked("#hide-game", hideGame)
@@ -182,11 +360,53 @@ func (g *Game) sendLeave() {
 // Leave changes the view for game by hiding it.
 func (g *Game) Leave() {
 	g.id = 0
+	g.inviteCode = ""
+	g.status = 0
+	g.isHost = false
+	g.forfeited = false
 	g.setFinalBoards(nil)
 	g.hide(true)
+	g.dom.SetChecked("#hide-spectate", true)
 	g.dom.SetChecked("#tab-lobby", true)
 }
 
+// forfeit gives up the game.  The board of the player is frozen.
+func (g *Game) forfeit() {
+	if ok := g.dom.Confirm("Are you sure you want to forfeit? Your board will be frozen and you will not be able to win."); !ok {
+		return
+	}
+	m := message.Message{
+		Type: message.Forfeit,
+	}
+	g.Socket.Send(m)
+}
+
+// toggleReady tells the server that the player is ready, or no longer ready, to start the game.
+func (g *Game) toggleReady() {
+	m := message.Message{
+		Type: message.ToggleReady,
+	}
+	g.Socket.Send(m)
+}
+
+// kick removes the selected player from the game.  Only the host can kick players.
+func (g *Game) kick() {
+	playerName := g.dom.Value(".game .actions>.kick-player")
+	if len(playerName) == 0 {
+		return
+	}
+	if ok := g.dom.Confirm("Are you sure you want to kick " + playerName + " from the game?"); !ok {
+		return
+	}
+	m := message.Message{
+		Type: message.KickPlayer,
+		Game: &game.Info{
+			Players: []string{playerName},
+		},
+	}
+	g.Socket.Send(m)
+}
+
 // delete removes everyone from the game and deletes it.
 func (g *Game) delete() {
 	if ok := g.dom.Confirm("Are you sure? Deleting the game will kick everyone out."); !ok {
@@ -233,6 +453,106 @@ func (g *Game) startTileSwap() {
 	g.canvas.StartSwap()
 }
 
+// addBot asks for a computer player of the selected difficulty to join the game.
+func (g *Game) addBot() {
+	difficultyStr := g.dom.Value(".game .actions>.bot-difficulty")
+	difficulty, err := strconv.Atoi(difficultyStr)
+	if err != nil {
+		g.log.Error("retrieving bot difficulty: " + err.Error())
+		return
+	}
+	m := message.Message{
+		Type: message.AddBot,
+		Game: &game.Info{
+			BotDifficulty: player.Difficulty(difficulty),
+			InviteCode:    g.inviteCode,
+		},
+	}
+	g.Socket.Send(m)
+}
+
+// requestHint asks the game where unused tiles could be placed to form a new word.
+func (g *Game) requestHint() {
+	m := message.Message{
+		Type: message.Hint,
+	}
+	g.Socket.Send(m)
+}
+
+// challenge votes that the words typed by the player are not allowed on the board of the player who claims to win.
+func (g *Game) challenge() {
+	words := strings.Fields(strings.ToUpper(g.dom.Value(".game .actions>.challenge-words")))
+	if len(words) == 0 {
+		g.log.Error("type the words to challenge, separated by spaces")
+		return
+	}
+	g.sendChallengedWords(words)
+}
+
+// acceptWin votes to let the player who claims to win finish the game.
+func (g *Game) acceptWin() {
+	g.sendChallengedWords(nil)
+}
+
+// sendChallengedWords tells the server which words of the player who claims to win are challenged.
+func (g *Game) sendChallengedWords(words []string) {
+	m := message.Message{
+		Type: message.ChallengeWords,
+		Game: &game.Info{
+			ChallengedWords: words,
+		},
+	}
+	g.Socket.Send(m)
+	g.dom.SetValue(".game .actions>.challenge-words", "")
+}
+
+// ShowHint highlights the positions the server suggested to move tiles to.
+func (g *Game) ShowHint(m message.Message) {
+	if m.Game == nil || m.Game.Board == nil {
+		return
+	}
+	positions := make([]tile.Position, 0, len(m.Game.Board.UsedTiles))
+	for _, tp := range m.Game.Board.UsedTiles {
+		positions = append(positions, tp)
+	}
+	g.canvas.SetHint(positions)
+	g.canvas.Redraw()
+}
+
+// ShowReport outlines the invalid words and stray tiles from the report of the player's board.
+func (g *Game) ShowReport(m message.Message) {
+	if m.Game == nil || m.Game.Report == nil {
+		return
+	}
+	g.canvas.SetReport(m.Game.Report)
+	g.canvas.Redraw()
+}
+
+// HandleWarning updates the buttons and canvas for the code of the warning.
+func (g *Game) HandleWarning(m message.Message) {
+	switch m.Code {
+	case message.CodeNoTilesLeft:
+		g.dom.SetButtonDisabled(".game .actions>.snag", true)
+		g.dom.SetButtonDisabled(".game .actions>.swap", true)
+	case message.CodeHintsNotAllowed:
+		g.dom.SetButtonDisabled(".game .actions>.hint", true)
+	case message.CodeSwapLimit:
+		g.dom.SetButtonDisabled(".game .actions>.swap", true)
+	case message.CodeBananasRace:
+		g.dom.SetButtonDisabled(".game .actions>.snag", true)
+		g.dom.SetButtonDisabled(".game .actions>.finish", false)
+	case message.CodeMultipleGroups:
+		if m.Details == nil {
+			return
+		}
+		r := board.Report{
+			Groups: [][]tile.ID{nil, m.Details.TileIDs},
+		}
+		g.canvas.SetReport(&r)
+		g.canvas.Redraw()
+	}
+}
+
 // sendChat sends a chat message from the form of the event.
 func (g *Game) sendChat(event js.Value) {
 	f, err := ui.NewForm(g.dom.QuerySelectorAll, event)
@@ -252,6 +572,7 @@ func (g *Game) sendChat(event js.Value) {
 // replacegameTiles completely replaces the games used and unused tiles.
 func (g *Game) replaceGameTiles(m message.Message) {
 	g.resetTiles()
+	g.board.Revision = m.Game.Board.Revision
 	for _, tp := range m.Game.Board.UsedTiles {
 		g.board.UsedTiles[tp.Tile.ID] = tp
 		if _, ok := g.board.UsedTileLocs[tp.X]; !ok {
@@ -264,88 +585,239 @@ func (g *Game) replaceGameTiles(m message.Message) {
 
 // addUnusedTilesappends new tiles onto the game.
 func (g *Game) addUnusedTiles(m message.Message) {
-	tileStrings := make([]string, 0, len(m.Game.Board.UnusedTiles))
+	tiles := make([]tile.Tile, 0, len(m.Game.Board.UnusedTiles))
 	for _, tID := range m.Game.Board.UnusedTileIDs {
 		t, ok := m.Game.Board.UnusedTiles[tID]
 		if !ok {
 			g.log.Error(("could not add all unused tiles"))
 			return
 		}
-		tileText := `"` + string(t.Ch) + `"`
-		tileStrings = append(tileStrings, tileText)
+		tiles = append(tiles, t)
 		if err := g.board.AddTile(t); err != nil {
 			g.log.Error("could not add unused tile(s): " + err.Error())
 			return
 		}
 	}
 	if m.Type != message.JoinGame {
-		message := "adding unused tile"
-		if len(tileStrings) == 1 {
-			message += "s"
+		g.logAddedTiles(tiles)
+	}
+}
+
+// applyDiff makes the changes from the server to the board.
+// The whole board is requested if the changes are for a different revision of the board, which happens when tiles are moved while the server is changing the board.
+func (g *Game) applyDiff(m message.Message) {
+	if err := g.board.Apply(*m.Game.Diff); err != nil {
+		g.setBoardSize(message.Message{Type: message.RefreshGameBoard})
+		return
+	}
+	var tiles []tile.Tile
+	for _, c := range m.Game.Diff.Changes {
+		if c.Op == board.OpAdd {
+			tiles = append(tiles, c.Tile)
 		}
-		message += ": " + strings.Join(tileStrings, ", ")
-		g.log.Info(message)
+	}
+	if len(tiles) > 0 {
+		g.logAddedTiles(tiles)
 	}
 }
 
-// UpdateInfo updates the game for the specified message.
-func (g *Game) UpdateInfo(m message.Message) {
+// logAddedTiles informs the user about the letters of new tiles.
+func (g *Game) logAddedTiles(tiles []tile.Tile) {
+	tileStrings := make([]string, len(tiles))
+	for i, t := range tiles {
+		tileStrings[i] = `"` + string(t.Ch) + `"`
+	}
+	message := "adding unused tile"
+	if len(tileStrings) == 1 {
+		message += "s"
+	}
+	message += ": " + strings.Join(tileStrings, ", ")
+	g.log.Info(message)
+}
+
+// UpdateInfo updates the game for the specified message.  The username is used to determine if the user is the host of the game.
+func (g *Game) UpdateInfo(m message.Message, username string) {
+	g.updateHost(m, username)
+	g.updateForfeited(m, username)
 	g.updateStatus(m)
+	g.updateChallenge(m, username)
 	g.updateTilesLeft(m)
-	g.updatePlayers(m)
+	g.updateTimeLeft(m)
+	g.updatePlayers(m, username)
 	switch {
-	case m.Game.Board == nil:
-		// NOOP
-	case len(m.Game.Board.UsedTiles) > 0:
+	case m.Game.Board != nil:
 		g.replaceGameTiles(m)
-	case len(m.Game.Board.UnusedTiles) > 0:
-		g.addUnusedTiles(m)
+	case m.Game.Diff != nil:
+		g.applyDiff(m)
 	}
 	g.canvas.Redraw()
 	if m.Type == message.JoinGame {
 		g.setRules(m.Game.Config.Rules())
 		g.id = m.Game.ID
+		g.inviteCode = m.Game.Config.InviteCode
+		g.setInviteLink()
+	}
+}
+
+// setInviteLink shows the link that lets other players join the game if it is private.
+// The link is opened to the lobby with the id and invite code of the game in the join form.
+func (g *Game) setInviteLink() {
+	var inviteLink string
+	if len(g.inviteCode) != 0 {
+		inviteLink = g.dom.PageURL() + "?game=" + strconv.Itoa(int(g.id)) + "&inviteCode=" + g.dom.EncodeURIComponent(g.inviteCode)
 	}
+	g.dom.SetValue(".game>.info .invite-link", inviteLink)
 }
 
-// updateStatus sets the statusText and enables or disables the snag, swap, start, and finish buttons.
+// UpdateSpectate shows the snapshot of the game that is being watched, drawing the boards of the players side by side.
+// The boards are drawn on canvases that do not accept moves.
+func (g *Game) UpdateSpectate(m message.Message) {
+	g.dom.SetValue(".spectate>.info .status", m.Game.Status.String())
+	g.dom.SetValue(".spectate>.info .tiles-left", strconv.Itoa(m.Game.TilesLeft))
+	g.dom.SetValue(".spectate>.info .time-left", timeLeft(m.Game.SecondsLeft))
+	g.dom.SetValue(".spectate>.info .players", strings.Join(m.Game.PlayerLabels(), ","))
+	boardsDiv := g.dom.QuerySelector(".spectate .boards")
+	boardsDiv.Set("innerHTML", "")
+	playerNames := make([]string, 0, len(m.Game.PlayerBoards))
+	for playerName := range m.Game.PlayerBoards {
+		playerNames = append(playerNames, playerName)
+	}
+	sort.Strings(playerNames)
+	tileLength := g.canvas.TileLength()
+	for i, playerName := range playerNames {
+		b := m.Game.PlayerBoards[playerName]
+		id := "spectate-board-" + strconv.Itoa(i)
+		div := g.newSpectateBoardDiv(id, playerName)
+		boardsDiv.Call("appendChild", div)
+		canvas := g.canvasCreator.Create(&b, "#"+id)
+		canvas.SetTileLength(tileLength)
+		width := canvas.DesiredWidth()
+		canvas.UpdateSize(width)
+		canvas.SetGameStatus(m.Game.Status)
+		canvas.Redraw()
+	}
+}
+
+// newSpectateBoardDiv creates a new div to draw the board of a player that is being watched.
+func (g *Game) newSpectateBoardDiv(id, playerName string) js.Value {
+	clone := g.dom.CloneElement(".spectate template")
+	cloneChildren := clone.Get("children")
+	div := cloneChildren.Index(0)
+	div.Set("id", id)
+	divChildren := div.Get("children")
+	nameDiv := divChildren.Index(0)
+	nameDiv.Set("innerHTML", playerName)
+	return div
+}
+
+// updateStatus sets the statusText and enables or disables the snag, swap, start, ready, finish, add bot, hint, and forfeit buttons.
+// The board of a player who forfeited the game is frozen.
 func (g *Game) updateStatus(m message.Message) {
-	var snagDisabled, swapDisabled, startDisabled, finishDisabled bool
+	var snagDisabled, swapDisabled, startDisabled, readyDisabled, finishDisabled, addBotDisabled, hintDisabled, forfeitDisabled bool
 	switch m.Game.Status {
 	case game.NotStarted:
 		snagDisabled = true
 		swapDisabled = true
 		finishDisabled = true
+		hintDisabled = true
+		forfeitDisabled = true
 	case game.InProgress:
 		startDisabled = true
-		finishDisabled = m.Game.TilesLeft > 0
-	case game.Finished:
+		readyDisabled = true
+		snagDisabled = m.Game.BananasRace
+		finishDisabled = m.Game.TilesLeft > 0 && !m.Game.BananasRace
+		addBotDisabled = true
+	case game.Finished, game.Challenging:
 		snagDisabled = true
 		swapDisabled = true
 		startDisabled = true
+		readyDisabled = true
 		finishDisabled = true
+		addBotDisabled = true
+		hintDisabled = true
+		forfeitDisabled = true
 	default:
 		return
 	}
+	canvasStatus := m.Game.Status
+	if g.forfeited {
+		snagDisabled = true
+		swapDisabled = true
+		finishDisabled = true
+		hintDisabled = true
+		forfeitDisabled = true
+		canvasStatus = game.Finished // moves are not sent to the server
+	}
+	g.status = m.Game.Status
+	startDisabled = startDisabled || !g.isHost
 	statusText := m.Game.Status.String()
-	g.setFinalBoards(m.Game.FinalBoards)
+	if m.Game.Status != game.Challenging || len(m.Game.FinalBoards) != 0 { // the final boards are only sent when a challenge starts
+		g.setFinalBoards(m.Game.FinalBoards)
+	}
 	g.dom.SetValue(".game>.info .status", statusText)
 	g.dom.SetButtonDisabled(".game .actions>.snag", snagDisabled)
 	g.dom.SetButtonDisabled(".game .actions>.swap", swapDisabled)
 	g.dom.SetButtonDisabled(".game .actions>.start", startDisabled)
+	g.dom.SetButtonDisabled(".game .actions>.ready", readyDisabled)
 	g.dom.SetButtonDisabled(".game .actions>.finish", finishDisabled)
-	g.canvas.SetGameStatus(m.Game.Status)
+	g.dom.SetButtonDisabled(".game .actions>.add-bot", addBotDisabled)
+	g.dom.SetButtonDisabled(".game .actions>.hint", hintDisabled)
+	g.dom.SetButtonDisabled(".game .actions>.forfeit", forfeitDisabled)
+	g.canvas.SetGameStatus(canvasStatus)
+}
+
+// updateForfeited records if the player forfeited the game from the players in the message, if it has any.
+// The board is frozen when the player forfeits.
+func (g *Game) updateForfeited(m message.Message, username string) {
+	if len(m.Game.Players) == 0 {
+		return
+	}
+	forfeited := slices.Contains(m.Game.ForfeitedPlayers, username)
+	if forfeited && !g.forfeited {
+		g.dom.SetButtonDisabled(".game .actions>.snag", true)
+		g.dom.SetButtonDisabled(".game .actions>.swap", true)
+		g.dom.SetButtonDisabled(".game .actions>.finish", true)
+		g.dom.SetButtonDisabled(".game .actions>.hint", true)
+		g.dom.SetButtonDisabled(".game .actions>.forfeit", true)
+		g.canvas.SetGameStatus(game.Finished) // moves are not sent to the server
+	}
+	g.forfeited = forfeited
+}
+
+// updateChallenge enables the challenge and accept buttons for players who can challenge the win claimed by another player.
+func (g *Game) updateChallenge(m message.Message, username string) {
+	if m.Game.Status == 0 {
+		return
+	}
+	canChallenge := m.Game.Status == game.Challenging && m.Game.Claimant != username && !g.forfeited
+	g.dom.SetButtonDisabled(".game .actions>.challenge", !canChallenge)
+	g.dom.SetButtonDisabled(".game .actions>.accept", !canChallenge)
 }
 
-// updateTilesLeft updates the TilesLeft label.  Other labels are updated if there are no tiles left.
+// updateHost shows the host of the game from the message, if it has one.
+// The start, delete, and kick buttons are only enabled for the host.
+func (g *Game) updateHost(m message.Message, username string) {
+	if len(m.Game.Host) == 0 {
+		return
+	}
+	g.isHost = m.Game.Host == username
+	g.dom.SetValue(".game>.info .host", m.Game.Host)
+	g.dom.SetButtonDisabled(".game .actions>.start", !g.isHost || g.status != game.NotStarted)
+	g.dom.SetButtonDisabled(".game .actions>.kick", !g.isHost)
+	g.dom.SetButtonDisabled(".game .actions>.delete", !g.isHost)
+}
+
+// updateTilesLeft updates the TilesLeft label.  Other labels are updated if there are no tiles left or the Bananas race has started.
 func (g *Game) updateTilesLeft(m message.Message) {
 	g.dom.SetValue(".game>.info .tiles-left", strconv.Itoa(m.Game.TilesLeft))
-	if m.Game.TilesLeft == 0 {
+	if m.Game.TilesLeft == 0 || m.Game.BananasRace {
 		g.dom.SetButtonDisabled(".game .actions>.snag", true)
-		g.dom.SetButtonDisabled(".game .actions>.swap", true)
+		if m.Game.TilesLeft == 0 {
+			g.dom.SetButtonDisabled(".game .actions>.swap", true)
+		}
 		// enable the finish button if the game is not being started or is already finished
 		switch m.Game.Status {
-		case game.NotStarted, game.Finished:
+		case game.NotStarted, game.Finished, game.Challenging:
 			// NOOP
 		default:
 			g.dom.SetButtonDisabled(".game .actions>.finish", false)
@@ -353,13 +825,46 @@ func (g *Game) updateTilesLeft(m message.Message) {
 	}
 }
 
+// updateTimeLeft sets the countdown display for timed games from the message.
+// The countdown is cleared when the game is not in progress.  Messages without the time left do not change it.
+func (g *Game) updateTimeLeft(m message.Message) {
+	switch {
+	case m.Game.SecondsLeft > 0,
+		m.Game.Status == game.NotStarted,
+		m.Game.Status == game.Finished:
+		g.dom.SetValue(".game>.info .time-left", timeLeft(m.Game.SecondsLeft))
+	}
+}
+
+// timeLeft formats the seconds left in a timed game, which is empty if there are none.
+func timeLeft(secondsLeft int) string {
+	if secondsLeft <= 0 {
+		return ""
+	}
+	return game.FormatSeconds(secondsLeft)
+}
+
 // updatePlayers sets the players list display from the message.
-func (g *Game) updatePlayers(m message.Message) {
+// The other players are the options of players to kick.
+func (g *Game) updatePlayers(m message.Message, username string) {
 	if len(m.Game.Players) == 0 {
 		return
 	}
-	players := strings.Join(m.Game.Players, ",")
+	players := strings.Join(m.Game.PlayerLabels(), ",")
 	g.dom.SetValue(".game>.info .players", players)
+	kickSelect := g.dom.QuerySelector(".game .actions>.kick-player")
+	kickSelect.Set("innerHTML", "")
+	for _, playerName := range m.Game.Players {
+		if playerName == username {
+			continue
+		}
+		clone := g.dom.CloneElement(".game .actions>template")
+		cloneChildren := clone.Get("children")
+		option := cloneChildren.Index(0)
+		option.Set("value", playerName)
+		option.Set("innerHTML", playerName)
+		kickSelect.Call("appendChild", option)
+	}
 }
 
 // resetTiles clears the tiles on the board.
@@ -394,6 +899,7 @@ func (g *Game) resizeTiles() {
 // setTabActive performs the actions need to activate the game tab and create or join a game.
 func (g *Game) setTabActive(m message.Message) {
 	g.hide(false)
+	g.dom.SetChecked("#hide-spectate", true)
 	g.dom.SetChecked("#hide-game-create", true)
 	g.dom.SetChecked("#tab-game", true)
 	// the tab now has a size, so update the canvas and board
@@ -450,6 +956,8 @@ func (g *Game) setFinalBoards(finalBoards map[string]board.Board) {
 	playersList := g.dom.QuerySelector(".final-boards .player-list form")
 	playersList.Set("innerHTML", "")
 	g.finalBoards = finalBoards
+	g.replay = nil
+	g.dom.SetChecked("#hide-replay", true)
 	for playerName := range finalBoards {
 		div := g.newFinalBoardDiv(playerName)
 		playersList.Call("appendChild", div)
@@ -482,6 +990,14 @@ func (g *Game) viewFinalBoard() {
 		g.log.Error("could not view final board for " + playerName)
 		return
 	}
+	if g.replay != nil {
+		replayBoard, err := g.replayBoard(player.Name(playerName))
+		if err != nil {
+			g.log.Error("could not view replay board for " + playerName + ": " + err.Error())
+			return
+		}
+		b = *replayBoard
+	}
 	canvas := g.canvasCreator.Create(&b, ".final-boards .canvas")
 	tileLength := g.canvas.TileLength()
 	canvas.SetTileLength(tileLength)
@@ -489,3 +1005,84 @@ func (g *Game) viewFinalBoard() {
 	canvas.UpdateSize(width)
 	canvas.Redraw()
 }
+
+// requestReplay asks the server for the moves of the finished game.
+func (g *Game) requestReplay() {
+	m := message.Message{
+		Type: message.GameReplay,
+	}
+	g.Socket.Send(m)
+}
+
+// SetReplay stores the replay of the game so the boards of players can be viewed as they were after any event.
+// The replay is also made available to download.
+func (g *Game) SetReplay(r replay.Replay) {
+	g.replay = &r
+	numEvents := len(r.Events)
+	stepInput := g.dom.QuerySelector(".final-boards .replay .replay-step")
+	stepInput.Set("max", numEvents)
+	stepInput.Set("value", numEvents)
+	replayJSON, err := json.Marshal(r)
+	if err != nil {
+		g.log.Error("encoding replay to download: " + err.Error())
+		return
+	}
+	downloadLink := g.dom.QuerySelector(".final-boards .replay .download")
+	downloadLink.Set("href", "data:application/json;charset=utf-8,"+g.dom.EncodeURIComponent(string(replayJSON)))
+	downloadLink.Set("download", "selene-bananas-game-"+strconv.Itoa(int(r.GameID))+"-replay.json")
+	g.dom.SetChecked("#hide-replay", false)
+	g.viewReplayStep()
+}
+
+// viewReplayStep describes the selected event of the replay and redraws the selected player's board as it was after the event.
+func (g *Game) viewReplayStep() {
+	if g.replay == nil {
+		return
+	}
+	step, err := g.replayStep()
+	if err != nil {
+		g.log.Error(err.Error())
+		return
+	}
+	stepText := strconv.Itoa(step) + "/" + strconv.Itoa(len(g.replay.Events))
+	if step > 0 {
+		e := g.replay.Events[step-1]
+		stepText += ": " + string(e.PlayerName) + " " + e.Type.String()
+	}
+	g.dom.SetValue(".final-boards .replay .replay-step-display", stepText)
+	checkedLabel := g.dom.QuerySelector(".player-list input:checked+label")
+	if checkedLabel.IsNull() {
+		return
+	}
+	g.viewFinalBoard()
+}
+
+// replayStep gets the number of events of the replay that should be applied to the boards.
+func (g *Game) replayStep() (int, error) {
+	stepStr := g.dom.Value(".final-boards .replay .replay-step")
+	step, err := strconv.Atoi(stepStr)
+	if err != nil {
+		return 0, errors.New("retrieving replay step: " + err.Error())
+	}
+	return step, nil
+}
+
+// replayBoard recreates the board of the player at the selected step of the replay.
+func (g *Game) replayBoard(pn player.Name) (*board.Board, error) {
+	step, err := g.replayStep()
+	if err != nil {
+		return nil, err
+	}
+	boards, err := g.replay.Boards(step)
+	if err != nil {
+		return nil, err
+	}
+	b, ok := boards[pn]
+	if !ok {
+		b = board.New(nil, nil)
+	}
+	if b.NumCols == 0 || b.NumRows == 0 {
+		b.Config = g.finalBoards[string(pn)].Config
+	}
+	return b, nil
+}
@@ -13,6 +13,8 @@ import (
 	"github.com/jacobpatterson1549/selene-bananas/game"
 	"github.com/jacobpatterson1549/selene-bananas/game/board"
 	"github.com/jacobpatterson1549/selene-bananas/game/message"
+	"github.com/jacobpatterson1549/selene-bananas/game/player"
+	"github.com/jacobpatterson1549/selene-bananas/game/replay"
 	"github.com/jacobpatterson1549/selene-bananas/game/tile"
 )
 
@@ -40,16 +42,27 @@ func TestInitDom(t *testing.T) {
 		"create",
 		"createWithConfig",
 		"join",
+		"joinWithInviteCode",
+		"spectate",
 		"leave",
+		"forfeit",
 		"delete",
 		"start",
+		"toggleReady",
 		"finish",
 		"snagTile",
 		"swapTile",
+		"addBot",
+		"kick",
+		"requestHint",
+		"challenge",
+		"acceptWin",
 		"sendChat",
 		"resizeTiles",
 		"refreshTileLength",
 		"viewFinalBoard",
+		"requestReplay",
+		"viewReplayStep",
 	}
 	functionsRegistered := false
 	g := Game{
@@ -106,20 +119,180 @@ func TestStartCreate(t *testing.T) {
 
 func TestCreateWithConfig(t *testing.T) {
 	tests := []struct {
-		MinLength string
-		wantErr   bool
-		numRows   int
-		numCols   int
+		MinLength    string
+		Scoring      string
+		TimeLimit    string
+		TileBag      string
+		LetterCounts string
+		NumNewTiles  string
+		SwapRatio    string
+		SwapLimit    string
+		SwapCooldown string
+		TeamSize     string
+		ChallengeSec string
+		wantErr      bool
+		numRows      int
+		numCols      int
 	}{
 		{
-			MinLength: "NaN",
-			wantErr:   true,
+			MinLength:    "NaN",
+			Scoring:      "0",
+			TimeLimit:    "0",
+			TileBag:      "0",
+			NumNewTiles:  "0",
+			SwapRatio:    "3",
+			SwapLimit:    "0",
+			SwapCooldown: "0",
+			TeamSize:     "0",
+			ChallengeSec: "0",
+			wantErr:      true,
+		},
+		{
+			MinLength:    "5",
+			Scoring:      "NaN",
+			TimeLimit:    "0",
+			TileBag:      "0",
+			NumNewTiles:  "0",
+			SwapRatio:    "3",
+			SwapLimit:    "0",
+			SwapCooldown: "0",
+			TeamSize:     "0",
+			ChallengeSec: "0",
+			wantErr:      true,
+		},
+		{
+			MinLength:    "5",
+			Scoring:      "0",
+			TimeLimit:    "NaN",
+			TileBag:      "0",
+			NumNewTiles:  "0",
+			SwapRatio:    "3",
+			SwapLimit:    "0",
+			SwapCooldown: "0",
+			TeamSize:     "0",
+			ChallengeSec: "0",
+			wantErr:      true,
+		},
+		{
+			MinLength:    "5",
+			Scoring:      "0",
+			TimeLimit:    "0",
+			TileBag:      "NaN",
+			NumNewTiles:  "0",
+			SwapRatio:    "3",
+			SwapLimit:    "0",
+			SwapCooldown: "0",
+			TeamSize:     "0",
+			ChallengeSec: "0",
+			wantErr:      true,
+		},
+		{
+			MinLength:    "5",
+			Scoring:      "0",
+			TimeLimit:    "0",
+			TileBag:      "3",
+			LetterCounts: "A13",
+			NumNewTiles:  "0",
+			SwapRatio:    "3",
+			SwapLimit:    "0",
+			SwapCooldown: "0",
+			TeamSize:     "0",
+			ChallengeSec: "0",
+			wantErr:      true,
+		},
+		{
+			MinLength:    "5",
+			Scoring:      "0",
+			TimeLimit:    "0",
+			TileBag:      "0",
+			NumNewTiles:  "NaN",
+			SwapRatio:    "3",
+			SwapLimit:    "0",
+			SwapCooldown: "0",
+			TeamSize:     "0",
+			ChallengeSec: "0",
+			wantErr:      true,
+		},
+		{
+			MinLength:    "5",
+			Scoring:      "0",
+			TimeLimit:    "0",
+			TileBag:      "0",
+			NumNewTiles:  "0",
+			SwapRatio:    "3",
+			SwapLimit:    "0",
+			SwapCooldown: "0",
+			TeamSize:     "NaN",
+			ChallengeSec: "0",
+			wantErr:      true,
+		},
+		{
+			MinLength:    "5",
+			Scoring:      "0",
+			TimeLimit:    "0",
+			TileBag:      "0",
+			NumNewTiles:  "0",
+			SwapRatio:    "NaN",
+			SwapLimit:    "0",
+			SwapCooldown: "0",
+			TeamSize:     "0",
+			ChallengeSec: "0",
+			wantErr:      true,
+		},
+		{
+			MinLength:    "5",
+			Scoring:      "0",
+			TimeLimit:    "0",
+			TileBag:      "0",
+			NumNewTiles:  "0",
+			SwapRatio:    "3",
+			SwapLimit:    "NaN",
+			SwapCooldown: "0",
+			TeamSize:     "0",
+			ChallengeSec: "0",
+			wantErr:      true,
+		},
+		{
+			MinLength:    "5",
+			Scoring:      "0",
+			TimeLimit:    "0",
+			TileBag:      "0",
+			NumNewTiles:  "0",
+			SwapRatio:    "3",
+			SwapLimit:    "0",
+			SwapCooldown: "NaN",
+			TeamSize:     "0",
+			ChallengeSec: "0",
+			wantErr:      true,
+		},
+		{
+			MinLength:    "5",
+			Scoring:      "0",
+			TimeLimit:    "0",
+			TileBag:      "0",
+			NumNewTiles:  "0",
+			SwapRatio:    "3",
+			SwapLimit:    "0",
+			SwapCooldown: "0",
+			TeamSize:     "0",
+			ChallengeSec: "NaN",
+			wantErr:      true,
 		},
 		{
-			MinLength: "5",
-			numRows:   0,
-			numCols:   0,
-			wantErr:   true,
+			MinLength:    "5",
+			Scoring:      "2",
+			TimeLimit:    "5",
+			TileBag:      "3",
+			LetterCounts: "A:13 B:3",
+			NumNewTiles:  "15",
+			SwapRatio:    "3",
+			SwapLimit:    "0",
+			SwapCooldown: "0",
+			TeamSize:     "2",
+			ChallengeSec: "0",
+			numRows:      0,
+			numCols:      0,
+			wantErr:      true,
 		},
 	}
 	for i, test := range tests {
@@ -136,6 +309,30 @@ func TestCreateWithConfig(t *testing.T) {
 					// NOOP
 				},
 				ValueFunc: func(query string) string {
+					switch query {
+					case ".scoring":
+						return test.Scoring
+					case ".timeLimit":
+						return test.TimeLimit
+					case ".tileBag":
+						return test.TileBag
+					case ".letterCounts":
+						return test.LetterCounts
+					case ".numNewTiles":
+						return test.NumNewTiles
+					case ".swapRatio":
+						return test.SwapRatio
+					case ".swapLimit":
+						return test.SwapLimit
+					case ".swapCooldown":
+						return test.SwapCooldown
+					case ".teamSize":
+						return test.TeamSize
+					case ".challengeSec":
+						return test.ChallengeSec
+					case ".language":
+						return "es"
+					}
 					return test.MinLength
 				},
 			},
@@ -169,6 +366,40 @@ func TestCreateWithConfig(t *testing.T) {
 	}
 }
 
+func TestParseLetterCounts(t *testing.T) {
+	parseLetterCountsTests := []struct {
+		text             string
+		wantOk           bool
+		wantLetterCounts map[string]int
+	}{
+		{},
+		{
+			text: "A13",
+		},
+		{
+			text: "A:many",
+		},
+		{
+			text:             "A:13 B:3,c:2\nÑ:1",
+			wantOk:           true,
+			wantLetterCounts: map[string]int{"A": 13, "B": 3, "C": 2, "Ñ": 1},
+		},
+	}
+	for i, test := range parseLetterCountsTests {
+		got, err := parseLetterCounts(test.text)
+		switch {
+		case !test.wantOk:
+			if err == nil {
+				t.Errorf("Test %v: wanted error", i)
+			}
+		case err != nil:
+			t.Errorf("Test %v: unwanted error: %v", i, err)
+		case !reflect.DeepEqual(test.wantLetterCounts, got):
+			t.Errorf("Test %v: letter counts not equal:\nwanted: %v\ngot:    %v", i, test.wantLetterCounts, got)
+		}
+	}
+}
+
 func TestJoin(t *testing.T) {
 	tests := []struct {
 		gameID          string
@@ -246,6 +477,168 @@ func TestJoin(t *testing.T) {
 	}
 }
 
+func TestJoinWithInviteCode(t *testing.T) {
+	tests := []struct {
+		gameID          string
+		wantErr         bool
+		wantGameID      game.ID
+		wantMessageSent bool
+	}{
+		{
+			gameID:  "NaN",
+			wantErr: true,
+		},
+		{
+			gameID:          "7",
+			wantGameID:      7,
+			wantMessageSent: true,
+		},
+	}
+	for i, test := range tests {
+		errorLogged := false
+		messageSent := false
+		form := js.ValueOf(map[string]any{
+			"method": "get",
+			"action": "https://example.com/",
+		})
+		g := Game{
+			board: &board.Board{},
+			dom: &mockDOM{
+				SetCheckedFunc: func(query string, checked bool) {
+					// NOOP
+				},
+				QuerySelectorAllFunc: func(document js.Value, query string) []js.Value {
+					return []js.Value{
+						js.ValueOf(map[string]any{
+							"name":  "game-id",
+							"value": test.gameID,
+						}),
+						js.ValueOf(map[string]any{
+							"name":  "invite-code",
+							"value": "b4n4n4",
+						}),
+					}
+				},
+			},
+			log: &mockLog{
+				ErrorFunc: func(text string) {
+					errorLogged = true
+				},
+			},
+			canvas: &mockCanvas{
+				ParentDivOffsetWidthFunc: func() int {
+					return 120
+				},
+				UpdateSizeFunc: func(width int) {
+					// NOOP
+				},
+				NumColsFunc: func() int {
+					return 15
+				},
+				NumRowsFunc: func() int {
+					return 15
+				},
+			},
+			Socket: &mockSocket{
+				SendFunc: func(m message.Message) {
+					switch {
+					case m.Type != message.JoinGame:
+						t.Errorf("Test %v: join message types not equal: wanted %v, got %v", i, message.JoinGame, m.Type)
+					case m.Game.InviteCode != "b4n4n4":
+						t.Errorf("Test %v: wanted invite code to be sent, got %q", i, m.Game.InviteCode)
+					case m.Game.Board == nil:
+						t.Errorf("Test %v: wanted board config to be sent", i)
+					}
+					messageSent = true
+				},
+			},
+		}
+		event := js.ValueOf(map[string]any{
+			"target": form,
+		})
+		g.joinWithInviteCode(event)
+		if want, got := test.wantErr, errorLogged; want != got {
+			t.Errorf("Test %v: error logged not equal: wanted %v, got %v", i, want, got)
+		}
+		if want, got := test.wantMessageSent, messageSent; want != got {
+			t.Errorf("Test %v: messagedSent not equal: wanted %v, got %v", i, want, got)
+		}
+		if want, got := test.wantGameID, g.id; want != got {
+			t.Errorf("Test %v: wanted gameID to be set to %v, got %v", i, want, got)
+		}
+	}
+}
+
+func TestSpectate(t *testing.T) {
+	tests := []struct {
+		gameID          string
+		wantErr         bool
+		wantGameID      game.ID
+		wantMessageSent bool
+	}{
+		{
+			gameID:  "NaN",
+			wantErr: true,
+		},
+		{
+			gameID:          "7",
+			wantGameID:      7,
+			wantMessageSent: true,
+		},
+	}
+	for i, test := range tests {
+		errorLogged := false
+		messageSent := false
+		spectateTabShown := false
+		g := Game{
+			dom: &mockDOM{
+				SetCheckedFunc: func(query string, checked bool) {
+					if query == "#tab-spectate" && checked {
+						spectateTabShown = true
+					}
+				},
+			},
+			log: &mockLog{
+				ErrorFunc: func(text string) {
+					errorLogged = true
+				},
+			},
+			Socket: &mockSocket{
+				SendFunc: func(m message.Message) {
+					if want, got := message.SpectateGame, m.Type; want != got {
+						t.Errorf("Test %v: spectate message types not equal: wanted %v, got %v", i, want, got)
+					}
+					messageSent = true
+				},
+			},
+		}
+		event := js.ValueOf(map[string]any{
+			"srcElement": map[string]any{ // spectateGameButton
+				"parentElement": map[string]any{
+					"children": []any{
+						map[string]any{ // gameIDInput
+							"value": test.gameID,
+						},
+					},
+				},
+			},
+		})
+		g.spectate(event)
+		if want, got := test.wantErr, errorLogged; want != got {
+			t.Errorf("Test %v: error logged not equal: wanted %v, got %v", i, want, got)
+		}
+		if want, got := test.wantMessageSent, messageSent; want != got {
+			t.Errorf("Test %v: messagedSent not equal: wanted %v, got %v", i, want, got)
+		}
+		if want, got := test.wantMessageSent, spectateTabShown; want != got {
+			t.Errorf("Test %v: spectate tab shown not equal: wanted %v, got %v", i, want, got)
+		}
+		if want, got := test.wantGameID, g.id; want != got {
+			t.Errorf("Test %v: wanted gameID to be set to %v, got %v", i, want, got)
+		}
+	}
+}
+
 func TestHide(t *testing.T) {
 	tests := []bool{true, false}
 	for i, want := range tests {
@@ -309,7 +702,10 @@ func TestSendLeave(t *testing.T) {
 func TestLeave(t *testing.T) {
 	setCheckedCallCount := 0
 	g := Game{
-		id: 1,
+		id:         1,
+		inviteCode: "b4n4n4",
+		status:     game.InProgress,
+		isHost:     true,
 		dom: &mockDOM{
 			QuerySelectorFunc: func(query string) js.Value {
 				return js.ValueOf(map[string]any{})
@@ -326,8 +722,12 @@ func TestLeave(t *testing.T) {
 	switch {
 	case g.id != 0:
 		t.Errorf("wanted game id to be set to 0, got %v", g.id)
-	case setCheckedCallCount != 3:
-		t.Errorf("wanted setChecked to be called 3 times, got %v", setCheckedCallCount)
+	case len(g.inviteCode) != 0:
+		t.Errorf("wanted invite code to be cleared, got %q", g.inviteCode)
+	case g.status != 0, g.isHost:
+		t.Errorf("wanted status and host to be cleared")
+	case setCheckedCallCount != 5:
+		t.Errorf("wanted setChecked to be called 5 times, got %v", setCheckedCallCount)
 	}
 }
 
@@ -362,6 +762,442 @@ func TestDelete(t *testing.T) {
 	}
 }
 
+func TestForfeit(t *testing.T) {
+	tests := []bool{true, false}
+	for i, want := range tests {
+		confirmCalled := false
+		messageSent := false
+		g := Game{
+			dom: &mockDOM{
+				ConfirmFunc: func(message string) bool {
+					confirmCalled = true
+					return want
+				},
+			},
+			Socket: &mockSocket{
+				SendFunc: func(m message.Message) {
+					if want, got := message.Forfeit, m.Type; want != got {
+						t.Errorf("forfeit message types not equal: wanted %v, got %v", want, got)
+					}
+					messageSent = true
+				},
+			},
+		}
+		g.forfeit()
+		if !confirmCalled {
+			t.Errorf("Test %v: wanted confirm to be called", i)
+		}
+		if want, got := want, messageSent; want != got {
+			t.Errorf("Test %v: wanted forfeit message to be sent: %v, got %v", i, want, got)
+		}
+	}
+}
+
+func TestKick(t *testing.T) {
+	tests := []struct {
+		playerName string
+		confirm    bool
+		wantSent   bool
+	}{
+		{}, // no player selected
+		{
+			playerName: "barney",
+		},
+		{
+			playerName: "barney",
+			confirm:    true,
+			wantSent:   true,
+		},
+	}
+	for i, test := range tests {
+		messageSent := false
+		g := Game{
+			dom: &mockDOM{
+				ValueFunc: func(query string) string {
+					return test.playerName
+				},
+				ConfirmFunc: func(message string) bool {
+					if !strings.Contains(message, test.playerName) {
+						t.Errorf("Test %v: wanted confirm message to contain name of player to kick, got %q", i, message)
+					}
+					return test.confirm
+				},
+			},
+			Socket: &mockSocket{
+				SendFunc: func(m message.Message) {
+					switch {
+					case m.Type != message.KickPlayer:
+						t.Errorf("Test %v: kick message types not equal: wanted %v, got %v", i, message.KickPlayer, m.Type)
+					case !reflect.DeepEqual([]string{test.playerName}, m.Game.Players):
+						t.Errorf("Test %v: wanted %v to be kicked, got %v", i, test.playerName, m.Game.Players)
+					}
+					messageSent = true
+				},
+			},
+		}
+		g.kick()
+		if want, got := test.wantSent, messageSent; want != got {
+			t.Errorf("Test %v: wanted kick message to be sent: %v, got %v", i, want, got)
+		}
+	}
+}
+
+func TestAddBot(t *testing.T) {
+	tests := []struct {
+		difficulty string
+		want       player.Difficulty
+		wantOk     bool
+	}{
+		{
+			difficulty: "hard",
+		},
+		{
+			difficulty: "2",
+			want:       player.Hard,
+			wantOk:     true,
+		},
+	}
+	for i, test := range tests {
+		messageSent := false
+		g := Game{
+			inviteCode: "b4n4n4",
+			log: &mockLog{
+				ErrorFunc: func(text string) {
+					if test.wantOk {
+						t.Errorf("Test %v: unwanted error: %v", i, text)
+					}
+				},
+			},
+			dom: &mockDOM{
+				ValueFunc: func(query string) string {
+					return test.difficulty
+				},
+			},
+			Socket: &mockSocket{
+				SendFunc: func(m message.Message) {
+					switch {
+					case m.Type != message.AddBot:
+						t.Errorf("Test %v: add bot message types not equal: wanted %v, got %v", i, message.AddBot, m.Type)
+					case test.want != m.Game.BotDifficulty:
+						t.Errorf("Test %v: bot difficulties not equal: wanted %v, got %v", i, test.want, m.Game.BotDifficulty)
+					case m.Game.InviteCode != "b4n4n4":
+						t.Errorf("Test %v: wanted invite code of private game to be sent for bot, got %q", i, m.Game.InviteCode)
+					}
+					messageSent = true
+				},
+			},
+		}
+		g.addBot()
+		if want, got := test.wantOk, messageSent; want != got {
+			t.Errorf("Test %v: wanted add bot message to be sent: %v, got %v", i, want, got)
+		}
+	}
+}
+
+func TestRequestHint(t *testing.T) {
+	messageSent := false
+	g := Game{
+		Socket: &mockSocket{
+			SendFunc: func(m message.Message) {
+				if want, got := message.Hint, m.Type; want != got {
+					t.Errorf("hint message types not equal: wanted %v, got %v", want, got)
+				}
+				messageSent = true
+			},
+		},
+	}
+	g.requestHint()
+	if !messageSent {
+		t.Error("wanted hint message to be sent")
+	}
+}
+
+func TestChallenge(t *testing.T) {
+	tests := []struct {
+		words     string
+		wantWords []string
+	}{
+		{},
+		{
+			words:     " cat  Dog ",
+			wantWords: []string{"CAT", "DOG"},
+		},
+	}
+	for i, test := range tests {
+		var gotMessage *message.Message
+		errorLogged := false
+		g := Game{
+			dom: &mockDOM{
+				ValueFunc: func(query string) string {
+					return test.words
+				},
+				SetValueFunc: func(query, value string) {
+					if len(value) != 0 {
+						t.Errorf("Test %v: wanted challenge words to be cleared, got %q", i, value)
+					}
+				},
+			},
+			log: &mockLog{
+				ErrorFunc: func(text string) {
+					errorLogged = true
+				},
+			},
+			Socket: &mockSocket{
+				SendFunc: func(m message.Message) {
+					gotMessage = &m
+				},
+			},
+		}
+		g.challenge()
+		switch {
+		case test.wantWords == nil:
+			if !errorLogged || gotMessage != nil {
+				t.Errorf("Test %v: wanted error logged and no message sent when no words are challenged", i)
+			}
+		case gotMessage == nil, gotMessage.Type != message.ChallengeWords:
+			t.Errorf("Test %v: wanted challenge message to be sent, got %v", i, gotMessage)
+		case !reflect.DeepEqual(test.wantWords, gotMessage.Game.ChallengedWords):
+			t.Errorf("Test %v: challenged words not equal:\nwanted: %v\ngot:    %v", i, test.wantWords, gotMessage.Game.ChallengedWords)
+		}
+	}
+}
+
+func TestAcceptWin(t *testing.T) {
+	messageSent := false
+	g := Game{
+		dom: &mockDOM{
+			SetValueFunc: func(query, value string) {
+				// NOOP
+			},
+		},
+		Socket: &mockSocket{
+			SendFunc: func(m message.Message) {
+				if want, got := message.ChallengeWords, m.Type; want != got {
+					t.Errorf("accept message types not equal: wanted %v, got %v", want, got)
+				}
+				if len(m.Game.ChallengedWords) != 0 {
+					t.Errorf("wanted no words to be challenged when accepting the win, got %v", m.Game.ChallengedWords)
+				}
+				messageSent = true
+			},
+		},
+	}
+	g.acceptWin()
+	if !messageSent {
+		t.Error("wanted accept message to be sent")
+	}
+}
+
+func TestUpdateChallenge(t *testing.T) {
+	tests := []struct {
+		status       game.Status
+		claimant     string
+		forfeited    bool
+		wantCalled   bool
+		wantDisabled bool
+	}{
+		{ // status not changed
+		},
+		{
+			status:       game.InProgress,
+			wantCalled:   true,
+			wantDisabled: true,
+		},
+		{
+			status:     game.Challenging,
+			claimant:   "fred",
+			wantCalled: true,
+		},
+		{
+			status:       game.Challenging,
+			claimant:     "selene",
+			wantCalled:   true,
+			wantDisabled: true,
+		},
+		{
+			status:       game.Challenging,
+			claimant:     "fred",
+			forfeited:    true,
+			wantCalled:   true,
+			wantDisabled: true,
+		},
+	}
+	for i, test := range tests {
+		called := false
+		g := Game{
+			forfeited: test.forfeited,
+			dom: &mockDOM{
+				SetButtonDisabledFunc: func(query string, disabled bool) {
+					called = true
+					if want, got := test.wantDisabled, disabled; want != got {
+						t.Errorf("Test %v: %v button not disabled correctly: wanted %v, got %v", i, query, want, got)
+					}
+				},
+			},
+		}
+		m := message.Message{
+			Game: &game.Info{
+				Status:   test.status,
+				Claimant: test.claimant,
+			},
+		}
+		g.updateChallenge(m, "selene")
+		if want, got := test.wantCalled, called; want != got {
+			t.Errorf("Test %v: wanted challenge buttons to be updated: %v, got %v", i, want, got)
+		}
+	}
+}
+
+func TestShowHint(t *testing.T) {
+	tp := tile.Position{
+		Tile: tile.Tile{
+			ID: 7,
+			Ch: 'X',
+		},
+		X: 3,
+		Y: 4,
+	}
+	var gotPositions []tile.Position
+	redrawn := false
+	g := Game{
+		canvas: &mockCanvas{
+			SetHintFunc: func(positions []tile.Position) {
+				gotPositions = positions
+			},
+			RedrawFunc: func() {
+				redrawn = true
+			},
+		},
+	}
+	m := message.Message{
+		Type: message.Hint,
+		Game: &game.Info{
+			Board: board.New(nil, []tile.Position{tp}),
+		},
+	}
+	g.ShowHint(m)
+	want := []tile.Position{tp}
+	switch {
+	case !reflect.DeepEqual(want, gotPositions):
+		t.Errorf("hint positions not equal:\nwanted: %v\ngot:    %v", want, gotPositions)
+	case !redrawn:
+		t.Errorf("wanted canvas to be redrawn after hint is set")
+	}
+}
+
+func TestShowReport(t *testing.T) {
+	r := board.Report{
+		Words: []board.WordReport{
+			{Word: "XYZ", X: 1, Y: 2},
+		},
+	}
+	var gotReport *board.Report
+	redrawn := false
+	g := Game{
+		canvas: &mockCanvas{
+			SetReportFunc: func(r *board.Report) {
+				gotReport = r
+			},
+			RedrawFunc: func() {
+				redrawn = true
+			},
+		},
+	}
+	m := message.Message{
+		Type: message.BoardReport,
+		Game: &game.Info{
+			Report: &r,
+		},
+	}
+	g.ShowReport(m)
+	switch {
+	case !reflect.DeepEqual(&r, gotReport):
+		t.Errorf("reports not equal:\nwanted: %v\ngot:    %v", r, gotReport)
+	case !redrawn:
+		t.Errorf("wanted canvas to be redrawn after report is set")
+	}
+}
+
+func TestHandleWarning(t *testing.T) {
+	tests := []struct {
+		message.Message
+		wantDisabledButtons []string
+		wantReport          *board.Report
+	}{
+		{ // no code
+		},
+		{
+			Message: message.Message{
+				Code: message.CodeNoTilesLeft,
+			},
+			wantDisabledButtons: []string{".game .actions>.snag", ".game .actions>.swap"},
+		},
+		{
+			Message: message.Message{
+				Code: message.CodeHintsNotAllowed,
+			},
+			wantDisabledButtons: []string{".game .actions>.hint"},
+		},
+		{
+			Message: message.Message{
+				Code: message.CodeSwapLimit,
+			},
+			wantDisabledButtons: []string{".game .actions>.swap"},
+		},
+		{
+			Message: message.Message{
+				Code: message.CodeBananasRace,
+			},
+			wantDisabledButtons: []string{".game .actions>.snag"},
+		},
+		{ // no details
+			Message: message.Message{
+				Code: message.CodeMultipleGroups,
+			},
+		},
+		{
+			Message: message.Message{
+				Code: message.CodeMultipleGroups,
+				Details: &message.Details{
+					TileIDs: []tile.ID{4, 7},
+				},
+			},
+			wantReport: &board.Report{
+				Groups: [][]tile.ID{nil, {4, 7}},
+			},
+		},
+	}
+	for i, test := range tests {
+		var gotDisabledButtons []string
+		var gotReport *board.Report
+		g := Game{
+			dom: &mockDOM{
+				SetButtonDisabledFunc: func(query string, disabled bool) {
+					if disabled {
+						gotDisabledButtons = append(gotDisabledButtons, query)
+					}
+				},
+			},
+			canvas: &mockCanvas{
+				SetReportFunc: func(r *board.Report) {
+					gotReport = r
+				},
+				RedrawFunc: func() {
+					// NOOP
+				},
+			},
+		}
+		test.Message.Type = message.SocketWarning
+		g.HandleWarning(test.Message)
+		switch {
+		case !reflect.DeepEqual(test.wantDisabledButtons, gotDisabledButtons):
+			t.Errorf("Test %v: disabled buttons not equal:\nwanted: %v\ngot:    %v", i, test.wantDisabledButtons, gotDisabledButtons)
+		case !reflect.DeepEqual(test.wantReport, gotReport):
+			t.Errorf("Test %v: reports not equal:\nwanted: %v\ngot:    %v", i, test.wantReport, gotReport)
+		}
+	}
+}
+
 func TestStart(t *testing.T) {
 	messageSent := false
 	g := Game{
@@ -383,6 +1219,24 @@ func TestStart(t *testing.T) {
 	}
 }
 
+func TestToggleReady(t *testing.T) {
+	messageSent := false
+	g := Game{
+		Socket: &mockSocket{
+			SendFunc: func(m message.Message) {
+				if want, got := message.ToggleReady, m.Type; want != got {
+					t.Errorf("toggle ready message types not equal: wanted %v, got %v", want, got)
+				}
+				messageSent = true
+			},
+		},
+	}
+	g.toggleReady()
+	if !messageSent {
+		t.Error("wanted toggle ready message to be sent")
+	}
+}
+
 func TestFinish(t *testing.T) {
 	messageSent := false
 	g := Game{
@@ -521,6 +1375,7 @@ func TestReplaceGameTiles(t *testing.T) {
 				UnusedTileIDs: []tile.ID{5},
 				UsedTiles:     map[tile.ID]tile.Position{6: {Tile: tile.Tile{ID: 6}, X: 7, Y: 8}},
 				UsedTileLocs:  map[tile.X]map[tile.Y]tile.Tile{7: {8: {ID: 6}}},
+				Revision:      9,
 			},
 		},
 	}
@@ -531,12 +1386,99 @@ func TestReplaceGameTiles(t *testing.T) {
 		UnusedTileIDs: []tile.ID{5},
 		UsedTiles:     map[tile.ID]tile.Position{6: {Tile: tile.Tile{ID: 6}, X: 7, Y: 8}},
 		UsedTileLocs:  map[tile.X]map[tile.Y]tile.Tile{7: {8: {ID: 6}}},
+		Revision:      9,
 	}
 	if !reflect.DeepEqual(want, got) {
 		t.Errorf("boards not equal:\nwanted: %v\ngot:    %v", want, got)
 	}
 }
 
+func TestApplyDiff(t *testing.T) {
+	tests := []struct {
+		board.Diff
+		wantRevision int
+		wantInfo     bool
+		wantRefresh  bool
+	}{
+		{ // move
+			Diff: board.Diff{
+				Revision: 4,
+				Changes: []board.Change{
+					{Op: board.OpMove, Position: tile.Position{Tile: tile.Tile{ID: 1}, X: 2, Y: 3}},
+				},
+			},
+			wantRevision: 5,
+		},
+		{ // add
+			Diff: board.Diff{
+				Revision: 4,
+				Changes: []board.Change{
+					{Op: board.OpAdd, Position: tile.Position{Tile: tile.Tile{ID: 2, Ch: 'B'}}},
+				},
+			},
+			wantRevision: 5,
+			wantInfo:     true,
+		},
+		{ // stale revision
+			Diff: board.Diff{
+				Revision: 3,
+				Changes: []board.Change{
+					{Op: board.OpAdd, Position: tile.Position{Tile: tile.Tile{ID: 2, Ch: 'B'}}},
+				},
+			},
+			wantRefresh: true,
+		},
+	}
+	for i, test := range tests {
+		infoLogged := false
+		var refresh *message.Message
+		b := board.New([]tile.Tile{{ID: 1, Ch: 'A'}}, nil)
+		b.Config = board.Config{NumRows: 10, NumCols: 10}
+		b.Revision = 4
+		g := Game{
+			board: b,
+			log: &mockLog{
+				InfoFunc: func(text string) {
+					infoLogged = true
+				},
+			},
+			canvas: &mockCanvas{
+				NumRowsFunc: func() int {
+					return 12
+				},
+				NumColsFunc: func() int {
+					return 15
+				},
+			},
+			Socket: &mockSocket{
+				SendFunc: func(m message.Message) {
+					refresh = &m
+				},
+			},
+		}
+		m := message.Message{
+			Type: message.ChangeGameTiles,
+			Game: &game.Info{
+				Diff: &test.Diff,
+			},
+		}
+		g.applyDiff(m)
+		switch {
+		case test.wantRefresh:
+			wantCfg := board.Config{NumRows: 12, NumCols: 15}
+			if refresh == nil || refresh.Type != message.RefreshGameBoard || refresh.Game.Board.Config != wantCfg {
+				t.Errorf("Test %v: wanted whole board to be requested, got %v", i, refresh)
+			}
+		case refresh != nil:
+			t.Errorf("Test %v: unwanted message: %v", i, refresh)
+		case test.wantRevision != g.board.Revision:
+			t.Errorf("Test %v: revisions not equal: wanted %v, got %v", i, test.wantRevision, g.board.Revision)
+		case test.wantInfo != infoLogged:
+			t.Errorf("Test %v: wanted info about added tiles: %v, got %v", i, test.wantInfo, infoLogged)
+		}
+	}
+}
+
 func TestAddUnusedTiles(t *testing.T) {
 	tests := []struct {
 		messageBoard         board.Board
@@ -688,11 +1630,24 @@ func TestUpdateInfo(t *testing.T) {
 			},
 			wantGameID: 6,
 		},
-		{ // addUnusedTiles
+		{ // replaceGameTiles with only unused tiles
 			m: message.Message{
 				Game: &game.Info{
 					Board: &board.Board{
-						UnusedTiles: map[tile.ID]tile.Tile{2: {}},
+						UnusedTiles:   map[tile.ID]tile.Tile{2: {ID: 2}},
+						UnusedTileIDs: []tile.ID{2},
+					},
+				},
+			},
+			wantGameID: 6,
+		},
+		{ // applyDiff
+			m: message.Message{
+				Game: &game.Info{
+					Diff: &board.Diff{
+						Changes: []board.Change{
+							{Op: board.OpAdd, Position: tile.Position{Tile: tile.Tile{ID: 3}}},
+						},
 					},
 				},
 			},
@@ -756,7 +1711,7 @@ func TestUpdateInfo(t *testing.T) {
 				},
 			},
 		}
-		g.UpdateInfo(test.m)
+		g.UpdateInfo(test.m, "fred")
 		appendChild.Release()
 		if !canvasRedrawn {
 			t.Errorf("Test %v: wanted canvas to be redrawn", i)
@@ -767,26 +1722,87 @@ func TestUpdateInfo(t *testing.T) {
 	}
 }
 
+func TestSetInviteLink(t *testing.T) {
+	tests := []struct {
+		inviteCode string
+		want       string
+	}{
+		{},
+		{
+			inviteCode: "b4n4n4 split",
+			want:       "https://example.com/?game=7&inviteCode=b4n4n4%20split",
+		},
+	}
+	for i, test := range tests {
+		var got string
+		g := Game{
+			id:         7,
+			inviteCode: test.inviteCode,
+			dom: &mockDOM{
+				PageURLFunc: func() string {
+					return "https://example.com/"
+				},
+				EncodeURIComponentFunc: func(str string) string {
+					return strings.ReplaceAll(str, " ", "%20")
+				},
+				SetValueFunc: func(query, value string) {
+					if want, got := ".game>.info .invite-link", query; want != got {
+						t.Errorf("Test %v: queries not equal: wanted %v, got %v", i, want, got)
+					}
+					got = value
+				},
+			},
+		}
+		g.setInviteLink()
+		if test.want != got {
+			t.Errorf("Test %v: invite links not equal:\nwanted: %v\ngot:    %v", i, test.want, got)
+		}
+	}
+}
+
 func TestUpdateStatus(t *testing.T) {
 	tests := []struct {
-		s                        game.Status
-		gameTilesLeft            int
-		wantStatusText           string
-		wantSnagButtonDisabled   bool
-		wantSwapButtonDisabled   bool
-		wantStartButtonDisabled  bool
-		wantFinishButtonDisabled bool
+		s                         game.Status
+		gameTilesLeft             int
+		bananasRace               bool
+		wantStatusText            string
+		wantSnagButtonDisabled    bool
+		wantSwapButtonDisabled    bool
+		wantStartButtonDisabled   bool
+		wantReadyButtonDisabled   bool
+		wantFinishButtonDisabled  bool
+		wantAddBotButtonDisabled  bool
+		wantHintButtonDisabled    bool
+		wantForfeitButtonDisabled bool
+		wantCanvasStatus          game.Status
+		notHost                   bool
+		forfeited                 bool
 	}{
 		{
 			s: game.Deleted, // do not set status
 		},
 		{
-			s:                        game.NotStarted,
-			wantStatusText:           "Not Started",
-			wantSnagButtonDisabled:   true,
-			wantSwapButtonDisabled:   true,
-			wantStartButtonDisabled:  false,
-			wantFinishButtonDisabled: true,
+			s:                         game.NotStarted,
+			notHost:                   true,
+			wantStatusText:            "Not Started",
+			wantSnagButtonDisabled:    true,
+			wantSwapButtonDisabled:    true,
+			wantStartButtonDisabled:   true,
+			wantFinishButtonDisabled:  true,
+			wantAddBotButtonDisabled:  false,
+			wantHintButtonDisabled:    true,
+			wantForfeitButtonDisabled: true,
+		},
+		{
+			s:                         game.NotStarted,
+			wantStatusText:            "Not Started",
+			wantSnagButtonDisabled:    true,
+			wantSwapButtonDisabled:    true,
+			wantStartButtonDisabled:   false,
+			wantFinishButtonDisabled:  true,
+			wantAddBotButtonDisabled:  false,
+			wantHintButtonDisabled:    true,
+			wantForfeitButtonDisabled: true,
 		},
 		{
 			s:                        game.InProgress,
@@ -794,7 +1810,9 @@ func TestUpdateStatus(t *testing.T) {
 			wantSnagButtonDisabled:   false,
 			wantSwapButtonDisabled:   false,
 			wantStartButtonDisabled:  true,
+			wantReadyButtonDisabled:  true,
 			wantFinishButtonDisabled: false,
+			wantAddBotButtonDisabled: true,
 		},
 		{
 			s:                        game.InProgress,
@@ -803,21 +1821,67 @@ func TestUpdateStatus(t *testing.T) {
 			wantSnagButtonDisabled:   false,
 			wantSwapButtonDisabled:   false,
 			wantStartButtonDisabled:  true,
+			wantReadyButtonDisabled:  true,
 			wantFinishButtonDisabled: true,
+			wantAddBotButtonDisabled: true,
 		},
 		{
-			s:                        game.Finished,
-			wantStatusText:           "Finished",
+			s:                        game.InProgress,
+			gameTilesLeft:            1,
+			bananasRace:              true,
+			wantStatusText:           "In Progress",
 			wantSnagButtonDisabled:   true,
-			wantSwapButtonDisabled:   true,
+			wantSwapButtonDisabled:   false,
 			wantStartButtonDisabled:  true,
-			wantFinishButtonDisabled: true,
+			wantReadyButtonDisabled:  true,
+			wantFinishButtonDisabled: false,
+			wantAddBotButtonDisabled: true,
+		},
+		{
+			s:                         game.InProgress,
+			forfeited:                 true,
+			wantStatusText:            "In Progress",
+			wantSnagButtonDisabled:    true,
+			wantSwapButtonDisabled:    true,
+			wantStartButtonDisabled:   true,
+			wantReadyButtonDisabled:   true,
+			wantFinishButtonDisabled:  true,
+			wantAddBotButtonDisabled:  true,
+			wantHintButtonDisabled:    true,
+			wantForfeitButtonDisabled: true,
+			wantCanvasStatus:          game.Finished,
+		},
+		{
+			s:                         game.Challenging,
+			wantStatusText:            "Challenging",
+			wantSnagButtonDisabled:    true,
+			wantSwapButtonDisabled:    true,
+			wantStartButtonDisabled:   true,
+			wantReadyButtonDisabled:   true,
+			wantFinishButtonDisabled:  true,
+			wantAddBotButtonDisabled:  true,
+			wantHintButtonDisabled:    true,
+			wantForfeitButtonDisabled: true,
+		},
+		{
+			s:                         game.Finished,
+			wantStatusText:            "Finished",
+			wantSnagButtonDisabled:    true,
+			wantSwapButtonDisabled:    true,
+			wantStartButtonDisabled:   true,
+			wantReadyButtonDisabled:   true,
+			wantFinishButtonDisabled:  true,
+			wantAddBotButtonDisabled:  true,
+			wantHintButtonDisabled:    true,
+			wantForfeitButtonDisabled: true,
 		},
 	}
 	for i, test := range tests {
 		wantStatusSet := len(test.wantStatusText) > 0
 		statusSet := false
 		g := Game{
+			isHost:    !test.notHost,
+			forfeited: test.forfeited,
 			dom: &mockDOM{
 				QuerySelectorFunc: func(query string) js.Value {
 					return js.ValueOf(map[string]any{})
@@ -845,10 +1909,26 @@ func TestUpdateStatus(t *testing.T) {
 						if want, got := test.wantStartButtonDisabled, disabled; want != got {
 							t.Errorf("Test %v: start button not disabled correctly: wanted %v, got %v", i, want, got)
 						}
+					case strings.Contains(query, "ready"):
+						if want, got := test.wantReadyButtonDisabled, disabled; want != got {
+							t.Errorf("Test %v: ready button not disabled correctly: wanted %v, got %v", i, want, got)
+						}
 					case strings.Contains(query, "finish"):
 						if want, got := test.wantFinishButtonDisabled, disabled; want != got {
 							t.Errorf("Test %v: finish button not disabled correctly: wanted %v, got %v", i, want, got)
 						}
+					case strings.Contains(query, "add-bot"):
+						if want, got := test.wantAddBotButtonDisabled, disabled; want != got {
+							t.Errorf("Test %v: add bot button not disabled correctly: wanted %v, got %v", i, want, got)
+						}
+					case strings.Contains(query, "forfeit"):
+						if want, got := test.wantForfeitButtonDisabled, disabled; want != got {
+							t.Errorf("Test %v: forfeit button not disabled correctly: wanted %v, got %v", i, want, got)
+						}
+					case strings.Contains(query, "hint"):
+						if want, got := test.wantHintButtonDisabled, disabled; want != got {
+							t.Errorf("Test %v: hint button not disabled correctly: wanted %v, got %v", i, want, got)
+						}
 					default:
 						t.Errorf("Test %v: unwanted button disabled (%v): %v", i, disabled, query)
 					}
@@ -856,7 +1936,11 @@ func TestUpdateStatus(t *testing.T) {
 			},
 			canvas: &mockCanvas{
 				SetGameStatusFunc: func(s game.Status) {
-					if want, got := test.s, s; want != got {
+					want := test.s
+					if test.wantCanvasStatus != 0 {
+						want = test.wantCanvasStatus
+					}
+					if got := s; want != got {
 						t.Errorf("Test %v: canvas status should be same as game: wanted %v, got %v", i, want, got)
 					}
 				},
@@ -864,14 +1948,18 @@ func TestUpdateStatus(t *testing.T) {
 		}
 		m := message.Message{
 			Game: &game.Info{
-				Status:    test.s,
-				TilesLeft: test.gameTilesLeft,
+				Status:      test.s,
+				TilesLeft:   test.gameTilesLeft,
+				BananasRace: test.bananasRace,
 			},
 		}
 		g.updateStatus(m)
 		if want, got := wantStatusSet, statusSet; want != got {
 			t.Errorf("Test %v: statusSet not as desired: wanted %v, got %v", i, want, got)
 		}
+		if wantStatusSet && test.s != g.status {
+			t.Errorf("Test %v: wanted status of game to be stored as %v, got %v", i, test.s, g.status)
+		}
 	}
 }
 
@@ -920,6 +2008,17 @@ func TestUpdateTilesLeft(t *testing.T) {
 			wantSetValue:                   "0",
 			wantSetButtonDisabledCallCount: 3,
 		},
+		{
+			m: message.Message{
+				Game: &game.Info{
+					TilesLeft:   2,
+					Status:      game.InProgress,
+					BananasRace: true,
+				},
+			},
+			wantSetValue:                   "2",
+			wantSetButtonDisabledCallCount: 2,
+		},
 	}
 	for i, test := range tests {
 		setValueCalled := false
@@ -947,24 +2046,94 @@ func TestUpdateTilesLeft(t *testing.T) {
 	}
 }
 
-func TestUpdatePlayers(t *testing.T) {
+func TestUpdateTimeLeft(t *testing.T) {
 	tests := []struct {
-		players      []string
+		m            message.Message
 		wantSetValue bool
 		want         string
 	}{
-		{},
+		{ // not timed
+			m: message.Message{
+				Game: &game.Info{
+					Status: game.InProgress,
+				},
+			},
+		},
+		{
+			m: message.Message{
+				Game: &game.Info{
+					Status:      game.InProgress,
+					SecondsLeft: 75,
+				},
+			},
+			wantSetValue: true,
+			want:         "1:15",
+		},
 		{
-			players:      []string{"larry", "curly", "moe"},
+			m: message.Message{
+				Game: &game.Info{
+					Status: game.Finished,
+				},
+			},
 			wantSetValue: true,
-			want:         "larry,curly,moe", // no spaces to save space
 		},
 	}
 	for i, test := range tests {
 		setValueCalled := false
+		g := Game{
+			dom: &mockDOM{
+				SetValueFunc: func(query, value string) {
+					if want, got := test.want, value; want != got {
+						t.Errorf("Test %v: wanted setValue to be called with %q, got %q", i, want, got)
+					}
+					setValueCalled = true
+				},
+			},
+		}
+		g.updateTimeLeft(test.m)
+		if want, got := test.wantSetValue, setValueCalled; want != got {
+			t.Errorf("Test %v: wanted SetValue to be called: %v, got %v", i, want, got)
+		}
+	}
+}
+
+func TestUpdatePlayers(t *testing.T) {
+	tests := []struct {
+		players         []string
+		playerPoints    map[string]int
+		wantSetValue    bool
+		want            string
+		wantKickOptions []string
+	}{
+		{},
+		{
+			players:         []string{"larry", "curly", "moe"},
+			wantSetValue:    true,
+			want:            "larry,curly,moe", // no spaces to save space
+			wantKickOptions: []string{"larry", "moe"},
+		},
+		{
+			players: []string{"larry", "curly", "moe"},
+			playerPoints: map[string]int{
+				"larry": 4,
+				"moe":   12,
+			},
+			wantSetValue:    true,
+			want:            "larry (4),curly,moe (12)",
+			wantKickOptions: []string{"larry", "moe"},
+		},
+	}
+	for i, test := range tests {
+		setValueCalled := false
+		var gotKickOptions []string
+		appendChild := js.FuncOf(func(this js.Value, args []js.Value) any {
+			gotKickOptions = append(gotKickOptions, args[0].Get("value").String())
+			return nil
+		})
 		m := message.Message{
 			Game: &game.Info{
-				Players: test.players,
+				Players:      test.players,
+				PlayerPoints: test.playerPoints,
 			},
 		}
 		g := Game{
@@ -975,12 +2144,163 @@ func TestUpdatePlayers(t *testing.T) {
 					}
 					setValueCalled = true
 				},
+				QuerySelectorFunc: func(query string) js.Value {
+					return js.ValueOf(map[string]any{
+						"appendChild": appendChild,
+					})
+				},
+				CloneElementFunc: func(query string) js.Value {
+					return js.ValueOf(map[string]any{
+						"children": []any{
+							map[string]any{},
+						},
+					})
+				},
 			},
 		}
-		g.updatePlayers(m)
+		g.updatePlayers(m, "curly")
+		appendChild.Release()
 		if want, got := test.wantSetValue, setValueCalled; want != got {
 			t.Errorf("Test %v: wanted set value to be called (%v), got %v", i, want, got)
 		}
+		if want, got := test.wantKickOptions, gotKickOptions; !reflect.DeepEqual(want, got) {
+			t.Errorf("Test %v: players that can be kicked not equal:\nwanted: %v\ngot:    %v", i, want, got)
+		}
+	}
+}
+
+func TestUpdateHost(t *testing.T) {
+	tests := []struct {
+		host             string
+		status           game.Status
+		wantIsHost       bool
+		wantSetHost      bool
+		wantStartEnabled bool
+	}{
+		{}, // no host in message
+		{
+			host:        "barney",
+			status:      game.NotStarted,
+			wantSetHost: true,
+		},
+		{
+			host:             "fred",
+			status:           game.NotStarted,
+			wantIsHost:       true,
+			wantSetHost:      true,
+			wantStartEnabled: true,
+		},
+		{
+			host:        "fred",
+			status:      game.InProgress,
+			wantIsHost:  true,
+			wantSetHost: true,
+		},
+	}
+	for i, test := range tests {
+		hostSet := false
+		enabledButtons := make(map[string]bool)
+		g := Game{
+			status: test.status,
+			dom: &mockDOM{
+				SetValueFunc: func(query, value string) {
+					if want, got := test.host, value; want != got {
+						t.Errorf("Test %v: hosts not equal: wanted %v, got %v", i, want, got)
+					}
+					hostSet = true
+				},
+				SetButtonDisabledFunc: func(query string, disabled bool) {
+					enabledButtons[query[strings.LastIndex(query, ".")+1:]] = !disabled
+				},
+			},
+		}
+		m := message.Message{
+			Game: &game.Info{
+				Host: test.host,
+			},
+		}
+		g.updateHost(m, "fred")
+		switch {
+		case test.wantIsHost != g.isHost:
+			t.Errorf("Test %v: wanted user to be host: %v", i, test.wantIsHost)
+		case test.wantSetHost != hostSet:
+			t.Errorf("Test %v: wanted host to be set: %v", i, test.wantSetHost)
+		case !test.wantSetHost:
+			if len(enabledButtons) != 0 {
+				t.Errorf("Test %v: wanted no buttons to change, got %v", i, enabledButtons)
+			}
+		case test.wantStartEnabled != enabledButtons["start"],
+			test.wantIsHost != enabledButtons["kick"],
+			test.wantIsHost != enabledButtons["delete"]:
+			t.Errorf("Test %v: enabled buttons not correct: %v", i, enabledButtons)
+		}
+	}
+}
+
+func TestUpdateForfeited(t *testing.T) {
+	tests := []struct {
+		forfeited        bool
+		players          []string
+		forfeitedPlayers []string
+		wantForfeited    bool
+		wantFrozen       bool
+	}{
+		{ // no players in message
+			forfeited:     true,
+			wantForfeited: true,
+		},
+		{
+			players:          []string{"barney", "fred"},
+			forfeitedPlayers: []string{"barney"},
+		},
+		{
+			players:          []string{"barney", "fred"},
+			forfeitedPlayers: []string{"fred"},
+			wantForfeited:    true,
+			wantFrozen:       true,
+		},
+		{ // already forfeited
+			forfeited:        true,
+			players:          []string{"barney", "fred"},
+			forfeitedPlayers: []string{"fred"},
+			wantForfeited:    true,
+		},
+	}
+	for i, test := range tests {
+		disabledButtons := make(map[string]bool)
+		var canvasStatus game.Status
+		g := Game{
+			forfeited: test.forfeited,
+			dom: &mockDOM{
+				SetButtonDisabledFunc: func(query string, disabled bool) {
+					disabledButtons[query[strings.LastIndex(query, ".")+1:]] = disabled
+				},
+			},
+			canvas: &mockCanvas{
+				SetGameStatusFunc: func(s game.Status) {
+					canvasStatus = s
+				},
+			},
+		}
+		m := message.Message{
+			Game: &game.Info{
+				Players:          test.players,
+				ForfeitedPlayers: test.forfeitedPlayers,
+			},
+		}
+		g.updateForfeited(m, "fred")
+		switch {
+		case test.wantForfeited != g.forfeited:
+			t.Errorf("Test %v: wanted player to have forfeited: %v", i, test.wantForfeited)
+		case !test.wantFrozen:
+			if len(disabledButtons) != 0 || canvasStatus != 0 {
+				t.Errorf("Test %v: wanted board to not be frozen again, got disabled buttons %v and canvas status %v", i, disabledButtons, canvasStatus)
+			}
+		case len(disabledButtons) != 5, !disabledButtons["snag"], !disabledButtons["swap"], !disabledButtons["finish"], !disabledButtons["hint"], !disabledButtons["forfeit"]:
+			t.Errorf("Test %v: wanted play buttons to be disabled, got %v", i, disabledButtons)
+		case canvasStatus != game.Finished:
+			t.Errorf("Test %v: wanted canvas to stop sending moves, got status %v", i, canvasStatus)
+		}
 	}
 }
 
@@ -1446,3 +2766,154 @@ func TestViewFinalBoard(t *testing.T) {
 		}
 	}
 }
+
+func TestSetReplay(t *testing.T) {
+	elements := make(map[string]js.Value)
+	replayShown := false
+	var gotStepText string
+	g := Game{
+		dom: &mockDOM{
+			QuerySelectorFunc: func(query string) js.Value {
+				if query == ".player-list input:checked+label" {
+					return js.Null()
+				}
+				e := js.ValueOf(map[string]any{})
+				elements[query] = e
+				return e
+			},
+			EncodeURIComponentFunc: func(str string) string {
+				return "ENCODED"
+			},
+			SetCheckedFunc: func(query string, checked bool) {
+				if query == "#hide-replay" && !checked {
+					replayShown = true
+				}
+			},
+			ValueFunc: func(query string) string {
+				return "1"
+			},
+			SetValueFunc: func(query, value string) {
+				gotStepText = value
+			},
+		},
+	}
+	r := replay.Replay{
+		GameID: 7,
+		Events: []replay.Event{
+			{Type: replay.Join, PlayerName: "alice"},
+			{Type: replay.Start, PlayerName: "alice"},
+		},
+	}
+	g.SetReplay(r)
+	stepInput := elements[".final-boards .replay .replay-step"]
+	downloadLink := elements[".final-boards .replay .download"]
+	switch {
+	case g.replay == nil:
+		t.Error("wanted replay to be stored")
+	case !replayShown:
+		t.Error("wanted replay controls to be shown")
+	case stepInput.Get("max").Int() != 2:
+		t.Errorf("wanted replay step max to be the number of events (2), got %v", stepInput.Get("max"))
+	case downloadLink.Get("href").String() != "data:application/json;charset=utf-8,ENCODED":
+		t.Errorf("unwanted download href: %v", downloadLink.Get("href"))
+	case downloadLink.Get("download").String() != "selene-bananas-game-7-replay.json":
+		t.Errorf("unwanted download file name: %v", downloadLink.Get("download"))
+	case gotStepText != "1/2: alice Join":
+		t.Errorf("unwanted replay step text: %v", gotStepText)
+	}
+}
+
+func TestUpdateSpectate(t *testing.T) {
+	appendCount := 0
+	appendChild := js.FuncOf(func(this js.Value, args []js.Value) any {
+		appendCount++
+		return nil
+	})
+	values := make(map[string]string)
+	var canvasQueries []string
+	numRedraws := 0
+	g := Game{
+		dom: &mockDOM{
+			SetValueFunc: func(query, value string) {
+				values[query] = value
+			},
+			QuerySelectorFunc: func(query string) js.Value {
+				return js.ValueOf(map[string]any{
+					"appendChild": appendChild,
+				})
+			},
+			CloneElementFunc: func(query string) js.Value {
+				return js.ValueOf(map[string]any{
+					"children": []any{
+						map[string]any{
+							"children": []any{
+								map[string]any{}, // player name
+							},
+						},
+					},
+				})
+			},
+		},
+		canvas: &mockCanvas{
+			TileLengthFunc: func() int {
+				return 20
+			},
+		},
+		canvasCreator: mockCanvasCreator{
+			CreateFunc: func(board *board.Board, canvasParentDivQuery string) Canvas {
+				canvasQueries = append(canvasQueries, canvasParentDivQuery)
+				return &mockCanvas{
+					SetTileLengthFunc: func(tileLength int) {
+						if tileLength != 20 {
+							t.Errorf("wanted tile length to be set to 20, got %v", tileLength)
+						}
+					},
+					DesiredWidthFunc: func() int {
+						return 300
+					},
+					UpdateSizeFunc: func(width int) {
+						// NOOP
+					},
+					SetGameStatusFunc: func(s game.Status) {
+						if s != game.InProgress {
+							t.Errorf("wanted canvas status to be set to in progress, got %v", s)
+						}
+					},
+					RedrawFunc: func() {
+						numRedraws++
+					},
+				}
+			},
+		},
+	}
+	m := message.Message{
+		Type: message.SpectateGame,
+		Game: &game.Info{
+			Status:      game.InProgress,
+			TilesLeft:   8,
+			SecondsLeft: 30,
+			Players:     []string{"barney", "fred"},
+			PlayerBoards: map[string]board.Board{
+				"fred":   {},
+				"barney": {},
+			},
+		},
+	}
+	g.UpdateSpectate(m)
+	appendChild.Release()
+	wantCanvasQueries := []string{"#spectate-board-0", "#spectate-board-1"}
+	switch {
+	case values[".spectate>.info .tiles-left"] != "8":
+		t.Errorf("wanted tiles left to be set, got %v", values)
+	case values[".spectate>.info .time-left"] != "0:30":
+		t.Errorf("wanted time left to be set, got %v", values)
+	case values[".spectate>.info .players"] != "barney,fred":
+		t.Errorf("wanted players to be set, got %v", values)
+	case appendCount != 2:
+		t.Errorf("wanted a div to be appended for each board, got %v", appendCount)
+	case !reflect.DeepEqual(wantCanvasQueries, canvasQueries):
+		t.Errorf("canvas queries not equal:\nwanted: %v\ngot:    %v", wantCanvasQueries, canvasQueries)
+	case numRedraws != 2:
+		t.Errorf("wanted each board to be drawn, got %v", numRedraws)
+	}
+}
@@ -5,20 +5,24 @@ package lobby
 
 import (
 	"context"
+	"encoding/json"
+	"strconv"
 	"strings"
 	"sync"
 	"syscall/js"
 
 	"github.com/jacobpatterson1549/selene-bananas/game"
+	"github.com/jacobpatterson1549/selene-bananas/ui/http"
 )
 
 type (
 	// Lobby handles viewing, joining, and creating games on the server.
 	Lobby struct {
-		dom    DOM
-		log    Log
-		game   Game
-		Socket Socket
+		dom        DOM
+		log        Log
+		game       Game
+		httpClient HTTPRequester
+		Socket     Socket
 	}
 
 	// Log is used to store text about connection errors.
@@ -40,31 +44,61 @@ type (
 	// DOM interacts with the page.
 	DOM interface {
 		QuerySelector(query string) js.Value
+		SetValue(query, value string)
+		QueryParam(key string) string
 		FormatTime(utcSeconds int64) string
 		CloneElement(query string) js.Value
+		NewXHR() js.Value
 		RegisterFuncs(ctx context.Context, wg *sync.WaitGroup, parentName string, jsFuncs map[string]js.Func)
 		NewJsFunc(fn func()) js.Func
+		NewJsEventFunc(fn func(event js.Value)) js.Func
 		NewJsEventFuncAsync(fn func(event js.Value), async bool) js.Func
 	}
+
+	// HTTPRequester does HTTP requests.
+	HTTPRequester interface {
+		Do(dom http.DOM, req http.Request) (*http.Response, error)
+	}
+
+	// leaderboardEntry is the position of a user on the leaderboard.
+	leaderboardEntry struct {
+		Rank     int    `json:"rank"`
+		Username string `json:"username"`
+		Points   int    `json:"points"`
+	}
 )
 
 // New creates a lobby for games.
-func New(dom DOM, log Log, game Game) *Lobby {
+func New(dom DOM, log Log, game Game, httpClient HTTPRequester) *Lobby {
 	l := Lobby{
-		dom:  dom,
-		log:  log,
-		game: game,
+		dom:        dom,
+		log:        log,
+		game:       game,
+		httpClient: httpClient,
 	}
 	return &l
 }
 
-// InitDom registers lobby dom functions.
+// InitDom registers lobby dom functions and fills the join form from the invite link the page was opened with.
 func (l *Lobby) InitDom(ctx context.Context, wg *sync.WaitGroup) {
 	jsFuncs := map[string]js.Func{
-		"connect": l.dom.NewJsEventFuncAsync(l.connect, true),
-		"leave":   l.dom.NewJsFunc(l.leave),
+		"connect":     l.dom.NewJsEventFuncAsync(l.connect, true),
+		"leave":       l.dom.NewJsFunc(l.leave),
+		"leaderboard": l.dom.NewJsEventFuncAsync(l.leaderboard, true),
 	}
 	l.dom.RegisterFuncs(ctx, wg, "lobby", jsFuncs)
+	l.prefillJoinForm()
+}
+
+// prefillJoinForm sets the game id and invite code of the form to join private games from the query of the page url.
+// The form is not changed if the page was not opened with an invite link.
+func (l *Lobby) prefillJoinForm() {
+	gameID := l.dom.QueryParam("game")
+	if len(gameID) == 0 {
+		return
+	}
+	l.dom.SetValue(".lobby .join-game .game-id", gameID)
+	l.dom.SetValue(".lobby .join-game .invite-code", l.dom.QueryParam("inviteCode"))
 }
 
 // connect makes a BLOCKING request to connect to the lobby.
@@ -107,7 +141,7 @@ func (l *Lobby) gameInfoElement(gameInfo game.Info, username string) js.Value {
 	createdAtTimeText := l.dom.FormatTime(gameInfo.CreatedAt)
 	rowElement.Get("children").Index(0).Set("innerHTML", createdAtTimeText)
 
-	players := strings.Join(gameInfo.Players, ", ")
+	players := strings.Join(gameInfo.PlayerLabels(), ", ")
 	rowElement.Get("children").Index(1).Set("innerHTML", players)
 
 	capacityRatio := gameInfo.CapacityRatio()
@@ -121,6 +155,51 @@ func (l *Lobby) gameInfoElement(gameInfo game.Info, username string) js.Value {
 	if !gameInfo.CanJoin(username) {
 		joinElements.Get("children").Index(1).Set("disabled", true)
 	}
+	if !gameInfo.CanSpectate(username) {
+		joinElements.Get("children").Index(2).Set("disabled", true)
+	}
 
 	return gameInfoElement
 }
+
+// leaderboard makes a BLOCKING request to get the users with the most points and shows them in the leaderboard table.
+func (l *Lobby) leaderboard(event js.Value) {
+	req := http.Request{
+		Method: "GET",
+		URL:    "/leaderboard",
+	}
+	resp, err := l.httpClient.Do(l.dom, req)
+	switch {
+	case err != nil:
+		l.log.Error("making http request: " + err.Error())
+		return
+	case resp.Code >= 400:
+		l.log.Error("HTTP error: status " + strconv.Itoa(resp.Code) + ": " + resp.Body)
+		return
+	}
+	var entries []leaderboardEntry
+	if err := json.Unmarshal([]byte(resp.Body), &entries); err != nil {
+		l.log.Error("reading leaderboard: " + err.Error())
+		return
+	}
+	l.setLeaderboard(entries)
+}
+
+// setLeaderboard replaces the rows of the leaderboard table with the entries.
+func (l *Lobby) setLeaderboard(entries []leaderboardEntry) {
+	tbodyElement := l.dom.QuerySelector(".leaderboard>tbody")
+	tbodyElement.Set("innerHTML", "")
+	if len(entries) == 0 {
+		emptyLeaderboardElement := l.dom.CloneElement(".no-leaderboard-row")
+		tbodyElement.Call("appendChild", emptyLeaderboardElement)
+		return
+	}
+	for _, e := range entries {
+		entryElement := l.dom.CloneElement(".leaderboard-row")
+		rowElement := entryElement.Get("children").Index(0)
+		rowElement.Get("children").Index(0).Set("innerHTML", strconv.Itoa(e.Rank))
+		rowElement.Get("children").Index(1).Set("innerHTML", e.Username)
+		rowElement.Get("children").Index(2).Set("innerHTML", strconv.Itoa(e.Points))
+		tbodyElement.Call("appendChild", entryElement)
+	}
+}
@@ -11,13 +11,15 @@ import (
 	"testing"
 
 	"github.com/jacobpatterson1549/selene-bananas/game"
+	"github.com/jacobpatterson1549/selene-bananas/ui/http"
 )
 
 func TestNew(t *testing.T) {
 	dom := new(mockDOM)
 	log := new(mockLog)
 	game := new(mockGame)
-	l := New(dom, log, game)
+	httpClient := new(mockHTTPRequester)
+	l := New(dom, log, game, httpClient)
 	switch {
 	case !reflect.DeepEqual(dom, l.dom):
 		t.Errorf("doms not equal: wanted %v, got %v", dom, l.dom)
@@ -25,6 +27,8 @@ func TestNew(t *testing.T) {
 		t.Errorf("logs not equal: wanted %v, got %v", log, l.log)
 	case !reflect.DeepEqual(game, l.game):
 		t.Errorf("games not equal: wanted %v, got %v", game, l.game)
+	case !reflect.DeepEqual(httpClient, l.httpClient):
+		t.Errorf("http clients not equal: wanted %v, got %v", httpClient, l.httpClient)
 	case l.Socket != nil:
 		t.Errorf("wanted nil lobby socket when new is called, got %v", l.Socket)
 	}
@@ -34,6 +38,7 @@ func TestInitDom(t *testing.T) {
 	wantJsFuncNames := []string{
 		"connect",
 		"leave",
+		"leaderboard",
 	}
 	functionsRegistered := false
 	u := Lobby{
@@ -60,6 +65,9 @@ func TestInitDom(t *testing.T) {
 			NewJsEventFuncAsyncFunc: func(fn func(event js.Value), async bool) js.Func {
 				return js.FuncOf(func(this js.Value, args []js.Value) any { return nil })
 			},
+			QueryParamFunc: func(key string) string {
+				return ""
+			},
 		},
 	}
 	ctx := context.Background()
@@ -70,6 +78,44 @@ func TestInitDom(t *testing.T) {
 	}
 }
 
+func TestPrefillJoinForm(t *testing.T) {
+	tests := []struct {
+		queryParams map[string]string
+		want        map[string]string
+	}{
+		{ // not opened with invite link
+			want: map[string]string{},
+		},
+		{
+			queryParams: map[string]string{
+				"game":       "7",
+				"inviteCode": "b4n4n4",
+			},
+			want: map[string]string{
+				".lobby .join-game .game-id":     "7",
+				".lobby .join-game .invite-code": "b4n4n4",
+			},
+		},
+	}
+	for i, test := range tests {
+		got := make(map[string]string)
+		l := Lobby{
+			dom: &mockDOM{
+				QueryParamFunc: func(key string) string {
+					return test.queryParams[key]
+				},
+				SetValueFunc: func(query, value string) {
+					got[query] = value
+				},
+			},
+		}
+		l.prefillJoinForm()
+		if !reflect.DeepEqual(test.want, got) {
+			t.Errorf("Test %v: form values not equal:\nwanted: %v\ngot:    %v", i, test.want, got)
+		}
+	}
+}
+
 func TestConnect(t *testing.T) {
 	tests := []struct {
 		event      js.Value
@@ -188,7 +234,7 @@ func TestSetGameInfos(t *testing.T) {
 	})
 	t.Run("happy path", func(t *testing.T) {
 		numAppended := 0
-		newGameInfoRow := func(createdAt, players, capacityRatio, status string, id int, canJoin bool) js.Value {
+		newGameInfoRow := func(createdAt, players, capacityRatio, status string, id int, canJoin, canSpectate bool) js.Value {
 			return js.ValueOf(map[string]any{ // gameInfoElement
 				"children": []any{
 					map[string]any{ //rowElement
@@ -201,6 +247,7 @@ func TestSetGameInfos(t *testing.T) {
 								"children": []any{
 									map[string]any{"value": id},
 									map[string]any{"disabled": !canJoin},
+									map[string]any{"disabled": !canSpectate},
 								},
 							},
 						},
@@ -209,8 +256,8 @@ func TestSetGameInfos(t *testing.T) {
 			})
 		}
 		wantAppends := []js.Value{
-			newGameInfoRow("A", "use, server, sort, order", "4/6", "In Progress", 3, false),
-			newGameInfoRow("B", "me", "1/1", "Finished", 2, true),
+			newGameInfoRow("A", "use, server, sort, order", "4/6", "In Progress", 3, false, true),
+			newGameInfoRow("B", "me (5)", "1/1", "Finished", 2, true, false),
 		}
 		jsonString := func(v js.Value) string { // hack to get around js.Value.Equal using === (refs are different)
 			return js.Global().Get("JSON").Call("stringify", v).String()
@@ -232,7 +279,7 @@ func TestSetGameInfos(t *testing.T) {
 				return gameInfosTbodyElement
 			},
 			CloneElementFunc: func(query string) js.Value {
-				return newGameInfoRow("", "", "", "", 0, true)
+				return newGameInfoRow("", "", "", "", 0, true, true)
 			},
 			FormatTimeFunc: func(utcSeconds int64) string {
 				return string(rune(utcSeconds))
@@ -252,6 +299,9 @@ func TestSetGameInfos(t *testing.T) {
 				Players:   []string{"me"},
 				Capacity:  1,
 				Status:    game.Finished,
+				PlayerPoints: map[string]int{
+					"me": 5,
+				},
 			},
 		}
 		l := Lobby{
@@ -267,3 +317,119 @@ func TestSetGameInfos(t *testing.T) {
 		appendChild.Release()
 	})
 }
+
+func TestLeaderboard(t *testing.T) {
+	newLeaderboardRow := func(rank, username, points string) js.Value {
+		return js.ValueOf(map[string]any{ // leaderboardElement
+			"children": []any{
+				map[string]any{ // rowElement
+					"children": []any{
+						map[string]any{"innerHTML": rank},
+						map[string]any{"innerHTML": username},
+						map[string]any{"innerHTML": points},
+					},
+				},
+			},
+		})
+	}
+	jsonString := func(v js.Value) string { // hack to get around js.Value.Equal using === (refs are different)
+		return js.Global().Get("JSON").Call("stringify", v).String()
+	}
+	emptyLeaderboardElement := js.ValueOf(1337)
+	tests := []struct {
+		httpResponse    http.Response
+		httpResponseErr error
+		wantErrorLogged bool
+		wantAppends     []js.Value
+	}{
+		{
+			httpResponseErr: errors.New("http error"),
+			wantErrorLogged: true,
+		},
+		{
+			httpResponse: http.Response{
+				Code: 500,
+				Body: "internal error",
+			},
+			wantErrorLogged: true,
+		},
+		{
+			httpResponse: http.Response{
+				Code: 200,
+				Body: "{bad json",
+			},
+			wantErrorLogged: true,
+		},
+		{
+			httpResponse: http.Response{
+				Code: 200,
+				Body: "[]",
+			},
+			wantAppends: []js.Value{
+				emptyLeaderboardElement,
+			},
+		},
+		{
+			httpResponse: http.Response{
+				Code: 200,
+				Body: `[{"rank":1,"username":"selene","points":99},{"rank":2,"username":"fred","points":7}]`,
+			},
+			wantAppends: []js.Value{
+				newLeaderboardRow("1", "selene", "99"),
+				newLeaderboardRow("2", "fred", "7"),
+			},
+		},
+	}
+	for i, test := range tests {
+		var gotAppends []js.Value
+		appendChild := js.FuncOf(func(this js.Value, args []js.Value) any {
+			gotAppends = append(gotAppends, args[0])
+			return nil
+		})
+		leaderboardTbodyElement := js.ValueOf(map[string]any{
+			"innerHTML":   "existing leaderboard",
+			"appendChild": appendChild,
+		})
+		errorLogged := false
+		l := Lobby{
+			dom: &mockDOM{
+				QuerySelectorFunc: func(query string) js.Value {
+					return leaderboardTbodyElement
+				},
+				CloneElementFunc: func(query string) js.Value {
+					if query == ".no-leaderboard-row" {
+						return emptyLeaderboardElement
+					}
+					return newLeaderboardRow("", "", "")
+				},
+			},
+			log: mockLog{
+				errorFunc: func(text string) {
+					errorLogged = true
+				},
+			},
+			httpClient: mockHTTPRequester{
+				doFunc: func(dom http.DOM, req http.Request) (*http.Response, error) {
+					if want, got := "/leaderboard", req.URL; want != got {
+						t.Errorf("Test %v: urls not equal: wanted %v, got %v", i, want, got)
+					}
+					return &test.httpResponse, test.httpResponseErr
+				},
+			},
+		}
+		l.leaderboard(js.Undefined())
+		appendChild.Release()
+		switch {
+		case test.wantErrorLogged != errorLogged:
+			t.Errorf("Test %v: wanted error logged: %v, got %v", i, test.wantErrorLogged, errorLogged)
+		case len(test.wantAppends) != len(gotAppends):
+			t.Errorf("Test %v: wanted %v rows appended, got %v", i, len(test.wantAppends), len(gotAppends))
+		default:
+			for j := range test.wantAppends {
+				if want, got := jsonString(test.wantAppends[j]), jsonString(gotAppends[j]); want != got {
+					t.Errorf("Test %v: append %v not equal:\nwanted: %v\ngot:    %v", i, j, want, got)
+				}
+			}
+		}
+	}
+}
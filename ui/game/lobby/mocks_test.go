@@ -6,6 +6,8 @@ import (
 	"context"
 	"sync"
 	"syscall/js"
+
+	"github.com/jacobpatterson1549/selene-bananas/ui/http"
 )
 
 type mockSocket struct {
@@ -29,6 +31,14 @@ func (m mockLog) Error(text string) {
 	m.errorFunc(text)
 }
 
+type mockHTTPRequester struct {
+	doFunc func(dom http.DOM, req http.Request) (*http.Response, error)
+}
+
+func (m mockHTTPRequester) Do(dom http.DOM, req http.Request) (*http.Response, error) {
+	return m.doFunc(dom, req)
+}
+
 type mockGame struct {
 	leaveFunc func()
 }
@@ -39,10 +49,14 @@ func (m mockGame) Leave() {
 
 type mockDOM struct {
 	QuerySelectorFunc       func(query string) js.Value
+	SetValueFunc            func(query, value string)
+	QueryParamFunc          func(key string) string
 	FormatTimeFunc          func(utcSeconds int64) string
 	CloneElementFunc        func(query string) js.Value
+	NewXHRFunc              func() js.Value
 	RegisterFuncsFunc       func(ctx context.Context, wg *sync.WaitGroup, parentName string, jsFuncs map[string]js.Func)
 	NewJsFuncFunc           func(fn func()) js.Func
+	NewJsEventFuncFunc      func(fn func(event js.Value)) js.Func
 	NewJsEventFuncAsyncFunc func(fn func(event js.Value), async bool) js.Func
 }
 
@@ -50,6 +64,14 @@ func (m mockDOM) QuerySelector(query string) js.Value {
 	return m.QuerySelectorFunc(query)
 }
 
+func (m *mockDOM) SetValue(query, value string) {
+	m.SetValueFunc(query, value)
+}
+
+func (m mockDOM) QueryParam(key string) string {
+	return m.QueryParamFunc(key)
+}
+
 func (m mockDOM) FormatTime(utcSeconds int64) string {
 	return m.FormatTimeFunc(utcSeconds)
 }
@@ -58,6 +80,10 @@ func (m mockDOM) CloneElement(query string) js.Value {
 	return m.CloneElementFunc(query)
 }
 
+func (m mockDOM) NewXHR() js.Value {
+	return m.NewXHRFunc()
+}
+
 func (m *mockDOM) RegisterFuncs(ctx context.Context, wg *sync.WaitGroup, parentName string, jsFuncs map[string]js.Func) {
 	m.RegisterFuncsFunc(ctx, wg, parentName, jsFuncs)
 }
@@ -66,6 +92,10 @@ func (m *mockDOM) NewJsFunc(fn func()) js.Func {
 	return m.NewJsFuncFunc(fn)
 }
 
+func (m *mockDOM) NewJsEventFunc(fn func(event js.Value)) js.Func {
+	return m.NewJsEventFuncFunc(fn)
+}
+
 func (m *mockDOM) NewJsEventFuncAsync(fn func(event js.Value), async bool) js.Func {
 	return m.NewJsEventFuncAsyncFunc(fn, async)
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/jacobpatterson1549/selene-bananas/game"
 	"github.com/jacobpatterson1549/selene-bananas/game/board"
 	"github.com/jacobpatterson1549/selene-bananas/game/message"
+	"github.com/jacobpatterson1549/selene-bananas/game/tile"
 )
 
 type mockDOM struct {
@@ -23,6 +24,8 @@ type mockDOM struct {
 	CloneElementFunc         func(query string) js.Value
 	ConfirmFunc              func(message string) bool
 	ColorFunc                func(element js.Value) string
+	EncodeURIComponentFunc   func(str string) string
+	PageURLFunc              func() string
 	RegisterFuncsFunc        func(ctx context.Context, wg *sync.WaitGroup, parentName string, jsFuncs map[string]js.Func)
 	NewJsFuncFunc            func(fn func()) js.Func
 	NewJsEventFuncFunc       func(fn func(event js.Value)) js.Func
@@ -69,6 +72,14 @@ func (m mockDOM) Color(element js.Value) string {
 	return m.ColorFunc(element)
 }
 
+func (m mockDOM) EncodeURIComponent(str string) string {
+	return m.EncodeURIComponentFunc(str)
+}
+
+func (m mockDOM) PageURL() string {
+	return m.PageURLFunc()
+}
+
 func (m *mockDOM) RegisterFuncs(ctx context.Context, wg *sync.WaitGroup, parentName string, jsFuncs map[string]js.Func) {
 	m.RegisterFuncsFunc(ctx, wg, parentName, jsFuncs)
 }
@@ -117,6 +128,8 @@ type mockCanvas struct {
 	UpdateSizeFunc           func(width int)
 	NumRowsFunc              func() int
 	NumColsFunc              func() int
+	SetHintFunc              func(positions []tile.Position)
+	SetReportFunc            func(r *board.Report)
 }
 
 func (m *mockCanvas) StartSwap() {
@@ -159,6 +172,14 @@ func (m mockCanvas) NumCols() int {
 	return m.NumColsFunc()
 }
 
+func (m *mockCanvas) SetHint(positions []tile.Position) {
+	m.SetHintFunc(positions)
+}
+
+func (m *mockCanvas) SetReport(r *board.Report) {
+	m.SetReportFunc(r)
+}
+
 type mockCanvasCreator struct {
 	CreateFunc func(board *board.Board, canvasParentDivQuery string) Canvas
 }
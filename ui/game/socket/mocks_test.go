@@ -7,6 +7,7 @@ import (
 
 	"github.com/jacobpatterson1549/selene-bananas/game"
 	"github.com/jacobpatterson1549/selene-bananas/game/message"
+	"github.com/jacobpatterson1549/selene-bananas/game/replay"
 )
 
 type mockUser struct {
@@ -28,9 +29,14 @@ func (m mockUser) Logout() {
 }
 
 type mockGame struct {
-	IDFunc         func() game.ID
-	LeaveFunc      func()
-	UpdateInfoFunc func(msg message.Message)
+	IDFunc             func() game.ID
+	LeaveFunc          func()
+	UpdateInfoFunc     func(msg message.Message, username string)
+	SetReplayFunc      func(r replay.Replay)
+	UpdateSpectateFunc func(msg message.Message)
+	ShowHintFunc       func(msg message.Message)
+	ShowReportFunc     func(msg message.Message)
+	HandleWarningFunc  func(msg message.Message)
 }
 
 func (m mockGame) ID() game.ID {
@@ -41,8 +47,28 @@ func (m mockGame) Leave() {
 	m.LeaveFunc()
 }
 
-func (m *mockGame) UpdateInfo(msg message.Message) {
-	m.UpdateInfoFunc(msg)
+func (m *mockGame) UpdateInfo(msg message.Message, username string) {
+	m.UpdateInfoFunc(msg, username)
+}
+
+func (m *mockGame) SetReplay(r replay.Replay) {
+	m.SetReplayFunc(r)
+}
+
+func (m *mockGame) UpdateSpectate(msg message.Message) {
+	m.UpdateSpectateFunc(msg)
+}
+
+func (m *mockGame) ShowHint(msg message.Message) {
+	m.ShowHintFunc(msg)
+}
+
+func (m *mockGame) ShowReport(msg message.Message) {
+	m.ShowReportFunc(msg)
+}
+
+func (m *mockGame) HandleWarning(msg message.Message) {
+	m.HandleWarningFunc(msg)
 }
 
 type mockLobby struct {
@@ -57,7 +83,9 @@ type mockDOM struct {
 	QuerySelectorFunc      func(query string) js.Value
 	QuerySelectorAllFunc   func(document js.Value, query string) []js.Value
 	SetCheckedFunc         func(query string, checked bool)
-	NewWebSocketFunc       func(url string) js.Value
+	NewWebSocketFunc       func(url string, protocols ...string) js.Value
+	NewUint8ArrayFunc      func(data []byte) js.Value
+	ArrayBufferBytesFunc   func(arrayBuffer js.Value) []byte
 	EncodeURIComponentFunc func(str string) string
 	NewJsFuncFunc          func(fn func()) js.Func
 	NewJsEventFuncFunc     func(fn func(event js.Value)) js.Func
@@ -76,8 +104,16 @@ func (m *mockDOM) SetChecked(query string, checked bool) {
 	m.SetCheckedFunc(query, checked)
 }
 
-func (m *mockDOM) NewWebSocket(url string) js.Value {
-	return m.NewWebSocketFunc(url)
+func (m *mockDOM) NewWebSocket(url string, protocols ...string) js.Value {
+	return m.NewWebSocketFunc(url, protocols...)
+}
+
+func (m mockDOM) NewUint8Array(data []byte) js.Value {
+	return m.NewUint8ArrayFunc(data)
+}
+
+func (m mockDOM) ArrayBufferBytes(arrayBuffer js.Value) []byte {
+	return m.ArrayBufferBytesFunc(arrayBuffer)
 }
 
 func (m mockDOM) EncodeURIComponent(str string) string {
@@ -9,9 +9,11 @@ import (
 	"errors"
 	"sync"
 	"syscall/js"
+	"time"
 
 	"github.com/jacobpatterson1549/selene-bananas/game"
 	"github.com/jacobpatterson1549/selene-bananas/game/message"
+	"github.com/jacobpatterson1549/selene-bananas/game/replay"
 	"github.com/jacobpatterson1549/selene-bananas/ui"
 )
 
@@ -21,10 +23,14 @@ type (
 		dom       DOM
 		log       Log
 		webSocket js.Value
-		user      User
-		game      Game
-		lobby     Lobby
-		jsFuncs   struct {
+		// url is the address of the last websocket that was connected to, including the access token of the user.
+		url string
+		// reconnecting is set while the socket is trying to open a new websocket after the last one was closed unexpectedly.
+		reconnecting bool
+		user         User
+		game         Game
+		lobby        Lobby
+		jsFuncs      struct {
 			onOpen    js.Func
 			onClose   js.Func
 			onError   js.Func
@@ -49,7 +55,17 @@ type (
 		// Leave removes the user from his current game.
 		Leave()
 		// UpdateInfo updates the game for the specified message.
-		UpdateInfo(m message.Message)
+		UpdateInfo(m message.Message, username string)
+		// SetReplay stores the events of the finished game so they can be viewed.
+		SetReplay(r replay.Replay)
+		// UpdateSpectate shows the snapshot of the game being watched.
+		UpdateSpectate(m message.Message)
+		// ShowHint highlights where tiles could be moved to form a new word.
+		ShowHint(m message.Message)
+		// ShowReport outlines the invalid words and stray tiles on the board.
+		ShowReport(m message.Message)
+		// HandleWarning updates the game for the code and details of a warning.
+		HandleWarning(m message.Message)
 	}
 
 	// Lobby is used to display available games and give users a place to join a game from.
@@ -62,7 +78,9 @@ type (
 		QuerySelector(query string) js.Value
 		QuerySelectorAll(document js.Value, query string) []js.Value
 		SetChecked(query string, checked bool)
-		NewWebSocket(url string) js.Value
+		NewWebSocket(url string, protocols ...string) js.Value
+		NewUint8Array(data []byte) js.Value
+		ArrayBufferBytes(arrayBuffer js.Value) []byte
 		EncodeURIComponent(str string) string
 		NewJsFunc(fn func()) js.Func
 		NewJsEventFunc(fn func(event js.Value)) js.Func
@@ -78,6 +96,16 @@ type (
 	}
 )
 
+const (
+	// normalClosure is the code of websockets that were closed on purpose.
+	normalClosure = 1000
+	// reconnectDelay is how long to wait before trying to reconnect a websocket that was closed unexpectedly.
+	// The delay is doubled after each failed attempt.
+	reconnectDelay = 1 * time.Second
+	// maxReconnectAttempts is the number of times to try to reconnect before leaving the lobby.
+	maxReconnectAttempts = 5
+)
+
 // New creates a new socket.
 func New(dom DOM, log Log, user User, game Game, lobby Lobby) *Socket {
 	s := Socket{
@@ -121,14 +149,20 @@ func (s *Socket) Connect(event js.Value) error {
 	if err != nil {
 		return err
 	}
-	url := s.webSocketURL(*f)
+	s.url = s.webSocketURL(*f)
+	return s.open()
+}
+
+// open creates a websocket to the url, waiting for it to open or fail.
+func (s *Socket) open() error {
 	s.releaseWebSocketJsFuncs()
 	errC := make(chan error, 1)
 	s.jsFuncs.onOpen = s.dom.NewJsFunc(s.onOpen(errC))
 	s.jsFuncs.onClose = s.dom.NewJsEventFunc(s.onClose)
 	s.jsFuncs.onError = s.dom.NewJsFunc(s.onError(errC))
 	s.jsFuncs.onMessage = s.dom.NewJsEventFunc(s.onMessage)
-	s.webSocket = s.dom.NewWebSocket(url)
+	s.webSocket = s.dom.NewWebSocket(s.url, message.BinarySubprotocol)
+	s.webSocket.Set("binaryType", "arraybuffer")
 	s.webSocket.Set("onopen", s.jsFuncs.onOpen)
 	s.webSocket.Set("onclose", s.jsFuncs.onClose)
 	s.webSocket.Set("onerror", s.jsFuncs.onError)
@@ -159,39 +193,87 @@ func (s *Socket) onOpen(errC chan<- error) func() {
 }
 
 // onMessage is called when the websocket is closing.
+// Websockets that were not closed on purpose are reconnected.
 func (s *Socket) onClose(event js.Value) {
+	if s.reconnecting {
+		return // the reconnect attempt failed
+	}
+	if code := event.Get("code"); !code.IsUndefined() && code.Int() != normalClosure && len(s.url) != 0 {
+		s.reconnecting = true
+		s.removeWebSocketListeners()
+		s.log.Warning("lost connection to lobby, reconnecting")
+		go s.reconnect(reconnectDelay)
+		return
+	}
 	if reason := event.Get("reason"); !reason.IsUndefined() && len(reason.String()) != 0 {
 		s.log.Warning("left lobby: " + reason.String())
 	}
 	s.closeWebSocket()
 }
 
+// reconnect opens a new websocket with the url of the closed one, waiting longer after each failed attempt.
+// The game stays open while reconnecting so the server can resume it.  The lobby is closed if the websocket cannot be reopened.
+func (s *Socket) reconnect(delay time.Duration) {
+	defer func() {
+		s.reconnecting = false
+	}()
+	for i := 0; i < maxReconnectAttempts; i++ {
+		time.Sleep(delay)
+		if len(s.url) == 0 {
+			return // closed while waiting
+		}
+		if err := s.open(); err == nil {
+			s.log.Info("reconnected to lobby")
+			return
+		}
+		s.removeWebSocketListeners()
+		delay *= 2
+	}
+	s.log.Error("could not reconnect to lobby")
+	s.closeWebSocket()
+}
+
 // closeWebSocket releases the event listeners and does some dom cleanup.
 func (s *Socket) closeWebSocket() {
+	s.removeWebSocketListeners()
+	s.dom.SetChecked("#has-websocket", false)
+	s.dom.SetChecked("#hide-game", true)
+	s.dom.SetChecked("#tab-lobby", true)
+}
+
+// removeWebSocketListeners removes and releases the event listeners of the websocket.
+func (s *Socket) removeWebSocketListeners() {
 	s.webSocket.Set("onopen", nil)
 	s.webSocket.Set("onclose", nil)
 	s.webSocket.Set("onerror", nil)
 	s.webSocket.Set("onmessage", nil)
 	s.releaseWebSocketJsFuncs()
-	s.dom.SetChecked("#has-websocket", false)
-	s.dom.SetChecked("#hide-game", true)
-	s.dom.SetChecked("#tab-lobby", true)
 }
 
 // onMessage is called when the websocket encounters an unwanted error.
 func (s *Socket) onError(errC chan<- error) func() {
 	return func() {
-		s.user.Logout()
+		if !s.reconnecting {
+			s.user.Logout()
+		}
 		errC <- errors.New("lobby closed")
 	}
 }
 
 // onMessage is called when the websocket receives a message.
+// Text messages are json and other messages are ArrayBuffers of the binary encoding of messages.
 func (s *Socket) onMessage(event js.Value) {
 	jsMessage := event.Get("data")
-	messageJSON := jsMessage.String()
 	var m message.Message
-	err := json.Unmarshal([]byte(messageJSON), &m)
+	var err error
+	switch jsMessage.Type() {
+	case js.TypeString:
+		messageJSON := jsMessage.String()
+		err = json.Unmarshal([]byte(messageJSON), &m)
+	default:
+		data := s.dom.ArrayBufferBytes(jsMessage)
+		err = m.UnmarshalBinary(data)
+	}
 	if err != nil {
 		s.log.Error("unmarshalling message: " + err.Error())
 		return
@@ -209,15 +291,49 @@ func (s *Socket) onMessage(event js.Value) {
 		s.log.Error(m.Info)
 	case message.SocketWarning:
 		s.log.Warning(m.Info)
+		s.game.HandleWarning(m)
 	case message.SocketHTTPPing:
 		s.httpPing()
 	case message.GameChat:
 		s.log.Chat(m.Info)
+	case message.GameReplay:
+		s.handleGameReplay(m)
+	case message.SpectateGame:
+		s.handleSpectate(m)
+	case message.Hint:
+		s.handleHint(m)
+	case message.BoardReport:
+		s.game.ShowReport(m)
 	default:
 		s.log.Error("unknown message type received")
 	}
 }
 
+// handleGameReplay passes the replay of the game to the game.
+func (s *Socket) handleGameReplay(m message.Message) {
+	if m.Replay == nil {
+		s.log.Error("no replay received")
+		return
+	}
+	s.game.SetReplay(*m.Replay)
+}
+
+// handleSpectate shows the snapshot of the game being watched and logs any info text from the message.
+func (s *Socket) handleSpectate(m message.Message) {
+	s.game.UpdateSpectate(m)
+	if len(m.Info) > 0 {
+		s.log.Info(m.Info)
+	}
+}
+
+// handleHint shows the hint on the game and logs the info text from the message.
+func (s *Socket) handleHint(m message.Message) {
+	s.game.ShowHint(m)
+	if len(m.Info) > 0 {
+		s.log.Info(m.Info)
+	}
+}
+
 // Send delivers a message to the server via it's websocket.
 func (s *Socket) Send(m message.Message) {
 	if !s.isOpen() {
@@ -231,6 +347,15 @@ func (s *Socket) Send(m message.Message) {
 	if m.Type != message.CreateGame { // all messages except CREATE are for a specific game
 		m.Game.ID = s.game.ID()
 	}
+	if s.webSocket.Get("protocol").String() == message.BinarySubprotocol {
+		data, err := m.MarshalBinary()
+		if err != nil {
+			s.log.Error("marshalling binary socket message to send: " + err.Error())
+			return
+		}
+		s.webSocket.Call("send", s.dom.NewUint8Array(data))
+		return
+	}
 	messageJSON, err := json.Marshal(m)
 	if err != nil {
 		s.log.Error("marshalling socket message to send: " + err.Error())
@@ -241,6 +366,7 @@ func (s *Socket) Send(m message.Message) {
 
 // Close releases the websocket
 func (s *Socket) Close() {
+	s.url = "" // do not reconnect
 	if s.isOpen() {
 		s.closeWebSocket() // removes onClose
 		s.webSocket.Call("close")
@@ -272,7 +398,7 @@ func (s *Socket) handlePlayerRemove(m message.Message) {
 
 // handleInfo contains the logic for handling messages with types Info and GameJoin.
 func (s *Socket) handleInfo(m message.Message) {
-	s.game.UpdateInfo(m)
+	s.game.UpdateInfo(m, s.user.Username())
 	if len(m.Info) > 0 {
 		s.log.Info(m.Info)
 	}
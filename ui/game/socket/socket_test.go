@@ -222,6 +222,83 @@ func TestSend(t *testing.T) {
 			t.Errorf("send function not called")
 		}
 	})
+	t.Run("binary", func(t *testing.T) {
+		m := message.Message{
+			Type: message.GameChat,
+			Info: "selene : hi",
+		}
+		var sent js.Value
+		sendJsFunc := js.FuncOf(func(this js.Value, args []js.Value) any {
+			sent = args[0]
+			return nil
+		})
+		defer sendJsFunc.Release()
+		s := Socket{
+			webSocket: js.ValueOf(map[string]any{
+				"readyState": 1,
+				"protocol":   message.BinarySubprotocol,
+				"send":       sendJsFunc,
+			}),
+			game: &mockGame{
+				IDFunc: func() game.ID {
+					return 21
+				},
+			},
+			dom: &mockDOM{
+				NewUint8ArrayFunc: func(data []byte) js.Value {
+					var got message.Message
+					want := m
+					want.Game = &game.Info{ID: 21}
+					if err := got.UnmarshalBinary(data); err != nil || !reflect.DeepEqual(want, got) {
+						t.Errorf("binary message not equal:\nwanted: %v\ngot:    %v (%v)", want, got, err)
+					}
+					return js.ValueOf("binary message")
+				},
+			},
+		}
+		s.Send(m)
+		if want, got := "binary message", sent.String(); want != got {
+			t.Errorf("wanted binary message to be sent, got %v", got)
+		}
+	})
+}
+
+func TestOnMessageBinary(t *testing.T) {
+	want := message.Message{
+		Type: message.GameChat,
+		Info: "selene : hi",
+	}
+	data, err := want.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unwanted error: %v", err)
+	}
+	arrayBuffer := js.ValueOf(map[string]any{})
+	chatLogged := false
+	s := Socket{
+		dom: &mockDOM{
+			ArrayBufferBytesFunc: func(gotArrayBuffer js.Value) []byte {
+				if !arrayBuffer.Equal(gotArrayBuffer) {
+					t.Errorf("wanted data of event to be read")
+				}
+				return data
+			},
+		},
+		log: &mockLog{
+			ChatFunc: func(text string) {
+				if want.Info != text {
+					t.Errorf("chat not equal: wanted %v, got %v", want.Info, text)
+				}
+				chatLogged = true
+			},
+		},
+	}
+	event := js.ValueOf(map[string]any{
+		"data": arrayBuffer,
+	})
+	s.onMessage(event)
+	if !chatLogged {
+		t.Error("wanted binary chat message to be logged")
+	}
 }
 
 func TestOnMessageWithSetGameInfos(t *testing.T) {
@@ -310,6 +387,11 @@ func TestOnMessageWithLogging(t *testing.T) {
 					got = 3
 				},
 			},
+			game: &mockGame{
+				HandleWarningFunc: func(m message.Message) {
+					// NOOP
+				},
+			},
 		}
 		s.onMessage(event)
 		if test.want != got {
@@ -317,6 +399,29 @@ func TestOnMessageWithLogging(t *testing.T) {
 		}
 	}
 }
+
+func TestOnMessageWarning(t *testing.T) {
+	event := js.ValueOf(map[string]any{
+		"data": `{"type":` + strconv.Itoa(int(message.SocketWarning)) + `,"code":` + strconv.Itoa(int(message.CodeNoTilesLeft)) + `}`,
+	})
+	var got message.Code
+	s := Socket{
+		log: &mockLog{
+			WarningFunc: func(text string) {
+				// NOOP
+			},
+		},
+		game: &mockGame{
+			HandleWarningFunc: func(m message.Message) {
+				got = m.Code
+			},
+		},
+	}
+	s.onMessage(event)
+	if want := message.CodeNoTilesLeft; want != got {
+		t.Errorf("wanted game to handle warning with code %v, got %v", want, got)
+	}
+}
 func TestOnMessageWithHandlers(t *testing.T) {
 	tests := []struct {
 		messageType     message.Type
@@ -348,6 +453,14 @@ func TestOnMessageWithHandlers(t *testing.T) {
 			messageType:    message.RefreshGameBoard,
 			wantActionType: 2,
 		},
+		{
+			messageType:    message.SpectateGame,
+			wantActionType: 3,
+		},
+		{
+			messageType:    message.Hint,
+			wantActionType: 4,
+		},
 	}
 	for i, test := range tests {
 		event := js.ValueOf(map[string]any{
@@ -374,9 +487,18 @@ func TestOnMessageWithHandlers(t *testing.T) {
 				LeaveFunc: func() {
 					gotAction = 1
 				},
-				UpdateInfoFunc: func(msg message.Message) {
+				UpdateInfoFunc: func(msg message.Message, username string) {
+					if username != "fred" {
+						t.Errorf("Test %v: wanted game info to be updated for fred, got %q", i, username)
+					}
 					gotAction = 2
 				},
+				UpdateSpectateFunc: func(msg message.Message) {
+					gotAction = 3
+				},
+				ShowHintFunc: func(msg message.Message) {
+					gotAction = 4
+				},
 			},
 			dom: &mockDOM{
 				SetCheckedFunc: func(query string, checked bool) {
@@ -385,6 +507,11 @@ func TestOnMessageWithHandlers(t *testing.T) {
 					}
 				},
 			},
+			user: &mockUser{
+				UsernameFunc: func() string {
+					return "fred"
+				},
+			},
 		}
 		s.onMessage(event)
 		closeWebSocket.Release()
@@ -422,6 +549,28 @@ func TestOnMessageWithHandlers(t *testing.T) {
 			t.Error("wanted ping to be handled")
 		}
 	})
+	t.Run("boardReport", func(t *testing.T) {
+		event := js.ValueOf(map[string]any{
+			"data": `{"type":` + strconv.Itoa(int(message.BoardReport)) + `,"game":{"report":{"words":[{"word":"CAT","x":1,"y":2}]}}}`,
+		})
+		var got *board.Report
+		s := Socket{
+			game: &mockGame{
+				ShowReportFunc: func(msg message.Message) {
+					got = msg.Game.Report
+				},
+			},
+		}
+		s.onMessage(event)
+		want := &board.Report{
+			Words: []board.WordReport{
+				{Word: "CAT", X: 1, Y: 2},
+			},
+		}
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("reports not equal:\nwanted: %v\ngot:    %v", want, got)
+		}
+	})
 }
 
 func TestNew(t *testing.T) {
@@ -551,6 +700,119 @@ func TestOnClose(t *testing.T) {
 	}
 }
 
+func TestOnCloseUnexpected(t *testing.T) {
+	warningLogged := false
+	s := Socket{
+		webSocket: js.ValueOf(map[string]any{}),
+		url:       "wss://example.com/lobby?access_token=user.jwt.token",
+		log: &mockLog{
+			WarningFunc: func(text string) {
+				warningLogged = true
+			},
+		},
+		dom: &mockDOM{
+			SetCheckedFunc: func(query string, checked bool) {
+				t.Errorf("unwanted dom change when socket is reconnecting: %v", query)
+			},
+		},
+	}
+	event := js.ValueOf(map[string]any{"code": 1006})
+	s.onClose(event)
+	switch {
+	case !s.reconnecting:
+		t.Error("wanted socket to be reconnecting after it was closed unexpectedly")
+	case !warningLogged:
+		t.Error("wanted warning logged when socket is reconnecting")
+	}
+	s.url = "" // stop reconnecting
+}
+
+func TestReconnect(t *testing.T) {
+	reconnectTests := []struct {
+		numFailures int
+		wantOk      bool
+	}{
+		{
+			wantOk: true,
+		},
+		{
+			numFailures: 2,
+			wantOk:      true,
+		},
+		{
+			numFailures: maxReconnectAttempts,
+		},
+	}
+	for i, test := range reconnectTests {
+		url := "wss://example.com/lobby?access_token=user.jwt.token"
+		var jsFuncs []func()
+		numAttempts := 0
+		infoLogged, errorLogged, userLoggedOut, lobbyClosed := false, false, false, false
+		s := Socket{
+			url:          url,
+			reconnecting: true,
+			webSocket:    js.ValueOf(map[string]any{}),
+			dom: &mockDOM{
+				NewJsFuncFunc: func(fn func()) js.Func {
+					jsFuncs = append(jsFuncs, fn)
+					return js.FuncOf(func(this js.Value, args []js.Value) any {
+						return nil
+					})
+				},
+				NewJsEventFuncFunc: func(fn func(event js.Value)) js.Func {
+					return js.FuncOf(func(this js.Value, args []js.Value) any {
+						return nil
+					})
+				},
+				NewWebSocketFunc: func(gotURL string, protocols ...string) js.Value {
+					if url != gotURL {
+						t.Errorf("Test %v: wanted websocket to be reopened with %v, got %v", i, url, gotURL)
+					}
+					numAttempts++
+					onOpen, onError := jsFuncs[len(jsFuncs)-2], jsFuncs[len(jsFuncs)-1]
+					switch {
+					case numAttempts <= test.numFailures:
+						onError()
+					default:
+						onOpen()
+					}
+					return js.ValueOf(map[string]any{})
+				},
+				SetCheckedFunc: func(query string, checked bool) {
+					if query == "#tab-lobby" {
+						lobbyClosed = true
+					}
+				},
+			},
+			log: &mockLog{
+				InfoFunc: func(text string) {
+					infoLogged = true
+				},
+				ErrorFunc: func(text string) {
+					errorLogged = true
+				},
+			},
+			user: mockUser{
+				LogoutFunc: func() {
+					userLoggedOut = true
+				},
+			},
+		}
+		s.reconnect(0)
+		switch {
+		case s.reconnecting:
+			t.Errorf("Test %v: wanted socket to stop reconnecting", i)
+		case userLoggedOut:
+			t.Errorf("Test %v: wanted user to stay logged in when reconnect attempts fail", i)
+		case test.wantOk && (!infoLogged || lobbyClosed || numAttempts != test.numFailures+1):
+			t.Errorf("Test %v: wanted socket to be reconnected after %v attempts, got %v", i, test.numFailures+1, numAttempts)
+		case !test.wantOk && (!errorLogged || !lobbyClosed || numAttempts != maxReconnectAttempts):
+			t.Errorf("Test %v: wanted lobby closed after %v failed attempts, got %v", i, maxReconnectAttempts, numAttempts)
+		}
+		s.releaseWebSocketJsFuncs()
+	}
+}
+
 func TestOnError(t *testing.T) {
 	userLoggedOut := false
 	s := Socket{
@@ -683,10 +945,15 @@ func TestHandle(t *testing.T) {
 					LeaveFunc: func() {
 						callID = 1
 					},
-					UpdateInfoFunc: func(msg message.Message) {
+					UpdateInfoFunc: func(msg message.Message, username string) {
 						callID = 2
 					},
 				},
+				user: &mockUser{
+					UsernameFunc: func() string {
+						return "fred"
+					},
+				},
 				log: &mockLog{
 					InfoFunc: func(text string) {
 						if want, got := test.info, text; want != got {
@@ -35,6 +35,8 @@ type mockDOM struct {
 	SetCheckedFunc          func(query string, checked bool)
 	ValueFunc               func(query string) string
 	SetValueFunc            func(query, value string)
+	FormatTimeFunc          func(utcSeconds int64) string
+	CloneElementFunc        func(query string) js.Value
 	ConfirmFunc             func(message string) bool
 	NewXHRFunc              func() js.Value
 	StoreCredentialsFunc    func(form js.Value)
@@ -69,6 +71,14 @@ func (m *mockDOM) SetValue(query, value string) {
 	m.SetValueFunc(query, value)
 }
 
+func (m mockDOM) FormatTime(utcSeconds int64) string {
+	return m.FormatTimeFunc(utcSeconds)
+}
+
+func (m mockDOM) CloneElement(query string) js.Value {
+	return m.CloneElementFunc(query)
+}
+
 func (m *mockDOM) Confirm(message string) bool {
 	return m.ConfirmFunc(message)
 }
@@ -92,6 +92,8 @@ func (u *User) newRequest(f ui.Form) (*request, error) {
 			u.dom.StoreCredentials(f.Element)
 			u.login(body)
 		}
+	case "/user_stats":
+		handler = u.setStats
 	case "/ping":
 		// NOOP
 	default:
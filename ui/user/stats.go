@@ -0,0 +1,86 @@
+//go:build js && wasm
+
+package user
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"syscall/js"
+)
+
+type (
+	// stats summarizes the results of the games a user has played in.
+	stats struct {
+		GamesPlayed        int          `json:"gamesPlayed"`
+		Wins               int          `json:"wins"`
+		WinRate            float64      `json:"winRate"`
+		AverageWordsPerWin float64      `json:"averageWordsPerWin"`
+		RecentGames        []gameResult `json:"recentGames"`
+	}
+
+	// gameResult is the outcome of a finished game.
+	gameResult struct {
+		Players     []string `json:"players"`
+		Winner      string   `json:"winner"`
+		WordCount   int      `json:"wordCount"`
+		LongestWord string   `json:"longestWord"`
+		FinishedAt  int64    `json:"finishedAt"`
+		DurationSec int64    `json:"durationSec"`
+	}
+)
+
+// setStats shows the statistics in the json body of the response.
+func (u *User) setStats(body string) {
+	var s stats
+	if err := json.Unmarshal([]byte(body), &s); err != nil {
+		u.log.Error("parsing user stats: " + err.Error())
+		return
+	}
+	u.dom.SetValue(".user-stats .games-played", strconv.Itoa(s.GamesPlayed))
+	u.dom.SetValue(".user-stats .wins", strconv.Itoa(s.Wins))
+	u.dom.SetValue(".user-stats .win-rate", strconv.FormatFloat(s.WinRate*100, 'f', 1, 64)+"%")
+	u.dom.SetValue(".user-stats .average-words-per-win", strconv.FormatFloat(s.AverageWordsPerWin, 'f', 1, 64))
+	tbodyElement := u.dom.QuerySelector(".user-stats .recent-games>tbody")
+	tbodyElement.Set("innerHTML", "")
+	if len(s.RecentGames) == 0 {
+		emptyRowElement := u.dom.CloneElement(".user-stats .no-recent-game-row")
+		tbodyElement.Call("appendChild", emptyRowElement)
+		return
+	}
+	for _, r := range s.RecentGames {
+		rowElement := u.recentGameElement(r)
+		tbodyElement.Call("appendChild", rowElement)
+	}
+}
+
+// recentGameElement creates a table row for the result.
+func (u *User) recentGameElement(r gameResult) js.Value {
+	recentGameElement := u.dom.CloneElement(".user-stats .recent-game-row")
+	cells := recentGameElement.Get("children").Index(0).Get("children")
+	values := []string{
+		u.dom.FormatTime(r.FinishedAt),
+		r.Winner,
+		strings.Join(r.Players, ", "),
+		strconv.Itoa(r.WordCount),
+		r.LongestWord,
+		formatDuration(r.DurationSec),
+	}
+	for i, v := range values {
+		cells.Index(i).Set("innerHTML", v)
+	}
+	return recentGameElement
+}
+
+// formatDuration formats the number of seconds as minutes and seconds, such as "12m05s".
+func formatDuration(sec int64) string {
+	if sec < 0 {
+		sec = 0
+	}
+	m, s := sec/60, sec%60
+	ss := strconv.FormatInt(s, 10)
+	if s < 10 {
+		ss = "0" + ss
+	}
+	return strconv.FormatInt(m, 10) + "m" + ss + "s"
+}
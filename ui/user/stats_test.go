@@ -0,0 +1,154 @@
+//go:build js && wasm
+
+package user
+
+import (
+	"syscall/js"
+	"testing"
+)
+
+func TestSetStats(t *testing.T) {
+	t.Run("bad json", func(t *testing.T) {
+		errorLogged := false
+		u := User{
+			log: &mockLog{
+				ErrorFunc: func(text string) {
+					errorLogged = true
+				},
+			},
+		}
+		u.setStats("{bad json")
+		if !errorLogged {
+			t.Error("wanted error to be logged")
+		}
+	})
+	t.Run("no recent games", func(t *testing.T) {
+		emptyRowElement := js.ValueOf(1337)
+		emptyRowAppended := false
+		appendChild := js.FuncOf(func(this js.Value, args []js.Value) any {
+			element := args[0]
+			emptyRowAppended = true
+			if want, got := emptyRowElement, element; !want.Equal(got) {
+				t.Errorf("wanted %v to be appended, got %v", want, got)
+			}
+			return nil
+		})
+		tbodyElement := js.ValueOf(map[string]any{
+			"innerHTML":   "existing recent games",
+			"appendChild": appendChild,
+		})
+		u := User{
+			dom: &mockDOM{
+				SetValueFunc: func(query, value string) {
+					// NOOP
+				},
+				QuerySelectorFunc: func(query string) js.Value {
+					return tbodyElement
+				},
+				CloneElementFunc: func(query string) js.Value {
+					return emptyRowElement
+				},
+			},
+		}
+		u.setStats("{}")
+		if got := tbodyElement.Get("innerHTML").String(); len(got) != 0 {
+			t.Error("wanted recent games table to be cleared")
+		}
+		if !emptyRowAppended {
+			t.Errorf("wanted empty row to be appended")
+		}
+		appendChild.Release()
+	})
+	t.Run("happy path", func(t *testing.T) {
+		newRecentGameRow := func(finishedAt, winner, players, wordCount, longestWord, duration string) js.Value {
+			return js.ValueOf(map[string]any{
+				"children": []any{
+					map[string]any{
+						"children": []any{
+							map[string]any{"innerHTML": finishedAt},
+							map[string]any{"innerHTML": winner},
+							map[string]any{"innerHTML": players},
+							map[string]any{"innerHTML": wordCount},
+							map[string]any{"innerHTML": longestWord},
+							map[string]any{"innerHTML": duration},
+						},
+					},
+				},
+			})
+		}
+		wantAppends := []js.Value{
+			newRecentGameRow("A", "selene", "fred, selene", "9", "BANANAS", "2m05s"),
+			newRecentGameRow("B", "fred", "fred, selene", "4", "APPLE", "13m40s"),
+		}
+		jsonString := func(v js.Value) string { // hack to get around js.Value.Equal using === (refs are different)
+			return js.Global().Get("JSON").Call("stringify", v).String()
+		}
+		numAppended := 0
+		appendChild := js.FuncOf(func(this js.Value, args []js.Value) any {
+			element := args[0]
+			if want, got := jsonString(wantAppends[numAppended]), jsonString(element); want != got {
+				t.Errorf("append %v not equal:\nwanted: %v\ngot:    %v", numAppended+1, want, got)
+			}
+			numAppended++
+			return nil
+		})
+		tbodyElement := js.ValueOf(map[string]any{
+			"innerHTML":   "existing recent games",
+			"appendChild": appendChild,
+		})
+		wantValues := map[string]string{
+			".user-stats .games-played":          "2",
+			".user-stats .wins":                  "1",
+			".user-stats .win-rate":              "50.0%",
+			".user-stats .average-words-per-win": "9.0",
+		}
+		gotValues := make(map[string]string)
+		u := User{
+			dom: &mockDOM{
+				SetValueFunc: func(query, value string) {
+					gotValues[query] = value
+				},
+				QuerySelectorFunc: func(query string) js.Value {
+					return tbodyElement
+				},
+				CloneElementFunc: func(query string) js.Value {
+					return newRecentGameRow("", "", "", "", "", "")
+				},
+				FormatTimeFunc: func(utcSeconds int64) string {
+					return string(rune(utcSeconds))
+				},
+			},
+		}
+		body := `{"gamesPlayed":2,"wins":1,"winRate":0.5,"averageWordsPerWin":9,"recentGames":[` +
+			`{"players":["fred","selene"],"winner":"selene","wordCount":9,"longestWord":"BANANAS","finishedAt":65,"durationSec":125},` +
+			`{"players":["fred","selene"],"winner":"fred","wordCount":4,"longestWord":"APPLE","finishedAt":66,"durationSec":820}]}`
+		u.setStats(body)
+		for query, want := range wantValues {
+			if got := gotValues[query]; want != got {
+				t.Errorf("values for %v not equal: wanted %v, got %v", query, want, got)
+			}
+		}
+		if numAppended != 2 {
+			t.Errorf("wanted 2 recent game rows to be appended, got %v", numAppended)
+		}
+		appendChild.Release()
+	})
+}
+
+func TestFormatDuration(t *testing.T) {
+	formatDurationTests := []struct {
+		sec  int64
+		want string
+	}{
+		{-5, "0m00s"},
+		{0, "0m00s"},
+		{9, "0m09s"},
+		{60, "1m00s"},
+		{3671, "61m11s"},
+	}
+	for i, test := range formatDurationTests {
+		if got := formatDuration(test.sec); test.want != got {
+			t.Errorf("Test %v: durations of %v seconds not equal: wanted %v, got %v", i, test.sec, test.want, got)
+		}
+	}
+}
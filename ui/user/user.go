@@ -39,6 +39,8 @@ type (
 		SetChecked(query string, checked bool)
 		Value(query string) string
 		SetValue(query, value string)
+		FormatTime(utcSeconds int64) string
+		CloneElement(query string) js.Value
 		Confirm(message string) bool
 		NewXHR() js.Value
 		Base64Decode(a string) []byte